@@ -5,6 +5,7 @@ import (
 	"go-api-boilerplate/internal/bootstrap"
 	"go-api-boilerplate/internal/config"
 	"log"
+	"os"
 
 	_ "go-api-boilerplate/docs"
 
@@ -21,12 +22,24 @@ import (
 
 // @host      localhost:8080
 // @BasePath  /
+
+// @securityDefinitions.apikey  AdminToken
+// @in                          header
+// @name                        Authorization
+// @description                 "Bearer " followed by the ADMIN_TOKEN value
 func main() {
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		log.Fatalf("failed to load config: %v", err)
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := bootstrap.RunMigrate(context.Background(), cfg, os.Args[2:], os.Stdout); err != nil {
+			log.Fatalf("failed to migrate: %v", err)
+		}
+		return
+	}
+
 	app, err := bootstrap.NewApp(context.Background(), cfg)
 	if err != nil {
 		log.Fatalf("failed to create app: %v", err)
@@ -0,0 +1,132 @@
+package events
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+	"log"
+	"slices"
+	"sync"
+)
+
+// asyncQueueSize is the number of events an asynchronous subscriber can fall
+// behind by before further events are dropped.
+const asyncQueueSize = 1024
+
+// Subscriber reacts to a published event. Its errors are logged.
+type Subscriber func(ctx context.Context, event domain.Event) error
+
+// Bus is an in-process out.EventPublisher. Synchronous subscribers run during
+// Publish, one after the other, and asynchronous ones each on a goroutine of
+// their own, in the order the events were published. Either way a subscriber
+// that fails or panics is logged and never affects the publisher or the other
+// subscribers. Events are lost when the process exits.
+type Bus struct {
+	mu      sync.RWMutex
+	subs    []subscription
+	async   []asyncSubscription
+	closed  bool
+	running sync.WaitGroup
+}
+
+var _ out.EventPublisher = &Bus{}
+
+type subscription struct {
+	name string
+	fn   Subscriber
+}
+
+type asyncSubscription struct {
+	subscription
+	queue chan delivery
+}
+
+type delivery struct {
+	ctx   context.Context
+	event domain.Event
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers fn to run during Publish, which it slows down. name
+// identifies it in logs.
+func (b *Bus) Subscribe(name string, fn Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, subscription{name: name, fn: fn})
+}
+
+// SubscribeAsync registers fn to run on a goroutine of its own. Events
+// published while asyncQueueSize of them are waiting for fn are dropped and
+// logged.
+func (b *Bus) SubscribeAsync(name string, fn Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	sub := asyncSubscription{subscription{name: name, fn: fn}, make(chan delivery, asyncQueueSize)}
+	b.async = append(b.async, sub)
+	b.running.Go(func() {
+		for d := range sub.queue {
+			sub.deliver(d.ctx, d.event)
+		}
+	})
+}
+
+// Publish hands each event to every subscriber. Asynchronous subscribers get
+// ctx without its cancellation, as they outlive the request. Synchronous
+// subscribers run without the lock held, so they may subscribe, publish or
+// close the bus themselves.
+func (b *Bus) Publish(ctx context.Context, events ...domain.Event) {
+	b.mu.RLock()
+	subs := slices.Clone(b.subs)
+	b.mu.RUnlock()
+	for _, event := range events {
+		for _, sub := range subs {
+			sub.deliver(ctx, event)
+		}
+		b.enqueue(ctx, event)
+	}
+}
+
+// enqueue hands event to the asynchronous subscribers, unless the bus is
+// closed.
+func (b *Bus) enqueue(ctx context.Context, event domain.Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return
+	}
+	for _, sub := range b.async {
+		select {
+		case sub.queue <- delivery{ctx: context.WithoutCancel(ctx), event: event}:
+		default:
+			log.Printf("[EVENTS]: %s dropped %s: queue full\n", sub.name, event.EventName())
+		}
+	}
+}
+
+// Close stops handing events to the asynchronous subscribers and waits for
+// them to handle the events already queued.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		for _, sub := range b.async {
+			close(sub.queue)
+		}
+	}
+	b.mu.Unlock()
+	b.running.Wait()
+}
+
+func (s subscription) deliver(ctx context.Context, event domain.Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[EVENTS]: %s panicked on %s: %v\n", s.name, event.EventName(), r)
+		}
+	}()
+	if err := s.fn(ctx, event); err != nil {
+		log.Printf("[EVENTS]: %s failed on %s: %v\n", s.name, event.EventName(), err)
+	}
+}
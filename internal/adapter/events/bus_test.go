@@ -0,0 +1,129 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBus_Publish(t *testing.T) {
+	created := domain.BookCreated{Book: domain.Book{ID: 1}}
+	deleted := domain.BookDeleted{Book: domain.Book{ID: 1}}
+
+	var mu sync.Mutex
+	var got []string
+	record := func(name string) Subscriber {
+		return func(ctx context.Context, event domain.Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, name+" "+event.EventName())
+			return nil
+		}
+	}
+
+	bus := NewBus()
+	bus.Subscribe("failing", func(ctx context.Context, event domain.Event) error {
+		return errors.New("unavailable")
+	})
+	bus.Subscribe("panicking", func(ctx context.Context, event domain.Event) error {
+		panic("boom")
+	})
+	bus.Subscribe("sync", record("sync"))
+	bus.SubscribeAsync("async panicking", func(ctx context.Context, event domain.Event) error {
+		panic("boom")
+	})
+
+	var async []domain.Event
+	var asyncCtxErr error
+	bus.SubscribeAsync("async", func(ctx context.Context, event domain.Event) error {
+		async = append(async, event)
+		asyncCtxErr = ctx.Err()
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bus.Publish(ctx, created, deleted)
+	cancel()
+
+	mu.Lock()
+	if want := []string{"sync book.created", "sync book.deleted"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("synchronous deliveries = %v, want %v", got, want)
+	}
+	mu.Unlock()
+
+	bus.Close()
+	if want := []domain.Event{created, deleted}; !reflect.DeepEqual(async, want) {
+		t.Errorf("asynchronous deliveries = %v, want %v", async, want)
+	}
+	if asyncCtxErr != nil {
+		t.Errorf("asynchronous subscriber ctx error = %v, want the request's cancellation detached", asyncCtxErr)
+	}
+
+	// Once closed, only synchronous subscribers are handed events.
+	bus.Publish(context.Background(), created)
+	if len(async) != 2 {
+		t.Errorf("asynchronous deliveries after Close = %v", async)
+	}
+	if len(got) != 3 {
+		t.Errorf("synchronous deliveries after Close = %v", got)
+	}
+}
+
+func TestBus_SubscribeAsync_QueueFull(t *testing.T) {
+	bus := NewBus()
+	release := make(chan struct{})
+	var handled int
+	bus.SubscribeAsync("slow", func(ctx context.Context, event domain.Event) error {
+		<-release
+		handled++
+		return nil
+	})
+
+	// The first event is taken off the queue by the subscriber, which blocks;
+	// the queue then holds asyncQueueSize more and the rest are dropped.
+	for range asyncQueueSize + 10 {
+		bus.Publish(context.Background(), domain.BookCreated{})
+	}
+	close(release)
+	bus.Close()
+
+	if handled < asyncQueueSize || handled > asyncQueueSize+1 {
+		t.Errorf("handled %d events, want %d or %d", handled, asyncQueueSize, asyncQueueSize+1)
+	}
+}
+
+func TestBus_Publish_Reentrant(t *testing.T) {
+	bus := NewBus()
+	var got []string
+	bus.Subscribe("reentrant", func(ctx context.Context, event domain.Event) error {
+		if _, ok := event.(domain.BookCreated); ok {
+			bus.Subscribe("late", func(ctx context.Context, event domain.Event) error {
+				got = append(got, "late "+event.EventName())
+				return nil
+			})
+			bus.Publish(ctx, domain.BookDeleted{})
+			bus.Close()
+		}
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		bus.Publish(context.Background(), domain.BookCreated{})
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Publish() deadlocked on a subscriber using the bus")
+	}
+
+	if want := []string{"late book.deleted"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,15 @@
+package events
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+	"log"
+)
+
+// Log is a Subscriber writing every event to the log, with the actor and
+// request that caused it.
+func Log(ctx context.Context, event domain.Event) error {
+	audit := domain.AuditFrom(ctx)
+	log.Printf("[EVENT]: %s by %q in request %q: %+v\n", event.EventName(), audit.Actor, audit.RequestID, event)
+	return nil
+}
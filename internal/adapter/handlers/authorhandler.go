@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"fmt"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/internal/http/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	CreateAuthorReq struct {
+		Name string `json:"name" binding:"required" example:"George Orwell"`
+	}
+	GetAuthorsReq struct {
+		Page    int `form:"page,default=1" binding:"min=1" example:"1"`
+		PerPage int `form:"per_page,default=10" binding:"min=1,max=100" example:"10"`
+	}
+	UpdateAuthorReq struct {
+		Name string `json:"name" binding:"required" example:"George Orwell"`
+	}
+	AuthorRes struct {
+		ID        int    `json:"id" example:"1"`
+		Name      string `json:"name" example:"George Orwell"`
+		CreatedAt string `json:"created_at" example:"2025-01-01T00:00:00Z"`
+		UpdatedAt string `json:"updated_at" example:"2025-01-01T00:00:00Z"`
+	}
+)
+
+func newAuthorRes(author domain.Author) AuthorRes {
+	return AuthorRes{
+		ID:        author.ID,
+		Name:      author.Name,
+		CreatedAt: author.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: author.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+type AuthorHandler struct {
+	authorService in.AuthorUseCase
+	pages         *util.Paginator
+}
+
+func NewAuthorHandler(authorService in.AuthorUseCase, pages *util.Paginator) *AuthorHandler {
+	return &AuthorHandler{authorService: authorService, pages: pages}
+}
+
+// CreateAuthor godoc
+// @Summary      Create an author
+// @Description  Create an author
+// @Tags         authors
+// @Accept       json
+// @Produce      json
+// @Param        request  body		CreateAuthorReq	true "Create author"
+// @Success      201  {object}	AuthorRes
+// @Header       201  {string}	Location  "/authors/{id}"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /authors [post]
+func (h *AuthorHandler) CreateAuthor(c *gin.Context) {
+	var json CreateAuthorReq
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	created, err := h.authorService.CreateAuthor(c.Request.Context(), domain.Author{Name: json.Name})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/authors/%d", created.ID))
+	c.JSON(http.StatusCreated, newAuthorRes(created))
+}
+
+// GetAuthor godoc
+// @Summary      Get an author
+// @Description  Get an author
+// @Tags         authors
+// @Accept       json
+// @Produce      json
+// @Param        id  path  int  true  "Author ID"
+// @Success      200  {object}  AuthorRes
+// @Failure      400  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /authors/{id} [get]
+func (h *AuthorHandler) GetAuthor(c *gin.Context) {
+	type params struct {
+		ID int `uri:"id" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	author, err := h.authorService.GetAuthor(c.Request.Context(), p.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAuthorRes(author))
+}
+
+// GetAuthors godoc
+// @Summary      Get authors
+// @Description  Get authors. With PAGINATION_STYLE=envelope the array is wrapped in a util.PageRes.
+// @Tags         authors
+// @Accept       json
+// @Produce      json
+// @Param        page  query  int  false  "Page"
+// @Param        per_page  query  int  false  "Per Page"
+// @Success      200  {object}  []AuthorRes
+// @Header       200  {integer}  X-Total-Count  "Total number of items (headers style)"
+// @Header       200  {string}  Link  "RFC 8288 first, prev, next and last page links (headers style)"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /authors [get]
+func (h *AuthorHandler) GetAuthors(c *gin.Context) {
+	var query GetAuthorsReq
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	authors, total, err := h.authorService.GetAuthors(c.Request.Context(), query.Page, query.PerPage)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res := make([]AuthorRes, 0, len(authors))
+	for _, author := range authors {
+		res = append(res, newAuthorRes(author))
+	}
+	h.pages.Write(c, res, query.Page, query.PerPage, total)
+}
+
+// GetAuthorBooks godoc
+// @Summary      Get an author's books
+// @Description  Get the books linked to an author. With PAGINATION_STYLE=envelope the array is wrapped in a util.PageRes.
+// @Tags         authors
+// @Accept       json
+// @Produce      json
+// @Param        id  path  int  true  "Author ID"
+// @Param        page  query  int  false  "Page"
+// @Param        per_page  query  int  false  "Per Page"
+// @Success      200  {object}  []BookRes
+// @Header       200  {integer}  X-Total-Count  "Total number of items (headers style)"
+// @Header       200  {string}  Link  "RFC 8288 first, prev, next and last page links (headers style)"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /authors/{id}/books [get]
+func (h *AuthorHandler) GetAuthorBooks(c *gin.Context) {
+	type params struct {
+		ID int `uri:"id" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	var query PageReq
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	books, total, err := h.authorService.GetAuthorBooks(c.Request.Context(), p.ID, query.Page, query.PerPage)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res := make([]BookRes, 0, len(books))
+	for _, book := range books {
+		res = append(res, newBookRes(book))
+	}
+	h.pages.Write(c, res, query.Page, query.PerPage, total)
+}
+
+// UpdateAuthor godoc
+// @Summary      Update an author
+// @Description  Rename an author. The byline of every linked book is updated too.
+// @Tags         authors
+// @Accept       json
+// @Produce      json
+// @Param        id  path  int  true  "Author ID"
+// @Param        request  body  UpdateAuthorReq  true  "Update author"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /authors/{id} [put]
+func (h *AuthorHandler) UpdateAuthor(c *gin.Context) {
+	type params struct {
+		ID int `uri:"id" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	var json UpdateAuthorReq
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	err := h.authorService.UpdateAuthor(c.Request.Context(), domain.Author{
+		ID:   p.ID,
+		Name: json.Name,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteAuthor godoc
+// @Summary      Delete an author
+// @Description  Delete an author that is not linked to any book
+// @Tags         authors
+// @Accept       json
+// @Produce      json
+// @Param        id  path  int  true  "Author ID"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      409  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /authors/{id} [delete]
+func (h *AuthorHandler) DeleteAuthor(c *gin.Context) {
+	type params struct {
+		ID int `uri:"id" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	err := h.authorService.DeleteAuthor(c.Request.Context(), p.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
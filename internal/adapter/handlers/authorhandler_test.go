@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuthorHandler_CreateAuthor(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         interface{}
+		setup        func(*mocks.MockAuthorUseCase)
+		wantStatus   int
+		wantLocation string
+	}{
+		{
+			name: "success",
+			body: CreateAuthorReq{Name: "George Orwell"},
+			setup: func(m *mocks.MockAuthorUseCase) {
+				m.EXPECT().CreateAuthor(gomock.Any(), domain.Author{Name: "George Orwell"}).
+					Return(domain.Author{ID: 1, Name: "George Orwell"}, nil)
+			},
+			wantStatus:   http.StatusCreated,
+			wantLocation: "/authors/1",
+		},
+		{
+			name:       "validation error - missing name",
+			body:       CreateAuthorReq{},
+			setup:      func(m *mocks.MockAuthorUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "validation error - blank name",
+			body: CreateAuthorReq{Name: "  "},
+			setup: func(m *mocks.MockAuthorUseCase) {
+				m.EXPECT().CreateAuthor(gomock.Any(), gomock.Any()).Return(domain.Author{}, domain.ErrAuthorNameRequired)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service error",
+			body: CreateAuthorReq{Name: "George Orwell"},
+			setup: func(m *mocks.MockAuthorUseCase) {
+				m.EXPECT().CreateAuthor(gomock.Any(), gomock.Any()).Return(domain.Author{}, errors.New("service error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockAuthorUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewAuthorHandler(mockService, testPages)
+
+			r := setupTestRouter()
+			r.POST("/authors", h.CreateAuthor)
+
+			w := httptest.NewRecorder()
+			bodyBytes, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/authors", bytes.NewBuffer(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("CreateAuthor() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("CreateAuthor() Location = %v, want %v", got, tt.wantLocation)
+			}
+		})
+	}
+}
+
+func TestAuthorHandler_GetAuthorBooks(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		setup      func(*mocks.MockAuthorUseCase)
+		wantStatus int
+	}{
+		{
+			name: "success",
+			path: "/authors/1/books",
+			setup: func(m *mocks.MockAuthorUseCase) {
+				m.EXPECT().GetAuthorBooks(gomock.Any(), 1, 1, 10).
+					Return([]domain.Book{{ID: 1, Title: "1984", Author: "George Orwell"}}, 1, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid id",
+			path:       "/authors/invalid/books",
+			setup:      func(m *mocks.MockAuthorUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid pagination",
+			path:       "/authors/1/books?per_page=1000",
+			setup:      func(m *mocks.MockAuthorUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "author not found",
+			path: "/authors/999/books",
+			setup: func(m *mocks.MockAuthorUseCase) {
+				m.EXPECT().GetAuthorBooks(gomock.Any(), 999, 1, 10).Return(nil, 0, domain.ErrAuthorNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockAuthorUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewAuthorHandler(mockService, testPages)
+
+			r := setupTestRouter()
+			r.GET("/authors/:id/books", h.GetAuthorBooks)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetAuthorBooks() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAuthorHandler_UpdateAuthor(t *testing.T) {
+	tests := []struct {
+		name       string
+		authorID   string
+		body       interface{}
+		setup      func(*mocks.MockAuthorUseCase)
+		wantStatus int
+	}{
+		{
+			name:     "success",
+			authorID: "1",
+			body:     UpdateAuthorReq{Name: "Eric Blair"},
+			setup: func(m *mocks.MockAuthorUseCase) {
+				m.EXPECT().UpdateAuthor(gomock.Any(), domain.Author{ID: 1, Name: "Eric Blair"}).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "validation error - missing name",
+			authorID:   "1",
+			body:       UpdateAuthorReq{},
+			setup:      func(m *mocks.MockAuthorUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:     "author not found",
+			authorID: "999",
+			body:     UpdateAuthorReq{Name: "Eric Blair"},
+			setup: func(m *mocks.MockAuthorUseCase) {
+				m.EXPECT().UpdateAuthor(gomock.Any(), gomock.Any()).Return(domain.ErrAuthorNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockAuthorUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewAuthorHandler(mockService, testPages)
+
+			r := setupTestRouter()
+			r.PUT("/authors/:id", h.UpdateAuthor)
+
+			w := httptest.NewRecorder()
+			bodyBytes, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPut, "/authors/"+tt.authorID, bytes.NewBuffer(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("UpdateAuthor() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAuthorHandler_DeleteAuthor(t *testing.T) {
+	tests := []struct {
+		name       string
+		authorID   string
+		setup      func(*mocks.MockAuthorUseCase)
+		wantStatus int
+	}{
+		{
+			name:     "success",
+			authorID: "1",
+			setup: func(m *mocks.MockAuthorUseCase) {
+				m.EXPECT().DeleteAuthor(gomock.Any(), 1).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:     "author not found",
+			authorID: "999",
+			setup: func(m *mocks.MockAuthorUseCase) {
+				m.EXPECT().DeleteAuthor(gomock.Any(), 999).Return(domain.ErrAuthorNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:     "author still has books",
+			authorID: "1",
+			setup: func(m *mocks.MockAuthorUseCase) {
+				m.EXPECT().DeleteAuthor(gomock.Any(), 1).Return(domain.ErrAuthorHasBooks)
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockAuthorUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewAuthorHandler(mockService, testPages)
+
+			r := setupTestRouter()
+			r.DELETE("/authors/:id", h.DeleteAuthor)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, "/authors/"+tt.authorID, nil)
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("DeleteAuthor() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestBookHandler_ExportBooks(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	books := []domain.Book{
+		{ID: 1, Title: "Animal Farm", Author: "George Orwell", Authors: []domain.Author{{ID: 3, Name: "George Orwell"}}, ISBN: "9780451526342", Version: 1, CreatedAt: created, UpdatedAt: created},
+		{ID: 2, Title: "Good Omens", Author: "Terry Pratchett, Neil Gaiman", Authors: []domain.Author{{ID: 4, Name: "Terry Pratchett"}, {ID: 5, Name: "Neil Gaiman"}}, Version: 2, CreatedAt: created, UpdatedAt: created},
+	}
+	export := func(chunks ...[]domain.Book) func(context.Context, domain.BookCriteria, func([]domain.Book) error) error {
+		return func(_ context.Context, _ domain.BookCriteria, fn func([]domain.Book) error) error {
+			for _, chunk := range chunks {
+				if err := fn(chunk); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	tests := []struct {
+		name            string
+		query           string
+		setup           func(*mocks.MockBookUseCase)
+		wantStatus      int
+		wantContentType string
+		wantFilename    string
+		wantBody        string
+	}{
+		{
+			name:  "csv by default",
+			query: "",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().ExportBooks(gomock.Any(), domain.BookCriteria{}, gomock.Any()).
+					DoAndReturn(export(books[:1], books[1:]))
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantFilename:    `books-\d{8}-\d{6}\.csv`,
+			wantBody: "id,title,author,author_ids,isbn,version,created_at,updated_at\n" +
+				"1,Animal Farm,George Orwell,3,9780451526342,1,2025-01-01T00:00:00Z,2025-01-01T00:00:00Z\n" +
+				"2,Good Omens,\"Terry Pratchett, Neil Gaiman\",4;5,,2,2025-01-01T00:00:00Z,2025-01-01T00:00:00Z\n",
+		},
+		{
+			name:  "csv with no books has a header",
+			query: "?format=csv",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().ExportBooks(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(export())
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantFilename:    `books-\d{8}-\d{6}\.csv`,
+			wantBody:        "id,title,author,author_ids,isbn,version,created_at,updated_at\n",
+		},
+		{
+			name:  "ndjson with filters and sort",
+			query: "?format=ndjson&author=George+Orwell&title_contains=farm&sort=-title",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().ExportBooks(gomock.Any(), domain.BookCriteria{
+					Author:        "George Orwell",
+					TitleContains: "farm",
+					Sort:          domain.BookSort{{Field: "title", Desc: true}},
+				}, gomock.Any()).DoAndReturn(export(books[:1]))
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantFilename:    `books-\d{8}-\d{6}\.ndjson`,
+			wantBody:        `{"id":1,"title":"Animal Farm","author":"George Orwell","authors":[{"id":3,"name":"George Orwell"}],"isbn":"9780451526342","version":1,"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}` + "\n",
+		},
+		{
+			name:  "json array across chunks",
+			query: "?format=json",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().ExportBooks(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(export([]domain.Book{{ID: 1, CreatedAt: created, UpdatedAt: created}}, []domain.Book{{ID: 2, CreatedAt: created, UpdatedAt: created}}))
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json; charset=utf-8",
+			wantFilename:    `books-\d{8}-\d{6}\.json`,
+			wantBody: `[{"id":1,"title":"","author":"","authors":[],"version":0,"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"},` +
+				`{"id":2,"title":"","author":"","authors":[],"version":0,"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}]`,
+		},
+		{
+			name:  "json with no books",
+			query: "?format=json",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().ExportBooks(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(export())
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json; charset=utf-8",
+			wantFilename:    `books-\d{8}-\d{6}\.json`,
+			wantBody:        "[]",
+		},
+		{
+			name:       "unknown format",
+			query:      "?format=xml",
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unsortable field",
+			query:      "?sort=isbn",
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "error before the first books",
+			query: "?format=json",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().ExportBooks(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("service error"))
+			},
+			wantStatus:      http.StatusInternalServerError,
+			wantContentType: "application/json; charset=utf-8",
+		},
+		{
+			name:  "error after the first books truncates",
+			query: "?format=json",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().ExportBooks(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ domain.BookCriteria, fn func([]domain.Book) error) error {
+						if err := fn([]domain.Book{{ID: 1, CreatedAt: created, UpdatedAt: created}}); err != nil {
+							return err
+						}
+						return errors.New("connection lost")
+					})
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json; charset=utf-8",
+			wantFilename:    `books-\d{8}-\d{6}\.json`,
+			wantBody:        `[{"id":1,"title":"","author":"","authors":[],"version":0,"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockBookUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewBookHandler(mockService, mocks.NewMockBookAsOfUseCase(ctrl), testCursors, testPages)
+
+			r := setupTestRouter()
+			r.GET("/books/export", h.ExportBooks)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/books/export"+tt.query, nil)
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("ExportBooks() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantContentType != "" && w.Header().Get("Content-Type") != tt.wantContentType {
+				t.Errorf("ExportBooks() Content-Type = %q, want %q", w.Header().Get("Content-Type"), tt.wantContentType)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			disposition := regexp.MustCompile(`^attachment; filename="` + tt.wantFilename + `"$`)
+			if got := w.Header().Get("Content-Disposition"); !disposition.MatchString(got) {
+				t.Errorf("ExportBooks() Content-Disposition = %q, want %v", got, disposition)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("ExportBooks() body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
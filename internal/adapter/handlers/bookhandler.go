@@ -1,79 +1,380 @@
 package handlers
 
 import (
+	"cmp"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"go-api-boilerplate/internal/application/port/in"
 	"go-api-boilerplate/internal/constant"
 	"go-api-boilerplate/internal/domain"
 	"go-api-boilerplate/internal/http/util"
+	"maps"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
 type (
 	CreateBookReq struct {
-		Title  string `json:"title" binding:"required" example:"The Great Gatsby"`
-		Author string `json:"author" binding:"required" example:"John Doe"`
+		Title     string `json:"title" binding:"required" example:"The Great Gatsby"`
+		Author    string `json:"author" binding:"required_without=AuthorIDs,excluded_with=AuthorIDs" example:"John Doe"`
+		AuthorIDs []int  `json:"author_ids" binding:"omitempty,dive,min=1" example:"1,2"`
+		ISBN      string `json:"isbn" example:"978-0-7432-7356-5"`
 	}
-	GetBooksReq struct {
+	CreateBooksReq struct {
+		Mode string `form:"mode,default=all_or_nothing" binding:"oneof=all_or_nothing best_effort" example:"best_effort"`
+	}
+	PageReq struct {
 		Page    int `form:"page,default=1" binding:"min=1" example:"1"`
 		PerPage int `form:"per_page,default=10" binding:"min=1,max=100" example:"10"`
 	}
+	// BookFilterReq holds the filters and sort shared by the book list and
+	// export. Export jobs keep it as JSON.
+	BookFilterReq struct {
+		Author        string    `json:"author,omitempty" form:"author" example:"George Orwell"`
+		TitleContains string    `json:"title_contains,omitempty" form:"title_contains" example:"gatsby"`
+		CreatedAfter  time.Time `json:"created_after,omitzero" form:"created_after" example:"2025-01-01T00:00:00Z"`
+		CreatedBefore time.Time `json:"created_before,omitzero" form:"created_before" example:"2026-01-01T00:00:00Z"`
+		Sort          string    `json:"sort,omitempty" form:"sort" example:"-created_at,title"`
+	}
+	GetBooksReq struct {
+		Page    int       `form:"page,default=1" binding:"min=1" example:"1"`
+		PerPage int       `form:"per_page,default=10" binding:"min=1,max=100" example:"10"`
+		Cursor  string    `form:"cursor"`
+		Limit   int       `form:"limit" binding:"omitempty,min=1,max=100" example:"10"`
+		AsOf    time.Time `form:"as_of" example:"2026-01-01T00:00:00Z"`
+		BookFilterReq
+	}
+	// AsOfReq asks for a read of the catalog as it stood at AsOf.
+	AsOfReq struct {
+		AsOf time.Time `form:"as_of" example:"2026-01-01T00:00:00Z"`
+	}
 	UpdateBookReq struct {
-		Title  string `json:"title" binding:"required" example:"The Great Gatsby"`
-		Author string `json:"author" binding:"required" example:"John Doe"`
+		Title     string `json:"title" binding:"required" example:"The Great Gatsby"`
+		Author    string `json:"author" binding:"required_without=AuthorIDs,excluded_with=AuthorIDs" example:"John Doe"`
+		AuthorIDs []int  `json:"author_ids" binding:"omitempty,dive,min=1" example:"1,2"`
+		ISBN      string `json:"isbn" example:"978-0-7432-7356-5"`
+	}
+	// PatchBookReq documents the JSON Merge Patch (RFC 7396) accepted by
+	// PatchBook: absent fields are left unchanged and null clears a field.
+	PatchBookReq struct {
+		Title     *string `json:"title" example:"The Great Gatsby"`
+		Author    *string `json:"author" example:"John Doe"`
+		AuthorIDs *[]int  `json:"author_ids" example:"1,2"`
+		ISBN      *string `json:"isbn" example:"978-0-7432-7356-5"`
+	}
+	BookRes struct {
+		ID        int             `json:"id" example:"1"`
+		Title     string          `json:"title" example:"The Great Gatsby"`
+		Author    string          `json:"author" example:"John Doe"`
+		Authors   []BookAuthorRes `json:"authors"`
+		ISBN      string          `json:"isbn,omitempty" example:"9780743273565"`
+		Version   int             `json:"version" example:"1"`
+		CreatedAt string          `json:"created_at" example:"2025-01-01T00:00:00Z"`
+		UpdatedAt string          `json:"updated_at" example:"2025-01-01T00:00:00Z"`
+		DeletedAt *string         `json:"deleted_at,omitempty" example:"2025-01-02T00:00:00Z"`
+	}
+	// BookPageRes is a page of books listed by cursor. A null cursor means
+	// there is no page in that direction.
+	BookPageRes struct {
+		Data       []BookRes `json:"data"`
+		NextCursor *string   `json:"next_cursor" example:"eyJzIjoiaWQiLCJrIjpbIjEwIl19.3q2-7w"`
+		PrevCursor *string   `json:"prev_cursor" example:"eyJzIjoiaWQiLCJrIjpbIjEiXSwiYiI6dHJ1ZX0.vu8rzQ"`
+	}
+	BookAuthorRes struct {
+		ID   int    `json:"id" example:"1"`
+		Name string `json:"name" example:"John Doe"`
+	}
+	// BatchRes reports every book of a batch create in request order.
+	BatchRes struct {
+		Mode    string         `json:"mode" example:"best_effort"`
+		Created int            `json:"created" example:"1"`
+		Failed  int            `json:"failed" example:"1"`
+		Results []BatchItemRes `json:"results"`
+	}
+	// BatchItemRes is the outcome of the book at Index in the request. A
+	// rolled back book was valid but not kept because another book of an
+	// all-or-nothing batch failed.
+	BatchItemRes struct {
+		Index  int             `json:"index" example:"0"`
+		Status string          `json:"status" example:"created" enums:"created,failed,rolled_back"`
+		ID     int             `json:"id,omitempty" example:"1"`
+		Error  *util.HTTPError `json:"error,omitempty"`
 	}
 )
 
+// maxBatchSize caps the number of books in one batch create.
+const maxBatchSize = 1000
+
+func newBookRes(book domain.Book) BookRes {
+	authors := make([]BookAuthorRes, 0, len(book.Authors))
+	for _, a := range book.Authors {
+		authors = append(authors, BookAuthorRes{ID: a.ID, Name: a.Name})
+	}
+	res := BookRes{
+		ID:        book.ID,
+		Title:     book.Title,
+		Author:    book.Author,
+		Authors:   authors,
+		ISBN:      book.ISBN,
+		Version:   book.Version,
+		CreatedAt: book.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: book.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if book.DeletedAt != nil {
+		deletedAt := book.DeletedAt.UTC().Format(time.RFC3339)
+		res.DeletedAt = &deletedAt
+	}
+	return res
+}
+
+func newBatchRes(mode domain.BatchMode, results []domain.BatchResult) BatchRes {
+	res := BatchRes{Mode: string(mode), Results: make([]BatchItemRes, len(results))}
+	for i, r := range results {
+		item := BatchItemRes{Index: i}
+		switch {
+		case r.Err != nil:
+			item.Status = "failed"
+			item.Error = itemError(r.Err)
+			res.Failed++
+		case r.Book.ID != 0:
+			item.Status = "created"
+			item.ID = r.Book.ID
+			res.Created++
+		default:
+			item.Status = "rolled_back"
+		}
+		res.Results[i] = item
+	}
+	return res
+}
+
+// criteria returns the filters of the request with the given sort.
+func (q BookFilterReq) criteria(sort domain.BookSort) domain.BookCriteria {
+	return domain.BookCriteria{
+		Author:        q.Author,
+		TitleContains: q.TitleContains,
+		CreatedAfter:  q.CreatedAfter,
+		CreatedBefore: q.CreatedBefore,
+		Sort:          sort,
+	}
+}
+
+// newBookAuthors turns the requested author IDs into unresolved authors.
+func newBookAuthors(ids []int) []domain.Author {
+	if len(ids) == 0 {
+		return nil
+	}
+	authors := make([]domain.Author, len(ids))
+	for i, id := range ids {
+		authors[i] = domain.Author{ID: id}
+	}
+	return authors
+}
+
+const mergePatchContentType = "application/merge-patch+json"
+
+// decodeBookPatch reads a merge patch document into a domain.BookPatch.
+func decodeBookPatch(body []byte) (domain.BookPatch, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return domain.BookPatch{}, err
+	}
+	if fields == nil {
+		return domain.BookPatch{}, errors.New("merge patch must be a JSON object")
+	}
+
+	var req PatchBookReq
+	keys := slices.Sorted(maps.Keys(fields))
+	for _, key := range keys {
+		var err error
+		switch key {
+		case "title":
+			err = json.Unmarshal(fields[key], &req.Title)
+		case "author":
+			err = json.Unmarshal(fields[key], &req.Author)
+		case "author_ids":
+			err = json.Unmarshal(fields[key], &req.AuthorIDs)
+		case "isbn":
+			err = json.Unmarshal(fields[key], &req.ISBN)
+		default:
+			return domain.BookPatch{}, util.InvalidField(key, "unknown", fmt.Sprintf("%s cannot be patched", key))
+		}
+		if err != nil {
+			return domain.BookPatch{}, util.InvalidField(key, "type", fmt.Sprintf("%s has the wrong type", key))
+		}
+	}
+
+	// null removes a field, which leaves it empty
+	var patch domain.BookPatch
+	if _, ok := fields["title"]; ok {
+		patch.Title = cmp.Or(req.Title, new(string))
+	}
+	if _, ok := fields["author"]; ok {
+		patch.Author = cmp.Or(req.Author, new(string))
+	}
+	if _, ok := fields["isbn"]; ok {
+		patch.ISBN = cmp.Or(req.ISBN, new(string))
+	}
+	if req.AuthorIDs != nil {
+		if req.Author != nil {
+			return domain.BookPatch{}, util.InvalidField("author_ids", "excluded_with", "author_ids cannot be set together with author")
+		}
+		patch.Authors = make([]domain.Author, 0, len(*req.AuthorIDs))
+		for _, id := range *req.AuthorIDs {
+			if id < 1 {
+				return domain.BookPatch{}, util.InvalidField("author_ids", "min", "author_ids must only contain ids of at least 1")
+			}
+			patch.Authors = append(patch.Authors, domain.Author{ID: id})
+		}
+	} else if _, ok := fields["author_ids"]; ok && req.Author == nil {
+		patch.Authors = []domain.Author{}
+	}
+	return patch, nil
+}
+
 type BookHandler struct {
 	bookService in.BookUseCase
+	asOfService in.BookAsOfUseCase
+	cursors     *util.CursorCodec
+	pages       *util.Paginator
 }
 
-func NewBookHandler(bookService in.BookUseCase) *BookHandler {
-	return &BookHandler{bookService: bookService}
+func NewBookHandler(bookService in.BookUseCase, asOfService in.BookAsOfUseCase, cursors *util.CursorCodec, pages *util.Paginator) *BookHandler {
+	return &BookHandler{bookService: bookService, asOfService: asOfService, cursors: cursors, pages: pages}
 }
 
 // CreateBook godoc
 // @Summary      Create a book
-// @Description  Create a book
+// @Description  Create a book. Either author_ids (ordered) or a single author name is required; an unknown author name creates a new author.
 // @Tags         books
 // @Accept       json
 // @Produce      json
 // @Param        request  body		CreateBookReq	true "Create book"
-// @Success      204  {object}	nil
+// @Success      201  {object}	BookRes
+// @Header       201  {string}	Location  "/books/{id}"
+// @Header       201  {string}	ETag  "Current version of the book"
 // @Failure      400  {object}  util.HTTPError
+// @Failure      409  {object}  util.HTTPError
 // @Failure      500  {object}  util.HTTPError
 // @Router       /books [post]
 func (h *BookHandler) CreateBook(c *gin.Context) {
 	var json CreateBookReq
 	if err := c.ShouldBindJSON(&json); err != nil {
-		util.NewError(c, http.StatusBadRequest, constant.ErrValidationCode, err)
+		c.Error(util.BadRequest(err))
 		return
 	}
 
 	book := domain.Book{
-		Title:  json.Title,
-		Author: json.Author,
+		Title:   json.Title,
+		Author:  json.Author,
+		Authors: newBookAuthors(json.AuthorIDs),
+		ISBN:    json.ISBN,
 	}
 
-	err := h.bookService.CreateBook(c.Request.Context(), book)
+	created, err := h.bookService.CreateBook(c.Request.Context(), book)
 	if err != nil {
 		c.Error(err)
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	c.Header("Location", fmt.Sprintf("/books/%d", created.ID))
+	c.Header("ETag", util.ETag(created.Version))
+	c.JSON(http.StatusCreated, newBookRes(created))
+}
+
+// CreateBooks godoc
+// @Summary      Create books in a batch
+// @Description  Create up to 1000 books in one transaction. Each book is validated like POST /books. In all_or_nothing mode (the default) any failed book rolls back the whole batch; in best_effort mode the valid books are kept. The response reports every book by its index in the request: 201 when all were created, 207 when a best-effort batch has failures and 422 when an all-or-nothing batch was rolled back.
+// @Tags         books
+// @Accept       json
+// @Produce      json
+// @Param        mode  query  string  false  "Batch mode"  Enums(all_or_nothing, best_effort)
+// @Param        request  body  []CreateBookReq  true  "Books to create"
+// @Success      201  {object}  BatchRes
+// @Success      207  {object}  BatchRes
+// @Failure      400  {object}  util.HTTPError
+// @Failure      422  {object}  BatchRes
+// @Failure      500  {object}  util.HTTPError
+// @Router       /books:batch [post]
+func (h *BookHandler) CreateBooks(c *gin.Context) {
+	var query CreateBooksReq
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+	mode := domain.BatchMode(query.Mode)
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+	var items []CreateBookReq
+	if err := json.Unmarshal(body, &items); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+	if len(items) == 0 || len(items) > maxBatchSize {
+		c.Error(util.BadRequest(fmt.Errorf("a batch must hold between 1 and %d books", maxBatchSize)))
+		return
+	}
+
+	// Bind each book on its own so that a malformed one is reported at its
+	// index instead of failing the request.
+	results := make([]domain.BatchResult, len(items))
+	var books []domain.Book
+	var indexes []int
+	for i, item := range items {
+		if err := binding.Validator.ValidateStruct(&item); err != nil {
+			results[i].Err = util.BadRequest(err)
+			continue
+		}
+		books = append(books, domain.Book{
+			Title:   item.Title,
+			Author:  item.Author,
+			Authors: newBookAuthors(item.AuthorIDs),
+			ISBN:    item.ISBN,
+		})
+		indexes = append(indexes, i)
+	}
+
+	if len(books) > 0 && (mode == domain.BatchBestEffort || !domain.AnyFailed(results)) {
+		created, err := h.bookService.CreateBooks(c.Request.Context(), books, mode)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		for j, i := range indexes {
+			results[i] = created[j]
+		}
+	}
+
+	status := http.StatusCreated
+	switch {
+	case !domain.AnyFailed(results):
+	case mode == domain.BatchAllOrNothing:
+		status = http.StatusUnprocessableEntity
+	default:
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, newBatchRes(mode, results))
 }
 
 // GetBook godoc
 // @Summary      Get a book
-// @Description  Get a book
+// @Description  Get a book. With as_of the book is returned as it was at that time, without an ETag, and 404 when it did not exist yet or was in the trash; books deleted or purged since are still found.
 // @Tags         books
 // @Accept       json
 // @Produce      json
 // @Param        id  path  int  true  "Book ID"
-// @Success      200  {object}  domain.Book
+// @Param        as_of  query  string  false  "RFC 3339 time to read the book at"
+// @Success      200  {object}  BookRes
+// @Header       200  {string}  ETag  "Current version of the book"
 // @Failure      400  {object}  util.HTTPError
 // @Failure      404  {object}  util.HTTPError
 // @Failure      500  {object}  util.HTTPError
@@ -84,71 +385,192 @@ func (h *BookHandler) GetBook(c *gin.Context) {
 	}
 	var p params
 	if err := c.ShouldBindUri(&p); err != nil {
-		util.NewError(c, http.StatusBadRequest, constant.ErrValidationCode, err)
+		c.Error(util.BadRequest(err))
 		return
 	}
 
-	book, err := h.bookService.GetBook(c.Request.Context(), p.ID)
-	if err != nil {
-		if errors.Is(err, domain.ErrBookNotFound) {
-			util.NewError(c, http.StatusNotFound, constant.ErrNotFoundCode, domain.ErrBookNotFound)
+	var query AsOfReq
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+	if !query.AsOf.IsZero() {
+		// A past version cannot be written to, so it carries no ETag.
+		book, err := h.asOfService.GetBookAsOf(c.Request.Context(), p.ID, query.AsOf)
+		if err != nil {
+			c.Error(err)
 			return
 		}
+		c.JSON(http.StatusOK, newBookRes(book))
+		return
+	}
+
+	book, err := h.bookService.GetBook(c.Request.Context(), p.ID)
+	if err != nil {
 		c.Error(err)
 		return
 	}
 
-	res := struct {
-		ID     int    `json:"id"`
-		Title  string `json:"title"`
-		Author string `json:"author"`
-	}{
-		ID:     book.ID,
-		Title:  book.Title,
-		Author: book.Author,
+	c.Header("ETag", util.ETag(book.Version))
+	c.JSON(http.StatusOK, newBookRes(book))
+}
+
+// GetBookByISBN godoc
+// @Summary      Get a book by ISBN
+// @Description  Get a book by its ISBN-10 or ISBN-13, with or without hyphens
+// @Tags         books
+// @Accept       json
+// @Produce      json
+// @Param        isbn  path  string  true  "ISBN"
+// @Success      200  {object}  BookRes
+// @Header       200  {string}  ETag  "Current version of the book"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /books/isbn/{isbn} [get]
+func (h *BookHandler) GetBookByISBN(c *gin.Context) {
+	type params struct {
+		ISBN string `uri:"isbn" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	book, err := h.bookService.GetBookByISBN(c.Request.Context(), p.ISBN)
+	if err != nil {
+		c.Error(err)
+		return
 	}
-	c.JSON(http.StatusOK, res)
+
+	c.Header("ETag", util.ETag(book.Version))
+	c.JSON(http.StatusOK, newBookRes(book))
 }
 
 // GetBooks godoc
 // @Summary      Get books
-// @Description  Get books
+// @Description  Get books by page, optionally filtered and sorted. sort lists the fields to order by, each prefixed with - for descending order; books are ordered by ID when it is omitted. Passing cursor or limit switches to cursor pagination, which responds with a BookPageRes (data, next_cursor, prev_cursor) instead of an array; page and per_page cannot be combined with it. as_of lists the books that were live at that time, as they were then, including books deleted since; it cannot be combined with cursor pagination. With PAGINATION_STYLE=envelope the array is wrapped in a util.PageRes.
 // @Tags         books
 // @Accept       json
 // @Produce      json
 // @Param        page  query  int  false  "Page"
 // @Param        per_page  query  int  false  "Per Page"
-// @Success      200  {object}  []domain.Book
+// @Param        cursor  query  string  false  "Cursor from next_cursor or prev_cursor"
+// @Param        limit  query  int  false  "Books per page in cursor mode"
+// @Param        author  query  string  false  "Only books by the author with this name (case-insensitive)"
+// @Param        title_contains  query  string  false  "Only books whose title contains this text (case-insensitive)"
+// @Param        created_after  query  string  false  "Only books created after this RFC 3339 time"
+// @Param        created_before  query  string  false  "Only books created before this RFC 3339 time"
+// @Param        sort  query  string  false  "Comma-separated sort fields: id, title, author, created_at, updated_at"
+// @Param        as_of  query  string  false  "RFC 3339 time to list the books at"
+// @Success      200  {object}  []BookRes
+// @Header       200  {integer}  X-Total-Count  "Total number of items (headers style)"
+// @Header       200  {string}  Link  "RFC 8288 first, prev, next and last page links (headers style)"
 // @Failure      400  {object}  util.HTTPError
 // @Failure      500  {object}  util.HTTPError
 // @Router       /books [get]
 func (h *BookHandler) GetBooks(c *gin.Context) {
 	var query GetBooksReq
 	if err := c.ShouldBindQuery(&query); err != nil {
-		util.NewError(c, http.StatusBadRequest, constant.ErrValidationCode, err)
+		c.Error(util.BadRequest(err))
+		return
+	}
+	if query.Cursor != "" || query.Limit != 0 {
+		if !query.AsOf.IsZero() {
+			c.Error(util.InvalidField("as_of", "excluded_with", "as_of cannot be combined with cursor or limit"))
+			return
+		}
+		h.getBooksByCursor(c, query)
 		return
 	}
 
-	books, err := h.bookService.GetBooks(c.Request.Context(), query.Page, query.PerPage)
+	sort, err := domain.ParseBookSort(query.Sort)
 	if err != nil {
 		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, books)
+	var books []domain.Book
+	var total int
+	if query.AsOf.IsZero() {
+		books, total, err = h.bookService.GetBooks(c.Request.Context(), query.criteria(sort), query.Page, query.PerPage)
+	} else {
+		books, total, err = h.asOfService.GetBooksAsOf(c.Request.Context(), query.criteria(sort), query.AsOf, query.Page, query.PerPage)
+	}
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res := make([]BookRes, 0, len(books))
+	for _, book := range books {
+		res = append(res, newBookRes(book))
+	}
+	h.pages.Write(c, res, query.Page, query.PerPage, total)
+}
+
+func (h *BookHandler) getBooksByCursor(c *gin.Context, query GetBooksReq) {
+	for _, key := range []string{"page", "per_page"} {
+		if _, ok := c.GetQuery(key); ok {
+			c.Error(util.InvalidField(key, "excluded_with", key+" cannot be combined with cursor or limit"))
+			return
+		}
+	}
+
+	var cursor domain.Cursor
+	if query.Cursor != "" {
+		var err error
+		if cursor, err = h.cursors.Decode(query.Cursor); err != nil {
+			c.Error(err)
+			return
+		}
+	}
+
+	// Without sort the list keeps the sort the cursor was taken from.
+	sort, err := domain.ParseBookSort(cmp.Or(query.Sort, cursor.Sort))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	page, err := h.bookService.GetBooksByCursor(c.Request.Context(), query.criteria(sort), cursor, query.Limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res := BookPageRes{Data: make([]BookRes, 0, len(page.Books))}
+	for _, book := range page.Books {
+		res.Data = append(res.Data, newBookRes(book))
+	}
+	if page.Next != nil {
+		next := h.cursors.Encode(*page.Next)
+		res.NextCursor = &next
+	}
+	if page.Prev != nil {
+		prev := h.cursors.Encode(*page.Prev)
+		res.PrevCursor = &prev
+	}
+	c.JSON(http.StatusOK, res)
 }
 
 // UpdateBook godoc
 // @Summary      Update a book
-// @Description  Update a book
+// @Description  Update a book. Either author_ids (ordered) or a single author name is required; an unknown author name creates a new author.
 // @Tags         books
 // @Accept       json
 // @Produce      json
 // @Param        id  path  int  true  "Book ID"
+// @Param        If-Match  header  string  false  "ETag of the version being replaced"
 // @Param        request  body  UpdateBookReq  true  "Update book"
 // @Success      204  {object}  nil
+// @Header       204  {string}  ETag  "New version of the book"
 // @Failure      400  {object}  util.HTTPError
 // @Failure      404  {object}  util.HTTPError
+// @Failure      409  {object}  util.HTTPError
+// @Failure      412  {object}  util.HTTPError
+// @Failure      428  {object}  util.HTTPError
 // @Failure      500  {object}  util.HTTPError
 // @Router       /books/{id} [put]
 func (h *BookHandler) UpdateBook(c *gin.Context) {
@@ -157,42 +579,116 @@ func (h *BookHandler) UpdateBook(c *gin.Context) {
 	}
 	var p params
 	if err := c.ShouldBindUri(&p); err != nil {
-		util.NewError(c, http.StatusBadRequest, constant.ErrValidationCode, err)
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	version, ok := util.IfMatchVersion(c.GetHeader("If-Match"))
+	if !ok {
+		c.Error(domain.ErrVersionConflict)
 		return
 	}
 
 	var json UpdateBookReq
 	if err := c.ShouldBindJSON(&json); err != nil {
-		util.NewError(c, http.StatusBadRequest, constant.ErrValidationCode, err)
+		c.Error(util.BadRequest(err))
 		return
 	}
 
-	err := h.bookService.UpdateBook(c.Request.Context(), domain.Book{
-		ID:     p.ID,
-		Title:  json.Title,
-		Author: json.Author,
+	updated, err := h.bookService.UpdateBook(c.Request.Context(), domain.Book{
+		ID:      p.ID,
+		Title:   json.Title,
+		Author:  json.Author,
+		Authors: newBookAuthors(json.AuthorIDs),
+		ISBN:    json.ISBN,
+		Version: version,
 	})
 	if err != nil {
-		if errors.Is(err, domain.ErrBookNotFound) {
-			util.NewError(c, http.StatusNotFound, constant.ErrNotFoundCode, domain.ErrBookNotFound)
-			return
-		}
 		c.Error(err)
 		return
 	}
+	c.Header("ETag", util.ETag(updated.Version))
 	c.Status(http.StatusNoContent)
 }
 
+// PatchBook godoc
+// @Summary      Patch a book
+// @Description  Update only the fields present in a JSON Merge Patch (RFC 7396) document. null clears a field.
+// @Tags         books
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id  path  int  true  "Book ID"
+// @Param        If-Match  header  string  false  "ETag of the version being patched"
+// @Param        request  body  PatchBookReq  true  "Merge patch"
+// @Success      200  {object}  BookRes
+// @Header       200  {string}  ETag  "New version of the book"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      409  {object}  util.HTTPError
+// @Failure      412  {object}  util.HTTPError
+// @Failure      415  {object}  util.HTTPError
+// @Failure      428  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /books/{id} [patch]
+func (h *BookHandler) PatchBook(c *gin.Context) {
+	type params struct {
+		ID int `uri:"id" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	if c.ContentType() != mergePatchContentType {
+		c.Error(&util.RequestError{
+			Status: http.StatusUnsupportedMediaType,
+			Code:   constant.ErrUnsupportedMediaTypeCode,
+			Err:    fmt.Errorf("content type must be %s", mergePatchContentType),
+		})
+		return
+	}
+
+	version, ok := util.IfMatchVersion(c.GetHeader("If-Match"))
+	if !ok {
+		c.Error(domain.ErrVersionConflict)
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+	patch, err := decodeBookPatch(body)
+	if err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	book, err := h.bookService.PatchBook(c.Request.Context(), p.ID, version, patch)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", util.ETag(book.Version))
+	c.JSON(http.StatusOK, newBookRes(book))
+}
+
 // DeleteBook godoc
 // @Summary      Delete a book
-// @Description  Delete a book
+// @Description  Move a book to the trash. Trashed books are hidden from all other reads until restored.
 // @Tags         books
 // @Accept       json
 // @Produce      json
 // @Param        id  path  int  true  "Book ID"
+// @Param        If-Match  header  string  false  "ETag of the version being deleted"
 // @Success      204  {object}  nil
 // @Failure      400  {object}  util.HTTPError
 // @Failure      404  {object}  util.HTTPError
+// @Failure      412  {object}  util.HTTPError
+// @Failure      428  {object}  util.HTTPError
 // @Failure      500  {object}  util.HTTPError
 // @Router       /books/{id} [delete]
 func (h *BookHandler) DeleteBook(c *gin.Context) {
@@ -201,17 +697,120 @@ func (h *BookHandler) DeleteBook(c *gin.Context) {
 	}
 	var p params
 	if err := c.ShouldBindUri(&p); err != nil {
-		util.NewError(c, http.StatusBadRequest, constant.ErrValidationCode, err)
+		c.Error(util.BadRequest(err))
 		return
 	}
 
-	err := h.bookService.DeleteBook(c.Request.Context(), p.ID)
+	version, ok := util.IfMatchVersion(c.GetHeader("If-Match"))
+	if !ok {
+		c.Error(domain.ErrVersionConflict)
+		return
+	}
+
+	err := h.bookService.DeleteBook(c.Request.Context(), p.ID, version)
 	if err != nil {
-		if errors.Is(err, domain.ErrBookNotFound) {
-			util.NewError(c, http.StatusNotFound, constant.ErrNotFoundCode, domain.ErrBookNotFound)
-			return
-		}
-		util.NewError(c, http.StatusInternalServerError, constant.ErrInternalServerError, err)
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetTrash godoc
+// @Summary      Get trashed books
+// @Description  Get deleted books, most recently deleted first. With PAGINATION_STYLE=envelope the array is wrapped in a util.PageRes.
+// @Tags         books
+// @Accept       json
+// @Produce      json
+// @Param        page  query  int  false  "Page"
+// @Param        per_page  query  int  false  "Per Page"
+// @Success      200  {object}  []BookRes
+// @Header       200  {integer}  X-Total-Count  "Total number of items (headers style)"
+// @Header       200  {string}  Link  "RFC 8288 first, prev, next and last page links (headers style)"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /books/trash [get]
+func (h *BookHandler) GetTrash(c *gin.Context) {
+	var query PageReq
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	books, total, err := h.bookService.GetTrash(c.Request.Context(), query.Page, query.PerPage)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res := make([]BookRes, 0, len(books))
+	for _, book := range books {
+		res = append(res, newBookRes(book))
+	}
+	h.pages.Write(c, res, query.Page, query.PerPage, total)
+}
+
+// RestoreBook godoc
+// @Summary      Restore a book
+// @Description  Move a book out of the trash
+// @Tags         books
+// @Accept       json
+// @Produce      json
+// @Param        id  path  int  true  "Book ID"
+// @Success      200  {object}  BookRes
+// @Header       200  {string}  ETag  "Current version of the book"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      409  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /books/{id}/restore [post]
+func (h *BookHandler) RestoreBook(c *gin.Context) {
+	type params struct {
+		ID int `uri:"id" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	book, err := h.bookService.RestoreBook(c.Request.Context(), p.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", util.ETag(book.Version))
+	c.JSON(http.StatusOK, newBookRes(book))
+}
+
+// PurgeBook godoc
+// @Summary      Purge a book
+// @Description  Permanently delete a book from the trash. Requires the admin token.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id  path  int  true  "Book ID"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  util.HTTPError
+// @Failure      401  {object}  util.HTTPError
+// @Failure      403  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /admin/books/{id} [delete]
+func (h *BookHandler) PurgeBook(c *gin.Context) {
+	type params struct {
+		ID int `uri:"id" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	err := h.bookService.PurgeBook(c.Request.Context(), p.ID)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 	c.Status(http.StatusNoContent)
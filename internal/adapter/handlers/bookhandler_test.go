@@ -2,14 +2,18 @@ package handlers
 
 import (
 	"bytes"
+	"cmp"
 	"encoding/json"
 	"errors"
 	"go-api-boilerplate/internal/domain"
 	"go-api-boilerplate/internal/http/middlewares"
+	"go-api-boilerplate/internal/http/util"
 	"go-api-boilerplate/mocks"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/mock/gomock"
@@ -19,26 +23,34 @@ func init() {
 	gin.SetMode(gin.TestMode)
 }
 
+var (
+	testCursors = util.NewCursorCodec("test-secret")
+	testPages   = util.NewPaginator(util.PaginationHeaders)
+)
+
 func setupTestRouter() *gin.Engine {
 	r := gin.New()
-	r.Use(middlewares.ErrorHandler())
+	r.Use(middlewares.ErrorHandler(ErrorRegistry()))
 	return r
 }
 
 func TestBookHandler_CreateBook(t *testing.T) {
 	tests := []struct {
-		name       string
-		body       interface{}
-		setup      func(*mocks.MockBookUseCase)
-		wantStatus int
+		name         string
+		body         interface{}
+		setup        func(*mocks.MockBookUseCase)
+		wantStatus   int
+		wantLocation string
 	}{
 		{
 			name: "success",
 			body: CreateBookReq{Title: "Test Book", Author: "Test Author"},
 			setup: func(m *mocks.MockBookUseCase) {
-				m.EXPECT().CreateBook(gomock.Any(), domain.Book{Title: "Test Book", Author: "Test Author"}).Return(nil)
+				m.EXPECT().CreateBook(gomock.Any(), domain.Book{Title: "Test Book", Author: "Test Author"}).
+					Return(domain.Book{ID: 1, Title: "Test Book", Author: "Test Author"}, nil)
 			},
-			wantStatus: http.StatusNoContent,
+			wantStatus:   http.StatusCreated,
+			wantLocation: "/books/1",
 		},
 		{
 			name:       "invalid json",
@@ -52,11 +64,59 @@ func TestBookHandler_CreateBook(t *testing.T) {
 			setup:      func(m *mocks.MockBookUseCase) {},
 			wantStatus: http.StatusBadRequest,
 		},
+		{
+			name: "success - author ids",
+			body: CreateBookReq{Title: "Good Omens", AuthorIDs: []int{2, 1}},
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().CreateBook(gomock.Any(), domain.Book{Title: "Good Omens", Authors: []domain.Author{{ID: 2}, {ID: 1}}}).
+					Return(domain.Book{ID: 1, Title: "Good Omens", Author: "Neil Gaiman, Terry Pratchett"}, nil)
+			},
+			wantStatus:   http.StatusCreated,
+			wantLocation: "/books/1",
+		},
+		{
+			name:       "validation error - author and author ids",
+			body:       CreateBookReq{Title: "Good Omens", Author: "Neil Gaiman", AuthorIDs: []int{1}},
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "unknown author id",
+			body: CreateBookReq{Title: "Good Omens", AuthorIDs: []int{99}},
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(domain.Book{}, domain.ErrUnknownAuthor)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "domain validation error",
+			body: CreateBookReq{Title: " ", Author: "Test Author"},
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(domain.Book{}, domain.ErrTitleRequired)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid isbn",
+			body: CreateBookReq{Title: "Test Book", Author: "Test Author", ISBN: "123"},
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(domain.Book{}, domain.ErrInvalidISBN)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "duplicate isbn",
+			body: CreateBookReq{Title: "Test Book", Author: "Test Author", ISBN: "9780743273565"},
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(domain.Book{}, domain.ErrDuplicateISBN)
+			},
+			wantStatus: http.StatusConflict,
+		},
 		{
 			name: "service error",
 			body: CreateBookReq{Title: "Test Book", Author: "Test Author"},
 			setup: func(m *mocks.MockBookUseCase) {
-				m.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(errors.New("service error"))
+				m.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(domain.Book{}, errors.New("service error"))
 			},
 			wantStatus: http.StatusInternalServerError,
 		},
@@ -69,7 +129,7 @@ func TestBookHandler_CreateBook(t *testing.T) {
 			mockService := mocks.NewMockBookUseCase(ctrl)
 			tt.setup(mockService)
 
-			h := NewBookHandler(mockService)
+			h := NewBookHandler(mockService, mocks.NewMockBookAsOfUseCase(ctrl), testCursors, testPages)
 
 			r := setupTestRouter()
 			r.POST("/books", h.CreateBook)
@@ -84,6 +144,137 @@ func TestBookHandler_CreateBook(t *testing.T) {
 			if w.Code != tt.wantStatus {
 				t.Errorf("CreateBook() status = %v, want %v", w.Code, tt.wantStatus)
 			}
+			if got := w.Header().Get("Location"); got != tt.wantLocation {
+				t.Errorf("CreateBook() Location = %v, want %v", got, tt.wantLocation)
+			}
+		})
+	}
+}
+
+func TestBookHandler_CreateBooks(t *testing.T) {
+	farm := domain.Book{Title: "Animal Farm", Author: "George Orwell"}
+	tests := []struct {
+		name         string
+		query        string
+		body         string
+		setup        func(*mocks.MockBookUseCase)
+		wantStatus   int
+		wantStatuses []string
+	}{
+		{
+			name: "success",
+			body: `[{"title":"Animal Farm","author":"George Orwell"},{"title":"Good Omens","author_ids":[2,1]}]`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().CreateBooks(gomock.Any(), []domain.Book{farm, {Title: "Good Omens", Authors: []domain.Author{{ID: 2}, {ID: 1}}}}, domain.BatchAllOrNothing).
+					Return([]domain.BatchResult{{Book: domain.Book{ID: 1}}, {Book: domain.Book{ID: 2}}}, nil)
+			},
+			wantStatus:   http.StatusCreated,
+			wantStatuses: []string{"created", "created"},
+		},
+		{
+			name:         "all or nothing - malformed book skips the service",
+			body:         `[{"title":"Animal Farm","author":"George Orwell"},{"author":"George Orwell"}]`,
+			setup:        func(m *mocks.MockBookUseCase) {},
+			wantStatus:   http.StatusUnprocessableEntity,
+			wantStatuses: []string{"rolled_back", "failed"},
+		},
+		{
+			name: "all or nothing - rolled back by the service",
+			body: `[{"title":"Animal Farm","author":"George Orwell"},{"title":"1984","author":"George Orwell","isbn":"9780743273565"}]`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().CreateBooks(gomock.Any(), gomock.Any(), domain.BatchAllOrNothing).
+					Return([]domain.BatchResult{{}, {Err: domain.ErrDuplicateISBN}}, nil)
+			},
+			wantStatus:   http.StatusUnprocessableEntity,
+			wantStatuses: []string{"rolled_back", "failed"},
+		},
+		{
+			name:  "best effort - valid books sent to the service",
+			query: "?mode=best_effort",
+			body:  `[{"author":"George Orwell"},{"title":"Animal Farm","author":"George Orwell"}]`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().CreateBooks(gomock.Any(), []domain.Book{farm}, domain.BatchBestEffort).
+					Return([]domain.BatchResult{{Book: domain.Book{ID: 1}}}, nil)
+			},
+			wantStatus:   http.StatusMultiStatus,
+			wantStatuses: []string{"failed", "created"},
+		},
+		{
+			name:       "invalid mode",
+			query:      "?mode=some",
+			body:       `[{"title":"Animal Farm","author":"George Orwell"}]`,
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not an array",
+			body:       `{"title":"Animal Farm","author":"George Orwell"}`,
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "empty batch",
+			body:       `[]`,
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "too many books",
+			body:       "[" + strings.Repeat(`{"title":"t","author":"a"},`, maxBatchSize) + `{"title":"t","author":"a"}]`,
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "service error",
+			body: `[{"title":"Animal Farm","author":"George Orwell"}]`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().CreateBooks(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("service error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockBookUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewBookHandler(mockService, mocks.NewMockBookAsOfUseCase(ctrl), testCursors, testPages)
+
+			r := setupTestRouter()
+			r.POST("/books:batch", h.CreateBooks)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/books:batch"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("CreateBooks() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatuses == nil {
+				return
+			}
+			var res BatchRes
+			if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			var statuses []string
+			for i, item := range res.Results {
+				if item.Index != i {
+					t.Errorf("CreateBooks() result %d has index %d", i, item.Index)
+				}
+				if (item.Status == "failed") != (item.Error != nil) {
+					t.Errorf("CreateBooks() result %d = %+v, want an error only when failed", i, item)
+				}
+				statuses = append(statuses, item.Status)
+			}
+			if strings.Join(statuses, ",") != strings.Join(tt.wantStatuses, ",") {
+				t.Errorf("CreateBooks() statuses = %v, want %v", statuses, tt.wantStatuses)
+			}
 		})
 	}
 }
@@ -134,7 +325,7 @@ func TestBookHandler_GetBook(t *testing.T) {
 			mockService := mocks.NewMockBookUseCase(ctrl)
 			tt.setup(mockService)
 
-			h := NewBookHandler(mockService)
+			h := NewBookHandler(mockService, mocks.NewMockBookAsOfUseCase(ctrl), testCursors, testPages)
 
 			r := setupTestRouter()
 			r.GET("/books/:id", h.GetBook)
@@ -151,6 +342,72 @@ func TestBookHandler_GetBook(t *testing.T) {
 	}
 }
 
+func TestBookHandler_GetBookByISBN(t *testing.T) {
+	tests := []struct {
+		name       string
+		isbn       string
+		setup      func(*mocks.MockBookUseCase)
+		wantStatus int
+	}{
+		{
+			name: "success",
+			isbn: "978-0-7432-7356-5",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().GetBookByISBN(gomock.Any(), "978-0-7432-7356-5").
+					Return(domain.Book{ID: 1, Title: "Test Book", Author: "Test Author", ISBN: "9780743273565"}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "invalid isbn",
+			isbn: "123",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().GetBookByISBN(gomock.Any(), "123").Return(domain.Book{}, domain.ErrInvalidISBN)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "book not found",
+			isbn: "9780743273565",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().GetBookByISBN(gomock.Any(), "9780743273565").Return(domain.Book{}, domain.ErrBookNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "service error",
+			isbn: "9780743273565",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().GetBookByISBN(gomock.Any(), "9780743273565").Return(domain.Book{}, errors.New("service error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockBookUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewBookHandler(mockService, mocks.NewMockBookAsOfUseCase(ctrl), testCursors, testPages)
+
+			r := setupTestRouter()
+			r.GET("/books/isbn/:isbn", h.GetBookByISBN)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/books/isbn/"+tt.isbn, nil)
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetBookByISBN() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
 func TestBookHandler_GetBooks(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -162,9 +419,9 @@ func TestBookHandler_GetBooks(t *testing.T) {
 			name:  "success with defaults",
 			query: "",
 			setup: func(m *mocks.MockBookUseCase) {
-				m.EXPECT().GetBooks(gomock.Any(), 1, 10).Return([]domain.Book{
+				m.EXPECT().GetBooks(gomock.Any(), domain.BookCriteria{}, 1, 10).Return([]domain.Book{
 					{ID: 1, Title: "Book 1", Author: "Author 1"},
-				}, nil)
+				}, 1, nil)
 			},
 			wantStatus: http.StatusOK,
 		},
@@ -172,21 +429,95 @@ func TestBookHandler_GetBooks(t *testing.T) {
 			name:  "success with pagination",
 			query: "?page=2&per_page=20",
 			setup: func(m *mocks.MockBookUseCase) {
-				m.EXPECT().GetBooks(gomock.Any(), 2, 20).Return([]domain.Book{}, nil)
+				m.EXPECT().GetBooks(gomock.Any(), domain.BookCriteria{}, 2, 20).Return([]domain.Book{}, 0, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "success with filters and sort",
+			query: "?author=George+Orwell&title_contains=farm&created_after=2025-01-01T00:00:00Z&created_before=2026-01-01T00:00:00Z&sort=-created_at,title",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().GetBooks(gomock.Any(), domain.BookCriteria{
+					Author:        "George Orwell",
+					TitleContains: "farm",
+					CreatedAfter:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					CreatedBefore: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+					Sort:          domain.BookSort{{Field: "created_at", Desc: true}, {Field: "title"}},
+				}, 1, 10).Return([]domain.Book{}, 0, nil)
 			},
 			wantStatus: http.StatusOK,
 		},
+		{
+			name:       "unsortable field",
+			query:      "?sort=isbn",
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid created_after",
+			query:      "?created_after=yesterday",
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
 		{
 			name:       "invalid page",
 			query:      "?page=0",
 			setup:      func(m *mocks.MockBookUseCase) {},
 			wantStatus: http.StatusBadRequest,
 		},
+		{
+			name:  "cursor mode - first page",
+			query: "?limit=2",
+			setup: func(m *mocks.MockBookUseCase) {
+				next := domain.Cursor{Sort: domain.SortByID, Key: []string{"2"}}
+				m.EXPECT().GetBooksByCursor(gomock.Any(), domain.BookCriteria{}, domain.Cursor{}, 2).Return(domain.BookPage{
+					Books: []domain.Book{{ID: 1}, {ID: 2}},
+					Next:  &next,
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "cursor mode - with cursor",
+			query: "?cursor=" + testCursors.Encode(domain.Cursor{Sort: domain.SortByID, Key: []string{"2"}}),
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().GetBooksByCursor(gomock.Any(), domain.BookCriteria{Sort: domain.BookSort{{Field: "id"}}}, domain.Cursor{Sort: domain.SortByID, Key: []string{"2"}}, 0).
+					Return(domain.BookPage{Books: []domain.Book{}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:  "cursor mode - sorted",
+			query: "?limit=2&sort=-title&author=Orwell",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().GetBooksByCursor(gomock.Any(), domain.BookCriteria{Author: "Orwell", Sort: domain.BookSort{{Field: "title", Desc: true}}}, domain.Cursor{}, 2).
+					Return(domain.BookPage{Books: []domain.Book{}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "cursor mode - tampered cursor",
+			query:      "?cursor=" + util.NewCursorCodec("other-secret").Encode(domain.Cursor{Sort: domain.SortByID, Key: []string{"2"}}),
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "cursor mode - combined with page",
+			query:      "?limit=2&page=2",
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "cursor mode - limit too large",
+			query:      "?limit=500",
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
 		{
 			name:  "service error",
 			query: "",
 			setup: func(m *mocks.MockBookUseCase) {
-				m.EXPECT().GetBooks(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("service error"))
+				m.EXPECT().GetBooks(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, 0, errors.New("service error"))
 			},
 			wantStatus: http.StatusInternalServerError,
 		},
@@ -199,7 +530,7 @@ func TestBookHandler_GetBooks(t *testing.T) {
 			mockService := mocks.NewMockBookUseCase(ctrl)
 			tt.setup(mockService)
 
-			h := NewBookHandler(mockService)
+			h := NewBookHandler(mockService, mocks.NewMockBookAsOfUseCase(ctrl), testCursors, testPages)
 
 			r := setupTestRouter()
 			r.GET("/books", h.GetBooks)
@@ -216,22 +547,133 @@ func TestBookHandler_GetBooks(t *testing.T) {
 	}
 }
 
+func TestBookHandler_AsOf(t *testing.T) {
+	asOf := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		url        string
+		setup      func(*mocks.MockBookAsOfUseCase)
+		wantStatus int
+	}{
+		{
+			name: "book",
+			url:  "/books/1?as_of=2026-01-01T00:00:00Z",
+			setup: func(m *mocks.MockBookAsOfUseCase) {
+				m.EXPECT().GetBookAsOf(gomock.Any(), 1, asOf).Return(domain.Book{ID: 1, Title: "Test Book", Version: 2}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "book not there yet",
+			url:  "/books/1?as_of=2026-01-01T00:00:00Z",
+			setup: func(m *mocks.MockBookAsOfUseCase) {
+				m.EXPECT().GetBookAsOf(gomock.Any(), 1, asOf).Return(domain.Book{}, domain.ErrBookNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "invalid as_of",
+			url:        "/books/1?as_of=yesterday",
+			setup:      func(m *mocks.MockBookAsOfUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "books with filters",
+			url:  "/books?as_of=2026-01-01T00:00:00Z&author=George+Orwell&sort=title&page=2&per_page=5",
+			setup: func(m *mocks.MockBookAsOfUseCase) {
+				m.EXPECT().GetBooksAsOf(gomock.Any(), domain.BookCriteria{
+					Author: "George Orwell",
+					Sort:   domain.BookSort{{Field: "title"}},
+				}, asOf, 2, 5).Return([]domain.Book{{ID: 1, Title: "Animal Farm"}}, 6, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "books by cursor",
+			url:        "/books?as_of=2026-01-01T00:00:00Z&limit=5",
+			setup:      func(m *mocks.MockBookAsOfUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAsOf := mocks.NewMockBookAsOfUseCase(ctrl)
+			tt.setup(mockAsOf)
+
+			h := NewBookHandler(mocks.NewMockBookUseCase(ctrl), mockAsOf, testCursors, testPages)
+
+			r := setupTestRouter()
+			r.GET("/books", h.GetBooks)
+			r.GET("/books/:id", h.GetBook)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if etag := w.Header().Get("ETag"); etag != "" {
+				t.Errorf("ETag = %q, want none for a past version", etag)
+			}
+		})
+	}
+}
+
 func TestBookHandler_UpdateBook(t *testing.T) {
 	tests := []struct {
 		name       string
 		bookID     string
+		ifMatch    string
 		body       interface{}
 		setup      func(*mocks.MockBookUseCase)
 		wantStatus int
+		wantETag   string
 	}{
 		{
 			name:   "success",
 			bookID: "1",
 			body:   UpdateBookReq{Title: "Updated Book", Author: "Updated Author"},
 			setup: func(m *mocks.MockBookUseCase) {
-				m.EXPECT().UpdateBook(gomock.Any(), domain.Book{ID: 1, Title: "Updated Book", Author: "Updated Author"}).Return(nil)
+				m.EXPECT().UpdateBook(gomock.Any(), domain.Book{ID: 1, Title: "Updated Book", Author: "Updated Author"}).
+					Return(domain.Book{ID: 1, Title: "Updated Book", Author: "Updated Author", Version: 2}, nil)
+			},
+			wantStatus: http.StatusNoContent,
+			wantETag:   `"2"`,
+		},
+		{
+			name:    "success - matching version",
+			bookID:  "1",
+			ifMatch: `"1"`,
+			body:    UpdateBookReq{Title: "Updated Book", Author: "Updated Author"},
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().UpdateBook(gomock.Any(), domain.Book{ID: 1, Title: "Updated Book", Author: "Updated Author", Version: 1}).
+					Return(domain.Book{ID: 1, Title: "Updated Book", Author: "Updated Author", Version: 2}, nil)
 			},
 			wantStatus: http.StatusNoContent,
+			wantETag:   `"2"`,
+		},
+		{
+			name:    "stale version",
+			bookID:  "1",
+			ifMatch: `"1"`,
+			body:    UpdateBookReq{Title: "Updated Book", Author: "Updated Author"},
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).Return(domain.Book{}, domain.ErrVersionConflict)
+			},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:       "weak etag never matches",
+			bookID:     "1",
+			ifMatch:    `W/"1"`,
+			body:       UpdateBookReq{Title: "Updated Book", Author: "Updated Author"},
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusPreconditionFailed,
 		},
 		{
 			name:       "invalid id",
@@ -252,7 +694,7 @@ func TestBookHandler_UpdateBook(t *testing.T) {
 			bookID: "999",
 			body:   UpdateBookReq{Title: "Updated Book", Author: "Updated Author"},
 			setup: func(m *mocks.MockBookUseCase) {
-				m.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).Return(domain.ErrBookNotFound)
+				m.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).Return(domain.Book{}, domain.ErrBookNotFound)
 			},
 			wantStatus: http.StatusNotFound,
 		},
@@ -261,7 +703,7 @@ func TestBookHandler_UpdateBook(t *testing.T) {
 			bookID: "1",
 			body:   UpdateBookReq{Title: "Updated Book", Author: "Updated Author"},
 			setup: func(m *mocks.MockBookUseCase) {
-				m.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).Return(errors.New("service error"))
+				m.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).Return(domain.Book{}, errors.New("service error"))
 			},
 			wantStatus: http.StatusInternalServerError,
 		},
@@ -274,7 +716,7 @@ func TestBookHandler_UpdateBook(t *testing.T) {
 			mockService := mocks.NewMockBookUseCase(ctrl)
 			tt.setup(mockService)
 
-			h := NewBookHandler(mockService)
+			h := NewBookHandler(mockService, mocks.NewMockBookAsOfUseCase(ctrl), testCursors, testPages)
 
 			r := setupTestRouter()
 			r.PUT("/books/:id", h.UpdateBook)
@@ -283,12 +725,190 @@ func TestBookHandler_UpdateBook(t *testing.T) {
 			bodyBytes, _ := json.Marshal(tt.body)
 			req := httptest.NewRequest(http.MethodPut, "/books/"+tt.bookID, bytes.NewBuffer(bodyBytes))
 			req.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
 
 			r.ServeHTTP(w, req)
 
 			if w.Code != tt.wantStatus {
 				t.Errorf("UpdateBook() status = %v, want %v", w.Code, tt.wantStatus)
 			}
+			if got := w.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("UpdateBook() ETag = %v, want %v", got, tt.wantETag)
+			}
+		})
+	}
+}
+
+func TestBookHandler_PatchBook(t *testing.T) {
+	title := "Patched Book"
+	empty := ""
+	patched := domain.Book{ID: 1, Title: "Patched Book", Author: "Test Author", Version: 2}
+
+	tests := []struct {
+		name        string
+		bookID      string
+		contentType string
+		ifMatch     string
+		body        string
+		setup       func(*mocks.MockBookUseCase)
+		wantStatus  int
+		wantETag    string
+	}{
+		{
+			name:   "success",
+			bookID: "1",
+			body:   `{"title":"Patched Book"}`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().PatchBook(gomock.Any(), 1, 0, domain.BookPatch{Title: &title}).Return(patched, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantETag:   `"2"`,
+		},
+		{
+			name:    "success - matching version",
+			bookID:  "1",
+			ifMatch: `"1"`,
+			body:    `{"title":"Patched Book"}`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().PatchBook(gomock.Any(), 1, 1, domain.BookPatch{Title: &title}).Return(patched, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantETag:   `"2"`,
+		},
+		{
+			name:   "null clears isbn",
+			bookID: "1",
+			body:   `{"isbn":null}`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().PatchBook(gomock.Any(), 1, 0, domain.BookPatch{ISBN: &empty}).Return(patched, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantETag:   `"2"`,
+		},
+		{
+			name:   "author ids replace authors",
+			bookID: "1",
+			body:   `{"author_ids":[2,1]}`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().PatchBook(gomock.Any(), 1, 0, domain.BookPatch{Authors: []domain.Author{{ID: 2}, {ID: 1}}}).Return(patched, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantETag:   `"2"`,
+		},
+		{
+			name:        "wrong content type",
+			bookID:      "1",
+			contentType: "application/json",
+			body:        `{"title":"Patched Book"}`,
+			setup:       func(m *mocks.MockBookUseCase) {},
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:       "weak etag never matches",
+			bookID:     "1",
+			ifMatch:    `W/"1"`,
+			body:       `{"title":"Patched Book"}`,
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:       "read-only field",
+			bookID:     "1",
+			body:       `{"version":5}`,
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not an object",
+			bookID:     "1",
+			body:       `["title"]`,
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "author and author ids",
+			bookID:     "1",
+			body:       `{"author":"Someone","author_ids":[1]}`,
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "validation error",
+			bookID: "1",
+			body:   `{"title":null}`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().PatchBook(gomock.Any(), 1, 0, domain.BookPatch{Title: &empty}).Return(domain.Book{}, domain.ErrTitleRequired)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "stale version",
+			bookID:  "1",
+			ifMatch: `"1"`,
+			body:    `{"title":"Patched Book"}`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().PatchBook(gomock.Any(), 1, 1, gomock.Any()).Return(domain.Book{}, domain.ErrVersionConflict)
+			},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:   "duplicate isbn",
+			bookID: "1",
+			body:   `{"isbn":"9780743273565"}`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().PatchBook(gomock.Any(), 1, 0, gomock.Any()).Return(domain.Book{}, domain.ErrDuplicateISBN)
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:   "book not found",
+			bookID: "999",
+			body:   `{"title":"Patched Book"}`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().PatchBook(gomock.Any(), 999, 0, gomock.Any()).Return(domain.Book{}, domain.ErrBookNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "service error",
+			bookID: "1",
+			body:   `{"title":"Patched Book"}`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().PatchBook(gomock.Any(), 1, 0, gomock.Any()).Return(domain.Book{}, errors.New("service error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockBookUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewBookHandler(mockService, mocks.NewMockBookAsOfUseCase(ctrl), testCursors, testPages)
+
+			r := setupTestRouter()
+			r.PATCH("/books/:id", h.PatchBook)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPatch, "/books/"+tt.bookID, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", cmp.Or(tt.contentType, "application/merge-patch+json"))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("PatchBook() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("PatchBook() ETag = %v, want %v", got, tt.wantETag)
+			}
 		})
 	}
 }
@@ -297,6 +917,7 @@ func TestBookHandler_DeleteBook(t *testing.T) {
 	tests := []struct {
 		name       string
 		bookID     string
+		ifMatch    string
 		setup      func(*mocks.MockBookUseCase)
 		wantStatus int
 	}{
@@ -304,10 +925,19 @@ func TestBookHandler_DeleteBook(t *testing.T) {
 			name:   "success",
 			bookID: "1",
 			setup: func(m *mocks.MockBookUseCase) {
-				m.EXPECT().DeleteBook(gomock.Any(), 1).Return(nil)
+				m.EXPECT().DeleteBook(gomock.Any(), 1, 0).Return(nil)
 			},
 			wantStatus: http.StatusNoContent,
 		},
+		{
+			name:    "stale version",
+			bookID:  "1",
+			ifMatch: `"3"`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().DeleteBook(gomock.Any(), 1, 3).Return(domain.ErrVersionConflict)
+			},
+			wantStatus: http.StatusPreconditionFailed,
+		},
 		{
 			name:       "invalid id",
 			bookID:     "invalid",
@@ -318,7 +948,7 @@ func TestBookHandler_DeleteBook(t *testing.T) {
 			name:   "book not found",
 			bookID: "999",
 			setup: func(m *mocks.MockBookUseCase) {
-				m.EXPECT().DeleteBook(gomock.Any(), 999).Return(domain.ErrBookNotFound)
+				m.EXPECT().DeleteBook(gomock.Any(), 999, 0).Return(domain.ErrBookNotFound)
 			},
 			wantStatus: http.StatusNotFound,
 		},
@@ -326,7 +956,7 @@ func TestBookHandler_DeleteBook(t *testing.T) {
 			name:   "service error",
 			bookID: "1",
 			setup: func(m *mocks.MockBookUseCase) {
-				m.EXPECT().DeleteBook(gomock.Any(), 1).Return(errors.New("service error"))
+				m.EXPECT().DeleteBook(gomock.Any(), 1, 0).Return(errors.New("service error"))
 			},
 			wantStatus: http.StatusInternalServerError,
 		},
@@ -339,13 +969,16 @@ func TestBookHandler_DeleteBook(t *testing.T) {
 			mockService := mocks.NewMockBookUseCase(ctrl)
 			tt.setup(mockService)
 
-			h := NewBookHandler(mockService)
+			h := NewBookHandler(mockService, mocks.NewMockBookAsOfUseCase(ctrl), testCursors, testPages)
 
 			r := setupTestRouter()
 			r.DELETE("/books/:id", h.DeleteBook)
 
 			w := httptest.NewRecorder()
 			req := httptest.NewRequest(http.MethodDelete, "/books/"+tt.bookID, nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
 
 			r.ServeHTTP(w, req)
 
@@ -355,3 +988,158 @@ func TestBookHandler_DeleteBook(t *testing.T) {
 		})
 	}
 }
+
+func TestBookHandler_GetTrash(t *testing.T) {
+	deletedAt := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockBookUseCase(ctrl)
+	mockService.EXPECT().GetTrash(gomock.Any(), 1, 10).
+		Return([]domain.Book{{ID: 1, Title: "Test Book", Author: "Test Author", DeletedAt: &deletedAt}}, 1, nil)
+
+	h := NewBookHandler(mockService, mocks.NewMockBookAsOfUseCase(ctrl), testCursors, testPages)
+
+	r := setupTestRouter()
+	r.GET("/books/trash", h.GetTrash)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/books/trash", nil)
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetTrash() status = %v, want %v", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get("X-Total-Count"); got != "1" {
+		t.Errorf("GetTrash() X-Total-Count = %q, want 1", got)
+	}
+	var res []BookRes
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(res) != 1 || res[0].DeletedAt == nil || *res[0].DeletedAt != "2025-01-02T00:00:00Z" {
+		t.Errorf("GetTrash() = %+v, want one book deleted at 2025-01-02T00:00:00Z", res)
+	}
+}
+
+func TestBookHandler_RestoreBook(t *testing.T) {
+	tests := []struct {
+		name       string
+		bookID     string
+		setup      func(*mocks.MockBookUseCase)
+		wantStatus int
+	}{
+		{
+			name:   "success",
+			bookID: "1",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().RestoreBook(gomock.Any(), 1).Return(domain.Book{ID: 1, Title: "Test Book", Author: "Test Author"}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "invalid id",
+			bookID:     "invalid",
+			setup:      func(m *mocks.MockBookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "not in trash",
+			bookID: "999",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().RestoreBook(gomock.Any(), 999).Return(domain.Book{}, domain.ErrBookNotInTrash)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "isbn taken by a live book",
+			bookID: "1",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().RestoreBook(gomock.Any(), 1).Return(domain.Book{}, domain.ErrDuplicateISBN)
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockBookUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewBookHandler(mockService, mocks.NewMockBookAsOfUseCase(ctrl), testCursors, testPages)
+
+			r := setupTestRouter()
+			r.POST("/books/:id/restore", h.RestoreBook)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/books/"+tt.bookID+"/restore", nil)
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("RestoreBook() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestBookHandler_PurgeBook(t *testing.T) {
+	tests := []struct {
+		name       string
+		bookID     string
+		setup      func(*mocks.MockBookUseCase)
+		wantStatus int
+	}{
+		{
+			name:   "success",
+			bookID: "1",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().PurgeBook(gomock.Any(), 1).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:   "not in trash",
+			bookID: "999",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().PurgeBook(gomock.Any(), 999).Return(domain.ErrBookNotInTrash)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:   "service error",
+			bookID: "1",
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().PurgeBook(gomock.Any(), 1).Return(errors.New("service error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockBookUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewBookHandler(mockService, mocks.NewMockBookAsOfUseCase(ctrl), testCursors, testPages)
+
+			r := setupTestRouter()
+			r.DELETE("/admin/books/:id", h.PurgeBook)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, "/admin/books/"+tt.bookID, nil)
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("PurgeBook() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/domain"
+	"io"
+	"time"
+)
+
+// importProgressRows is how often, in rows read, an import job reports its
+// progress.
+const importProgressRows = 500
+
+// BookImportJob runs the book_import jobs queued by POST /books/import with
+// async=true. Its params are an ImportBooksReq and its input the CSV file.
+type BookImportJob struct {
+	importService in.BookImportUseCase
+}
+
+var _ in.JobRunner = &BookImportJob{}
+
+func NewBookImportJob(importService in.BookImportUseCase) *BookImportJob {
+	return &BookImportJob{importService: importService}
+}
+
+// importJobResult is the result of an import job; the full report is its
+// artifact.
+type importJobResult struct {
+	DryRun   bool `json:"dry_run"`
+	Inserted int  `json:"inserted"`
+	Skipped  int  `json:"skipped"`
+	Failed   int  `json:"failed"`
+}
+
+// RunJob fails for good on a CSV header that does not match the params, but
+// not on a failure to read the stored file. Once part of the file is
+// imported, a failure is for good too, as a retry would import that part
+// again.
+func (j *BookImportJob) RunJob(ctx context.Context, job domain.Job, input io.Reader, output io.Writer, progress func(int)) (domain.JobOutcome, error) {
+	var params ImportBooksReq
+	if err := json.Unmarshal(job.Params, &params); err != nil {
+		return domain.JobOutcome{}, &domain.PermanentJobError{Err: err}
+	}
+
+	src := &recordingReader{r: input}
+	csvRows, err := newCSVBookReader(src, params.columns())
+	if err != nil {
+		if src.err != nil {
+			return domain.JobOutcome{}, src.err
+		}
+		return domain.JobOutcome{}, &domain.PermanentJobError{Err: err}
+	}
+
+	rows := &progressRows{BookRowReader: csvRows, progress: progress}
+	report, err := j.importService.ImportBooks(ctx, rows, params.DryRun)
+	var interrupted *domain.ImportInterruptedError
+	if errors.As(err, &interrupted) {
+		return domain.JobOutcome{}, &domain.PermanentJobError{Err: err}
+	}
+	if err != nil {
+		return domain.JobOutcome{}, err
+	}
+	progress(rows.read)
+
+	if err := json.NewEncoder(output).Encode(newImportReportRes(report)); err != nil {
+		return domain.JobOutcome{}, err
+	}
+	result, err := json.Marshal(importJobResult{
+		DryRun:   report.DryRun,
+		Inserted: report.Inserted,
+		Skipped:  report.Skipped,
+		Failed:   report.Failed,
+	})
+	if err != nil {
+		return domain.JobOutcome{}, err
+	}
+	return domain.JobOutcome{
+		Result: result,
+		Artifact: &domain.JobArtifact{
+			Filename:    "import-report-" + time.Now().UTC().Format("20060102-150405") + ".json",
+			ContentType: "application/json; charset=utf-8",
+		},
+	}, nil
+}
+
+// recordingReader keeps the error its reader failed with, telling a stored
+// file that could not be read apart from one that could not be parsed.
+type recordingReader struct {
+	r   io.Reader
+	err error
+}
+
+func (r *recordingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+// progressRows reports the number of rows read every importProgressRows.
+type progressRows struct {
+	in.BookRowReader
+	progress func(int)
+	read     int
+}
+
+func (r *progressRows) Read() (domain.ImportRow, error) {
+	row, err := r.BookRowReader.Read()
+	if err == nil {
+		r.read++
+		if r.read%importProgressRows == 0 {
+			r.progress(r.read)
+		}
+	}
+	return row, err
+}
+
+// BookExportJob runs the book_export jobs queued by POST /books/export. Its
+// params are an ExportBooksReq and its artifact the exported file.
+type BookExportJob struct {
+	bookService in.BookUseCase
+}
+
+var _ in.JobRunner = &BookExportJob{}
+
+func NewBookExportJob(bookService in.BookUseCase) *BookExportJob {
+	return &BookExportJob{bookService: bookService}
+}
+
+// exportJobResult is the result of an export job.
+type exportJobResult struct {
+	Format string `json:"format"`
+	Books  int    `json:"books"`
+}
+
+func (j *BookExportJob) RunJob(ctx context.Context, job domain.Job, input io.Reader, output io.Writer, progress func(int)) (domain.JobOutcome, error) {
+	var params ExportBooksReq
+	if err := json.Unmarshal(job.Params, &params); err != nil {
+		return domain.JobOutcome{}, &domain.PermanentJobError{Err: err}
+	}
+	format, ok := exportFormats[params.Format]
+	if !ok {
+		return domain.JobOutcome{}, &domain.PermanentJobError{Err: fmt.Errorf("unknown export format %q", params.Format)}
+	}
+	sort, err := domain.ParseBookSort(params.Sort)
+	if err != nil {
+		return domain.JobOutcome{}, &domain.PermanentJobError{Err: err}
+	}
+
+	enc := format.newEncoder(output)
+	exported := 0
+	err = j.bookService.ExportBooks(ctx, params.criteria(sort), func(books []domain.Book) error {
+		for _, book := range books {
+			if err := enc.Encode(book); err != nil {
+				return err
+			}
+		}
+		exported += len(books)
+		progress(exported)
+		return enc.Flush()
+	})
+	if err != nil {
+		return domain.JobOutcome{}, err
+	}
+	if err := enc.Close(); err != nil {
+		return domain.JobOutcome{}, err
+	}
+
+	result, err := json.Marshal(exportJobResult{Format: params.Format, Books: exported})
+	if err != nil {
+		return domain.JobOutcome{}, err
+	}
+	return domain.JobOutcome{
+		Result:   result,
+		Artifact: &domain.JobArtifact{Filename: exportFilename(params.Format, time.Now()), ContentType: format.contentType},
+	}, nil
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestBookImportJob_RunJob(t *testing.T) {
+	errDB := errors.New("db error")
+	tests := []struct {
+		name          string
+		params        string
+		input         string
+		inputErr      error
+		setup         func(*mocks.MockBookImportUseCase)
+		wantResult    string
+		wantReport    string
+		wantProcessed []int
+		wantErr       error
+		wantPermanent bool
+	}{
+		{
+			name:   "success",
+			params: `{"title_column":"name","author_column":"writer","dry_run":true}`,
+			input:  "Name,Writer\nAnimal Farm,George Orwell\n,Nobody\n",
+			setup: func(m *mocks.MockBookImportUseCase) {
+				m.EXPECT().ImportBooks(gomock.Any(), gomock.Any(), true).
+					DoAndReturn(func(_ context.Context, rows in.BookRowReader, dryRun bool) (domain.ImportReport, error) {
+						report := domain.ImportReport{DryRun: dryRun, Rows: []domain.ImportRowResult{}}
+						for range 2 {
+							row, err := rows.Read()
+							if err != nil {
+								return domain.ImportReport{}, err
+							}
+							if row.Book.Title == "" {
+								report.Add(row.Line, domain.BatchResult{Err: domain.ErrTitleRequired})
+							} else {
+								report.Add(row.Line, domain.BatchResult{})
+							}
+						}
+						return report, nil
+					})
+			},
+			wantResult:    `{"dry_run":true,"inserted":1,"skipped":0,"failed":1}`,
+			wantReport:    `{"dry_run":true,"inserted":1,"skipped":0,"failed":1,"rows":[{"line":2,"status":"inserted"},{"line":3,"status":"failed","error":{"code":"VALIDATION_ERROR","message":"title is required"}}]}` + "\n",
+			wantProcessed: []int{2},
+		},
+		{
+			name:          "header without the mapped column",
+			params:        `{"title_column":"title","author_column":"author"}`,
+			input:         "name,author\n",
+			setup:         func(m *mocks.MockBookImportUseCase) {},
+			wantPermanent: true,
+		},
+		{
+			name:     "stored file cannot be read",
+			params:   `{"title_column":"title","author_column":"author"}`,
+			inputErr: errDB,
+			setup:    func(m *mocks.MockBookImportUseCase) {},
+			wantErr:  errDB,
+		},
+		{
+			name:   "import error is retried",
+			params: `{"title_column":"title","author_column":"author"}`,
+			input:  "title,author\nAnimal Farm,George Orwell\n",
+			setup: func(m *mocks.MockBookImportUseCase) {
+				m.EXPECT().ImportBooks(gomock.Any(), gomock.Any(), false).Return(domain.ImportReport{}, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:   "import error after a written batch fails for good",
+			params: `{"title_column":"title","author_column":"author"}`,
+			input:  "title,author\nAnimal Farm,George Orwell\n",
+			setup: func(m *mocks.MockBookImportUseCase) {
+				m.EXPECT().ImportBooks(gomock.Any(), gomock.Any(), false).
+					Return(domain.ImportReport{}, &domain.ImportInterruptedError{Line: 501, Err: errDB})
+			},
+			wantErr:       errDB,
+			wantPermanent: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockBookImportUseCase(ctrl)
+			tt.setup(mockService)
+
+			var input io.Reader = strings.NewReader(tt.input)
+			if tt.inputErr != nil {
+				input = &failingReader{err: tt.inputErr}
+			}
+			var output bytes.Buffer
+			var processed []int
+			job := domain.Job{ID: 1, Kind: domain.JobBookImport, Params: json.RawMessage(tt.params)}
+			outcome, err := NewBookImportJob(mockService).RunJob(context.Background(), job, input, &output,
+				func(n int) { processed = append(processed, n) })
+
+			var permanent *domain.PermanentJobError
+			if errors.As(err, &permanent) != tt.wantPermanent {
+				t.Fatalf("RunJob() error = %v, want permanent %v", err, tt.wantPermanent)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunJob() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil || tt.wantPermanent {
+				return
+			}
+			if err != nil {
+				t.Fatalf("RunJob() error = %v", err)
+			}
+			if string(outcome.Result) != tt.wantResult {
+				t.Errorf("RunJob() result = %s, want %s", outcome.Result, tt.wantResult)
+			}
+			if outcome.Artifact == nil || !strings.HasPrefix(outcome.Artifact.Filename, "import-report-") {
+				t.Errorf("RunJob() artifact = %+v, want an import report", outcome.Artifact)
+			}
+			if output.String() != tt.wantReport {
+				t.Errorf("RunJob() report = %s, want %s", output.String(), tt.wantReport)
+			}
+			if !slices.Equal(processed, tt.wantProcessed) {
+				t.Errorf("RunJob() progress = %v, want %v", processed, tt.wantProcessed)
+			}
+		})
+	}
+}
+
+func TestBookExportJob_RunJob(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		params        string
+		setup         func(*mocks.MockBookUseCase)
+		wantResult    string
+		wantOutput    string
+		wantProcessed []int
+		wantErr       bool
+		wantPermanent bool
+	}{
+		{
+			name:   "success",
+			params: `{"format":"ndjson","author":"George Orwell","sort":"-title"}`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().ExportBooks(gomock.Any(), domain.BookCriteria{
+					Author: "George Orwell",
+					Sort:   domain.BookSort{{Field: "title", Desc: true}},
+				}, gomock.Any()).DoAndReturn(func(_ context.Context, _ domain.BookCriteria, fn func([]domain.Book) error) error {
+					if err := fn([]domain.Book{{ID: 1, CreatedAt: created, UpdatedAt: created}}); err != nil {
+						return err
+					}
+					return fn([]domain.Book{{ID: 2, CreatedAt: created, UpdatedAt: created}})
+				})
+			},
+			wantResult: `{"format":"ndjson","books":2}`,
+			wantOutput: `{"id":1,"title":"","author":"","authors":[],"version":0,"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}` + "\n" +
+				`{"id":2,"title":"","author":"","authors":[],"version":0,"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}` + "\n",
+			wantProcessed: []int{1, 2},
+		},
+		{
+			name:          "unknown format",
+			params:        `{"format":"xml"}`,
+			setup:         func(m *mocks.MockBookUseCase) {},
+			wantErr:       true,
+			wantPermanent: true,
+		},
+		{
+			name:   "export error is retried",
+			params: `{"format":"csv"}`,
+			setup: func(m *mocks.MockBookUseCase) {
+				m.EXPECT().ExportBooks(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockBookUseCase(ctrl)
+			tt.setup(mockService)
+
+			var output bytes.Buffer
+			var processed []int
+			job := domain.Job{ID: 1, Kind: domain.JobBookExport, Params: json.RawMessage(tt.params)}
+			outcome, err := NewBookExportJob(mockService).RunJob(context.Background(), job, strings.NewReader(""), &output,
+				func(n int) { processed = append(processed, n) })
+
+			var permanent *domain.PermanentJobError
+			if (err != nil) != tt.wantErr || errors.As(err, &permanent) != tt.wantPermanent {
+				t.Fatalf("RunJob() error = %v, wantErr %v, want permanent %v", err, tt.wantErr, tt.wantPermanent)
+			}
+			if tt.wantErr {
+				return
+			}
+			if string(outcome.Result) != tt.wantResult {
+				t.Errorf("RunJob() result = %s, want %s", outcome.Result, tt.wantResult)
+			}
+			if outcome.Artifact == nil || outcome.Artifact.ContentType != "application/x-ndjson" || !strings.HasSuffix(outcome.Artifact.Filename, ".ndjson") {
+				t.Errorf("RunJob() artifact = %+v, want an ndjson file", outcome.Artifact)
+			}
+			if output.String() != tt.wantOutput {
+				t.Errorf("RunJob() output = %q, want %q", output.String(), tt.wantOutput)
+			}
+			if !slices.Equal(processed, tt.wantProcessed) {
+				t.Errorf("RunJob() progress = %v, want %v", processed, tt.wantProcessed)
+			}
+		})
+	}
+}
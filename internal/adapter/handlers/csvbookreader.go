@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/internal/http/util"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvColumns names the header columns that hold each book field. An empty
+// ISBN column is read from an "isbn" column when there is one. Author IDs are
+// always read from an "author_ids" column when there is one, as written by
+// GET /books/export.
+type csvColumns struct {
+	Title  string
+	Author string
+	ISBN   string
+}
+
+// csvBookReader reads books from a CSV file one record at a time. Columns are
+// matched to fields by their header, ignoring case and surrounding spaces.
+type csvBookReader struct {
+	r                              *csv.Reader
+	title, author, isbn, authorIDs int
+}
+
+var _ in.BookRowReader = &csvBookReader{}
+
+// newCSVBookReader reads the header of r and locates the mapped columns.
+func newCSVBookReader(r io.Reader, columns csvColumns) (*csvBookReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, util.BadRequest(errors.New("csv file is empty"))
+	}
+	if err != nil {
+		return nil, util.BadRequest(err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheets often save CSV with a UTF-8 byte order mark.
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := index[name]; !ok {
+			index[name] = i
+		}
+	}
+	find := func(param, column string) (int, error) {
+		i, ok := index[strings.ToLower(strings.TrimSpace(column))]
+		if !ok {
+			return 0, util.InvalidField(param, "column", fmt.Sprintf("csv header has no %q column", column))
+		}
+		return i, nil
+	}
+
+	reader := &csvBookReader{r: cr, isbn: -1, authorIDs: -1}
+	if reader.title, err = find("title_column", columns.Title); err != nil {
+		return nil, err
+	}
+	if reader.author, err = find("author_column", columns.Author); err != nil {
+		return nil, err
+	}
+	if columns.ISBN != "" {
+		if reader.isbn, err = find("isbn_column", columns.ISBN); err != nil {
+			return nil, err
+		}
+	} else if i, ok := index["isbn"]; ok {
+		reader.isbn = i
+	}
+	if i, ok := index["author_ids"]; ok {
+		reader.authorIDs = i
+	}
+	return reader, nil
+}
+
+// Read returns the next record as a book. A malformed record is returned as a
+// failed row, while an error reading the upload aborts the import. A record
+// with author IDs links the book to those authors instead of its author name.
+func (r *csvBookReader) Read() (domain.ImportRow, error) {
+	record, err := r.r.Read()
+	if errors.Is(err, io.EOF) {
+		return domain.ImportRow{}, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return domain.ImportRow{Line: parseErr.StartLine, Err: util.BadRequest(parseErr.Err)}, nil
+	}
+	if err != nil {
+		return domain.ImportRow{}, util.BadRequest(err)
+	}
+
+	line, _ := r.r.FieldPos(0)
+	field := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	row := domain.ImportRow{
+		Line: line,
+		Book: domain.Book{
+			Title:  field(r.title),
+			Author: field(r.author),
+			ISBN:   field(r.isbn),
+		},
+	}
+	row.Book.Authors, row.Err = parseAuthorIDs(field(r.authorIDs))
+	return row, nil
+}
+
+// parseAuthorIDs parses author IDs separated by ";", the way GET /books/export
+// writes them. An empty cell has no IDs.
+func parseAuthorIDs(cell string) ([]domain.Author, error) {
+	if cell == "" {
+		return nil, nil
+	}
+	parts := strings.Split(cell, ";")
+	authors := make([]domain.Author, len(parts))
+	for i, part := range parts {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || id < 1 {
+			return nil, util.InvalidField("author_ids", "author_ids", "author_ids must be positive integers separated by ;")
+		}
+		authors[i].ID = id
+	}
+	return authors, nil
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/internal/http/util"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCSVBookReader(t *testing.T) {
+	defaults := csvColumns{Title: "title", Author: "author"}
+
+	tests := []struct {
+		name    string
+		csv     string
+		columns csvColumns
+		want    []domain.ImportRow
+		wantErr bool
+	}{
+		{
+			name:    "default columns",
+			csv:     "\ufeffTitle, Author ,ISBN\nAnimal Farm,George Orwell,\n1984,George Orwell,978-0-7432-7356-5\n",
+			columns: defaults,
+			want: []domain.ImportRow{
+				{Line: 2, Book: domain.Book{Title: "Animal Farm", Author: "George Orwell"}},
+				{Line: 3, Book: domain.Book{Title: "1984", Author: "George Orwell", ISBN: "978-0-7432-7356-5"}},
+			},
+		},
+		{
+			name:    "mapped columns",
+			csv:     "Writer,Name,Code,Shelf\nGeorge Orwell,Animal Farm,9780743273565,B2\n",
+			columns: csvColumns{Title: "name", Author: "writer", ISBN: "code"},
+			want: []domain.ImportRow{
+				{Line: 2, Book: domain.Book{Title: "Animal Farm", Author: "George Orwell", ISBN: "9780743273565"}},
+			},
+		},
+		{
+			name:    "multi-line field and short record",
+			csv:     "title,author\n\"Animal\nFarm\",George Orwell\n\n1984\n",
+			columns: defaults,
+			want: []domain.ImportRow{
+				{Line: 2, Book: domain.Book{Title: "Animal\nFarm", Author: "George Orwell"}},
+				{Line: 5, Book: domain.Book{Title: "1984"}},
+			},
+		},
+		{
+			name:    "author ids",
+			csv:     "id,title,author,author_ids,isbn\n1,Good Omens,\"Neil Gaiman, Terry Pratchett\",2;1,\n2,Animal Farm,George Orwell,,\n3,1984,George Orwell,x,\n",
+			columns: defaults,
+			want: []domain.ImportRow{
+				{Line: 2, Book: domain.Book{Title: "Good Omens", Author: "Neil Gaiman, Terry Pratchett", Authors: []domain.Author{{ID: 2}, {ID: 1}}}},
+				{Line: 3, Book: domain.Book{Title: "Animal Farm", Author: "George Orwell"}},
+				{Line: 4, Book: domain.Book{Title: "1984", Author: "George Orwell"}, Err: util.InvalidField("author_ids", "author_ids", "author_ids must be positive integers separated by ;")},
+			},
+		},
+		{
+			name:    "missing column",
+			csv:     "title,writer\nAnimal Farm,George Orwell\n",
+			columns: defaults,
+			wantErr: true,
+		},
+		{
+			name:    "missing mapped isbn column",
+			csv:     "title,author\nAnimal Farm,George Orwell\n",
+			columns: csvColumns{Title: "title", Author: "author", ISBN: "isbn"},
+			wantErr: true,
+		},
+		{
+			name:    "empty file",
+			csv:     "",
+			columns: defaults,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := newCSVBookReader(strings.NewReader(tt.csv), tt.columns)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newCSVBookReader() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				var reqErr *util.RequestError
+				if !errors.As(err, &reqErr) {
+					t.Errorf("newCSVBookReader() error = %v, want a request error", err)
+				}
+				return
+			}
+
+			got := []domain.ImportRow{}
+			for {
+				row, err := r.Read()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("csvBookReader.Read() error = %v", err)
+				}
+				got = append(got, row)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("csvBookReader rows = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVBookReader_MalformedRecord(t *testing.T) {
+	r, err := newCSVBookReader(strings.NewReader("title,author\nAnimal \"Farm\",George Orwell\n1984,George Orwell\n"), csvColumns{Title: "title", Author: "author"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	row, err := r.Read()
+	if err != nil || row.Line != 2 || row.Err == nil {
+		t.Fatalf("csvBookReader.Read() = %+v, %v, want a failed row on line 2", row, err)
+	}
+	row, err = r.Read()
+	if err != nil || row.Line != 3 || row.Book.Title != "1984" {
+		t.Errorf("csvBookReader.Read() = %+v, %v, want 1984 on line 3", row, err)
+	}
+}
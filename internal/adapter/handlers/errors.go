@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"go-api-boilerplate/internal/constant"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/internal/http/util"
+	"net/http"
+)
+
+// ErrorRegistry maps the errors returned by the use cases, and by the
+// middlewares guarding routes, to HTTP responses.
+func ErrorRegistry() *util.ErrorRegistry {
+	return util.NewErrorRegistry().
+		RegisterFunc(resolveValidationError).
+		Register(domain.ErrBookNotFound, http.StatusNotFound, constant.ErrNotFoundCode).
+		Register(domain.ErrBookNotInTrash, http.StatusNotFound, constant.ErrNotFoundCode).
+		Register(domain.ErrAuthorNotFound, http.StatusNotFound, constant.ErrNotFoundCode).
+		Register(domain.ErrBookVersionNotFound, http.StatusNotFound, constant.ErrNotFoundCode).
+		Register(domain.ErrJobNotFound, http.StatusNotFound, constant.ErrNotFoundCode).
+		Register(domain.ErrJobArtifactNotFound, http.StatusNotFound, constant.ErrNotFoundCode).
+		Register(domain.ErrWebhookNotFound, http.StatusNotFound, constant.ErrNotFoundCode).
+		Register(domain.ErrTitleRequired, http.StatusBadRequest, constant.ErrValidationCode).
+		Register(domain.ErrAuthorRequired, http.StatusBadRequest, constant.ErrValidationCode).
+		Register(domain.ErrAuthorNameRequired, http.StatusBadRequest, constant.ErrValidationCode).
+		Register(domain.ErrDuplicateAuthor, http.StatusBadRequest, constant.ErrValidationCode).
+		Register(domain.ErrUnknownAuthor, http.StatusBadRequest, constant.ErrValidationCode).
+		Register(domain.ErrInvalidISBN, http.StatusBadRequest, constant.ErrValidationCode).
+		Register(domain.ErrInvalidCursor, http.StatusBadRequest, constant.ErrValidationCode).
+		Register(domain.ErrInvalidSort, http.StatusBadRequest, constant.ErrValidationCode).
+		Register(domain.ErrSearchQueryRequired, http.StatusBadRequest, constant.ErrValidationCode).
+		Register(domain.ErrWebhookURLInvalid, http.StatusBadRequest, constant.ErrValidationCode).
+		Register(domain.ErrWebhookURLPrivate, http.StatusBadRequest, constant.ErrValidationCode).
+		Register(domain.ErrUnknownEvent, http.StatusBadRequest, constant.ErrValidationCode).
+		Register(domain.ErrDuplicateISBN, http.StatusConflict, constant.ErrConflictCode).
+		Register(domain.ErrAuthorHasBooks, http.StatusConflict, constant.ErrConflictCode).
+		Register(domain.ErrRevertToDeleted, http.StatusConflict, constant.ErrConflictCode).
+		Register(domain.ErrVersionConflict, http.StatusPreconditionFailed, constant.ErrPreconditionFailedCode).
+		Register(constant.ErrIfMatchRequired, http.StatusPreconditionRequired, constant.ErrPreconditionRequiredCode).
+		Register(constant.ErrInvalidAdminToken, http.StatusUnauthorized, constant.ErrUnauthorizedCode).
+		Register(constant.ErrAdminDisabled, http.StatusForbidden, constant.ErrForbiddenCode)
+}
+
+// resolveValidationError reports every field of a domain.ValidationError.
+func resolveValidationError(err error) (int, util.HTTPError, bool) {
+	var verr *domain.ValidationError
+	if !errors.As(err, &verr) {
+		return 0, util.HTTPError{}, false
+	}
+	res := util.HTTPError{Code: constant.ErrValidationCode, Message: verr.Error()}
+	for _, f := range verr.Fields {
+		res.Errors = append(res.Errors, util.FieldError{Field: f.Field, Rule: f.Rule, Message: f.Err.Error()})
+	}
+	return http.StatusBadRequest, res, true
+}
+
+// batchErrors reports the errors of single items of a batch.
+var batchErrors = ErrorRegistry()
+
+// itemError returns the body an error handler would report err with, for
+// embedding in a batch response.
+func itemError(err error) *util.HTTPError {
+	_, res, _ := batchErrors.Resolve(err)
+	return &res
+}
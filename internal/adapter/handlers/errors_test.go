@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api-boilerplate/internal/constant"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/internal/http/util"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestErrorRegistry(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   constant.ErrorCode
+		wantMsg    string
+		wantFields []util.FieldError
+	}{
+		{
+			name:       "not found",
+			err:        domain.ErrBookNotFound,
+			wantStatus: http.StatusNotFound,
+			wantCode:   constant.ErrNotFoundCode,
+			wantMsg:    "book not found",
+		},
+		{
+			name:       "validation",
+			err:        domain.ErrTitleRequired,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   constant.ErrValidationCode,
+			wantMsg:    "title is required",
+		},
+		{
+			name: "domain validation error",
+			err: &domain.ValidationError{Fields: []domain.FieldError{
+				{Field: "title", Rule: "required", Err: domain.ErrTitleRequired},
+				{Field: "isbn", Rule: "isbn", Err: domain.ErrInvalidISBN},
+			}},
+			wantStatus: http.StatusBadRequest,
+			wantCode:   constant.ErrValidationCode,
+			wantMsg:    "title is required; isbn is invalid",
+			wantFields: []util.FieldError{
+				{Field: "title", Rule: "required", Message: "title is required"},
+				{Field: "isbn", Rule: "isbn", Message: "isbn is invalid"},
+			},
+		},
+		{
+			name: "binding error",
+			err: util.BadRequest(&json.UnmarshalTypeError{
+				Value: "number", Type: reflect.TypeOf(""), Struct: "CreateBookReq", Field: "title",
+			}),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   constant.ErrValidationCode,
+			wantMsg:    "request validation failed",
+			wantFields: []util.FieldError{
+				{Field: "title", Rule: "type", Message: "title must be of type string"},
+			},
+		},
+		{
+			name:       "conflict",
+			err:        domain.ErrDuplicateISBN,
+			wantStatus: http.StatusConflict,
+			wantCode:   constant.ErrConflictCode,
+			wantMsg:    "a book with this isbn already exists",
+		},
+		{
+			name:       "version conflict",
+			err:        domain.ErrVersionConflict,
+			wantStatus: http.StatusPreconditionFailed,
+			wantCode:   constant.ErrPreconditionFailedCode,
+			wantMsg:    "book has been modified since it was read",
+		},
+		{
+			name:       "if-match required",
+			err:        constant.ErrIfMatchRequired,
+			wantStatus: http.StatusPreconditionRequired,
+			wantCode:   constant.ErrPreconditionRequiredCode,
+			wantMsg:    "the If-Match header is required",
+		},
+		{
+			name:       "invalid admin token",
+			err:        constant.ErrInvalidAdminToken,
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   constant.ErrUnauthorizedCode,
+			wantMsg:    "invalid admin token",
+		},
+		{
+			name:       "admin access disabled",
+			err:        constant.ErrAdminDisabled,
+			wantStatus: http.StatusForbidden,
+			wantCode:   constant.ErrForbiddenCode,
+			wantMsg:    "admin access is disabled",
+		},
+		{
+			name:       "wrapped",
+			err:        fmt.Errorf("resolve authors for book 7: %w", domain.ErrUnknownAuthor),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   constant.ErrValidationCode,
+			wantMsg:    "a linked author does not exist",
+		},
+		{
+			name:       "unknown",
+			err:        errors.New(`pq: relation "books" does not exist`),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   constant.ErrInternalServerError,
+			wantMsg:    "an unexpected error occurred",
+		},
+	}
+	registry := ErrorRegistry()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, res, _ := registry.Resolve(tt.err)
+			if status != tt.wantStatus {
+				t.Errorf("Resolve() status = %v, want %v", status, tt.wantStatus)
+			}
+			if res.Code != tt.wantCode {
+				t.Errorf("Resolve() code = %v, want %v", res.Code, tt.wantCode)
+			}
+			if res.Message != tt.wantMsg {
+				t.Errorf("Resolve() message = %v, want %v", res.Message, tt.wantMsg)
+			}
+			if !reflect.DeepEqual(res.Errors, tt.wantFields) {
+				t.Errorf("Resolve() errors = %v, want %v", res.Errors, tt.wantFields)
+			}
+		})
+	}
+}
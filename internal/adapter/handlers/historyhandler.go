@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/internal/http/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BookChangeRes is an entry of a book's history: the write that brought the
+// book to version, with the book before and after it. before is null for the
+// write that created the book.
+type BookChangeRes struct {
+	Version   int      `json:"version" example:"2"`
+	Action    string   `json:"action" example:"update" enums:"create,update,delete,restore,revert"`
+	Before    *BookRes `json:"before"`
+	After     BookRes  `json:"after"`
+	Actor     string   `json:"actor" example:"alice"`
+	RequestID string   `json:"request_id" example:"4f9c2a7d1e6b4c0a"`
+	CreatedAt string   `json:"created_at" example:"2025-01-01T00:00:00Z"`
+}
+
+func newBookChangeRes(change domain.BookChange) BookChangeRes {
+	res := BookChangeRes{
+		Version:   change.Version,
+		Action:    string(change.Action),
+		After:     newBookRes(change.After),
+		Actor:     change.Actor,
+		RequestID: change.RequestID,
+		CreatedAt: change.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if change.Before != nil {
+		before := newBookRes(*change.Before)
+		res.Before = &before
+	}
+	return res
+}
+
+type HistoryHandler struct {
+	historyService in.BookHistoryUseCase
+	pages          *util.Paginator
+}
+
+func NewHistoryHandler(historyService in.BookHistoryUseCase, pages *util.Paginator) *HistoryHandler {
+	return &HistoryHandler{historyService: historyService, pages: pages}
+}
+
+// GetBookHistory godoc
+// @Summary      Get a book's history
+// @Description  List every create, update, delete, restore and revert of a book, latest first, with the book before and after each, who made it and in which request. The history outlives the book. Purging a book from the trash is not recorded: its history ends with the delete. With PAGINATION_STYLE=envelope the array is wrapped in a util.PageRes.
+// @Tags         books
+// @Produce      json
+// @Param        id  path  int  true  "Book ID"
+// @Param        page  query  int  false  "Page"
+// @Param        per_page  query  int  false  "Per Page"
+// @Success      200  {object}  []BookChangeRes
+// @Header       200  {integer}  X-Total-Count  "Total number of items (headers style)"
+// @Header       200  {string}  Link  "RFC 8288 first, prev, next and last page links (headers style)"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /books/{id}/history [get]
+func (h *HistoryHandler) GetBookHistory(c *gin.Context) {
+	type params struct {
+		ID int `uri:"id" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	var query PageReq
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	changes, total, err := h.historyService.GetBookHistory(c.Request.Context(), p.ID, query.Page, query.PerPage)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res := make([]BookChangeRes, 0, len(changes))
+	for _, change := range changes {
+		res = append(res, newBookChangeRes(change))
+	}
+	h.pages.Write(c, res, query.Page, query.PerPage, total)
+}
+
+// GetBookVersion godoc
+// @Summary      Get a version of a book
+// @Description  Get the change that brought a book to a version, with the book before and after it.
+// @Tags         books
+// @Produce      json
+// @Param        id  path  int  true  "Book ID"
+// @Param        version  path  int  true  "Version"
+// @Success      200  {object}  BookChangeRes
+// @Failure      400  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /books/{id}/history/{version} [get]
+func (h *HistoryHandler) GetBookVersion(c *gin.Context) {
+	type params struct {
+		ID      int `uri:"id" binding:"required"`
+		Version int `uri:"version" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	change, err := h.historyService.GetBookVersion(c.Request.Context(), p.ID, p.Version)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newBookChangeRes(change))
+}
+
+// RevertBook godoc
+// @Summary      Revert a book
+// @Description  Write a book back as it was at a version. The old title, authors and ISBN are validated like PUT /books/{id}, so reverting to an author that has since been deleted, or to an ISBN another book now holds, fails. The revert is a new version of the book. A book in the trash must be restored first.
+// @Tags         books
+// @Produce      json
+// @Param        id  path  int  true  "Book ID"
+// @Param        version  path  int  true  "Version to revert to"
+// @Param        If-Match  header  string  false  "ETag of the version being replaced"
+// @Success      200  {object}  BookRes
+// @Header       200  {string}  ETag  "New version of the book"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      409  {object}  util.HTTPError
+// @Failure      412  {object}  util.HTTPError
+// @Failure      428  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /books/{id}/revert/{version} [post]
+func (h *HistoryHandler) RevertBook(c *gin.Context) {
+	type params struct {
+		ID      int `uri:"id" binding:"required"`
+		Version int `uri:"version" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	current, ok := util.IfMatchVersion(c.GetHeader("If-Match"))
+	if !ok {
+		c.Error(domain.ErrVersionConflict)
+		return
+	}
+
+	book, err := h.historyService.RevertBook(c.Request.Context(), p.ID, p.Version, current)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.Header("ETag", util.ETag(book.Version))
+	c.JSON(http.StatusOK, newBookRes(book))
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestHistoryHandler_GetBookHistory(t *testing.T) {
+	v1 := domain.Book{ID: 1, Title: "1984", Author: "George Orwell", Version: 1, CreatedAt: testJobTime, UpdatedAt: testJobTime}
+	v2 := v1
+	v2.Title, v2.Version, v2.UpdatedAt = "Nineteen Eighty-Four", 2, testJobTime.Add(time.Hour)
+	tests := []struct {
+		name       string
+		url        string
+		setup      func(*mocks.MockBookHistoryUseCase)
+		wantStatus int
+		wantRes    []BookChangeRes
+		wantTotal  string
+	}{
+		{
+			name: "success",
+			url:  "/books/1/history?per_page=2",
+			setup: func(m *mocks.MockBookHistoryUseCase) {
+				m.EXPECT().GetBookHistory(gomock.Any(), 1, 1, 2).Return([]domain.BookChange{
+					{BookID: 1, Version: 2, Action: domain.BookActionUpdate, Before: &v1, After: v2, Actor: "alice", RequestID: "req-2", CreatedAt: v2.UpdatedAt},
+					{BookID: 1, Version: 1, Action: domain.BookActionCreate, After: v1, Actor: "alice", RequestID: "req-1", CreatedAt: testJobTime},
+				}, 2, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantRes: []BookChangeRes{
+				{Version: 2, Action: "update", Before: &BookRes{ID: 1, Title: "1984", Author: "George Orwell", Authors: []BookAuthorRes{}, Version: 1, CreatedAt: "2025-01-01T00:00:00Z", UpdatedAt: "2025-01-01T00:00:00Z"},
+					After: BookRes{ID: 1, Title: "Nineteen Eighty-Four", Author: "George Orwell", Authors: []BookAuthorRes{}, Version: 2, CreatedAt: "2025-01-01T00:00:00Z", UpdatedAt: "2025-01-01T01:00:00Z"},
+					Actor: "alice", RequestID: "req-2", CreatedAt: "2025-01-01T01:00:00Z"},
+				{Version: 1, Action: "create",
+					After: BookRes{ID: 1, Title: "1984", Author: "George Orwell", Authors: []BookAuthorRes{}, Version: 1, CreatedAt: "2025-01-01T00:00:00Z", UpdatedAt: "2025-01-01T00:00:00Z"},
+					Actor: "alice", RequestID: "req-1", CreatedAt: "2025-01-01T00:00:00Z"},
+			},
+			wantTotal: "2",
+		},
+		{
+			name: "unknown book",
+			url:  "/books/9/history",
+			setup: func(m *mocks.MockBookHistoryUseCase) {
+				m.EXPECT().GetBookHistory(gomock.Any(), 9, 1, 10).Return(nil, 0, domain.ErrBookNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "invalid id",
+			url:        "/books/abc/history",
+			setup:      func(m *mocks.MockBookHistoryUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockBookHistoryUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewHistoryHandler(mockService, testPages)
+
+			r := setupTestRouter()
+			r.GET("/books/:id/history", h.GetBookHistory)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GetBookHistory() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantRes != nil {
+				var res []BookChangeRes
+				if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if !reflect.DeepEqual(res, tt.wantRes) {
+					t.Errorf("GetBookHistory() = %+v, want %+v", res, tt.wantRes)
+				}
+				if got := w.Header().Get("X-Total-Count"); got != tt.wantTotal {
+					t.Errorf("GetBookHistory() X-Total-Count = %q, want %q", got, tt.wantTotal)
+				}
+			}
+		})
+	}
+}
+
+func TestHistoryHandler_GetBookVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		setup      func(*mocks.MockBookHistoryUseCase)
+		wantStatus int
+	}{
+		{
+			name: "success",
+			url:  "/books/1/history/1",
+			setup: func(m *mocks.MockBookHistoryUseCase) {
+				m.EXPECT().GetBookVersion(gomock.Any(), 1, 1).Return(domain.BookChange{BookID: 1, Version: 1, Action: domain.BookActionCreate, After: domain.Book{ID: 1, Version: 1}}, nil)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "unknown version",
+			url:  "/books/1/history/7",
+			setup: func(m *mocks.MockBookHistoryUseCase) {
+				m.EXPECT().GetBookVersion(gomock.Any(), 1, 7).Return(domain.BookChange{}, domain.ErrBookVersionNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "invalid version",
+			url:        "/books/1/history/abc",
+			setup:      func(m *mocks.MockBookHistoryUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockBookHistoryUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewHistoryHandler(mockService, testPages)
+
+			r := setupTestRouter()
+			r.GET("/books/:id/history/:version", h.GetBookVersion)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("GetBookVersion() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHistoryHandler_RevertBook(t *testing.T) {
+	tests := []struct {
+		name       string
+		ifMatch    string
+		setup      func(*mocks.MockBookHistoryUseCase)
+		wantStatus int
+		wantETag   string
+	}{
+		{
+			name:    "success",
+			ifMatch: `"2"`,
+			setup: func(m *mocks.MockBookHistoryUseCase) {
+				m.EXPECT().RevertBook(gomock.Any(), 1, 1, 2).Return(domain.Book{ID: 1, Title: "1984", Version: 3}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantETag:   `"3"`,
+		},
+		{
+			name:    "stale version",
+			ifMatch: `"1"`,
+			setup: func(m *mocks.MockBookHistoryUseCase) {
+				m.EXPECT().RevertBook(gomock.Any(), 1, 1, 1).Return(domain.Book{}, domain.ErrVersionConflict)
+			},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:       "malformed if-match",
+			ifMatch:    "two",
+			setup:      func(m *mocks.MockBookHistoryUseCase) {},
+			wantStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name: "revert to a deleted version",
+			setup: func(m *mocks.MockBookHistoryUseCase) {
+				m.EXPECT().RevertBook(gomock.Any(), 1, 1, 0).Return(domain.Book{}, domain.ErrRevertToDeleted)
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockBookHistoryUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewHistoryHandler(mockService, testPages)
+
+			r := setupTestRouter()
+			r.POST("/books/:id/revert/:version", h.RevertBook)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/books/1/revert/1", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("RevertBook() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("RevertBook() ETag = %q, want %q", got, tt.wantETag)
+			}
+		})
+	}
+}
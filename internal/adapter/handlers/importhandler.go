@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/constant"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/internal/http/util"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	// ImportBooksReq is kept as the params of an import job, save Async.
+	ImportBooksReq struct {
+		TitleColumn  string `json:"title_column" form:"title_column,default=title" example:"Title"`
+		AuthorColumn string `json:"author_column" form:"author_column,default=author" example:"Writer"`
+		ISBNColumn   string `json:"isbn_column,omitempty" form:"isbn_column" example:"ISBN"`
+		DryRun       bool   `json:"dry_run" form:"dry_run" example:"true"`
+		Async        bool   `json:"-" form:"async" example:"true"`
+	}
+	// ImportReportRes sums up an import. In a dry run nothing is written and
+	// inserted counts the rows that would have been inserted.
+	ImportReportRes struct {
+		DryRun   bool           `json:"dry_run" example:"false"`
+		Inserted int            `json:"inserted" example:"1"`
+		Skipped  int            `json:"skipped" example:"0"`
+		Failed   int            `json:"failed" example:"1"`
+		Rows     []ImportRowRes `json:"rows"`
+	}
+	// ImportRowRes is the outcome of the CSV record starting at Line. A
+	// skipped row has an ISBN that is already in the catalog.
+	ImportRowRes struct {
+		Line   int             `json:"line" example:"2"`
+		Status string          `json:"status" example:"inserted" enums:"inserted,skipped,failed"`
+		ID     int             `json:"id,omitempty" example:"1"`
+		Error  *util.HTTPError `json:"error,omitempty"`
+	}
+)
+
+func (q ImportBooksReq) columns() csvColumns {
+	return csvColumns{Title: q.TitleColumn, Author: q.AuthorColumn, ISBN: q.ISBNColumn}
+}
+
+func newImportReportRes(report domain.ImportReport) ImportReportRes {
+	res := ImportReportRes{
+		DryRun:   report.DryRun,
+		Inserted: report.Inserted,
+		Skipped:  report.Skipped,
+		Failed:   report.Failed,
+		Rows:     make([]ImportRowRes, len(report.Rows)),
+	}
+	for i, row := range report.Rows {
+		res.Rows[i] = ImportRowRes{Line: row.Line, Status: string(row.Status), ID: row.BookID}
+		if row.Err != nil {
+			res.Rows[i].Error = itemError(row.Err)
+		}
+	}
+	return res
+}
+
+// importFileField is the multipart field that holds the CSV file.
+const importFileField = "file"
+
+type ImportHandler struct {
+	importService in.BookImportUseCase
+	jobService    in.JobUseCase
+}
+
+func NewImportHandler(importService in.BookImportUseCase, jobService in.JobUseCase) *ImportHandler {
+	return &ImportHandler{importService: importService, jobService: jobService}
+}
+
+// ImportBooks godoc
+// @Summary      Import books from CSV
+// @Description  Import books from a CSV file uploaded in the multipart field "file". The first record is the header; title_column, author_column and isbn_column name the columns to read, ignoring case, and an "isbn" column is read when isbn_column is not given. When the header has an "author_ids" column, as in GET /books/export, a record with IDs in it (separated by ";") is linked to those authors instead of its author name. Each record is validated like POST /books. Records are written in transactions of 500 as the file streams in, so a failed record never holds back the others. Records whose ISBN is already in the catalog are skipped. With dry_run=true nothing is written. With async=true the file is stored and imported by a background job instead: the response is 202 with the job, whose result holds the counts and whose artifact is the full report.
+// @Tags         books
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file  formData  file  true  "CSV file"
+// @Param        title_column  query  string  false  "Header of the title column (default title)"
+// @Param        author_column  query  string  false  "Header of the author column (default author)"
+// @Param        isbn_column  query  string  false  "Header of the ISBN column"
+// @Param        dry_run  query  bool  false  "Validate without writing"
+// @Param        async  query  bool  false  "Import in a background job"
+// @Success      200  {object}  ImportReportRes
+// @Success      202  {object}  JobRes
+// @Header       202  {string}  Location  "URL of the job, /jobs/{id}"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      415  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /books/import [post]
+func (h *ImportHandler) ImportBooks(c *gin.Context) {
+	var query ImportBooksReq
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	// Read the upload as it arrives instead of parsing the whole form.
+	mr, err := c.Request.MultipartReader()
+	if err != nil {
+		c.Error(&util.RequestError{
+			Status: http.StatusUnsupportedMediaType,
+			Code:   constant.ErrUnsupportedMediaTypeCode,
+			Err:    errors.New("content type must be multipart/form-data"),
+		})
+		return
+	}
+	file, err := nextFilePart(mr, importFileField)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer file.Close()
+
+	if query.Async {
+		params, err := json.Marshal(query)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		job, err := h.jobService.SubmitJob(c.Request.Context(), domain.JobBookImport, params, file)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		writeJobAccepted(c, job)
+		return
+	}
+
+	rows, err := newCSVBookReader(file, query.columns())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	report, err := h.importService.ImportBooks(c.Request.Context(), rows, query.DryRun)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newImportReportRes(report))
+}
+
+// nextFilePart skips to the part of mr named field.
+func nextFilePart(mr *multipart.Reader, field string) (*multipart.Part, error) {
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, util.InvalidField(field, "required", field+" is required")
+		}
+		if err != nil {
+			return nil, util.BadRequest(err)
+		}
+		if part.FormName() == field {
+			return part, nil
+		}
+		part.Close()
+	}
+}
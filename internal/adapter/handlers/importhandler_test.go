@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+// multipartBody returns a multipart form with the given file field and its
+// content type.
+func multipartBody(t *testing.T, field, content string) (*bytes.Buffer, string) {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("note", "ignored"); err != nil {
+		t.Fatal(err)
+	}
+	fw, err := w.CreateFormFile(field, "books.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &body, w.FormDataContentType()
+}
+
+// readAllRows matches any BookRowReader and drains it into rows.
+type readAllRows struct {
+	rows *[]domain.ImportRow
+}
+
+func (m readAllRows) Matches(x any) bool {
+	r, ok := x.(*csvBookReader)
+	if !ok {
+		return false
+	}
+	for {
+		row, err := r.Read()
+		if err != nil {
+			return true
+		}
+		*m.rows = append(*m.rows, row)
+	}
+}
+
+func (m readAllRows) String() string { return "is a csv book reader" }
+
+func TestImportHandler_ImportBooks(t *testing.T) {
+	report := domain.ImportReport{
+		Inserted: 1,
+		Failed:   1,
+		Rows: []domain.ImportRowResult{
+			{Line: 2, Status: domain.ImportInserted, BookID: 7},
+			{Line: 3, Status: domain.ImportFailed, Err: domain.ErrTitleRequired},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		field       string
+		csv         string
+		contentType string
+		setup       func(*mocks.MockBookImportUseCase, *[]domain.ImportRow)
+		wantStatus  int
+		wantRows    []domain.ImportRow
+		wantRes     *ImportReportRes
+	}{
+		{
+			name:  "success",
+			field: "file",
+			csv:   "title,author\nAnimal Farm,George Orwell\n,George Orwell\n",
+			setup: func(m *mocks.MockBookImportUseCase, rows *[]domain.ImportRow) {
+				m.EXPECT().ImportBooks(gomock.Any(), readAllRows{rows}, false).Return(report, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantRows: []domain.ImportRow{
+				{Line: 2, Book: domain.Book{Title: "Animal Farm", Author: "George Orwell"}},
+				{Line: 3, Book: domain.Book{Author: "George Orwell"}},
+			},
+			wantRes: &ImportReportRes{
+				Inserted: 1,
+				Failed:   1,
+				Rows: []ImportRowRes{
+					{Line: 2, Status: "inserted", ID: 7},
+					{Line: 3, Status: "failed", Error: itemError(domain.ErrTitleRequired)},
+				},
+			},
+		},
+		{
+			name:  "mapped columns and dry run",
+			query: "?title_column=Name&author_column=Writer&isbn_column=Code&dry_run=true",
+			field: "file",
+			csv:   "Name,Writer,Code\n1984,George Orwell,9780743273565\n",
+			setup: func(m *mocks.MockBookImportUseCase, rows *[]domain.ImportRow) {
+				m.EXPECT().ImportBooks(gomock.Any(), readAllRows{rows}, true).Return(domain.ImportReport{DryRun: true}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantRows: []domain.ImportRow{
+				{Line: 2, Book: domain.Book{Title: "1984", Author: "George Orwell", ISBN: "9780743273565"}},
+			},
+		},
+		{
+			name:       "missing column",
+			query:      "?title_column=Name",
+			field:      "file",
+			csv:        "title,author\n",
+			setup:      func(m *mocks.MockBookImportUseCase, rows *[]domain.ImportRow) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "missing file",
+			field:      "upload",
+			csv:        "title,author\n",
+			setup:      func(m *mocks.MockBookImportUseCase, rows *[]domain.ImportRow) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "not multipart",
+			contentType: "text/csv",
+			csv:         "title,author\n",
+			setup:       func(m *mocks.MockBookImportUseCase, rows *[]domain.ImportRow) {},
+			wantStatus:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:  "service error",
+			field: "file",
+			csv:   "title,author\nAnimal Farm,George Orwell\n",
+			setup: func(m *mocks.MockBookImportUseCase, rows *[]domain.ImportRow) {
+				m.EXPECT().ImportBooks(gomock.Any(), gomock.Any(), false).Return(domain.ImportReport{}, errors.New("service error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var rows []domain.ImportRow
+			mockService := mocks.NewMockBookImportUseCase(ctrl)
+			tt.setup(mockService, &rows)
+
+			h := NewImportHandler(mockService, mocks.NewMockJobUseCase(ctrl))
+
+			r := setupTestRouter()
+			r.POST("/books/import", h.ImportBooks)
+
+			var body *bytes.Buffer
+			contentType := tt.contentType
+			if contentType == "" {
+				body, contentType = multipartBody(t, tt.field, tt.csv)
+			} else {
+				body = bytes.NewBufferString(tt.csv)
+			}
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/books/import"+tt.query, body)
+			req.Header.Set("Content-Type", contentType)
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("ImportBooks() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantRows != nil && !reflect.DeepEqual(rows, tt.wantRows) {
+				t.Errorf("ImportBooks() read rows %+v, want %+v", rows, tt.wantRows)
+			}
+			if tt.wantRes != nil {
+				var res ImportReportRes
+				if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if !reflect.DeepEqual(res, *tt.wantRes) {
+					t.Errorf("ImportBooks() = %+v, want %+v", res, *tt.wantRes)
+				}
+			}
+		})
+	}
+}
+
+func TestImportHandler_ImportBooksAsync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockJobs := mocks.NewMockJobUseCase(ctrl)
+	mockJobs.EXPECT().SubmitJob(gomock.Any(), domain.JobBookImport,
+		json.RawMessage(`{"title_column":"name","author_column":"author","dry_run":true}`), gomock.Any()).
+		DoAndReturn(func(_ context.Context, kind domain.JobKind, params json.RawMessage, input io.Reader) (domain.Job, error) {
+			data, err := io.ReadAll(input)
+			if err != nil || string(data) != "name,author\nAnimal Farm,George Orwell\n" {
+				t.Errorf("SubmitJob() input = %q, %v", data, err)
+			}
+			return domain.Job{ID: 7, Kind: kind, Status: domain.JobQueued, Params: params, MaxAttempts: 3, RunAt: created, CreatedAt: created, UpdatedAt: created}, nil
+		})
+
+	h := NewImportHandler(mocks.NewMockBookImportUseCase(ctrl), mockJobs)
+
+	r := setupTestRouter()
+	r.POST("/books/import", h.ImportBooks)
+
+	body, contentType := multipartBody(t, "file", "name,author\nAnimal Farm,George Orwell\n")
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/books/import?async=true&dry_run=true&title_column=name", body)
+	req.Header.Set("Content-Type", contentType)
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("ImportBooks() status = %v, want %v: %s", w.Code, http.StatusAccepted, w.Body.String())
+	}
+	if got := w.Header().Get("Location"); got != "/jobs/7" {
+		t.Errorf("ImportBooks() Location = %q, want /jobs/7", got)
+	}
+	var res JobRes
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if res.ID != 7 || res.Kind != "book_import" || res.Status != "queued" {
+		t.Errorf("ImportBooks() = %+v, want queued book_import job 7", res)
+	}
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/internal/http/util"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobRes reports a background job. processed counts the items handled by the
+// current or last attempt, and error holds the failure of the last attempt.
+// A queued job runs at run_at. artifact_url is set once the job has produced
+// a file.
+type JobRes struct {
+	ID          int             `json:"id" example:"1"`
+	Kind        string          `json:"kind" example:"book_import" enums:"book_import,book_export"`
+	Status      string          `json:"status" example:"running" enums:"queued,running,succeeded,failed"`
+	Processed   int             `json:"processed" example:"1500"`
+	Attempts    int             `json:"attempts" example:"1"`
+	MaxAttempts int             `json:"max_attempts" example:"3"`
+	Result      json.RawMessage `json:"result,omitempty" swaggertype:"object"`
+	Error       string          `json:"error,omitempty" example:"connection reset by peer"`
+	ArtifactURL string          `json:"artifact_url,omitempty" example:"/jobs/1/artifact"`
+	RunAt       string          `json:"run_at" example:"2025-01-01T00:00:00Z"`
+	CreatedAt   string          `json:"created_at" example:"2025-01-01T00:00:00Z"`
+	UpdatedAt   string          `json:"updated_at" example:"2025-01-01T00:00:00Z"`
+	FinishedAt  *string         `json:"finished_at,omitempty" example:"2025-01-01T00:01:00Z"`
+}
+
+func newJobRes(job domain.Job) JobRes {
+	res := JobRes{
+		ID:          job.ID,
+		Kind:        string(job.Kind),
+		Status:      string(job.Status),
+		Processed:   job.Processed,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		Result:      job.Result,
+		Error:       job.Error,
+		RunAt:       job.RunAt.UTC().Format(time.RFC3339),
+		CreatedAt:   job.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:   job.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if job.Status == domain.JobSucceeded && job.Artifact != nil {
+		res.ArtifactURL = jobPath(job.ID) + "/artifact"
+	}
+	if job.FinishedAt != nil {
+		finishedAt := job.FinishedAt.UTC().Format(time.RFC3339)
+		res.FinishedAt = &finishedAt
+	}
+	return res
+}
+
+func jobPath(id int) string {
+	return fmt.Sprintf("/jobs/%d", id)
+}
+
+// writeJobAccepted responds to the request that queued job.
+func writeJobAccepted(c *gin.Context, job domain.Job) {
+	c.Header("Location", jobPath(job.ID))
+	c.JSON(http.StatusAccepted, newJobRes(job))
+}
+
+type JobHandler struct {
+	jobService in.JobUseCase
+}
+
+func NewJobHandler(jobService in.JobUseCase) *JobHandler {
+	return &JobHandler{jobService: jobService}
+}
+
+// GetJob godoc
+// @Summary      Get a job
+// @Description  Get the status and progress of a background job. Once it has succeeded, result holds a kind-specific summary (book_import: dry_run, inserted, skipped and failed; book_export: format and books) and artifact_url the file it produced.
+// @Tags         jobs
+// @Produce      json
+// @Param        id  path  int  true  "Job ID"
+// @Success      200  {object}  JobRes
+// @Failure      400  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /jobs/{id} [get]
+func (h *JobHandler) GetJob(c *gin.Context) {
+	type params struct {
+		ID int `uri:"id" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	job, err := h.jobService.GetJob(c.Request.Context(), p.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newJobRes(job))
+}
+
+// GetJobArtifact godoc
+// @Summary      Download a job artifact
+// @Description  Download the file a succeeded job produced: the full report of a book_import job, or the file of a book_export job.
+// @Tags         jobs
+// @Produce      octet-stream
+// @Param        id  path  int  true  "Job ID"
+// @Success      200  {file}  file
+// @Header       200  {string}  Content-Disposition  "attachment; filename of the artifact"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /jobs/{id}/artifact [get]
+func (h *JobHandler) GetJobArtifact(c *gin.Context) {
+	type params struct {
+		ID int `uri:"id" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	job, artifact, err := h.jobService.OpenJobArtifact(c.Request.Context(), p.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	// Read the first chunk before sending the headers, so a failure to load
+	// it can still be reported as JSON.
+	r := bufio.NewReader(artifact)
+	if _, err := r.Peek(1); err != nil && !errors.Is(err, io.EOF) {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", job.Artifact.ContentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", job.Artifact.Filename))
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, r); err != nil {
+		log.Printf("[JOB_ARTIFACT]: %v\n", err)
+	}
+}
+
+// ExportBooks godoc
+// @Summary      Export books in the background
+// @Description  Queue a book_export job that writes the same file as GET /books/export, taking the same parameters. The file is downloaded from the job's artifact_url once it has succeeded.
+// @Tags         books
+// @Produce      json
+// @Param        format  query  string  false  "Export format (default csv)"  Enums(csv, ndjson, json)
+// @Param        author  query  string  false  "Only books by the author with this name (case-insensitive)"
+// @Param        title_contains  query  string  false  "Only books whose title contains this text (case-insensitive)"
+// @Param        created_after  query  string  false  "Only books created after this RFC 3339 time"
+// @Param        created_before  query  string  false  "Only books created before this RFC 3339 time"
+// @Param        sort  query  string  false  "Comma-separated sort fields: id, title, author, created_at, updated_at"
+// @Success      202  {object}  JobRes
+// @Header       202  {string}  Location  "URL of the job, /jobs/{id}"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /books/export [post]
+func (h *JobHandler) ExportBooks(c *gin.Context) {
+	var query ExportBooksReq
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+	if _, err := domain.ParseBookSort(query.Sort); err != nil {
+		c.Error(err)
+		return
+	}
+
+	params, err := json.Marshal(query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	job, err := h.jobService.SubmitJob(c.Request.Context(), domain.JobBookExport, params, nil)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	writeJobAccepted(c, job)
+}
@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+var testJobTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestJobHandler_GetJob(t *testing.T) {
+	finished := testJobTime.Add(time.Minute)
+	tests := []struct {
+		name       string
+		id         string
+		setup      func(*mocks.MockJobUseCase)
+		wantStatus int
+		wantRes    *JobRes
+	}{
+		{
+			name: "succeeded with artifact",
+			id:   "1",
+			setup: func(m *mocks.MockJobUseCase) {
+				m.EXPECT().GetJob(gomock.Any(), 1).Return(domain.Job{
+					ID: 1, Kind: domain.JobBookExport, Status: domain.JobSucceeded,
+					Processed: 3, Result: json.RawMessage(`{"format":"csv","books":3}`),
+					Attempts: 1, MaxAttempts: 3, Artifact: &domain.JobArtifact{Filename: "books.csv", ContentType: "text/csv"},
+					RunAt: testJobTime, CreatedAt: testJobTime, UpdatedAt: finished, FinishedAt: &finished,
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantRes: &JobRes{
+				ID: 1, Kind: "book_export", Status: "succeeded", Processed: 3,
+				Result:   json.RawMessage(`{"format":"csv","books":3}`),
+				Attempts: 1, MaxAttempts: 3, ArtifactURL: "/jobs/1/artifact",
+				RunAt: "2025-01-01T00:00:00Z", CreatedAt: "2025-01-01T00:00:00Z", UpdatedAt: "2025-01-01T00:01:00Z",
+				FinishedAt: func() *string { s := "2025-01-01T00:01:00Z"; return &s }(),
+			},
+		},
+		{
+			name: "queued for retry",
+			id:   "2",
+			setup: func(m *mocks.MockJobUseCase) {
+				m.EXPECT().GetJob(gomock.Any(), 2).Return(domain.Job{
+					ID: 2, Kind: domain.JobBookImport, Status: domain.JobQueued, Error: "connection reset",
+					Attempts: 1, MaxAttempts: 3, RunAt: finished, CreatedAt: testJobTime, UpdatedAt: testJobTime,
+				}, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantRes: &JobRes{
+				ID: 2, Kind: "book_import", Status: "queued", Error: "connection reset",
+				Attempts: 1, MaxAttempts: 3,
+				RunAt: "2025-01-01T00:01:00Z", CreatedAt: "2025-01-01T00:00:00Z", UpdatedAt: "2025-01-01T00:00:00Z",
+			},
+		},
+		{
+			name: "not found",
+			id:   "9",
+			setup: func(m *mocks.MockJobUseCase) {
+				m.EXPECT().GetJob(gomock.Any(), 9).Return(domain.Job{}, domain.ErrJobNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "invalid id",
+			id:         "abc",
+			setup:      func(m *mocks.MockJobUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockJobUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewJobHandler(mockService)
+
+			r := setupTestRouter()
+			r.GET("/jobs/:id", h.GetJob)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/jobs/"+tt.id, nil)
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GetJob() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantRes != nil {
+				var res JobRes
+				if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if !reflect.DeepEqual(res, *tt.wantRes) {
+					t.Errorf("GetJob() = %+v, want %+v", res, *tt.wantRes)
+				}
+			}
+		})
+	}
+}
+
+func TestJobHandler_GetJobArtifact(t *testing.T) {
+	artifactJob := domain.Job{ID: 1, Status: domain.JobSucceeded, Artifact: &domain.JobArtifact{Filename: "books.csv", ContentType: "text/csv; charset=utf-8"}}
+	tests := []struct {
+		name            string
+		setup           func(*mocks.MockJobUseCase)
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name: "success",
+			setup: func(m *mocks.MockJobUseCase) {
+				m.EXPECT().OpenJobArtifact(gomock.Any(), 1).Return(artifactJob, strings.NewReader("id,title\n1,Animal Farm\n"), nil)
+			},
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/csv; charset=utf-8",
+			wantBody:        "id,title\n1,Animal Farm\n",
+		},
+		{
+			name: "no artifact",
+			setup: func(m *mocks.MockJobUseCase) {
+				m.EXPECT().OpenJobArtifact(gomock.Any(), 1).Return(domain.Job{}, nil, domain.ErrJobArtifactNotFound)
+			},
+			wantStatus:      http.StatusNotFound,
+			wantContentType: "application/json; charset=utf-8",
+		},
+		{
+			name: "read error before the first byte",
+			setup: func(m *mocks.MockJobUseCase) {
+				m.EXPECT().OpenJobArtifact(gomock.Any(), 1).Return(artifactJob, &failingReader{err: errors.New("db error")}, nil)
+			},
+			wantStatus:      http.StatusInternalServerError,
+			wantContentType: "application/json; charset=utf-8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockJobUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewJobHandler(mockService)
+
+			r := setupTestRouter()
+			r.GET("/jobs/:id/artifact", h.GetJobArtifact)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/jobs/1/artifact", nil)
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GetJobArtifact() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("GetJobArtifact() Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := w.Header().Get("Content-Disposition"); got != `attachment; filename="books.csv"` {
+				t.Errorf("GetJobArtifact() Content-Disposition = %q", got)
+			}
+			if got := w.Body.String(); got != tt.wantBody {
+				t.Errorf("GetJobArtifact() body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestJobHandler_ExportBooks(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		setup      func(*mocks.MockJobUseCase)
+		wantStatus int
+	}{
+		{
+			name:  "queued",
+			query: "?format=ndjson&author=George+Orwell&sort=-title",
+			setup: func(m *mocks.MockJobUseCase) {
+				m.EXPECT().SubmitJob(gomock.Any(), domain.JobBookExport,
+					json.RawMessage(`{"format":"ndjson","author":"George Orwell","sort":"-title"}`), nil).
+					Return(domain.Job{ID: 4, Kind: domain.JobBookExport, Status: domain.JobQueued}, nil)
+			},
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:       "unknown format",
+			query:      "?format=xml",
+			setup:      func(m *mocks.MockJobUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unsortable field",
+			query:      "?sort=isbn",
+			setup:      func(m *mocks.MockJobUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "service error",
+			query: "",
+			setup: func(m *mocks.MockJobUseCase) {
+				m.EXPECT().SubmitJob(gomock.Any(), domain.JobBookExport, gomock.Any(), nil).Return(domain.Job{}, errors.New("service error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockJobUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewJobHandler(mockService)
+
+			r := setupTestRouter()
+			r.POST("/books/export", h.ExportBooks)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/books/export"+tt.query, nil)
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("ExportBooks() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusAccepted && w.Header().Get("Location") != "/jobs/4" {
+				t.Errorf("ExportBooks() Location = %q, want /jobs/4", w.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+// failingReader fails every read with err.
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/internal/http/util"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	SearchBooksReq struct {
+		Q       string `form:"q" binding:"required" example:"orwell anim*"`
+		Mode    string `form:"mode" binding:"omitempty,oneof=fulltext fuzzy" example:"fulltext"`
+		Page    int    `form:"page,default=1" binding:"min=1" example:"1"`
+		PerPage int    `form:"per_page,default=10" binding:"min=1,max=100" example:"10"`
+	}
+	SuggestBooksReq struct {
+		Prefix string `form:"prefix" binding:"required" example:"anim"`
+		Limit  int    `form:"limit,default=10" binding:"min=1,max=20" example:"10"`
+	}
+	// BookHitRes is a book found by a search. Rank orders the hits, and the
+	// highlights are HTML with the matched words wrapped in <mark>.
+	BookHitRes struct {
+		BookRes
+		Rank      float64          `json:"rank" example:"0.6079271"`
+		Highlight BookHighlightRes `json:"highlight"`
+	}
+	BookHighlightRes struct {
+		Title  string `json:"title" example:"<mark>Animal</mark> Farm"`
+		Author string `json:"author" example:"George <mark>Orwell</mark>"`
+	}
+	// SuggestionRes completes a prefix with a book title or an author name.
+	SuggestionRes struct {
+		Text  string `json:"text" example:"Animal Farm"`
+		Field string `json:"field" example:"title" enums:"title,author"`
+	}
+)
+
+var highlightMarks = strings.NewReplacer(domain.HighlightStart, "<mark>", domain.HighlightEnd, "</mark>")
+
+// highlightHTML escapes a highlight for HTML and wraps its matched words in
+// <mark>.
+func highlightHTML(s string) string {
+	return highlightMarks.Replace(html.EscapeString(s))
+}
+
+func newBookHitRes(hit domain.BookHit) BookHitRes {
+	return BookHitRes{
+		BookRes: newBookRes(hit.Book),
+		Rank:    hit.Rank,
+		Highlight: BookHighlightRes{
+			Title:  highlightHTML(hit.TitleHighlight),
+			Author: highlightHTML(hit.AuthorHighlight),
+		},
+	}
+}
+
+type SearchHandler struct {
+	searchService in.BookSearchUseCase
+	pages         *util.Paginator
+}
+
+func NewSearchHandler(searchService in.BookSearchUseCase, pages *util.Paginator) *SearchHandler {
+	return &SearchHandler{searchService: searchService, pages: pages}
+}
+
+// SearchBooks godoc
+// @Summary      Search books
+// @Description  Full-text search over book titles and authors, most relevant first. Every word must match; "double quotes" match a phrase and a trailing * matches a prefix. With mode=fuzzy, titles and authors are matched by trigram similarity instead, which tolerates typos; rank is then the similarity between 0 and 1. With PAGINATION_STYLE=envelope the array is wrapped in a util.PageRes.
+// @Tags         books
+// @Accept       json
+// @Produce      json
+// @Param        q  query  string  true  "Search query, e.g. \"animal farm\" orw*"
+// @Param        mode  query  string  false  "Match mode"  Enums(fulltext, fuzzy)
+// @Param        page  query  int  false  "Page"
+// @Param        per_page  query  int  false  "Per Page"
+// @Success      200  {object}  []BookHitRes
+// @Header       200  {integer}  X-Total-Count  "Total number of items (headers style)"
+// @Header       200  {string}  Link  "RFC 8288 first, prev, next and last page links (headers style)"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /books/search [get]
+func (h *SearchHandler) SearchBooks(c *gin.Context) {
+	var query SearchBooksReq
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	search := h.searchService.SearchBooks
+	if query.Mode == "fuzzy" {
+		search = h.searchService.FuzzySearchBooks
+	}
+	hits, total, err := search(c.Request.Context(), query.Q, query.Page, query.PerPage)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res := make([]BookHitRes, 0, len(hits))
+	for _, hit := range hits {
+		res = append(res, newBookHitRes(hit))
+	}
+	h.pages.Write(c, res, query.Page, query.PerPage, total)
+}
+
+// SuggestBooks godoc
+// @Summary      Suggest books
+// @Description  Autocomplete: book titles and author names that start with prefix, ignoring case, shortest first.
+// @Tags         books
+// @Accept       json
+// @Produce      json
+// @Param        prefix  query  string  true  "Typed prefix"
+// @Param        limit  query  int  false  "Maximum number of suggestions (1-20)"
+// @Success      200  {object}  []SuggestionRes
+// @Failure      400  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /books/suggest [get]
+func (h *SearchHandler) SuggestBooks(c *gin.Context) {
+	var query SuggestBooksReq
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	suggestions, err := h.searchService.SuggestBooks(c.Request.Context(), query.Prefix, query.Limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res := make([]SuggestionRes, 0, len(suggestions))
+	for _, s := range suggestions {
+		res = append(res, SuggestionRes{Text: s.Text, Field: s.Field})
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearchHandler_SearchBooks(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		setup         func(*mocks.MockBookSearchUseCase)
+		wantStatus    int
+		wantHighlight BookHighlightRes
+	}{
+		{
+			name:  "success",
+			query: "?q=" + url.QueryEscape(`"animal farm" orw*`),
+			setup: func(m *mocks.MockBookSearchUseCase) {
+				m.EXPECT().SearchBooks(gomock.Any(), `"animal farm" orw*`, 1, 10).Return([]domain.BookHit{{
+					Book:            domain.Book{ID: 1, Title: "Animal <Farm>", Author: "George Orwell"},
+					Rank:            0.5,
+					TitleHighlight:  "\x02Animal\x03 <\x02Farm\x03>",
+					AuthorHighlight: "George \x02Orwell\x03",
+				}}, 1, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantHighlight: BookHighlightRes{
+				Title:  "<mark>Animal</mark> &lt;<mark>Farm</mark>&gt;",
+				Author: "George <mark>Orwell</mark>",
+			},
+		},
+		{
+			name:  "fuzzy mode",
+			query: "?q=orwel&mode=fuzzy",
+			setup: func(m *mocks.MockBookSearchUseCase) {
+				m.EXPECT().FuzzySearchBooks(gomock.Any(), "orwel", 1, 10).Return([]domain.BookHit{{
+					Book:            domain.Book{ID: 1, Title: "Animal Farm", Author: "George Orwell"},
+					Rank:            0.8,
+					TitleHighlight:  "Animal Farm",
+					AuthorHighlight: "George Orwell",
+				}}, 1, nil)
+			},
+			wantStatus:    http.StatusOK,
+			wantHighlight: BookHighlightRes{Title: "Animal Farm", Author: "George Orwell"},
+		},
+		{
+			name:       "invalid mode",
+			query:      "?q=farm&mode=regex",
+			setup:      func(m *mocks.MockBookSearchUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "missing q",
+			query:      "",
+			setup:      func(m *mocks.MockBookSearchUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "no words",
+			query: "?q=***",
+			setup: func(m *mocks.MockBookSearchUseCase) {
+				_, err := domain.ParseSearchQuery("***")
+				m.EXPECT().SearchBooks(gomock.Any(), "***", 1, 10).Return(nil, 0, err)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "service error",
+			query: "?q=farm",
+			setup: func(m *mocks.MockBookSearchUseCase) {
+				m.EXPECT().SearchBooks(gomock.Any(), "farm", 1, 10).Return(nil, 0, errors.New("service error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockBookSearchUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewSearchHandler(mockService, testPages)
+
+			r := setupTestRouter()
+			r.GET("/books/search", h.SearchBooks)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/books/search"+tt.query, nil)
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("SearchBooks() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var res []BookHitRes
+			if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if len(res) != 1 || res[0].ID != 1 || res[0].Highlight != tt.wantHighlight {
+				t.Errorf("SearchBooks() = %+v, want book 1 highlighted as %+v", res, tt.wantHighlight)
+			}
+		})
+	}
+}
+
+func TestSearchHandler_SuggestBooks(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		setup      func(*mocks.MockBookSearchUseCase)
+		wantStatus int
+		want       []SuggestionRes
+	}{
+		{
+			name:  "success",
+			query: "?prefix=ani&limit=5",
+			setup: func(m *mocks.MockBookSearchUseCase) {
+				m.EXPECT().SuggestBooks(gomock.Any(), "ani", 5).Return([]domain.Suggestion{{Text: "Animal Farm", Field: "title"}}, nil)
+			},
+			wantStatus: http.StatusOK,
+			want:       []SuggestionRes{{Text: "Animal Farm", Field: "title"}},
+		},
+		{
+			name:  "default limit",
+			query: "?prefix=zzz",
+			setup: func(m *mocks.MockBookSearchUseCase) {
+				m.EXPECT().SuggestBooks(gomock.Any(), "zzz", 10).Return([]domain.Suggestion{}, nil)
+			},
+			wantStatus: http.StatusOK,
+			want:       []SuggestionRes{},
+		},
+		{
+			name:       "missing prefix",
+			query:      "",
+			setup:      func(m *mocks.MockBookSearchUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "limit too large",
+			query:      "?prefix=ani&limit=21",
+			setup:      func(m *mocks.MockBookSearchUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "service error",
+			query: "?prefix=ani",
+			setup: func(m *mocks.MockBookSearchUseCase) {
+				m.EXPECT().SuggestBooks(gomock.Any(), "ani", 10).Return(nil, errors.New("service error"))
+			},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockBookSearchUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewSearchHandler(mockService, testPages)
+
+			r := setupTestRouter()
+			r.GET("/books/suggest", h.SuggestBooks)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/books/suggest"+tt.query, nil)
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("SuggestBooks() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var res []SuggestionRes
+			if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if !reflect.DeepEqual(res, tt.want) {
+				t.Errorf("SuggestBooks() = %+v, want %+v", res, tt.want)
+			}
+		})
+	}
+}
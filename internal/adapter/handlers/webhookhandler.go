@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/internal/http/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type (
+	// WebhookReq creates or replaces a webhook. Events lists the events it
+	// receives, every event when empty. A webhook created without a secret
+	// gets a generated one; one replaced without a secret keeps its own.
+	WebhookReq struct {
+		URL    string   `json:"url" binding:"required" example:"https://partner.example.com/hooks/books"`
+		Events []string `json:"events" example:"book.created,book.updated"`
+		Secret string   `json:"secret" example:"s3cret"`
+	}
+	// WebhookRes is a webhook. The secret is only returned when the webhook
+	// is created.
+	WebhookRes struct {
+		ID        int      `json:"id" example:"1"`
+		URL       string   `json:"url" example:"https://partner.example.com/hooks/books"`
+		Events    []string `json:"events" example:"book.created,book.updated"`
+		Secret    string   `json:"secret,omitempty" example:"s3cret"`
+		CreatedAt string   `json:"created_at" example:"2025-01-01T00:00:00Z"`
+		UpdatedAt string   `json:"updated_at" example:"2025-01-01T00:00:00Z"`
+	}
+	// WebhookDeliveryRes is an outbox message posted, or to be posted, to a
+	// webhook, with how its last attempt went. response_status is omitted
+	// when no response came back.
+	WebhookDeliveryRes struct {
+		ID             int64           `json:"id" example:"1"`
+		MessageID      int64           `json:"message_id" example:"7"`
+		Event          string          `json:"event" example:"book.created"`
+		Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+		Status         string          `json:"status" example:"pending" enums:"pending,succeeded,failed"`
+		Attempts       int             `json:"attempts" example:"1"`
+		ResponseStatus int             `json:"response_status,omitempty" example:"500"`
+		Error          string          `json:"error,omitempty" example:"webhook responded 500 Internal Server Error"`
+		NextAttemptAt  *string         `json:"next_attempt_at,omitempty" example:"2025-01-01T00:00:10Z"`
+		CreatedAt      string          `json:"created_at" example:"2025-01-01T00:00:00Z"`
+		DeliveredAt    *string         `json:"delivered_at,omitempty" example:"2025-01-01T00:00:00Z"`
+	}
+)
+
+func newWebhookRes(webhook domain.Webhook) WebhookRes {
+	events := webhook.Events
+	if events == nil {
+		events = []string{}
+	}
+	return WebhookRes{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    events,
+		CreatedAt: webhook.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: webhook.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func newWebhookDeliveryRes(delivery domain.WebhookDelivery) WebhookDeliveryRes {
+	res := WebhookDeliveryRes{
+		ID:             delivery.ID,
+		MessageID:      delivery.MessageID,
+		Event:          delivery.Event,
+		Payload:        delivery.Payload,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		Error:          delivery.Error,
+		CreatedAt:      delivery.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if delivery.Status == domain.DeliveryPending {
+		next := delivery.NextAttemptAt.UTC().Format(time.RFC3339)
+		res.NextAttemptAt = &next
+	}
+	if delivery.DeliveredAt != nil {
+		delivered := delivery.DeliveredAt.UTC().Format(time.RFC3339)
+		res.DeliveredAt = &delivered
+	}
+	return res
+}
+
+type WebhookHandler struct {
+	webhookService in.WebhookUseCase
+	pages          *util.Paginator
+}
+
+func NewWebhookHandler(webhookService in.WebhookUseCase, pages *util.Paginator) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService, pages: pages}
+}
+
+// CreateWebhook godoc
+// @Summary      Create a webhook
+// @Description  Subscribe a URL to book events. Every delivery is a POST of the outbox message as JSON, signed in the X-Signature header with "sha256=" and the hex HMAC-SHA256, keyed with the secret, of the X-Webhook-Timestamp value, a dot and the body. The URL may not name localhost or a loopback, link-local or private address unless WEBHOOK_ALLOW_PRIVATE is set. Requires the admin token.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        request  body  WebhookReq  true  "Create webhook"
+// @Success      201  {object}  WebhookRes
+// @Header       201  {string}  Location  "/webhooks/{id}"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      401  {object}  util.HTTPError
+// @Failure      403  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var json WebhookReq
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	created, err := h.webhookService.CreateWebhook(c.Request.Context(), domain.Webhook{
+		URL:    json.URL,
+		Events: json.Events,
+		Secret: json.Secret,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res := newWebhookRes(created)
+	res.Secret = created.Secret
+	c.Header("Location", fmt.Sprintf("/webhooks/%d", created.ID))
+	c.JSON(http.StatusCreated, res)
+}
+
+// GetWebhook godoc
+// @Summary      Get a webhook
+// @Description  Get a webhook, without its secret. Requires the admin token.
+// @Tags         webhooks
+// @Produce      json
+// @Security     AdminToken
+// @Param        id  path  int  true  "Webhook ID"
+// @Success      200  {object}  WebhookRes
+// @Failure      400  {object}  util.HTTPError
+// @Failure      401  {object}  util.HTTPError
+// @Failure      403  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	type params struct {
+		ID int `uri:"id" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhook(c.Request.Context(), p.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newWebhookRes(webhook))
+}
+
+// GetWebhooks godoc
+// @Summary      Get webhooks
+// @Description  Get webhooks, without their secrets. With PAGINATION_STYLE=envelope the array is wrapped in a util.PageRes. Requires the admin token.
+// @Tags         webhooks
+// @Produce      json
+// @Security     AdminToken
+// @Param        page  query  int  false  "Page"
+// @Param        per_page  query  int  false  "Per Page"
+// @Success      200  {object}  []WebhookRes
+// @Header       200  {integer}  X-Total-Count  "Total number of items (headers style)"
+// @Header       200  {string}  Link  "RFC 8288 first, prev, next and last page links (headers style)"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      401  {object}  util.HTTPError
+// @Failure      403  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	var query PageReq
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	webhooks, total, err := h.webhookService.GetWebhooks(c.Request.Context(), query.Page, query.PerPage)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res := make([]WebhookRes, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		res = append(res, newWebhookRes(webhook))
+	}
+	h.pages.Write(c, res, query.Page, query.PerPage, total)
+}
+
+// UpdateWebhook godoc
+// @Summary      Update a webhook
+// @Description  Replace the URL and events of a webhook, and its secret when one is given. Deliveries already queued go to the new URL. Requires the admin token.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     AdminToken
+// @Param        id  path  int  true  "Webhook ID"
+// @Param        request  body  WebhookReq  true  "Update webhook"
+// @Success      200  {object}  WebhookRes
+// @Failure      400  {object}  util.HTTPError
+// @Failure      401  {object}  util.HTTPError
+// @Failure      403  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	type params struct {
+		ID int `uri:"id" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	var json WebhookReq
+	if err := c.ShouldBindJSON(&json); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	updated, err := h.webhookService.UpdateWebhook(c.Request.Context(), domain.Webhook{
+		ID:     p.ID,
+		URL:    json.URL,
+		Events: json.Events,
+		Secret: json.Secret,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newWebhookRes(updated))
+}
+
+// DeleteWebhook godoc
+// @Summary      Delete a webhook
+// @Description  Delete a webhook along with its deliveries, including the ones still pending. Requires the admin token.
+// @Tags         webhooks
+// @Produce      json
+// @Security     AdminToken
+// @Param        id  path  int  true  "Webhook ID"
+// @Success      204  {object}  nil
+// @Failure      400  {object}  util.HTTPError
+// @Failure      401  {object}  util.HTTPError
+// @Failure      403  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	type params struct {
+		ID int `uri:"id" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), p.ID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries godoc
+// @Summary      Get a webhook's deliveries
+// @Description  List the deliveries to a webhook, latest first, with the body posted, the number of attempts, the response status and error of the last attempt, and when the next attempt is due. With PAGINATION_STYLE=envelope the array is wrapped in a util.PageRes. Requires the admin token.
+// @Tags         webhooks
+// @Produce      json
+// @Security     AdminToken
+// @Param        id  path  int  true  "Webhook ID"
+// @Param        page  query  int  false  "Page"
+// @Param        per_page  query  int  false  "Per Page"
+// @Success      200  {object}  []WebhookDeliveryRes
+// @Header       200  {integer}  X-Total-Count  "Total number of items (headers style)"
+// @Header       200  {string}  Link  "RFC 8288 first, prev, next and last page links (headers style)"
+// @Failure      400  {object}  util.HTTPError
+// @Failure      401  {object}  util.HTTPError
+// @Failure      403  {object}  util.HTTPError
+// @Failure      404  {object}  util.HTTPError
+// @Failure      500  {object}  util.HTTPError
+// @Router       /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	type params struct {
+		ID int `uri:"id" binding:"required"`
+	}
+	var p params
+	if err := c.ShouldBindUri(&p); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	var query PageReq
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(util.BadRequest(err))
+		return
+	}
+
+	deliveries, total, err := h.webhookService.GetWebhookDeliveries(c.Request.Context(), p.ID, query.Page, query.PerPage)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res := make([]WebhookDeliveryRes, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		res = append(res, newWebhookDeliveryRes(delivery))
+	}
+	h.pages.Write(c, res, query.Page, query.PerPage, total)
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"encoding/json"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestWebhookHandler_CreateWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		setup      func(*mocks.MockWebhookUseCase)
+		wantStatus int
+		wantRes    *WebhookRes
+	}{
+		{
+			name: "success returns the secret",
+			body: `{"url":"https://example.com/hooks","events":["book.created"]}`,
+			setup: func(m *mocks.MockWebhookUseCase) {
+				m.EXPECT().CreateWebhook(gomock.Any(), domain.Webhook{URL: "https://example.com/hooks", Events: []string{"book.created"}}).
+					Return(domain.Webhook{ID: 1, URL: "https://example.com/hooks", Events: []string{"book.created"}, Secret: "generated", CreatedAt: testJobTime, UpdatedAt: testJobTime}, nil)
+			},
+			wantStatus: http.StatusCreated,
+			wantRes: &WebhookRes{ID: 1, URL: "https://example.com/hooks", Events: []string{"book.created"}, Secret: "generated",
+				CreatedAt: "2025-01-01T00:00:00Z", UpdatedAt: "2025-01-01T00:00:00Z"},
+		},
+		{
+			name: "invalid webhook",
+			body: `{"url":"example.com","events":["author.created"]}`,
+			setup: func(m *mocks.MockWebhookUseCase) {
+				m.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Return(domain.Webhook{}, &domain.ValidationError{Fields: []domain.FieldError{
+					{Field: "url", Rule: "url", Err: domain.ErrWebhookURLInvalid},
+				}})
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "missing url",
+			body:       `{"events":["book.created"]}`,
+			setup:      func(m *mocks.MockWebhookUseCase) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockWebhookUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewWebhookHandler(mockService, testPages)
+
+			r := setupTestRouter()
+			r.POST("/webhooks", h.CreateWebhook)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("CreateWebhook() status = %v, want %v: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantRes != nil {
+				var res WebhookRes
+				if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if !reflect.DeepEqual(res, *tt.wantRes) {
+					t.Errorf("CreateWebhook() = %+v, want %+v", res, *tt.wantRes)
+				}
+				if got := w.Header().Get("Location"); got != "/webhooks/1" {
+					t.Errorf("CreateWebhook() Location = %q, want /webhooks/1", got)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_GetWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockWebhookUseCase(ctrl)
+	mockService.EXPECT().GetWebhook(gomock.Any(), 1).
+		Return(domain.Webhook{ID: 1, URL: "https://example.com/hooks", Secret: "s3cret", CreatedAt: testJobTime, UpdatedAt: testJobTime}, nil)
+
+	h := NewWebhookHandler(mockService, testPages)
+
+	r := setupTestRouter()
+	r.GET("/webhooks/:id", h.GetWebhook)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/webhooks/1", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetWebhook() status = %v, want %v", w.Code, http.StatusOK)
+	}
+	want := `{"id":1,"url":"https://example.com/hooks","events":[],"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}`
+	if got := w.Body.String(); got != want {
+		t.Errorf("GetWebhook() = %s, want %s", got, want)
+	}
+}
+
+func TestWebhookHandler_GetWebhookDeliveries(t *testing.T) {
+	delivered := testJobTime.Add(1e9)
+	tests := []struct {
+		name       string
+		url        string
+		setup      func(*mocks.MockWebhookUseCase)
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "success",
+			url:  "/webhooks/1/deliveries?per_page=2",
+			setup: func(m *mocks.MockWebhookUseCase) {
+				m.EXPECT().GetWebhookDeliveries(gomock.Any(), 1, 1, 2).Return([]domain.WebhookDelivery{
+					{ID: 2, WebhookID: 1, MessageID: 8, Event: "book.updated", Payload: json.RawMessage(`{"id":8}`), Status: domain.DeliveryPending,
+						Attempts: 1, ResponseStatus: 500, Error: "webhook responded 500 Internal Server Error", NextAttemptAt: testJobTime, CreatedAt: testJobTime},
+					{ID: 1, WebhookID: 1, MessageID: 7, Event: "book.created", Payload: json.RawMessage(`{"id":7}`), Status: domain.DeliverySucceeded,
+						Attempts: 1, ResponseStatus: 204, NextAttemptAt: testJobTime, CreatedAt: testJobTime, DeliveredAt: &delivered},
+				}, 2, nil)
+			},
+			wantStatus: http.StatusOK,
+			wantBody: `[{"id":2,"message_id":8,"event":"book.updated","payload":{"id":8},"status":"pending","attempts":1,"response_status":500,"error":"webhook responded 500 Internal Server Error","next_attempt_at":"2025-01-01T00:00:00Z","created_at":"2025-01-01T00:00:00Z"},` +
+				`{"id":1,"message_id":7,"event":"book.created","payload":{"id":7},"status":"succeeded","attempts":1,"response_status":204,"created_at":"2025-01-01T00:00:00Z","delivered_at":"2025-01-01T00:00:01Z"}]`,
+		},
+		{
+			name: "unknown webhook",
+			url:  "/webhooks/9/deliveries",
+			setup: func(m *mocks.MockWebhookUseCase) {
+				m.EXPECT().GetWebhookDeliveries(gomock.Any(), 9, 1, 10).Return(nil, 0, domain.ErrWebhookNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockWebhookUseCase(ctrl)
+			tt.setup(mockService)
+
+			h := NewWebhookHandler(mockService, testPages)
+
+			r := setupTestRouter()
+			r.GET("/webhooks/:id/deliveries", h.GetWebhookDeliveries)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("GetWebhookDeliveries() status = %v, want %v", w.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("GetWebhookDeliveries() = %s, want %s", w.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
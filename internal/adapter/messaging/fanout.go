@@ -0,0 +1,23 @@
+package messaging
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+)
+
+// Fanout is an out.MessagePublisher publishing every message to each of its
+// publishers in turn. It fails with the first publisher that fails, and the
+// publishers before it get the message again when it is retried.
+type Fanout []out.MessagePublisher
+
+var _ out.MessagePublisher = Fanout{}
+
+func (f Fanout) Publish(ctx context.Context, msg domain.OutboxMessage) error {
+	for _, p := range f {
+		if err := p.Publish(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
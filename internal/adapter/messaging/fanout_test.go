@@ -0,0 +1,25 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"testing"
+)
+
+func TestFanout(t *testing.T) {
+	msg := domain.OutboxMessage{ID: 1, Type: "book.created", Key: "book:1"}
+	errBroker := errors.New("broker unavailable")
+	first, second, third := NewMemoryPublisher(), NewMemoryPublisher(), NewMemoryPublisher()
+	second.FailWith(errBroker)
+
+	if err := (Fanout{first, second, third}).Publish(context.Background(), msg); !errors.Is(err, errBroker) {
+		t.Fatalf("Fanout.Publish() error = %v, want %v", err, errBroker)
+	}
+	if got := len(first.Messages()); got != 1 {
+		t.Errorf("first publisher got %d message(s), want 1", got)
+	}
+	if got := len(third.Messages()); got != 0 {
+		t.Errorf("publisher after the failing one got %d message(s), want 0", got)
+	}
+}
@@ -0,0 +1,46 @@
+package messaging
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+	"sync"
+)
+
+// MemoryPublisher is an out.MessagePublisher keeping the messages published
+// to it in memory, so the relay can be exercised without a broker.
+type MemoryPublisher struct {
+	mu       sync.Mutex
+	messages []domain.OutboxMessage
+	err      error
+}
+
+var _ out.MessagePublisher = &MemoryPublisher{}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, msg domain.OutboxMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+// Messages returns the messages published so far, in the order they were.
+func (p *MemoryPublisher) Messages() []domain.OutboxMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]domain.OutboxMessage{}, p.messages...)
+}
+
+// FailWith makes Publish fail with err, or succeed again when err is nil.
+func (p *MemoryPublisher) FailWith(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
@@ -0,0 +1,32 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func TestMemoryPublisher(t *testing.T) {
+	p := NewMemoryPublisher()
+	first := domain.OutboxMessage{ID: 1, Type: "book.created", Key: "book:1"}
+	second := domain.OutboxMessage{ID: 2, Type: "book.deleted", Key: "book:1"}
+	errBroker := errors.New("broker unavailable")
+
+	if err := p.Publish(context.Background(), first); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	p.FailWith(errBroker)
+	if err := p.Publish(context.Background(), second); !errors.Is(err, errBroker) {
+		t.Fatalf("Publish() error = %v, want %v", err, errBroker)
+	}
+	p.FailWith(nil)
+	if err := p.Publish(context.Background(), second); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if got, want := p.Messages(), []domain.OutboxMessage{first, second}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Messages() = %+v, want %+v", got, want)
+	}
+}
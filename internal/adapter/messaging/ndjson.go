@@ -0,0 +1,50 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+	"io"
+	"os"
+	"sync"
+)
+
+// NDJSONPublisher is an out.MessagePublisher writing every message as a line
+// of JSON, to stdout or a file, for local runs and tools such as jq.
+type NDJSONPublisher struct {
+	mu  sync.Mutex
+	w   io.Writer
+	enc *json.Encoder
+}
+
+var _ out.MessagePublisher = &NDJSONPublisher{}
+
+func NewNDJSONPublisher(w io.Writer) *NDJSONPublisher {
+	return &NDJSONPublisher{w: w, enc: json.NewEncoder(w)}
+}
+
+// OpenNDJSONPublisher returns a publisher appending to the file at path,
+// which is created when missing. Close closes the file.
+func OpenNDJSONPublisher(path string) (*NDJSONPublisher, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return NewNDJSONPublisher(f), nil
+}
+
+// Publish writes msg in a single write, so lines are never interleaved.
+func (p *NDJSONPublisher) Publish(ctx context.Context, msg domain.OutboxMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.enc.Encode(msg)
+}
+
+// Close closes the writer, unless it is stdout or cannot be closed.
+func (p *NDJSONPublisher) Close() error {
+	if c, ok := p.w.(io.Closer); ok && p.w != os.Stdout {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"go-api-boilerplate/internal/domain"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNDJSONPublisher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.ndjson")
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	messages := []domain.OutboxMessage{
+		{ID: 1, Type: "book.created", Key: "book:1", Payload: json.RawMessage(`{"book":{"id":1}}`), CreatedAt: createdAt, Attempts: 2, Error: "broker unavailable"},
+		{ID: 2, Type: "book.deleted", Key: "book:1", Payload: json.RawMessage(`{"book":{"id":1}}`), CreatedAt: createdAt},
+	}
+
+	// Appends to what the file already holds.
+	for _, msg := range messages {
+		p, err := OpenNDJSONPublisher(path)
+		if err != nil {
+			t.Fatalf("OpenNDJSONPublisher() error = %v", err)
+		}
+		if err := p.Publish(context.Background(), msg); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		if err := p.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		`{"id":1,"type":"book.created","key":"book:1","payload":{"book":{"id":1}},"created_at":"2025-01-01T00:00:00Z"}`,
+		`{"id":2,"type":"book.deleted","key":"book:1","payload":{"book":{"id":1}},"created_at":"2025-01-01T00:00:00Z"}`,
+	}, "\n") + "\n"
+	if string(got) != want {
+		t.Errorf("file = %s, want %s", got, want)
+	}
+}
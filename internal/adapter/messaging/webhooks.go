@@ -0,0 +1,25 @@
+package messaging
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+)
+
+// WebhookPublisher is an out.MessagePublisher queueing every message for the
+// webhooks subscribed to it. Run by the outbox relay, it queues them in the
+// transaction that takes the message out of the outbox.
+type WebhookPublisher struct {
+	webhooks in.WebhookUseCase
+}
+
+var _ out.MessagePublisher = &WebhookPublisher{}
+
+func NewWebhookPublisher(webhooks in.WebhookUseCase) *WebhookPublisher {
+	return &WebhookPublisher{webhooks: webhooks}
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, msg domain.OutboxMessage) error {
+	return p.webhooks.EnqueueWebhookDeliveries(ctx, msg)
+}
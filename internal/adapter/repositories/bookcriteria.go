@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"fmt"
+	"go-api-boilerplate/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bookSortColumns maps domain.BookSortFields to their columns. Sort fields
+// only reach SQL through this map.
+var bookSortColumns = map[string]string{
+	"id":         "id",
+	"title":      "title",
+	"author":     "author",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// queryArgs collects the arguments of a query as it is built.
+type queryArgs []any
+
+// add appends v and returns its placeholder.
+func (a *queryArgs) add(v any) string {
+	*a = append(*a, v)
+	return "$" + strconv.Itoa(len(*a))
+}
+
+// bookFilter returns the condition selecting the live books that match c.
+func bookFilter(c domain.BookCriteria, args *queryArgs) string {
+	return filterBooks(c, args, "id IN (SELECT ba.book_id FROM book_authors ba JOIN authors a ON a.id = ba.author_id WHERE lower(a.name) = lower(%s))")
+}
+
+// filterBooks returns the condition selecting the live books that match c
+// from a relation with the columns of books. authorCond is the condition
+// matching an author name, with %s for its placeholder.
+func filterBooks(c domain.BookCriteria, args *queryArgs, authorCond string) string {
+	conds := []string{"deleted_at IS NULL"}
+	if c.Author != "" {
+		conds = append(conds, fmt.Sprintf(authorCond, args.add(c.Author)))
+	}
+	if c.TitleContains != "" {
+		conds = append(conds, "title ILIKE '%' || "+args.add(likeEscaper.Replace(c.TitleContains))+" || '%'")
+	}
+	if !c.CreatedAfter.IsZero() {
+		conds = append(conds, "created_at > "+args.add(c.CreatedAfter))
+	}
+	if !c.CreatedBefore.IsZero() {
+		conds = append(conds, "created_at < "+args.add(c.CreatedBefore))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// bookOrder returns the ORDER BY list for sort, or for its reverse.
+func bookOrder(sort domain.BookSort, reverse bool) string {
+	keys := sort.Keys()
+	parts := make([]string, len(keys))
+	for i, f := range keys {
+		dir := "ASC"
+		if f.Desc != reverse {
+			dir = "DESC"
+		}
+		parts[i] = bookSortColumns[f.Field] + " " + dir
+	}
+	return strings.Join(parts, ", ")
+}
+
+// bookKeyset returns the condition selecting the books after the cursor in
+// sort order, or before it for a backward cursor. For keys a, b it reads
+// (a > $1 OR (a = $1 AND b > $2)), with < for descending keys.
+func bookKeyset(sort domain.BookSort, cursor domain.Cursor, args *queryArgs) (string, error) {
+	keys := sort.Keys()
+	if len(cursor.Key) != len(keys) {
+		return "", domain.ErrInvalidCursor
+	}
+	placeholders := make([]string, len(keys))
+	for i, f := range keys {
+		v, err := bookSortValue(f.Field, cursor.Key[i])
+		if err != nil {
+			return "", domain.ErrInvalidCursor
+		}
+		placeholders[i] = args.add(v)
+	}
+
+	terms := make([]string, len(keys))
+	for i, f := range keys {
+		conds := make([]string, 0, i+1)
+		for j := range i {
+			conds = append(conds, bookSortColumns[keys[j].Field]+" = "+placeholders[j])
+		}
+		op := ">"
+		if f.Desc != cursor.Backward {
+			op = "<"
+		}
+		conds = append(conds, bookSortColumns[f.Field]+" "+op+" "+placeholders[i])
+		terms[i] = strings.Join(conds, " AND ")
+		if i > 0 {
+			terms[i] = "(" + terms[i] + ")"
+		}
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", nil
+}
+
+// bookSortValue parses a cursor key into the type of the field's column.
+func bookSortValue(field, key string) (any, error) {
+	switch field {
+	case "id":
+		return strconv.Atoi(key)
+	case "created_at", "updated_at":
+		return time.Parse(time.RFC3339Nano, key)
+	}
+	return key, nil
+}
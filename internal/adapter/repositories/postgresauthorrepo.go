@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+
+	pgx "github.com/jackc/pgx/v5"
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+)
+
+const authorColumns = "id, name, created_at, updated_at"
+
+type PostgresAuthorRepo struct {
+	db PgxIface
+}
+
+var _ out.AuthorRepository = &PostgresAuthorRepo{}
+
+func NewPostgresAuthorRepo(db PgxIface) *PostgresAuthorRepo {
+	return &PostgresAuthorRepo{db: db}
+}
+
+func (r *PostgresAuthorRepo) conn(ctx context.Context) PgxIface {
+	return dbFor(ctx, r.db)
+}
+
+func (r *PostgresAuthorRepo) CreateAuthor(ctx context.Context, author domain.Author) (domain.Author, error) {
+	return scanAuthor(r.conn(ctx).QueryRow(
+		ctx,
+		"INSERT INTO authors (name) VALUES ($1) RETURNING "+authorColumns,
+		author.Name,
+	))
+}
+
+func (r *PostgresAuthorRepo) GetAuthor(ctx context.Context, id int) (domain.Author, error) {
+	author, err := scanAuthor(r.conn(ctx).QueryRow(
+		ctx,
+		"SELECT "+authorColumns+" FROM authors WHERE id = $1",
+		id,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.Author{}, domain.ErrAuthorNotFound
+		}
+		return domain.Author{}, err
+	}
+	return author, nil
+}
+
+func (r *PostgresAuthorRepo) GetAuthorsByIDs(ctx context.Context, ids []int) ([]domain.Author, error) {
+	rows, err := r.conn(ctx).Query(
+		ctx,
+		"SELECT "+authorColumns+" FROM authors WHERE id = ANY($1)",
+		ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[int]domain.Author, len(ids))
+	for rows.Next() {
+		author, err := scanAuthor(rows)
+		if err != nil {
+			return nil, err
+		}
+		byID[author.ID] = author
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	authors := make([]domain.Author, 0, len(ids))
+	for _, id := range ids {
+		author, ok := byID[id]
+		if !ok {
+			return nil, domain.ErrUnknownAuthor
+		}
+		authors = append(authors, author)
+	}
+	return authors, nil
+}
+
+func (r *PostgresAuthorRepo) GetAuthors(ctx context.Context, offset, limit int) ([]domain.Author, error) {
+	rows, err := r.conn(ctx).Query(
+		ctx,
+		"SELECT "+authorColumns+" FROM authors ORDER BY id ASC LIMIT $1 OFFSET $2",
+		limit,
+		offset,
+	)
+	if err != nil {
+		return []domain.Author{}, err
+	}
+	defer rows.Close()
+
+	authors := []domain.Author{}
+
+	for rows.Next() {
+		author, err := scanAuthor(rows)
+		if err != nil {
+			return []domain.Author{}, err
+		}
+		authors = append(authors, author)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.Author{}, err
+	}
+	return authors, nil
+}
+
+func (r *PostgresAuthorRepo) CountAuthors(ctx context.Context) (int, error) {
+	var n int
+	if err := r.conn(ctx).QueryRow(ctx, "SELECT COUNT(*) FROM authors").Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r *PostgresAuthorRepo) FindOrCreateAuthorByName(ctx context.Context, name string) (domain.Author, error) {
+	return scanAuthor(r.conn(ctx).QueryRow(
+		ctx,
+		`WITH existing AS (
+			SELECT `+authorColumns+` FROM authors WHERE name = $1 ORDER BY id ASC LIMIT 1
+		), inserted AS (
+			INSERT INTO authors (name) SELECT $1 WHERE NOT EXISTS (SELECT 1 FROM existing)
+			RETURNING `+authorColumns+`
+		)
+		SELECT `+authorColumns+` FROM existing
+		UNION ALL
+		SELECT `+authorColumns+` FROM inserted`,
+		name,
+	))
+}
+
+func (r *PostgresAuthorRepo) UpdateAuthor(ctx context.Context, author domain.Author) error {
+	cmdTag, err := r.conn(ctx).Exec(
+		ctx,
+		"UPDATE authors SET name = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2",
+		author.Name,
+		author.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return domain.ErrAuthorNotFound
+	}
+	return nil
+}
+
+func (r *PostgresAuthorRepo) DeleteAuthor(ctx context.Context, id int) error {
+	cmdTag, err := r.conn(ctx).Exec(
+		ctx,
+		"DELETE FROM authors WHERE id = $1",
+		id,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+			return domain.ErrAuthorHasBooks
+		}
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return domain.ErrAuthorNotFound
+	}
+	return nil
+}
+
+// scanAuthor scans a row selected with authorColumns.
+func scanAuthor(row pgx.Row) (domain.Author, error) {
+	var author domain.Author
+	err := row.Scan(&author.ID, &author.Name, &author.CreatedAt, &author.UpdatedAt)
+	if err != nil {
+		return domain.Author{}, err
+	}
+	return author, nil
+}
@@ -0,0 +1,408 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var authorRowColumns = []string{"id", "name", "created_at", "updated_at"}
+
+func TestPostgresAuthorRepo_CreateAuthor(t *testing.T) {
+	tests := []struct {
+		name    string
+		author  domain.Author
+		setup   func(pgxmock.PgxPoolIface)
+		want    domain.Author
+		wantErr bool
+	}{
+		{
+			name:   "success",
+			author: domain.Author{Name: "Test Author"},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("INSERT INTO authors").
+					WithArgs("Test Author").
+					WillReturnRows(pgxmock.NewRows(authorRowColumns).AddRow(1, "Test Author", testTime, testTime))
+			},
+			want:    testAuthor,
+			wantErr: false,
+		},
+		{
+			name:   "db error",
+			author: domain.Author{Name: "Test Author"},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("INSERT INTO authors").
+					WithArgs("Test Author").
+					WillReturnError(pgx.ErrTxClosed)
+			},
+			want:    domain.Author{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresAuthorRepo(mock)
+			got, err := r.CreateAuthor(context.Background(), tt.author)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostgresAuthorRepo.CreateAuthor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresAuthorRepo.CreateAuthor() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresAuthorRepo_GetAuthor(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		setup   func(pgxmock.PgxPoolIface)
+		want    domain.Author
+		wantErr error
+	}{
+		{
+			name: "success",
+			id:   1,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT (.+) FROM authors WHERE id").
+					WithArgs(1).
+					WillReturnRows(pgxmock.NewRows(authorRowColumns).AddRow(1, "Test Author", testTime, testTime))
+			},
+			want:    testAuthor,
+			wantErr: nil,
+		},
+		{
+			name: "not found",
+			id:   999,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT (.+) FROM authors WHERE id").
+					WithArgs(999).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			want:    domain.Author{},
+			wantErr: domain.ErrAuthorNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresAuthorRepo(mock)
+			got, err := r.GetAuthor(context.Background(), tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresAuthorRepo.GetAuthor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresAuthorRepo.GetAuthor() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresAuthorRepo_GetAuthorsByIDs(t *testing.T) {
+	second := domain.Author{ID: 2, Name: "Second Author", CreatedAt: testTime, UpdatedAt: testTime}
+
+	tests := []struct {
+		name    string
+		ids     []int
+		setup   func(pgxmock.PgxPoolIface)
+		want    []domain.Author
+		wantErr error
+	}{
+		{
+			name: "success - keeps requested order",
+			ids:  []int{2, 1},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT (.+) FROM authors WHERE id = ANY").
+					WithArgs([]int{2, 1}).
+					WillReturnRows(pgxmock.NewRows(authorRowColumns).
+						AddRow(1, "Test Author", testTime, testTime).
+						AddRow(2, "Second Author", testTime, testTime))
+			},
+			want:    []domain.Author{second, testAuthor},
+			wantErr: nil,
+		},
+		{
+			name: "missing author",
+			ids:  []int{1, 3},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT (.+) FROM authors WHERE id = ANY").
+					WithArgs([]int{1, 3}).
+					WillReturnRows(pgxmock.NewRows(authorRowColumns).
+						AddRow(1, "Test Author", testTime, testTime))
+			},
+			want:    nil,
+			wantErr: domain.ErrUnknownAuthor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresAuthorRepo(mock)
+			got, err := r.GetAuthorsByIDs(context.Background(), tt.ids)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresAuthorRepo.GetAuthorsByIDs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresAuthorRepo.GetAuthorsByIDs() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresAuthorRepo_GetAuthors(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(pgxmock.PgxPoolIface)
+		want    []domain.Author
+		wantErr bool
+	}{
+		{
+			name: "success",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT (.+) FROM authors ORDER BY id ASC").
+					WithArgs(10, 0).
+					WillReturnRows(pgxmock.NewRows(authorRowColumns).AddRow(1, "Test Author", testTime, testTime))
+			},
+			want:    []domain.Author{testAuthor},
+			wantErr: false,
+		},
+		{
+			name: "query error",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT (.+) FROM authors ORDER BY id ASC").
+					WithArgs(10, 0).
+					WillReturnError(pgx.ErrTxClosed)
+			},
+			want:    []domain.Author{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresAuthorRepo(mock)
+			got, err := r.GetAuthors(context.Background(), 0, 10)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostgresAuthorRepo.GetAuthors() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresAuthorRepo.GetAuthors() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresAuthorRepo_CountAuthors(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM authors").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(2))
+
+	r := NewPostgresAuthorRepo(mock)
+	got, err := r.CountAuthors(context.Background())
+	if err != nil {
+		t.Fatalf("PostgresAuthorRepo.CountAuthors() error = %v", err)
+	}
+	if got != 2 {
+		t.Errorf("PostgresAuthorRepo.CountAuthors() = %d, want 2", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostgresAuthorRepo_FindOrCreateAuthorByName(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery("WITH existing AS").
+		WithArgs("Test Author").
+		WillReturnRows(pgxmock.NewRows(authorRowColumns).AddRow(1, "Test Author", testTime, testTime))
+
+	r := NewPostgresAuthorRepo(mock)
+	got, err := r.FindOrCreateAuthorByName(context.Background(), "Test Author")
+	if err != nil {
+		t.Fatalf("PostgresAuthorRepo.FindOrCreateAuthorByName() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, testAuthor) {
+		t.Errorf("PostgresAuthorRepo.FindOrCreateAuthorByName() = %v, want %v", got, testAuthor)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostgresAuthorRepo_UpdateAuthor(t *testing.T) {
+	tests := []struct {
+		name    string
+		author  domain.Author
+		setup   func(pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name:   "success",
+			author: domain.Author{ID: 1, Name: "Renamed Author"},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("UPDATE authors SET name").
+					WithArgs("Renamed Author", 1).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name:   "not found - zero rows affected",
+			author: domain.Author{ID: 999, Name: "Renamed Author"},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("UPDATE authors SET name").
+					WithArgs("Renamed Author", 999).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+			},
+			wantErr: domain.ErrAuthorNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresAuthorRepo(mock)
+			if err := r.UpdateAuthor(context.Background(), tt.author); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresAuthorRepo.UpdateAuthor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresAuthorRepo_DeleteAuthor(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		setup   func(pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "success",
+			id:   1,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("DELETE FROM authors WHERE id").
+					WithArgs(1).
+					WillReturnResult(pgxmock.NewResult("DELETE", 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name: "not found - zero rows affected",
+			id:   999,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("DELETE FROM authors WHERE id").
+					WithArgs(999).
+					WillReturnResult(pgxmock.NewResult("DELETE", 0))
+			},
+			wantErr: domain.ErrAuthorNotFound,
+		},
+		{
+			name: "still linked to books",
+			id:   1,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("DELETE FROM authors WHERE id").
+					WithArgs(1).
+					WillReturnError(&pgconn.PgError{Code: "23503"})
+			},
+			wantErr: domain.ErrAuthorHasBooks,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresAuthorRepo(mock)
+			if err := r.DeleteAuthor(context.Background(), tt.id); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresAuthorRepo.DeleteAuthor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+	"time"
+)
+
+var _ out.BookAsOfReader = &PostgresBookHistoryRepo{}
+
+// booksAsOf is a WITH clause defining books_as_of: the latest snapshot of
+// every book recorded up to $1, with the columns of books that filterBooks and
+// bookOrder read, the authors and the snapshot itself.
+const booksAsOf = `WITH latest AS (
+	SELECT DISTINCT ON (book_id) after FROM book_history
+	WHERE created_at <= $1
+	ORDER BY book_id, version DESC
+), books_as_of AS (
+	SELECT (after->>'id')::int AS id,
+		after->>'title' AS title,
+		after->>'author' AS author,
+		(after->>'created_at')::timestamptz AS created_at,
+		(after->>'updated_at')::timestamptz AS updated_at,
+		(after->>'deleted_at')::timestamptz AS deleted_at,
+		CASE WHEN jsonb_typeof(after->'authors') = 'array' THEN after->'authors' ELSE '[]' END AS authors,
+		after
+	FROM latest
+) `
+
+// snapshotAuthorCond matches the books_as_of authors by name.
+const snapshotAuthorCond = "EXISTS (SELECT 1 FROM jsonb_array_elements(authors) a WHERE lower(a->>'name') = lower(%s))"
+
+func (r *PostgresBookHistoryRepo) GetBookAsOf(ctx context.Context, id int, at time.Time) (domain.Book, error) {
+	books, err := r.queryBookSnapshots(
+		ctx,
+		`SELECT after FROM book_history WHERE book_id = $1 AND created_at <= $2
+		ORDER BY version DESC LIMIT 1`,
+		id,
+		at,
+	)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	if len(books) == 0 || books[0].DeletedAt != nil {
+		return domain.Book{}, domain.ErrBookNotFound
+	}
+	return books[0], nil
+}
+
+func (r *PostgresBookHistoryRepo) GetBooksAsOf(ctx context.Context, criteria domain.BookCriteria, at time.Time, offset, limit int) ([]domain.Book, error) {
+	args := queryArgs{at}
+	sql := booksAsOf + "SELECT after FROM books_as_of WHERE " + filterBooks(criteria, &args, snapshotAuthorCond) +
+		" ORDER BY " + bookOrder(criteria.Sort, false) +
+		" LIMIT " + args.add(limit) + " OFFSET " + args.add(offset)
+	return r.queryBookSnapshots(ctx, sql, args...)
+}
+
+func (r *PostgresBookHistoryRepo) CountBooksAsOf(ctx context.Context, criteria domain.BookCriteria, at time.Time) (int, error) {
+	args := queryArgs{at}
+	sql := booksAsOf + "SELECT COUNT(*) FROM books_as_of WHERE " + filterBooks(criteria, &args, snapshotAuthorCond)
+	var n int
+	if err := r.conn(ctx).QueryRow(ctx, sql, args...).Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// queryBookSnapshots runs a query selecting after snapshots and decodes them.
+func (r *PostgresBookHistoryRepo) queryBookSnapshots(ctx context.Context, sql string, args ...any) ([]domain.Book, error) {
+	rows, err := r.conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return []domain.Book{}, err
+	}
+	defer rows.Close()
+
+	books := []domain.Book{}
+	for rows.Next() {
+		var after []byte
+		if err := rows.Scan(&after); err != nil {
+			return []domain.Book{}, err
+		}
+		var book domain.Book
+		if err := json.Unmarshal(after, &book); err != nil {
+			return []domain.Book{}, err
+		}
+		books = append(books, book)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.Book{}, err
+	}
+	return books, nil
+}
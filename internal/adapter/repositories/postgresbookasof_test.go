@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var asOfTime = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestPostgresBookHistoryRepo_GetBookAsOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(pgxmock.PgxPoolIface)
+		want    domain.Book
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(`SELECT after FROM book_history WHERE book_id = \$1 AND created_at <= \$2`).
+					WithArgs(1, asOfTime).
+					WillReturnRows(pgxmock.NewRows([]string{"after"}).
+						AddRow([]byte(`{"id":1,"title":"Test Book","authors":[{"id":1,"name":"Test Author"}],"version":2}`)))
+			},
+			want: domain.Book{ID: 1, Title: "Test Book", Authors: []domain.Author{{ID: 1, Name: "Test Author"}}, Version: 2},
+		},
+		{
+			name: "not created yet",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("FROM book_history").
+					WithArgs(1, asOfTime).
+					WillReturnRows(pgxmock.NewRows([]string{"after"}))
+			},
+			wantErr: domain.ErrBookNotFound,
+		},
+		{
+			name: "in the trash",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("FROM book_history").
+					WithArgs(1, asOfTime).
+					WillReturnRows(pgxmock.NewRows([]string{"after"}).
+						AddRow([]byte(`{"id":1,"title":"Test Book","version":3,"deleted_at":"2025-06-01T00:00:00Z"}`)))
+			},
+			wantErr: domain.ErrBookNotFound,
+		},
+		{
+			name: "db error",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("FROM book_history").
+					WithArgs(1, asOfTime).
+					WillReturnError(pgx.ErrTxClosed)
+			},
+			wantErr: pgx.ErrTxClosed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresBookHistoryRepo(mock)
+			got, err := r.GetBookAsOf(context.Background(), 1, asOfTime)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresBookHistoryRepo.GetBookAsOf() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresBookHistoryRepo.GetBookAsOf() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresBookHistoryRepo_GetBooksAsOf(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	criteria := domain.BookCriteria{
+		Author:        "Test Author",
+		TitleContains: "book",
+		Sort:          domain.BookSort{{Field: "title", Desc: true}},
+	}
+	mock.ExpectQuery(`WITH latest AS \(.+ WHERE created_at <= \$1 .+\) SELECT after FROM books_as_of `+
+		`WHERE deleted_at IS NULL AND EXISTS \(SELECT 1 FROM jsonb_array_elements\(authors\) a WHERE lower\(a->>'name'\) = lower\(\$2\)\) `+
+		`AND title ILIKE '%' \|\| \$3 \|\| '%' ORDER BY title DESC, id ASC LIMIT \$4 OFFSET \$5`).
+		WithArgs(asOfTime, "Test Author", "book", 10, 0).
+		WillReturnRows(pgxmock.NewRows([]string{"after"}).
+			AddRow([]byte(`{"id":2,"title":"Test Book 2","version":1}`)).
+			AddRow([]byte(`{"id":1,"title":"Test Book","version":4}`)))
+
+	r := NewPostgresBookHistoryRepo(mock)
+	got, err := r.GetBooksAsOf(context.Background(), criteria, asOfTime, 0, 10)
+	if err != nil {
+		t.Fatalf("PostgresBookHistoryRepo.GetBooksAsOf() error = %v", err)
+	}
+	want := []domain.Book{{ID: 2, Title: "Test Book 2", Version: 1}, {ID: 1, Title: "Test Book", Version: 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PostgresBookHistoryRepo.GetBooksAsOf() = %v, want %v", got, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostgresBookHistoryRepo_CountBooksAsOf(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM books_as_of WHERE deleted_at IS NULL AND created_at > \$2$`).
+		WithArgs(asOfTime, testTime).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(3))
+
+	r := NewPostgresBookHistoryRepo(mock)
+	got, err := r.CountBooksAsOf(context.Background(), domain.BookCriteria{CreatedAfter: testTime}, asOfTime)
+	if err != nil || got != 3 {
+		t.Errorf("PostgresBookHistoryRepo.CountBooksAsOf() = %v, %v, want 3", got, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
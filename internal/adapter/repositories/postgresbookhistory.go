@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+
+	pgx "github.com/jackc/pgx/v5"
+)
+
+const bookChangeColumns = "book_id, version, action, before, after, actor, request_id, created_at"
+
+// PostgresBookHistoryRepo keeps the books before and after each change as
+// JSON snapshots.
+type PostgresBookHistoryRepo struct {
+	db PgxIface
+}
+
+var _ out.BookHistoryRepository = &PostgresBookHistoryRepo{}
+
+func NewPostgresBookHistoryRepo(db PgxIface) *PostgresBookHistoryRepo {
+	return &PostgresBookHistoryRepo{db: db}
+}
+
+func (r *PostgresBookHistoryRepo) conn(ctx context.Context) PgxIface {
+	return dbFor(ctx, r.db)
+}
+
+// AddBookChanges inserts every change with a single statement.
+func (r *PostgresBookHistoryRepo) AddBookChanges(ctx context.Context, changes []domain.BookChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	n := len(changes)
+	bookIDs, versions := make([]int, n), make([]int, n)
+	actions, actors, requestIDs := make([]string, n), make([]string, n), make([]string, n)
+	befores, afters := make([]*string, n), make([]string, n)
+	for i, c := range changes {
+		bookIDs[i], versions[i] = c.BookID, c.Version
+		actions[i], actors[i], requestIDs[i] = string(c.Action), c.Actor, c.RequestID
+		if c.Before != nil {
+			before, err := json.Marshal(c.Before)
+			if err != nil {
+				return err
+			}
+			s := string(before)
+			befores[i] = &s
+		}
+		after, err := json.Marshal(c.After)
+		if err != nil {
+			return err
+		}
+		afters[i] = string(after)
+	}
+
+	_, err := r.conn(ctx).Exec(
+		ctx,
+		`INSERT INTO book_history (book_id, version, action, before, after, actor, request_id)
+		SELECT t.book_id, t.version, t.action, t.before::jsonb, t.after::jsonb, t.actor, t.request_id
+		FROM unnest($1::int[], $2::int[], $3::text[], $4::text[], $5::text[], $6::text[], $7::text[])
+			AS t(book_id, version, action, before, after, actor, request_id)`,
+		bookIDs, versions, actions, befores, afters, actors, requestIDs,
+	)
+	return err
+}
+
+func (r *PostgresBookHistoryRepo) GetBookHistory(ctx context.Context, bookID, offset, limit int) ([]domain.BookChange, error) {
+	rows, err := r.conn(ctx).Query(
+		ctx,
+		"SELECT "+bookChangeColumns+" FROM book_history WHERE book_id = $1 ORDER BY version DESC LIMIT $2 OFFSET $3",
+		bookID,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return []domain.BookChange{}, err
+	}
+	defer rows.Close()
+
+	changes := []domain.BookChange{}
+	for rows.Next() {
+		change, err := scanBookChange(rows)
+		if err != nil {
+			return []domain.BookChange{}, err
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.BookChange{}, err
+	}
+	return changes, nil
+}
+
+func (r *PostgresBookHistoryRepo) CountBookHistory(ctx context.Context, bookID int) (int, error) {
+	var n int
+	if err := r.conn(ctx).QueryRow(ctx, "SELECT COUNT(*) FROM book_history WHERE book_id = $1", bookID).Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r *PostgresBookHistoryRepo) GetBookChange(ctx context.Context, bookID, version int) (domain.BookChange, error) {
+	change, err := scanBookChange(r.conn(ctx).QueryRow(
+		ctx,
+		"SELECT "+bookChangeColumns+" FROM book_history WHERE book_id = $1 AND version = $2",
+		bookID,
+		version,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.BookChange{}, domain.ErrBookVersionNotFound
+	}
+	return change, err
+}
+
+func scanBookChange(row pgx.Row) (domain.BookChange, error) {
+	var change domain.BookChange
+	var action string
+	var before, after []byte
+	err := row.Scan(&change.BookID, &change.Version, &action, &before, &after,
+		&change.Actor, &change.RequestID, &change.CreatedAt)
+	if err != nil {
+		return domain.BookChange{}, err
+	}
+	change.Action = domain.BookAction(action)
+	if before != nil {
+		change.Before = &domain.Book{}
+		if err := json.Unmarshal(before, change.Before); err != nil {
+			return domain.BookChange{}, err
+		}
+	}
+	if err := json.Unmarshal(after, &change.After); err != nil {
+		return domain.BookChange{}, err
+	}
+	return change, nil
+}
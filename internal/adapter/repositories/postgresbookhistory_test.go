@@ -0,0 +1,188 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var bookChangeRowColumns = []string{"book_id", "version", "action", "before", "after", "actor", "request_id", "created_at"}
+
+func TestPostgresBookHistoryRepo_AddBookChanges(t *testing.T) {
+	v1 := domain.Book{ID: 1, Title: "Test Book", Author: "Test Author", Version: 1, CreatedAt: testTime, UpdatedAt: testTime}
+	v2 := v1
+	v2.Title, v2.Version = "New Title", 2
+	before := `{"id":1,"title":"Test Book","author":"Test Author","authors":null,"version":1,"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}`
+	after := `{"id":1,"title":"New Title","author":"Test Author","authors":null,"version":2,"created_at":"2025-01-01T00:00:00Z","updated_at":"2025-01-01T00:00:00Z"}`
+
+	tests := []struct {
+		name    string
+		changes []domain.BookChange
+		setup   func(pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "success",
+			changes: []domain.BookChange{
+				{BookID: 1, Version: 1, Action: domain.BookActionCreate, After: v1, Actor: "alice", RequestID: "req-1"},
+				{BookID: 1, Version: 2, Action: domain.BookActionUpdate, Before: &v1, After: v2, Actor: "alice", RequestID: "req-2"},
+			},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("INSERT INTO book_history (.+) FROM unnest").
+					WithArgs([]int{1, 1}, []int{1, 2}, []string{"create", "update"},
+						[]*string{nil, &before}, []string{before, after},
+						[]string{"alice", "alice"}, []string{"req-1", "req-2"}).
+					WillReturnResult(pgxmock.NewResult("INSERT", 2))
+			},
+		},
+		{
+			name:  "no changes",
+			setup: func(mock pgxmock.PgxPoolIface) {},
+		},
+		{
+			name:    "db error",
+			changes: []domain.BookChange{{BookID: 1, Version: 1, Action: domain.BookActionCreate, After: v1}},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("INSERT INTO book_history").
+					WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+						pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+					WillReturnError(pgx.ErrTxClosed)
+			},
+			wantErr: pgx.ErrTxClosed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresBookHistoryRepo(mock)
+			if err := r.AddBookChanges(context.Background(), tt.changes); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresBookHistoryRepo.AddBookChanges() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresBookHistoryRepo_GetBookHistory(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	rows := pgxmock.NewRows(bookChangeRowColumns).
+		AddRow(1, 2, "update", []byte(`{"id":1,"title":"Test Book","version":1}`), []byte(`{"id":1,"title":"New Title","version":2}`), "alice", "req-2", testTime).
+		AddRow(1, 1, "create", nil, []byte(`{"id":1,"title":"Test Book","version":1}`), "alice", "req-1", testTime)
+	mock.ExpectQuery("FROM book_history WHERE book_id = \\$1 ORDER BY version DESC LIMIT \\$2 OFFSET \\$3").
+		WithArgs(1, 10, 0).
+		WillReturnRows(rows)
+
+	r := NewPostgresBookHistoryRepo(mock)
+	got, err := r.GetBookHistory(context.Background(), 1, 0, 10)
+	if err != nil {
+		t.Fatalf("PostgresBookHistoryRepo.GetBookHistory() error = %v", err)
+	}
+	v1 := domain.Book{ID: 1, Title: "Test Book", Version: 1}
+	want := []domain.BookChange{
+		{BookID: 1, Version: 2, Action: domain.BookActionUpdate, Before: &v1, After: domain.Book{ID: 1, Title: "New Title", Version: 2}, Actor: "alice", RequestID: "req-2", CreatedAt: testTime},
+		{BookID: 1, Version: 1, Action: domain.BookActionCreate, After: v1, Actor: "alice", RequestID: "req-1", CreatedAt: testTime},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PostgresBookHistoryRepo.GetBookHistory() = %v, want %v", got, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostgresBookHistoryRepo_CountBookHistory(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM book_history WHERE book_id = \\$1").
+		WithArgs(1).
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(3))
+
+	r := NewPostgresBookHistoryRepo(mock)
+	got, err := r.CountBookHistory(context.Background(), 1)
+	if err != nil || got != 3 {
+		t.Errorf("PostgresBookHistoryRepo.CountBookHistory() = %v, %v, want 3", got, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostgresBookHistoryRepo_GetBookChange(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(pgxmock.PgxPoolIface)
+		want    domain.BookChange
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("FROM book_history WHERE book_id = \\$1 AND version = \\$2").
+					WithArgs(1, 1).
+					WillReturnRows(pgxmock.NewRows(bookChangeRowColumns).
+						AddRow(1, 1, "create", nil, []byte(`{"id":1,"title":"Test Book","version":1}`), "anonymous", "req-1", testTime))
+			},
+			want: domain.BookChange{BookID: 1, Version: 1, Action: domain.BookActionCreate, After: domain.Book{ID: 1, Title: "Test Book", Version: 1}, Actor: "anonymous", RequestID: "req-1", CreatedAt: testTime},
+		},
+		{
+			name: "not found",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("FROM book_history").
+					WithArgs(1, 1).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: domain.ErrBookVersionNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresBookHistoryRepo(mock)
+			got, err := r.GetBookChange(context.Background(), 1, 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresBookHistoryRepo.GetBookChange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresBookHistoryRepo.GetBookChange() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
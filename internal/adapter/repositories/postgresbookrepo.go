@@ -2,11 +2,26 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"go-api-boilerplate/internal/application/port/out"
 	"go-api-boilerplate/internal/domain"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	pgx "github.com/jackc/pgx/v5"
 	pgconn "github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	bookColumns = "id, title, author, isbn, version, created_at, updated_at, deleted_at"
+
+	uniqueViolationCode     = "23505"
+	foreignKeyViolationCode = "23503"
+	bookISBNConstraint      = "books_isbn_key"
 )
 
 type PgxIface interface {
@@ -16,6 +31,20 @@ type PgxIface interface {
 	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
 }
 
+// withTx runs fn in a transaction that is committed when fn succeeds and
+// rolled back otherwise.
+func withTx(ctx context.Context, db PgxIface, fn func(pgx.Tx) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
 type PostgresBookRepo struct {
 	db PgxIface
 }
@@ -26,85 +55,621 @@ func NewPostgresBookRepo(db PgxIface) *PostgresBookRepo {
 	return &PostgresBookRepo{db: db}
 }
 
-func (r *PostgresBookRepo) CreateBook(ctx context.Context, book domain.Book) error {
-	_, err := r.db.Exec(
-		ctx,
-		"INSERT INTO books (title, author) VALUES ($1, $2)",
-		book.Title,
-		book.Author,
-	)
-	return err
+func (r *PostgresBookRepo) conn(ctx context.Context) PgxIface {
+	return dbFor(ctx, r.db)
+}
+
+func (r *PostgresBookRepo) CreateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
+	var created domain.Book
+	err := withTx(ctx, r.conn(ctx), func(tx pgx.Tx) error {
+		var err error
+		created, err = scanBook(tx.QueryRow(
+			ctx,
+			"INSERT INTO books (title, author, isbn) VALUES ($1, $2, NULLIF($3, '')) RETURNING "+bookColumns,
+			book.Title,
+			book.Author,
+			book.ISBN,
+		))
+		if err != nil {
+			return err
+		}
+		return linkBookAuthors(ctx, tx, created.ID, book.AuthorIDs())
+	})
+	if err != nil {
+		return domain.Book{}, mapBookWriteError(err)
+	}
+	created.Authors = book.Authors
+	return created, nil
+}
+
+// errBatchRolledBack makes withTx roll back an all-or-nothing batch in which
+// some book failed.
+var errBatchRolledBack = errors.New("batch rolled back")
+
+// CreateBooks sends every insert in one pgx.Batch. Each statement links its
+// book's authors in the same round trip, and a taken ISBN makes it insert
+// nothing instead of aborting the transaction.
+func (r *PostgresBookRepo) CreateBooks(ctx context.Context, books []domain.Book, mode domain.BatchMode) ([]domain.BatchResult, error) {
+	results := make([]domain.BatchResult, len(books))
+	err := withTx(ctx, r.conn(ctx), func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for _, book := range books {
+			batch.Queue(
+				`WITH inserted AS (
+					INSERT INTO books (title, author, isbn) VALUES ($1, $2, NULLIF($3, ''))
+					ON CONFLICT (isbn) WHERE deleted_at IS NULL DO NOTHING
+					RETURNING `+bookColumns+`
+				), linked AS (
+					INSERT INTO book_authors (book_id, author_id, position)
+					SELECT inserted.id, t.author_id, t.position FROM inserted, unnest($4::int[]) WITH ORDINALITY AS t(author_id, position)
+				)
+				SELECT `+bookColumns+` FROM inserted`,
+				book.Title,
+				book.Author,
+				book.ISBN,
+				book.AuthorIDs(),
+			)
+		}
+
+		br := tx.SendBatch(ctx, batch)
+		defer br.Close()
+		for i, book := range books {
+			created, err := scanBook(br.QueryRow())
+			if err == pgx.ErrNoRows {
+				results[i].Err = domain.ErrDuplicateISBN
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			created.Authors = book.Authors
+			results[i].Book = created
+		}
+		if err := br.Close(); err != nil {
+			return err
+		}
+
+		if mode == domain.BatchAllOrNothing && domain.AnyFailed(results) {
+			return errBatchRolledBack
+		}
+		return nil
+	})
+	if errors.Is(err, errBatchRolledBack) {
+		for i := range results {
+			results[i].Book = domain.Book{}
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, mapBookWriteError(err)
+	}
+	return results, nil
 }
 
 func (r *PostgresBookRepo) GetBook(ctx context.Context, id int) (domain.Book, error) {
-	var book domain.Book
-	err := r.db.QueryRow(
+	book, err := scanBook(r.conn(ctx).QueryRow(
 		ctx,
-		"SELECT id, title, author FROM books WHERE id = $1",
+		"SELECT "+bookColumns+" FROM books WHERE id = $1 AND deleted_at IS NULL",
 		id,
-	).Scan(&book.ID, &book.Title, &book.Author)
+	))
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return domain.Book{}, domain.ErrBookNotFound
 		}
 		return domain.Book{}, err
 	}
-	return book, nil
+	return r.withAuthors(ctx, book)
 }
 
-func (r *PostgresBookRepo) GetBooks(ctx context.Context, offset, limit int) ([]domain.Book, error) {
-	rows, err := r.db.Query(
+func (r *PostgresBookRepo) LockBook(ctx context.Context, id int) (domain.Book, error) {
+	book, err := scanBook(r.conn(ctx).QueryRow(
 		ctx,
-		"SELECT id, title, author FROM books ORDER BY id ASC LIMIT $1 OFFSET $2",
-		limit,
-		offset,
+		"SELECT "+bookColumns+" FROM books WHERE id = $1 FOR UPDATE",
+		id,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.Book{}, domain.ErrBookNotFound
+		}
+		return domain.Book{}, err
+	}
+	return r.withAuthors(ctx, book)
+}
+
+func (r *PostgresBookRepo) GetBookByISBN(ctx context.Context, isbn string) (domain.Book, error) {
+	book, err := scanBook(r.conn(ctx).QueryRow(
+		ctx,
+		"SELECT "+bookColumns+" FROM books WHERE isbn = $1 AND deleted_at IS NULL",
+		isbn,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.Book{}, domain.ErrBookNotFound
+		}
+		return domain.Book{}, err
+	}
+	return r.withAuthors(ctx, book)
+}
+
+func (r *PostgresBookRepo) FindExistingISBNs(ctx context.Context, isbns []string) ([]string, error) {
+	rows, err := r.conn(ctx).Query(
+		ctx,
+		"SELECT isbn FROM books WHERE isbn = ANY($1) AND deleted_at IS NULL",
+		isbns,
 	)
 	if err != nil {
-		return []domain.Book{}, err
+		return nil, err
 	}
 	defer rows.Close()
 
-	books := []domain.Book{}
+	existing := []string{}
+	for rows.Next() {
+		var isbn string
+		if err := rows.Scan(&isbn); err != nil {
+			return nil, err
+		}
+		existing = append(existing, isbn)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (r *PostgresBookRepo) GetBooks(ctx context.Context, criteria domain.BookCriteria, offset, limit int) ([]domain.Book, error) {
+	var args queryArgs
+	sql := "SELECT " + bookColumns + " FROM books WHERE " + bookFilter(criteria, &args) +
+		" ORDER BY " + bookOrder(criteria.Sort, false) +
+		" LIMIT " + args.add(limit) + " OFFSET " + args.add(offset)
+	return r.queryBooks(ctx, sql, args...)
+}
+
+// exportFetchSize is the number of books ExportBooks fetches from its cursor
+// at a time.
+const exportFetchSize = 1000
 
+// ExportBooks reads the books through a server-side cursor, so neither the
+// database nor the client holds more than exportFetchSize of them at once.
+func (r *PostgresBookRepo) ExportBooks(ctx context.Context, criteria domain.BookCriteria, fn func([]domain.Book) error) error {
+	var args queryArgs
+	sql := "SELECT " + bookColumns + " FROM books WHERE " + bookFilter(criteria, &args) +
+		" ORDER BY " + bookOrder(criteria.Sort, false)
+	return withTx(ctx, r.conn(ctx), func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "DECLARE books_export NO SCROLL CURSOR FOR "+sql, args...); err != nil {
+			return err
+		}
+		for {
+			books, err := fetchBooks(ctx, tx, "FETCH "+strconv.Itoa(exportFetchSize)+" FROM books_export")
+			if err != nil {
+				return err
+			}
+			if len(books) == 0 {
+				return nil
+			}
+			if err := loadBookAuthors(ctx, tx, books); err != nil {
+				return err
+			}
+			if err := fn(books); err != nil {
+				return err
+			}
+			if len(books) < exportFetchSize {
+				return nil
+			}
+		}
+	})
+}
+
+// fetchBooks reads the books of a FETCH from a cursor over bookColumns.
+func fetchBooks(ctx context.Context, tx pgx.Tx, sql string) ([]domain.Book, error) {
+	rows, err := tx.Query(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	books := []domain.Book{}
 	for rows.Next() {
-		var book domain.Book
-		err := rows.Scan(&book.ID, &book.Title, &book.Author)
+		book, err := scanBook(rows)
 		if err != nil {
-			return []domain.Book{}, err
+			return nil, err
 		}
 		books = append(books, book)
 	}
+	return books, rows.Err()
+}
+
+func (r *PostgresBookRepo) CountBooks(ctx context.Context, criteria domain.BookCriteria) (int, error) {
+	var args queryArgs
+	sql := "SELECT COUNT(*) FROM books WHERE " + bookFilter(criteria, &args)
+	return r.count(ctx, sql, args...)
+}
+
+func (r *PostgresBookRepo) GetBooksByCursor(ctx context.Context, criteria domain.BookCriteria, cursor domain.Cursor, limit int) ([]domain.Book, error) {
+	var args queryArgs
+	where := bookFilter(criteria, &args)
+	if !cursor.IsStart() {
+		keyset, err := bookKeyset(criteria.Sort, cursor, &args)
+		if err != nil {
+			return []domain.Book{}, err
+		}
+		where += " AND " + keyset
+	}
+
+	// A backward page is read in reverse order from the cursor, then put back
+	// in list order.
+	sql := "SELECT " + bookColumns + " FROM books WHERE " + where +
+		" ORDER BY " + bookOrder(criteria.Sort, cursor.Backward) +
+		" LIMIT " + args.add(limit)
+	books, err := r.queryBooks(ctx, sql, args...)
+	if err != nil || !cursor.Backward {
+		return books, err
+	}
+	slices.Reverse(books)
 	return books, nil
 }
 
-func (r *PostgresBookRepo) UpdateBook(ctx context.Context, book domain.Book) error {
-	cmdTag, err := r.db.Exec(
+func (r *PostgresBookRepo) GetBooksByAuthor(ctx context.Context, authorID, offset, limit int) ([]domain.Book, error) {
+	return r.queryBooks(
 		ctx,
-		"UPDATE books SET title = $1, author = $2 WHERE id = $3",
-		book.Title,
-		book.Author,
-		book.ID,
+		"SELECT "+bookColumns+" FROM books WHERE deleted_at IS NULL AND id IN (SELECT book_id FROM book_authors WHERE author_id = $1) ORDER BY id ASC LIMIT $2 OFFSET $3",
+		authorID,
+		limit,
+		offset,
+	)
+}
+
+func (r *PostgresBookRepo) LockBooksByAuthor(ctx context.Context, authorID int) ([]domain.Book, error) {
+	return r.queryBooks(
+		ctx,
+		"SELECT "+bookColumns+" FROM books WHERE id IN (SELECT book_id FROM book_authors WHERE author_id = $1) ORDER BY id ASC FOR UPDATE",
+		authorID,
+	)
+}
+
+func (r *PostgresBookRepo) CountBooksByAuthor(ctx context.Context, authorID int) (int, error) {
+	return r.count(
+		ctx,
+		"SELECT COUNT(*) FROM books WHERE deleted_at IS NULL AND id IN (SELECT book_id FROM book_authors WHERE author_id = $1)",
+		authorID,
+	)
+}
+
+// UpdateBook writes the book and bumps its version. When book.Version is
+// non-zero the write only happens if it still matches the stored version.
+func (r *PostgresBookRepo) UpdateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
+	var updated domain.Book
+	err := withTx(ctx, r.conn(ctx), func(tx pgx.Tx) error {
+		var err error
+		updated, err = scanBook(tx.QueryRow(
+			ctx,
+			"UPDATE books SET title = $1, author = $2, isbn = NULLIF($3, ''), version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = $4 AND deleted_at IS NULL AND ($5 = 0 OR version = $5) RETURNING "+bookColumns,
+			book.Title,
+			book.Author,
+			book.ISBN,
+			book.ID,
+			book.Version,
+		))
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return missingBookError(ctx, tx, book.ID)
+			}
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, "DELETE FROM book_authors WHERE book_id = $1", book.ID); err != nil {
+			return err
+		}
+		return linkBookAuthors(ctx, tx, book.ID, book.AuthorIDs())
+	})
+	if err != nil {
+		return domain.Book{}, mapBookWriteError(err)
+	}
+	updated.Authors = book.Authors
+	return updated, nil
+}
+
+// PatchBook writes only the fields set in the patch and bumps the version.
+// A non-zero version makes the write conditional, as in UpdateBook.
+func (r *PostgresBookRepo) PatchBook(ctx context.Context, id, version int, patch domain.BookPatch) (domain.Book, error) {
+	var sets []string
+	var args []any
+	set := func(assignment string, value any) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf(assignment, len(args)))
+	}
+	if patch.Title != nil {
+		set("title = $%d", *patch.Title)
+	}
+	if patch.Author != nil {
+		set("author = $%d", *patch.Author)
+	}
+	if patch.ISBN != nil {
+		set("isbn = NULLIF($%d, '')", *patch.ISBN)
+	}
+	sets = append(sets, "version = version + 1", "updated_at = CURRENT_TIMESTAMP")
+	args = append(args, id, version)
+
+	sql := fmt.Sprintf(
+		"UPDATE books SET %s WHERE id = $%d AND deleted_at IS NULL AND ($%d = 0 OR version = $%d) RETURNING %s",
+		strings.Join(sets, ", "),
+		len(args)-1,
+		len(args),
+		len(args),
+		bookColumns,
+	)
+
+	var patched domain.Book
+	err := withTx(ctx, r.conn(ctx), func(tx pgx.Tx) error {
+		var err error
+		patched, err = scanBook(tx.QueryRow(ctx, sql, args...))
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return missingBookError(ctx, tx, id)
+			}
+			return err
+		}
+
+		if patch.Authors == nil {
+			return nil
+		}
+		if _, err := tx.Exec(ctx, "DELETE FROM book_authors WHERE book_id = $1", id); err != nil {
+			return err
+		}
+		ids := make([]int, len(patch.Authors))
+		for i, a := range patch.Authors {
+			ids[i] = a.ID
+		}
+		return linkBookAuthors(ctx, tx, id, ids)
+	})
+	if err != nil {
+		return domain.Book{}, mapBookWriteError(err)
+	}
+
+	if patch.Authors != nil {
+		patched.Authors = patch.Authors
+		return patched, nil
+	}
+	return r.withAuthors(ctx, patched)
+}
+
+// RefreshBylines rewrites the denormalised author byline of the given books
+// from their linked authors, bumping their versions, and returns the changed
+// books ordered by id.
+func (r *PostgresBookRepo) RefreshBylines(ctx context.Context, ids []int) ([]domain.Book, error) {
+	books, err := r.queryBooks(
+		ctx,
+		`UPDATE books b SET author = (
+			SELECT string_agg(a.name, ', ' ORDER BY ba.position)
+			FROM book_authors ba JOIN authors a ON a.id = ba.author_id
+			WHERE ba.book_id = b.id
+		), version = b.version + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE b.id = ANY($1)
+		RETURNING `+bookColumns,
+		ids,
+	)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(books, func(a, b domain.Book) int { return a.ID - b.ID })
+	return books, nil
+}
+
+// DeleteBook moves the book to the trash and bumps its version. A non-zero
+// version makes the delete conditional, as in UpdateBook. Use PurgeBook to
+// remove the book for good.
+func (r *PostgresBookRepo) DeleteBook(ctx context.Context, id, version int) error {
+	cmdTag, err := r.conn(ctx).Exec(
+		ctx,
+		"UPDATE books SET deleted_at = CURRENT_TIMESTAMP, version = version + 1 WHERE id = $1 AND deleted_at IS NULL AND ($2 = 0 OR version = $2)",
+		id,
+		version,
 	)
 	if err != nil {
 		return err
 	}
 	if cmdTag.RowsAffected() == 0 {
-		return domain.ErrBookNotFound
+		return missingBookError(ctx, r.conn(ctx), id)
 	}
 	return nil
 }
 
-func (r *PostgresBookRepo) DeleteBook(ctx context.Context, id int) error {
-	cmdTag, err := r.db.Exec(
+func (r *PostgresBookRepo) GetDeletedBooks(ctx context.Context, offset, limit int) ([]domain.Book, error) {
+	return r.queryBooks(
+		ctx,
+		"SELECT "+bookColumns+" FROM books WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC, id DESC LIMIT $1 OFFSET $2",
+		limit,
+		offset,
+	)
+}
+
+func (r *PostgresBookRepo) CountDeletedBooks(ctx context.Context) (int, error) {
+	return r.count(ctx, "SELECT COUNT(*) FROM books WHERE deleted_at IS NOT NULL")
+}
+
+func (r *PostgresBookRepo) RestoreBook(ctx context.Context, id int) (domain.Book, error) {
+	book, err := scanBook(r.conn(ctx).QueryRow(
 		ctx,
-		"DELETE FROM books WHERE id = $1",
+		"UPDATE books SET deleted_at = NULL, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NOT NULL RETURNING "+bookColumns,
+		id,
+	))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return domain.Book{}, domain.ErrBookNotInTrash
+		}
+		return domain.Book{}, mapBookWriteError(err)
+	}
+	return r.withAuthors(ctx, book)
+}
+
+func (r *PostgresBookRepo) PurgeBook(ctx context.Context, id int) error {
+	cmdTag, err := r.conn(ctx).Exec(
+		ctx,
+		"DELETE FROM books WHERE id = $1 AND deleted_at IS NOT NULL",
 		id,
 	)
 	if err != nil {
 		return err
 	}
 	if cmdTag.RowsAffected() == 0 {
-		return domain.ErrBookNotFound
+		return domain.ErrBookNotInTrash
 	}
 	return nil
 }
+
+// PurgeDeletedBooks permanently deletes the books trashed before the given
+// time and reports how many were removed.
+func (r *PostgresBookRepo) PurgeDeletedBooks(ctx context.Context, before time.Time) (int64, error) {
+	cmdTag, err := r.conn(ctx).Exec(
+		ctx,
+		"DELETE FROM books WHERE deleted_at < $1",
+		before,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return cmdTag.RowsAffected(), nil
+}
+
+func (r *PostgresBookRepo) count(ctx context.Context, sql string, args ...any) (int, error) {
+	var n int
+	if err := r.conn(ctx).QueryRow(ctx, sql, args...).Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r *PostgresBookRepo) queryBooks(ctx context.Context, sql string, args ...any) ([]domain.Book, error) {
+	rows, err := r.conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return []domain.Book{}, err
+	}
+	defer rows.Close()
+
+	books := []domain.Book{}
+
+	for rows.Next() {
+		book, err := scanBook(rows)
+		if err != nil {
+			return []domain.Book{}, err
+		}
+		books = append(books, book)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.Book{}, err
+	}
+	rows.Close()
+
+	if err := loadBookAuthors(ctx, r.conn(ctx), books); err != nil {
+		return []domain.Book{}, err
+	}
+	return books, nil
+}
+
+func (r *PostgresBookRepo) withAuthors(ctx context.Context, book domain.Book) (domain.Book, error) {
+	books := []domain.Book{book}
+	if err := loadBookAuthors(ctx, r.conn(ctx), books); err != nil {
+		return domain.Book{}, err
+	}
+	return books[0], nil
+}
+
+// loadBookAuthors fills in the linked authors of every book with a single
+// query.
+func loadBookAuthors(ctx context.Context, db PgxIface, books []domain.Book) error {
+	if len(books) == 0 {
+		return nil
+	}
+
+	index := make(map[int]int, len(books))
+	ids := make([]int, len(books))
+	for i := range books {
+		books[i].Authors = []domain.Author{}
+		index[books[i].ID] = i
+		ids[i] = books[i].ID
+	}
+
+	rows, err := db.Query(
+		ctx,
+		"SELECT ba.book_id, a.id, a.name, a.created_at, a.updated_at FROM book_authors ba JOIN authors a ON a.id = ba.author_id WHERE ba.book_id = ANY($1) ORDER BY ba.book_id, ba.position",
+		ids,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var bookID int
+		var author domain.Author
+		if err := rows.Scan(&bookID, &author.ID, &author.Name, &author.CreatedAt, &author.UpdatedAt); err != nil {
+			return err
+		}
+		i := index[bookID]
+		books[i].Authors = append(books[i].Authors, author)
+	}
+	return rows.Err()
+}
+
+// missingBookError explains why a conditional write on a live book matched no
+// row: either the book is gone or its version has moved on.
+func missingBookError(ctx context.Context, db PgxIface, id int) error {
+	var exists bool
+	err := db.QueryRow(
+		ctx,
+		"SELECT EXISTS (SELECT 1 FROM books WHERE id = $1 AND deleted_at IS NULL)",
+		id,
+	).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return domain.ErrVersionConflict
+	}
+	return domain.ErrBookNotFound
+}
+
+// linkBookAuthors records the book's authors in byline order.
+func linkBookAuthors(ctx context.Context, tx pgx.Tx, bookID int, authorIDs []int) error {
+	if len(authorIDs) == 0 {
+		return nil
+	}
+	_, err := tx.Exec(
+		ctx,
+		"INSERT INTO book_authors (book_id, author_id, position) SELECT $1, author_id, position FROM unnest($2::int[]) WITH ORDINALITY AS t(author_id, position)",
+		bookID,
+		authorIDs,
+	)
+	return err
+}
+
+// scanBook reads a row that starts with bookColumns. Any further columns are
+// scanned into extra.
+func scanBook(row pgx.Row, extra ...any) (domain.Book, error) {
+	var book domain.Book
+	var isbn pgtype.Text
+	var deletedAt pgtype.Timestamptz
+	dest := []any{&book.ID, &book.Title, &book.Author, &isbn, &book.Version, &book.CreatedAt, &book.UpdatedAt, &deletedAt}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	book.ISBN = isbn.String
+	if deletedAt.Valid {
+		book.DeletedAt = &deletedAt.Time
+	}
+	return book, nil
+}
+
+// mapBookWriteError translates constraint violations into domain errors.
+func mapBookWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == uniqueViolationCode && pgErr.ConstraintName == bookISBNConstraint:
+			return domain.ErrDuplicateISBN
+		case pgErr.Code == foreignKeyViolationCode:
+			return domain.ErrUnknownAuthor
+		}
+	}
+	return err
+}
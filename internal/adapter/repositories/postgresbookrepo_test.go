@@ -2,40 +2,90 @@ package repositories
 
 import (
 	"context"
+	"errors"
 	"go-api-boilerplate/internal/domain"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/pashagolub/pgxmock/v4"
 )
 
+var (
+	bookRowColumns       = []string{"id", "title", "author", "isbn", "version", "created_at", "updated_at", "deleted_at"}
+	bookAuthorRowColumns = []string{"book_id", "id", "name", "created_at", "updated_at"}
+	testTime             = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	testAuthor           = domain.Author{ID: 1, Name: "Test Author", CreatedAt: testTime, UpdatedAt: testTime}
+)
+
 func TestPostgresBookRepo_CreateBook(t *testing.T) {
 	tests := []struct {
 		name    string
 		book    domain.Book
 		setup   func(pgxmock.PgxPoolIface)
-		wantErr bool
+		want    domain.Book
+		wantErr error
 	}{
 		{
 			name: "success",
-			book: domain.Book{Title: "Test Book", Author: "Test Author"},
+			book: domain.Book{Title: "Test Book", Author: "Test Author", Authors: []domain.Author{testAuthor}},
 			setup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectExec("INSERT INTO books").
-					WithArgs("Test Book", "Test Author").
+				rows := pgxmock.NewRows(bookRowColumns).
+					AddRow(1, "Test Book", "Test Author", nil, 1, testTime, testTime, nil)
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO books").
+					WithArgs("Test Book", "Test Author", "").
+					WillReturnRows(rows)
+				mock.ExpectExec("INSERT INTO book_authors").
+					WithArgs(1, []int{1}).
 					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
 			},
-			wantErr: false,
+			want:    domain.Book{ID: 1, Title: "Test Book", Author: "Test Author", Authors: []domain.Author{testAuthor}, Version: 1, CreatedAt: testTime, UpdatedAt: testTime},
+			wantErr: nil,
+		},
+		{
+			name: "success with isbn",
+			book: domain.Book{Title: "Test Book", Author: "Test Author", ISBN: "9780743273565"},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(bookRowColumns).
+					AddRow(1, "Test Book", "Test Author", "9780743273565", 1, testTime, testTime, nil)
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO books").
+					WithArgs("Test Book", "Test Author", "9780743273565").
+					WillReturnRows(rows)
+				mock.ExpectCommit()
+			},
+			want:    domain.Book{ID: 1, Title: "Test Book", Author: "Test Author", ISBN: "9780743273565", Version: 1, CreatedAt: testTime, UpdatedAt: testTime},
+			wantErr: nil,
+		},
+		{
+			name: "duplicate isbn",
+			book: domain.Book{Title: "Test Book", Author: "Test Author", ISBN: "9780743273565"},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO books").
+					WithArgs("Test Book", "Test Author", "9780743273565").
+					WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "books_isbn_key"})
+				mock.ExpectRollback()
+			},
+			want:    domain.Book{},
+			wantErr: domain.ErrDuplicateISBN,
 		},
 		{
 			name: "db error",
 			book: domain.Book{Title: "Test Book", Author: "Test Author"},
 			setup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectExec("INSERT INTO books").
-					WithArgs("Test Book", "Test Author").
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO books").
+					WithArgs("Test Book", "Test Author", "").
 					WillReturnError(pgx.ErrTxClosed)
+				mock.ExpectRollback()
 			},
-			wantErr: true,
+			want:    domain.Book{},
+			wantErr: pgx.ErrTxClosed,
 		},
 	}
 	for _, tt := range tests {
@@ -49,8 +99,113 @@ func TestPostgresBookRepo_CreateBook(t *testing.T) {
 			tt.setup(mock)
 
 			r := NewPostgresBookRepo(mock)
-			if err := r.CreateBook(context.Background(), tt.book); (err != nil) != tt.wantErr {
+			got, err := r.CreateBook(context.Background(), tt.book)
+			if !errors.Is(err, tt.wantErr) {
 				t.Errorf("PostgresBookRepo.CreateBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresBookRepo.CreateBook() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresBookRepo_CreateBooks(t *testing.T) {
+	farm := domain.Book{Title: "Animal Farm", Author: "Test Author", Authors: []domain.Author{testAuthor}}
+	nineteen := domain.Book{Title: "1984", Author: "Test Author", Authors: []domain.Author{testAuthor}, ISBN: "9780743273565"}
+	created := func(id int, b domain.Book) domain.Book {
+		b.ID, b.Version, b.CreatedAt, b.UpdatedAt = id, 1, testTime, testTime
+		return b
+	}
+	expectInserts := func(mock pgxmock.PgxPoolIface, nineteenRows *pgxmock.Rows) {
+		batch := mock.ExpectBatch()
+		batch.ExpectQuery(`WITH inserted AS \(\s*INSERT INTO books \(title, author, isbn\) VALUES \(\$1, \$2, NULLIF\(\$3, ''\)\)\s*`+
+			`ON CONFLICT \(isbn\) WHERE deleted_at IS NULL DO NOTHING\s*RETURNING (.+)`+
+			`INSERT INTO book_authors \(book_id, author_id, position\)\s*SELECT inserted.id, t.author_id, t.position FROM inserted, unnest\(\$4::int\[\]\)`).
+			WithArgs("Animal Farm", "Test Author", "", []int{1}).
+			WillReturnRows(pgxmock.NewRows(bookRowColumns).AddRow(1, "Animal Farm", "Test Author", nil, 1, testTime, testTime, nil))
+		batch.ExpectQuery("WITH inserted AS").
+			WithArgs("1984", "Test Author", "9780743273565", []int{1}).
+			WillReturnRows(nineteenRows)
+	}
+
+	tests := []struct {
+		name    string
+		mode    domain.BatchMode
+		setup   func(pgxmock.PgxPoolIface)
+		want    []domain.BatchResult
+		wantErr error
+	}{
+		{
+			name: "success",
+			mode: domain.BatchAllOrNothing,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				expectInserts(mock, pgxmock.NewRows(bookRowColumns).AddRow(2, "1984", "Test Author", "9780743273565", 1, testTime, testTime, nil))
+				mock.ExpectCommit()
+			},
+			want: []domain.BatchResult{{Book: created(1, farm)}, {Book: created(2, nineteen)}},
+		},
+		{
+			name: "best effort - duplicate isbn skipped",
+			mode: domain.BatchBestEffort,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				expectInserts(mock, pgxmock.NewRows(bookRowColumns))
+				mock.ExpectCommit()
+			},
+			want: []domain.BatchResult{{Book: created(1, farm)}, {Err: domain.ErrDuplicateISBN}},
+		},
+		{
+			name: "all or nothing - duplicate isbn rolls back",
+			mode: domain.BatchAllOrNothing,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				expectInserts(mock, pgxmock.NewRows(bookRowColumns))
+				mock.ExpectRollback()
+			},
+			want: []domain.BatchResult{{}, {Err: domain.ErrDuplicateISBN}},
+		},
+		{
+			name: "unknown author",
+			mode: domain.BatchBestEffort,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				batch := mock.ExpectBatch()
+				batch.ExpectQuery("WITH inserted AS").
+					WithArgs("Animal Farm", "Test Author", "", []int{1}).
+					WillReturnRows(pgxmock.NewRows(bookRowColumns).AddRow(1, "Animal Farm", "Test Author", nil, 1, testTime, testTime, nil))
+				batch.ExpectQuery("WITH inserted AS").
+					WithArgs("1984", "Test Author", "9780743273565", []int{1}).
+					WillReturnError(&pgconn.PgError{Code: "23503"})
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrUnknownAuthor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresBookRepo(mock)
+			got, err := r.CreateBooks(context.Background(), []domain.Book{farm, nineteen}, tt.mode)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresBookRepo.CreateBooks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresBookRepo.CreateBooks() = %v, want %v", got, tt.want)
 			}
 
 			if err := mock.ExpectationsWereMet(); err != nil {
@@ -72,20 +227,23 @@ func TestPostgresBookRepo_GetBook(t *testing.T) {
 			name: "success",
 			id:   1,
 			setup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"id", "title", "author"}).
-					AddRow(1, "Test Book", "Test Author")
-				mock.ExpectQuery("SELECT id, title, author FROM books WHERE id").
+				rows := pgxmock.NewRows(bookRowColumns).
+					AddRow(1, "Test Book", "Test Author", nil, 1, testTime, testTime, nil)
+				mock.ExpectQuery("SELECT (.+) FROM books WHERE id").
 					WithArgs(1).
 					WillReturnRows(rows)
+				mock.ExpectQuery("FROM book_authors").
+					WithArgs([]int{1}).
+					WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns).AddRow(1, 1, "Test Author", testTime, testTime))
 			},
-			want:    domain.Book{ID: 1, Title: "Test Book", Author: "Test Author"},
+			want:    domain.Book{ID: 1, Title: "Test Book", Author: "Test Author", Authors: []domain.Author{testAuthor}, Version: 1, CreatedAt: testTime, UpdatedAt: testTime},
 			wantErr: false,
 		},
 		{
 			name: "not found",
 			id:   999,
 			setup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectQuery("SELECT id, title, author FROM books WHERE id").
+				mock.ExpectQuery("SELECT (.+) FROM books WHERE id").
 					WithArgs(999).
 					WillReturnError(pgx.ErrNoRows)
 			},
@@ -96,10 +254,10 @@ func TestPostgresBookRepo_GetBook(t *testing.T) {
 			name: "scan error - other error",
 			id:   1,
 			setup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"id", "title", "author"}).
-					AddRow(1, "Test Book", "Test Author").
+				rows := pgxmock.NewRows(bookRowColumns).
+					AddRow(1, "Test Book", "Test Author", nil, 1, testTime, testTime, nil).
 					RowError(0, pgx.ErrTxClosed)
-				mock.ExpectQuery("SELECT id, title, author FROM books WHERE id").
+				mock.ExpectQuery("SELECT (.+) FROM books WHERE id").
 					WithArgs(1).
 					WillReturnRows(rows)
 			},
@@ -134,30 +292,183 @@ func TestPostgresBookRepo_GetBook(t *testing.T) {
 	}
 }
 
-func TestPostgresBookRepo_GetBooks(t *testing.T) {
+func TestPostgresBookRepo_LockBook(t *testing.T) {
+	deletedAt := testTime.Add(time.Hour)
 	tests := []struct {
 		name    string
-		offset  int
-		limit   int
 		setup   func(pgxmock.PgxPoolIface)
-		want    []domain.Book
+		want    domain.Book
+		wantErr error
+	}{
+		{
+			name: "trashed book",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(bookRowColumns).
+					AddRow(1, "Test Book", "Test Author", nil, 2, testTime, testTime, deletedAt)
+				mock.ExpectQuery(`SELECT (.+) FROM books WHERE id = \$1 FOR UPDATE`).
+					WithArgs(1).
+					WillReturnRows(rows)
+				mock.ExpectQuery("FROM book_authors").
+					WithArgs([]int{1}).
+					WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns).AddRow(1, 1, "Test Author", testTime, testTime))
+			},
+			want: domain.Book{ID: 1, Title: "Test Book", Author: "Test Author", Authors: []domain.Author{testAuthor}, Version: 2, CreatedAt: testTime, UpdatedAt: testTime, DeletedAt: &deletedAt},
+		},
+		{
+			name: "not found",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("FOR UPDATE").
+					WithArgs(1).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: domain.ErrBookNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresBookRepo(mock)
+			got, err := r.LockBook(context.Background(), 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresBookRepo.LockBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresBookRepo.LockBook() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresBookRepo_FindExistingISBNs(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery(`SELECT isbn FROM books WHERE isbn = ANY\(\$1\) AND deleted_at IS NULL`).
+		WithArgs([]string{"9780743273565", "9780451524935"}).
+		WillReturnRows(pgxmock.NewRows([]string{"isbn"}).AddRow("9780451524935"))
+
+	r := NewPostgresBookRepo(mock)
+	got, err := r.FindExistingISBNs(context.Background(), []string{"9780743273565", "9780451524935"})
+	if err != nil {
+		t.Fatalf("PostgresBookRepo.FindExistingISBNs() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"9780451524935"}) {
+		t.Errorf("PostgresBookRepo.FindExistingISBNs() = %v, want [9780451524935]", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostgresBookRepo_GetBookByISBN(t *testing.T) {
+	tests := []struct {
+		name    string
+		isbn    string
+		setup   func(pgxmock.PgxPoolIface)
+		want    domain.Book
 		wantErr bool
+	}{
+		{
+			name: "success",
+			isbn: "9780743273565",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(bookRowColumns).
+					AddRow(1, "Test Book", "Test Author", "9780743273565", 1, testTime, testTime, nil)
+				mock.ExpectQuery("SELECT (.+) FROM books WHERE isbn").
+					WithArgs("9780743273565").
+					WillReturnRows(rows)
+				mock.ExpectQuery("FROM book_authors").
+					WithArgs([]int{1}).
+					WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns))
+			},
+			want:    domain.Book{ID: 1, Title: "Test Book", Author: "Test Author", Authors: []domain.Author{}, ISBN: "9780743273565", Version: 1, CreatedAt: testTime, UpdatedAt: testTime},
+			wantErr: false,
+		},
+		{
+			name: "not found",
+			isbn: "9780743273565",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT (.+) FROM books WHERE isbn").
+					WithArgs("9780743273565").
+					WillReturnError(pgx.ErrNoRows)
+			},
+			want:    domain.Book{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresBookRepo(mock)
+			got, err := r.GetBookByISBN(context.Background(), tt.isbn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostgresBookRepo.GetBookByISBN() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresBookRepo.GetBookByISBN() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresBookRepo_GetBooks(t *testing.T) {
+	tests := []struct {
+		name     string
+		criteria domain.BookCriteria
+		offset   int
+		limit    int
+		setup    func(pgxmock.PgxPoolIface)
+		want     []domain.Book
+		wantErr  bool
 	}{
 		{
 			name:   "success with books",
 			offset: 0,
 			limit:  10,
 			setup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"id", "title", "author"}).
-					AddRow(1, "Book 1", "Author 1").
-					AddRow(2, "Book 2", "Author 2")
-				mock.ExpectQuery("SELECT id, title, author FROM books ORDER BY id ASC").
+				rows := pgxmock.NewRows(bookRowColumns).
+					AddRow(1, "Book 1", "Author 1", nil, 1, testTime, testTime, nil).
+					AddRow(2, "Book 2", "Author 2", nil, 1, testTime, testTime, nil)
+				mock.ExpectQuery("SELECT (.+) FROM books WHERE deleted_at IS NULL ORDER BY id ASC").
 					WithArgs(10, 0).
 					WillReturnRows(rows)
+				mock.ExpectQuery("FROM book_authors").
+					WithArgs([]int{1, 2}).
+					WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns).
+						AddRow(1, 1, "Author 1", testTime, testTime).
+						AddRow(2, 2, "Author 2", testTime, testTime))
 			},
 			want: []domain.Book{
-				{ID: 1, Title: "Book 1", Author: "Author 1"},
-				{ID: 2, Title: "Book 2", Author: "Author 2"},
+				{ID: 1, Title: "Book 1", Author: "Author 1", Authors: []domain.Author{{ID: 1, Name: "Author 1", CreatedAt: testTime, UpdatedAt: testTime}}, Version: 1, CreatedAt: testTime, UpdatedAt: testTime},
+				{ID: 2, Title: "Book 2", Author: "Author 2", Authors: []domain.Author{{ID: 2, Name: "Author 2", CreatedAt: testTime, UpdatedAt: testTime}}, Version: 1, CreatedAt: testTime, UpdatedAt: testTime},
 			},
 			wantErr: false,
 		},
@@ -166,20 +477,43 @@ func TestPostgresBookRepo_GetBooks(t *testing.T) {
 			offset: 0,
 			limit:  10,
 			setup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"id", "title", "author"})
-				mock.ExpectQuery("SELECT id, title, author FROM books ORDER BY id ASC").
+				rows := pgxmock.NewRows(bookRowColumns)
+				mock.ExpectQuery("SELECT (.+) FROM books WHERE deleted_at IS NULL ORDER BY id ASC").
 					WithArgs(10, 0).
 					WillReturnRows(rows)
 			},
 			want:    []domain.Book{},
 			wantErr: false,
 		},
+		{
+			name: "filtered and sorted",
+			criteria: domain.BookCriteria{
+				Author:        "George Orwell",
+				TitleContains: "50%_off",
+				CreatedAfter:  testTime,
+				CreatedBefore: testTime.Add(time.Hour),
+				Sort:          domain.BookSort{{Field: "created_at", Desc: true}, {Field: "title"}},
+			},
+			offset: 20,
+			limit:  10,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(`FROM books WHERE deleted_at IS NULL`+
+					` AND id IN \(SELECT ba.book_id FROM book_authors ba JOIN authors a ON a.id = ba.author_id WHERE lower\(a.name\) = lower\(\$1\)\)`+
+					` AND title ILIKE '%' \|\| \$2 \|\| '%'`+
+					` AND created_at > \$3 AND created_at < \$4`+
+					` ORDER BY created_at DESC, title ASC, id ASC LIMIT \$5 OFFSET \$6`).
+					WithArgs("George Orwell", `50\%\_off`, testTime, testTime.Add(time.Hour), 10, 20).
+					WillReturnRows(pgxmock.NewRows(bookRowColumns))
+			},
+			want:    []domain.Book{},
+			wantErr: false,
+		},
 		{
 			name:   "query error",
 			offset: 0,
 			limit:  10,
 			setup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectQuery("SELECT id, title, author FROM books ORDER BY id ASC").
+				mock.ExpectQuery("SELECT (.+) FROM books WHERE deleted_at IS NULL ORDER BY id ASC").
 					WithArgs(10, 0).
 					WillReturnError(pgx.ErrTxClosed)
 			},
@@ -191,11 +525,11 @@ func TestPostgresBookRepo_GetBooks(t *testing.T) {
 			offset: 0,
 			limit:  10,
 			setup: func(mock pgxmock.PgxPoolIface) {
-				rows := pgxmock.NewRows([]string{"id", "title", "author"}).
-					AddRow(1, "Book 1", "Author 1").
-					AddRow(2, "Book 2", "Author 2").
+				rows := pgxmock.NewRows(bookRowColumns).
+					AddRow(1, "Book 1", "Author 1", nil, 1, testTime, testTime, nil).
+					AddRow(2, "Book 2", "Author 2", nil, 1, testTime, testTime, nil).
 					RowError(1, pgx.ErrTxClosed)
-				mock.ExpectQuery("SELECT id, title, author FROM books ORDER BY id ASC").
+				mock.ExpectQuery("SELECT (.+) FROM books WHERE deleted_at IS NULL ORDER BY id ASC").
 					WithArgs(10, 0).
 					WillReturnRows(rows)
 			},
@@ -214,7 +548,7 @@ func TestPostgresBookRepo_GetBooks(t *testing.T) {
 			tt.setup(mock)
 
 			r := NewPostgresBookRepo(mock)
-			got, err := r.GetBooks(context.Background(), tt.offset, tt.limit)
+			got, err := r.GetBooks(context.Background(), tt.criteria, tt.offset, tt.limit)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("PostgresBookRepo.GetBooks() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -230,42 +564,103 @@ func TestPostgresBookRepo_GetBooks(t *testing.T) {
 	}
 }
 
-func TestPostgresBookRepo_UpdateBook(t *testing.T) {
+func TestPostgresBookRepo_ExportBooks(t *testing.T) {
+	errStop := errors.New("client went away")
+	fullIDs := make([]int, exportFetchSize)
+	fullRows := func() *pgxmock.Rows {
+		rows := pgxmock.NewRows(bookRowColumns)
+		for i := range fullIDs {
+			fullIDs[i] = i + 1
+			rows.AddRow(i+1, "Book", "Author", nil, 1, testTime, testTime, nil)
+		}
+		return rows
+	}
 	tests := []struct {
-		name    string
-		book    domain.Book
-		setup   func(pgxmock.PgxPoolIface)
-		wantErr bool
+		name       string
+		criteria   domain.BookCriteria
+		fnErr      error
+		setup      func(pgxmock.PgxPoolIface)
+		wantChunks []int
+		wantErr    error
 	}{
 		{
-			name: "success",
-			book: domain.Book{ID: 1, Title: "Updated Book", Author: "Updated Author"},
+			name: "filtered and sorted",
+			criteria: domain.BookCriteria{
+				TitleContains: "gatsby",
+				Sort:          domain.BookSort{{Field: "title", Desc: true}},
+			},
 			setup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectExec("UPDATE books SET title").
-					WithArgs("Updated Book", "Updated Author", 1).
-					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mock.ExpectBegin()
+				mock.ExpectExec(`DECLARE books_export NO SCROLL CURSOR FOR SELECT (.+) FROM books WHERE deleted_at IS NULL` +
+					` AND title ILIKE '%' \|\| \$1 \|\| '%' ORDER BY title DESC, id ASC`).
+					WithArgs("gatsby").
+					WillReturnResult(pgxmock.NewResult("DECLARE CURSOR", 0))
+				mock.ExpectQuery("FETCH 1000 FROM books_export").
+					WillReturnRows(pgxmock.NewRows(bookRowColumns).
+						AddRow(2, "Gatsby 2", "Author 1", nil, 1, testTime, testTime, nil).
+						AddRow(1, "Gatsby 1", "Author 1", nil, 1, testTime, testTime, nil))
+				mock.ExpectQuery("FROM book_authors").
+					WithArgs([]int{2, 1}).
+					WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns).
+						AddRow(2, 1, "Author 1", testTime, testTime).
+						AddRow(1, 1, "Author 1", testTime, testTime))
+				mock.ExpectCommit()
 			},
-			wantErr: false,
+			wantChunks: []int{2},
 		},
 		{
-			name: "not found - zero rows affected",
-			book: domain.Book{ID: 999, Title: "Updated Book", Author: "Updated Author"},
+			name: "full chunk fetches again",
 			setup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectExec("UPDATE books SET title").
-					WithArgs("Updated Book", "Updated Author", 999).
-					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+				mock.ExpectBegin()
+				mock.ExpectExec("DECLARE books_export").
+					WillReturnResult(pgxmock.NewResult("DECLARE CURSOR", 0))
+				mock.ExpectQuery("FETCH 1000 FROM books_export").WillReturnRows(fullRows())
+				mock.ExpectQuery("FROM book_authors").
+					WithArgs(fullIDs).
+					WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns))
+				mock.ExpectQuery("FETCH 1000 FROM books_export").
+					WillReturnRows(pgxmock.NewRows(bookRowColumns))
+				mock.ExpectCommit()
 			},
-			wantErr: true,
+			wantChunks: []int{exportFetchSize},
 		},
 		{
-			name: "db error",
-			book: domain.Book{ID: 1, Title: "Updated Book", Author: "Updated Author"},
+			name: "no books",
 			setup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectExec("UPDATE books SET title").
-					WithArgs("Updated Book", "Updated Author", 1).
-					WillReturnError(pgx.ErrTxClosed)
+				mock.ExpectBegin()
+				mock.ExpectExec("DECLARE books_export").
+					WillReturnResult(pgxmock.NewResult("DECLARE CURSOR", 0))
+				mock.ExpectQuery("FETCH 1000 FROM books_export").
+					WillReturnRows(pgxmock.NewRows(bookRowColumns))
+				mock.ExpectCommit()
 			},
-			wantErr: true,
+		},
+		{
+			name:  "callback error rolls back",
+			fnErr: errStop,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec("DECLARE books_export").
+					WillReturnResult(pgxmock.NewResult("DECLARE CURSOR", 0))
+				mock.ExpectQuery("FETCH 1000 FROM books_export").
+					WillReturnRows(pgxmock.NewRows(bookRowColumns).
+						AddRow(1, "Book 1", "Author 1", nil, 1, testTime, testTime, nil))
+				mock.ExpectQuery("FROM book_authors").
+					WithArgs([]int{1}).
+					WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns))
+				mock.ExpectRollback()
+			},
+			wantChunks: []int{1},
+			wantErr:    errStop,
+		},
+		{
+			name: "declare error",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec("DECLARE books_export").WillReturnError(pgx.ErrTxClosed)
+				mock.ExpectRollback()
+			},
+			wantErr: pgx.ErrTxClosed,
 		},
 	}
 	for _, tt := range tests {
@@ -279,8 +674,16 @@ func TestPostgresBookRepo_UpdateBook(t *testing.T) {
 			tt.setup(mock)
 
 			r := NewPostgresBookRepo(mock)
-			if err := r.UpdateBook(context.Background(), tt.book); (err != nil) != tt.wantErr {
-				t.Errorf("PostgresBookRepo.UpdateBook() error = %v, wantErr %v", err, tt.wantErr)
+			var chunks []int
+			err = r.ExportBooks(context.Background(), tt.criteria, func(books []domain.Book) error {
+				chunks = append(chunks, len(books))
+				return tt.fnErr
+			})
+			if !errors.Is(err, tt.wantErr) || (err != nil) != (tt.wantErr != nil) {
+				t.Errorf("PostgresBookRepo.ExportBooks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(chunks, tt.wantChunks) {
+				t.Errorf("PostgresBookRepo.ExportBooks() chunks = %v, want %v", chunks, tt.wantChunks)
 			}
 
 			if err := mock.ExpectationsWereMet(); err != nil {
@@ -290,42 +693,47 @@ func TestPostgresBookRepo_UpdateBook(t *testing.T) {
 	}
 }
 
-func TestPostgresBookRepo_DeleteBook(t *testing.T) {
+func TestPostgresBookRepo_Count(t *testing.T) {
 	tests := []struct {
 		name    string
-		id      int
-		setup   func(pgxmock.PgxPoolIface)
-		wantErr bool
+		count   func(*PostgresBookRepo) (int, error)
+		query   string
+		args    []any
+		wantErr error
 	}{
 		{
-			name: "success",
-			id:   1,
-			setup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectExec("DELETE FROM books WHERE id").
-					WithArgs(1).
-					WillReturnResult(pgxmock.NewResult("DELETE", 1))
+			name: "books",
+			count: func(r *PostgresBookRepo) (int, error) {
+				return r.CountBooks(context.Background(), domain.BookCriteria{})
 			},
-			wantErr: false,
+			query: "SELECT COUNT\\(\\*\\) FROM books WHERE deleted_at IS NULL$",
 		},
 		{
-			name: "not found - zero rows affected",
-			id:   999,
-			setup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectExec("DELETE FROM books WHERE id").
-					WithArgs(999).
-					WillReturnResult(pgxmock.NewResult("DELETE", 0))
+			name: "filtered books",
+			count: func(r *PostgresBookRepo) (int, error) {
+				return r.CountBooks(context.Background(), domain.BookCriteria{TitleContains: "farm", Sort: domain.BookSort{{Field: "title"}}})
 			},
-			wantErr: true,
+			query: "SELECT COUNT\\(\\*\\) FROM books WHERE deleted_at IS NULL AND title ILIKE '%' \\|\\| \\$1 \\|\\| '%'$",
+			args:  []any{"farm"},
 		},
 		{
-			name: "db error",
-			id:   1,
-			setup: func(mock pgxmock.PgxPoolIface) {
-				mock.ExpectExec("DELETE FROM books WHERE id").
-					WithArgs(1).
-					WillReturnError(pgx.ErrTxClosed)
+			name:  "books by author",
+			count: func(r *PostgresBookRepo) (int, error) { return r.CountBooksByAuthor(context.Background(), 1) },
+			query: "SELECT COUNT\\(\\*\\) FROM books WHERE deleted_at IS NULL AND id IN \\(SELECT book_id FROM book_authors WHERE author_id = \\$1\\)",
+			args:  []any{1},
+		},
+		{
+			name:  "deleted books",
+			count: func(r *PostgresBookRepo) (int, error) { return r.CountDeletedBooks(context.Background()) },
+			query: "SELECT COUNT\\(\\*\\) FROM books WHERE deleted_at IS NOT NULL",
+		},
+		{
+			name: "query error",
+			count: func(r *PostgresBookRepo) (int, error) {
+				return r.CountBooks(context.Background(), domain.BookCriteria{})
 			},
-			wantErr: true,
+			query:   "SELECT COUNT\\(\\*\\) FROM books",
+			wantErr: pgx.ErrTxClosed,
 		},
 	}
 	for _, tt := range tests {
@@ -336,11 +744,19 @@ func TestPostgresBookRepo_DeleteBook(t *testing.T) {
 			}
 			defer mock.Close()
 
-			tt.setup(mock)
+			exp := mock.ExpectQuery(tt.query).WithArgs(tt.args...)
+			if tt.wantErr != nil {
+				exp.WillReturnError(tt.wantErr)
+			} else {
+				exp.WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(3))
+			}
 
-			r := NewPostgresBookRepo(mock)
-			if err := r.DeleteBook(context.Background(), tt.id); (err != nil) != tt.wantErr {
-				t.Errorf("PostgresBookRepo.DeleteBook() error = %v, wantErr %v", err, tt.wantErr)
+			got, err := tt.count(NewPostgresBookRepo(mock))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("count error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got != 3 {
+				t.Errorf("count = %d, want 3", got)
 			}
 
 			if err := mock.ExpectationsWereMet(); err != nil {
@@ -349,3 +765,706 @@ func TestPostgresBookRepo_DeleteBook(t *testing.T) {
 		})
 	}
 }
+
+func TestPostgresBookRepo_GetBooksByCursor(t *testing.T) {
+	book := func(id int) domain.Book {
+		return domain.Book{ID: id, Title: "Book", Author: "Author", Authors: []domain.Author{}, Version: 1, CreatedAt: testTime, UpdatedAt: testTime}
+	}
+	byNewest := domain.BookSort{{Field: "created_at", Desc: true}}
+	tests := []struct {
+		name    string
+		sort    domain.BookSort
+		cursor  domain.Cursor
+		setup   func(pgxmock.PgxPoolIface)
+		want    []domain.Book
+		wantErr error
+	}{
+		{
+			name:   "first page",
+			cursor: domain.Cursor{},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(`FROM books WHERE deleted_at IS NULL ORDER BY id ASC LIMIT \$1`).
+					WithArgs(3).
+					WillReturnRows(pgxmock.NewRows(bookRowColumns).
+						AddRow(1, "Book", "Author", nil, 1, testTime, testTime, nil).
+						AddRow(2, "Book", "Author", nil, 1, testTime, testTime, nil))
+				mock.ExpectQuery("FROM book_authors").
+					WithArgs([]int{1, 2}).
+					WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns))
+			},
+			want:    []domain.Book{book(1), book(2)},
+			wantErr: nil,
+		},
+		{
+			name:   "after cursor",
+			cursor: domain.Cursor{Sort: domain.SortByID, Key: []string{"2"}},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(`id > \$1 ORDER BY id ASC`).
+					WithArgs(2, 3).
+					WillReturnRows(pgxmock.NewRows(bookRowColumns).
+						AddRow(3, "Book", "Author", nil, 1, testTime, testTime, nil))
+				mock.ExpectQuery("FROM book_authors").
+					WithArgs([]int{3}).
+					WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns))
+			},
+			want:    []domain.Book{book(3)},
+			wantErr: nil,
+		},
+		{
+			name:   "before cursor - returned in list order",
+			cursor: domain.Cursor{Sort: domain.SortByID, Key: []string{"5"}, Backward: true},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(`id < \$1 ORDER BY id DESC`).
+					WithArgs(5, 3).
+					WillReturnRows(pgxmock.NewRows(bookRowColumns).
+						AddRow(4, "Book", "Author", nil, 1, testTime, testTime, nil).
+						AddRow(3, "Book", "Author", nil, 1, testTime, testTime, nil))
+				mock.ExpectQuery("FROM book_authors").
+					WithArgs([]int{4, 3}).
+					WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns))
+			},
+			want:    []domain.Book{book(3), book(4)},
+			wantErr: nil,
+		},
+		{
+			name:   "sorted after cursor",
+			sort:   byNewest,
+			cursor: domain.Cursor{Sort: "-created_at", Key: []string{testTime.Format(time.RFC3339Nano), "2"}},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(`AND \(created_at < \$1 OR \(created_at = \$1 AND id > \$2\)\) ORDER BY created_at DESC, id ASC LIMIT \$3`).
+					WithArgs(testTime, 2, 3).
+					WillReturnRows(pgxmock.NewRows(bookRowColumns))
+			},
+			want:    []domain.Book{},
+			wantErr: nil,
+		},
+		{
+			name:   "sorted before cursor",
+			sort:   byNewest,
+			cursor: domain.Cursor{Sort: "-created_at", Key: []string{testTime.Format(time.RFC3339Nano), "2"}, Backward: true},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(`AND \(created_at > \$1 OR \(created_at = \$1 AND id < \$2\)\) ORDER BY created_at ASC, id DESC LIMIT \$3`).
+					WithArgs(testTime, 2, 3).
+					WillReturnRows(pgxmock.NewRows(bookRowColumns))
+			},
+			want:    []domain.Book{},
+			wantErr: nil,
+		},
+		{
+			name:    "key does not fit the sort",
+			sort:    byNewest,
+			cursor:  domain.Cursor{Sort: "-created_at", Key: []string{"2"}},
+			setup:   func(mock pgxmock.PgxPoolIface) {},
+			want:    []domain.Book{},
+			wantErr: domain.ErrInvalidCursor,
+		},
+		{
+			name:    "malformed key",
+			cursor:  domain.Cursor{Sort: domain.SortByID, Key: []string{"abc"}},
+			setup:   func(mock pgxmock.PgxPoolIface) {},
+			want:    []domain.Book{},
+			wantErr: domain.ErrInvalidCursor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresBookRepo(mock)
+			got, err := r.GetBooksByCursor(context.Background(), domain.BookCriteria{Sort: tt.sort}, tt.cursor, 3)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresBookRepo.GetBooksByCursor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresBookRepo.GetBooksByCursor() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresBookRepo_GetBooksByAuthor(t *testing.T) {
+	tests := []struct {
+		name     string
+		authorID int
+		setup    func(pgxmock.PgxPoolIface)
+		want     []domain.Book
+		wantErr  bool
+	}{
+		{
+			name:     "success",
+			authorID: 1,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				rows := pgxmock.NewRows(bookRowColumns).
+					AddRow(1, "Test Book", "Test Author", nil, 1, testTime, testTime, nil)
+				mock.ExpectQuery("SELECT (.+) FROM books WHERE deleted_at IS NULL AND id IN \\(SELECT book_id FROM book_authors WHERE author_id").
+					WithArgs(1, 10, 0).
+					WillReturnRows(rows)
+				mock.ExpectQuery("FROM book_authors").
+					WithArgs([]int{1}).
+					WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns).AddRow(1, 1, "Test Author", testTime, testTime))
+			},
+			want: []domain.Book{
+				{ID: 1, Title: "Test Book", Author: "Test Author", Authors: []domain.Author{testAuthor}, Version: 1, CreatedAt: testTime, UpdatedAt: testTime},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "query error",
+			authorID: 1,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT (.+) FROM books WHERE deleted_at IS NULL AND id IN").
+					WithArgs(1, 10, 0).
+					WillReturnError(pgx.ErrTxClosed)
+			},
+			want:    []domain.Book{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresBookRepo(mock)
+			got, err := r.GetBooksByAuthor(context.Background(), tt.authorID, 0, 10)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostgresBookRepo.GetBooksByAuthor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresBookRepo.GetBooksByAuthor() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresBookRepo_RefreshBylines(t *testing.T) {
+	renamed := domain.Author{ID: 1, Name: "Renamed Author", CreatedAt: testTime, UpdatedAt: testTime}
+
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	// RETURNING rows come back in no particular order.
+	rows := pgxmock.NewRows(bookRowColumns).
+		AddRow(2, "Second Book", "Renamed Author", nil, 4, testTime, testTime, nil).
+		AddRow(1, "Test Book", "Renamed Author", nil, 2, testTime, testTime, nil)
+	mock.ExpectQuery("UPDATE books b SET author = (.+) version = b.version \\+ 1(.+) WHERE b.id = ANY\\(\\$1\\) RETURNING").
+		WithArgs([]int{1, 2}).
+		WillReturnRows(rows)
+	mock.ExpectQuery("FROM book_authors").
+		WithArgs([]int{2, 1}).
+		WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns).
+			AddRow(1, 1, "Renamed Author", testTime, testTime).
+			AddRow(2, 1, "Renamed Author", testTime, testTime))
+
+	r := NewPostgresBookRepo(mock)
+	got, err := r.RefreshBylines(context.Background(), []int{1, 2})
+	if err != nil {
+		t.Fatalf("PostgresBookRepo.RefreshBylines() error = %v", err)
+	}
+	want := []domain.Book{
+		{ID: 1, Title: "Test Book", Author: "Renamed Author", Authors: []domain.Author{renamed}, Version: 2, CreatedAt: testTime, UpdatedAt: testTime},
+		{ID: 2, Title: "Second Book", Author: "Renamed Author", Authors: []domain.Author{renamed}, Version: 4, CreatedAt: testTime, UpdatedAt: testTime},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PostgresBookRepo.RefreshBylines() = %v, want %v", got, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostgresBookRepo_UpdateBook(t *testing.T) {
+	tests := []struct {
+		name    string
+		book    domain.Book
+		setup   func(pgxmock.PgxPoolIface)
+		want    domain.Book
+		wantErr error
+	}{
+		{
+			name: "success",
+			book: domain.Book{ID: 1, Title: "Updated Book", Author: "Updated Author", Authors: []domain.Author{{ID: 3, Name: "Updated Author"}}},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE books SET title").
+					WithArgs("Updated Book", "Updated Author", "", 1, 0).
+					WillReturnRows(pgxmock.NewRows(bookRowColumns).AddRow(1, "Updated Book", "Updated Author", nil, 2, testTime, testTime, nil))
+				mock.ExpectExec("DELETE FROM book_authors").
+					WithArgs(1).
+					WillReturnResult(pgxmock.NewResult("DELETE", 1))
+				mock.ExpectExec("INSERT INTO book_authors").
+					WithArgs(1, []int{3}).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+			},
+			want:    domain.Book{ID: 1, Title: "Updated Book", Author: "Updated Author", Authors: []domain.Author{{ID: 3, Name: "Updated Author"}}, Version: 2, CreatedAt: testTime, UpdatedAt: testTime},
+			wantErr: nil,
+		},
+		{
+			name: "version conflict",
+			book: domain.Book{ID: 1, Title: "Updated Book", Author: "Updated Author", Version: 1},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE books SET title").
+					WithArgs("Updated Book", "Updated Author", "", 1, 1).
+					WillReturnError(pgx.ErrNoRows)
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(1).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectRollback()
+			},
+			want:    domain.Book{},
+			wantErr: domain.ErrVersionConflict,
+		},
+		{
+			name: "not found",
+			book: domain.Book{ID: 999, Title: "Updated Book", Author: "Updated Author"},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE books SET title").
+					WithArgs("Updated Book", "Updated Author", "", 999, 0).
+					WillReturnError(pgx.ErrNoRows)
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(999).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectRollback()
+			},
+			want:    domain.Book{},
+			wantErr: domain.ErrBookNotFound,
+		},
+		{
+			name: "db error",
+			book: domain.Book{ID: 1, Title: "Updated Book", Author: "Updated Author"},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE books SET title").
+					WithArgs("Updated Book", "Updated Author", "", 1, 0).
+					WillReturnError(pgx.ErrTxClosed)
+				mock.ExpectRollback()
+			},
+			want:    domain.Book{},
+			wantErr: pgx.ErrTxClosed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresBookRepo(mock)
+			got, err := r.UpdateBook(context.Background(), tt.book)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresBookRepo.UpdateBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresBookRepo.UpdateBook() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresBookRepo_PatchBook(t *testing.T) {
+	title := "New Title"
+	byline := "Updated Author"
+	tests := []struct {
+		name    string
+		id      int
+		version int
+		patch   domain.BookPatch
+		setup   func(pgxmock.PgxPoolIface)
+		want    domain.Book
+		wantErr error
+	}{
+		{
+			name:  "title only",
+			id:    1,
+			patch: domain.BookPatch{Title: &title},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE books SET title = \$1, version = version \+ 1, updated_at = CURRENT_TIMESTAMP WHERE id = \$2`).
+					WithArgs("New Title", 1, 0).
+					WillReturnRows(pgxmock.NewRows(bookRowColumns).AddRow(1, "New Title", "Test Author", nil, 2, testTime, testTime, nil))
+				mock.ExpectCommit()
+				mock.ExpectQuery("FROM book_authors").
+					WithArgs([]int{1}).
+					WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns).AddRow(1, 1, "Test Author", testTime, testTime))
+			},
+			want:    domain.Book{ID: 1, Title: "New Title", Author: "Test Author", Authors: []domain.Author{testAuthor}, Version: 2, CreatedAt: testTime, UpdatedAt: testTime},
+			wantErr: nil,
+		},
+		{
+			name:    "authors relinked",
+			id:      1,
+			version: 1,
+			patch:   domain.BookPatch{Author: &byline, Authors: []domain.Author{{ID: 3, Name: "Updated Author"}}},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`UPDATE books SET author = \$1, version`).
+					WithArgs("Updated Author", 1, 1).
+					WillReturnRows(pgxmock.NewRows(bookRowColumns).AddRow(1, "Test Book", "Updated Author", nil, 2, testTime, testTime, nil))
+				mock.ExpectExec("DELETE FROM book_authors").
+					WithArgs(1).
+					WillReturnResult(pgxmock.NewResult("DELETE", 1))
+				mock.ExpectExec("INSERT INTO book_authors").
+					WithArgs(1, []int{3}).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+			},
+			want:    domain.Book{ID: 1, Title: "Test Book", Author: "Updated Author", Authors: []domain.Author{{ID: 3, Name: "Updated Author"}}, Version: 2, CreatedAt: testTime, UpdatedAt: testTime},
+			wantErr: nil,
+		},
+		{
+			name:    "version conflict",
+			id:      1,
+			version: 1,
+			patch:   domain.BookPatch{Title: &title},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE books SET title").
+					WithArgs("New Title", 1, 1).
+					WillReturnError(pgx.ErrNoRows)
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(1).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectRollback()
+			},
+			want:    domain.Book{},
+			wantErr: domain.ErrVersionConflict,
+		},
+		{
+			name:  "not found",
+			id:    999,
+			patch: domain.BookPatch{Title: &title},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("UPDATE books SET title").
+					WithArgs("New Title", 999, 0).
+					WillReturnError(pgx.ErrNoRows)
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(999).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectRollback()
+			},
+			want:    domain.Book{},
+			wantErr: domain.ErrBookNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresBookRepo(mock)
+			got, err := r.PatchBook(context.Background(), tt.id, tt.version, tt.patch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresBookRepo.PatchBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresBookRepo.PatchBook() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresBookRepo_DeleteBook(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		version int
+		setup   func(pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "success",
+			id:   1,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("UPDATE books SET deleted_at = CURRENT_TIMESTAMP").
+					WithArgs(1, 0).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "version conflict",
+			id:      1,
+			version: 2,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("UPDATE books SET deleted_at = CURRENT_TIMESTAMP").
+					WithArgs(1, 2).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(1).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			wantErr: domain.ErrVersionConflict,
+		},
+		{
+			name: "not found - zero rows affected",
+			id:   999,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("UPDATE books SET deleted_at = CURRENT_TIMESTAMP").
+					WithArgs(999, 0).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+				mock.ExpectQuery("SELECT EXISTS").
+					WithArgs(999).
+					WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			wantErr: domain.ErrBookNotFound,
+		},
+		{
+			name: "db error",
+			id:   1,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("UPDATE books SET deleted_at = CURRENT_TIMESTAMP").
+					WithArgs(1, 0).
+					WillReturnError(pgx.ErrTxClosed)
+			},
+			wantErr: pgx.ErrTxClosed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresBookRepo(mock)
+			if err := r.DeleteBook(context.Background(), tt.id, tt.version); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresBookRepo.DeleteBook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresBookRepo_GetDeletedBooks(t *testing.T) {
+	deletedAt := testTime.Add(time.Hour)
+
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery("SELECT (.+) FROM books WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC").
+		WithArgs(10, 0).
+		WillReturnRows(pgxmock.NewRows(bookRowColumns).
+			AddRow(1, "Test Book", "Test Author", nil, 1, testTime, testTime, deletedAt))
+	mock.ExpectQuery("SELECT (.+) FROM book_authors ba JOIN authors a").
+		WithArgs([]int{1}).
+		WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns).AddRow(1, 1, "Test Author", testTime, testTime))
+
+	r := NewPostgresBookRepo(mock)
+	got, err := r.GetDeletedBooks(context.Background(), 0, 10)
+	if err != nil {
+		t.Fatalf("PostgresBookRepo.GetDeletedBooks() error = %v", err)
+	}
+	want := []domain.Book{{ID: 1, Title: "Test Book", Author: "Test Author", Authors: []domain.Author{testAuthor}, Version: 1, CreatedAt: testTime, UpdatedAt: testTime, DeletedAt: &deletedAt}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PostgresBookRepo.GetDeletedBooks() = %v, want %v", got, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostgresBookRepo_RestoreBook(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		setup   func(pgxmock.PgxPoolIface)
+		want    domain.Book
+		wantErr error
+	}{
+		{
+			name: "success",
+			id:   1,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE books SET deleted_at = NULL").
+					WithArgs(1).
+					WillReturnRows(pgxmock.NewRows(bookRowColumns).
+						AddRow(1, "Test Book", "Test Author", nil, 1, testTime, testTime, nil))
+				mock.ExpectQuery("SELECT (.+) FROM book_authors ba JOIN authors a").
+					WithArgs([]int{1}).
+					WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns).AddRow(1, 1, "Test Author", testTime, testTime))
+			},
+			want:    domain.Book{ID: 1, Title: "Test Book", Author: "Test Author", Authors: []domain.Author{testAuthor}, Version: 1, CreatedAt: testTime, UpdatedAt: testTime},
+			wantErr: nil,
+		},
+		{
+			name: "not in trash",
+			id:   2,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE books SET deleted_at = NULL").
+					WithArgs(2).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			want:    domain.Book{},
+			wantErr: domain.ErrBookNotInTrash,
+		},
+		{
+			name: "isbn taken by a live book",
+			id:   1,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE books SET deleted_at = NULL").
+					WithArgs(1).
+					WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "books_isbn_key"})
+			},
+			want:    domain.Book{},
+			wantErr: domain.ErrDuplicateISBN,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresBookRepo(mock)
+			got, err := r.RestoreBook(context.Background(), tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresBookRepo.RestoreBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresBookRepo.RestoreBook() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresBookRepo_PurgeBook(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		setup   func(pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "success",
+			id:   1,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("DELETE FROM books WHERE id = \\$1 AND deleted_at IS NOT NULL").
+					WithArgs(1).
+					WillReturnResult(pgxmock.NewResult("DELETE", 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name: "not in trash",
+			id:   2,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("DELETE FROM books WHERE id = \\$1 AND deleted_at IS NOT NULL").
+					WithArgs(2).
+					WillReturnResult(pgxmock.NewResult("DELETE", 0))
+			},
+			wantErr: domain.ErrBookNotInTrash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresBookRepo(mock)
+			if err := r.PurgeBook(context.Background(), tt.id); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresBookRepo.PurgeBook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresBookRepo_PurgeDeletedBooks(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectExec("DELETE FROM books WHERE deleted_at < \\$1").
+		WithArgs(testTime).
+		WillReturnResult(pgxmock.NewResult("DELETE", 3))
+
+	r := NewPostgresBookRepo(mock)
+	got, err := r.PurgeDeletedBooks(context.Background(), testTime)
+	if err != nil {
+		t.Fatalf("PostgresBookRepo.PurgeDeletedBooks() error = %v", err)
+	}
+	if got != 3 {
+		t.Errorf("PostgresBookRepo.PurgeDeletedBooks() = %v, want %v", got, 3)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,199 @@
+package repositories
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// searchConfig is the text search configuration of the books.search column.
+const searchConfig = "english"
+
+// searchHighlightOptions makes ts_headline return the whole field with the
+// matched words between domain.HighlightStart and domain.HighlightEnd.
+const searchHighlightOptions = "StartSel=" + domain.HighlightStart + ", StopSel=" + domain.HighlightEnd + ", HighlightAll=true"
+
+// PostgresBookSearch searches books through the generated tsvector column
+// books.search. It only reads, and never from within a book write, so it
+// queries the pool rather than an ambient transaction.
+type PostgresBookSearch struct {
+	db PgxIface
+}
+
+var _ out.BookSearcher = &PostgresBookSearch{}
+
+func NewPostgresBookSearch(db PgxIface) *PostgresBookSearch {
+	return &PostgresBookSearch{db: db}
+}
+
+func (s *PostgresBookSearch) SearchBooks(ctx context.Context, query domain.SearchQuery, offset, limit int) ([]domain.BookHit, error) {
+	return queryBookHits(
+		ctx,
+		s.db,
+		"SELECT "+bookColumns+", ts_rank(search, q), ts_headline('"+searchConfig+"', title, q, $2), ts_headline('"+searchConfig+"', author, q, $2)"+
+			" FROM books, to_tsquery('"+searchConfig+"', $1) q WHERE deleted_at IS NULL AND search @@ q"+
+			" ORDER BY ts_rank(search, q) DESC, id ASC LIMIT $3 OFFSET $4",
+		tsquery(query),
+		searchHighlightOptions,
+		limit,
+		offset,
+	)
+}
+
+func (s *PostgresBookSearch) CountSearchResults(ctx context.Context, query domain.SearchQuery) (int, error) {
+	var n int
+	err := s.db.QueryRow(
+		ctx,
+		"SELECT COUNT(*) FROM books WHERE deleted_at IS NULL AND search @@ to_tsquery('"+searchConfig+"', $1)",
+		tsquery(query),
+	).Scan(&n)
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// FuzzySearchBooks scores each book by the word similarity of text to its
+// title or author, whichever is higher. The highlights are the plain title
+// and author, as trigram matches do not map back to words.
+func (s *PostgresBookSearch) FuzzySearchBooks(ctx context.Context, text string, threshold float64, offset, limit int) ([]domain.BookHit, error) {
+	var hits []domain.BookHit
+	err := withWordSimilarityThreshold(ctx, s.db, threshold, func(tx pgx.Tx) error {
+		var err error
+		hits, err = queryBookHits(
+			ctx,
+			tx,
+			"SELECT "+bookColumns+", greatest(word_similarity($1, title), word_similarity($1, author)) AS similarity, title, author"+
+				" FROM books WHERE deleted_at IS NULL AND ($1 <% title OR $1 <% author)"+
+				" ORDER BY similarity DESC, id ASC LIMIT $2 OFFSET $3",
+			text,
+			limit,
+			offset,
+		)
+		return err
+	})
+	if err != nil {
+		return []domain.BookHit{}, err
+	}
+	return hits, nil
+}
+
+func (s *PostgresBookSearch) CountFuzzySearchResults(ctx context.Context, text string, threshold float64) (int, error) {
+	var n int
+	err := withWordSimilarityThreshold(ctx, s.db, threshold, func(tx pgx.Tx) error {
+		return tx.QueryRow(
+			ctx,
+			"SELECT COUNT(*) FROM books WHERE deleted_at IS NULL AND ($1 <% title OR $1 <% author)",
+			text,
+		).Scan(&n)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (s *PostgresBookSearch) SuggestBooks(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error) {
+	rows, err := s.db.Query(
+		ctx,
+		"SELECT text, field FROM ("+
+			"(SELECT DISTINCT title AS text, 'title' AS field FROM books WHERE deleted_at IS NULL AND title ILIKE $1 ORDER BY title LIMIT $2)"+
+			" UNION ALL "+
+			"(SELECT name, 'author' FROM authors WHERE name ILIKE $1 ORDER BY name LIMIT $2)"+
+			") s ORDER BY length(text), text, field LIMIT $2",
+		likeEscaper.Replace(prefix)+"%",
+		limit,
+	)
+	if err != nil {
+		return []domain.Suggestion{}, err
+	}
+	defer rows.Close()
+
+	suggestions := []domain.Suggestion{}
+	for rows.Next() {
+		var sg domain.Suggestion
+		if err := rows.Scan(&sg.Text, &sg.Field); err != nil {
+			return []domain.Suggestion{}, err
+		}
+		suggestions = append(suggestions, sg)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.Suggestion{}, err
+	}
+	return suggestions, nil
+}
+
+// withWordSimilarityThreshold runs fn in a transaction in which the <%
+// operator matches above threshold.
+func withWordSimilarityThreshold(ctx context.Context, db PgxIface, threshold float64, fn func(pgx.Tx) error) error {
+	return withTx(ctx, db, func(tx pgx.Tx) error {
+		_, err := tx.Exec(
+			ctx,
+			"SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)",
+			strconv.FormatFloat(threshold, 'f', -1, 64),
+		)
+		if err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// queryBookHits runs a query whose rows hold bookColumns followed by the
+// rank, title highlight and author highlight, and loads the books' authors.
+func queryBookHits(ctx context.Context, db PgxIface, sql string, args ...any) ([]domain.BookHit, error) {
+	rows, err := db.Query(ctx, sql, args...)
+	if err != nil {
+		return []domain.BookHit{}, err
+	}
+	defer rows.Close()
+
+	hits := []domain.BookHit{}
+	for rows.Next() {
+		var hit domain.BookHit
+		hit.Book, err = scanBook(rows, &hit.Rank, &hit.TitleHighlight, &hit.AuthorHighlight)
+		if err != nil {
+			return []domain.BookHit{}, err
+		}
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.BookHit{}, err
+	}
+	rows.Close()
+
+	books := make([]domain.Book, len(hits))
+	for i, hit := range hits {
+		books[i] = hit.Book
+	}
+	if err := loadBookAuthors(ctx, db, books); err != nil {
+		return []domain.BookHit{}, err
+	}
+	for i := range hits {
+		hits[i].Book = books[i]
+	}
+	return hits, nil
+}
+
+// tsquery writes query in to_tsquery syntax: terms are joined with &, the
+// words of a phrase with <->, and a prefix word ends in :*. Words hold only
+// letters and digits, so they need no quoting.
+func tsquery(query domain.SearchQuery) string {
+	terms := make([]string, len(query))
+	for i, t := range query {
+		words := slices.Clone(t.Words)
+		if t.Prefix {
+			words[len(words)-1] += ":*"
+		}
+		terms[i] = strings.Join(words, " <-> ")
+		if len(words) > 1 {
+			terms[i] = "(" + terms[i] + ")"
+		}
+	}
+	return strings.Join(terms, " & ")
+}
@@ -0,0 +1,304 @@
+package repositories
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var searchRowColumns = append(append([]string{}, bookRowColumns...), "rank", "title_highlight", "author_highlight")
+
+func TestPostgresBookSearch_SearchBooks(t *testing.T) {
+	query := domain.SearchQuery{{Words: []string{"animal", "farm"}}, {Words: []string{"orw"}, Prefix: true}}
+
+	tests := []struct {
+		name    string
+		setup   func(pgxmock.PgxPoolIface)
+		want    []domain.BookHit
+		wantErr bool
+	}{
+		{
+			name: "success",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(`SELECT (.+), ts_rank\(search, q\), ts_headline\('english', title, q, \$2\), ts_headline\('english', author, q, \$2\)`+
+					` FROM books, to_tsquery\('english', \$1\) q WHERE deleted_at IS NULL AND search @@ q`+
+					` ORDER BY ts_rank\(search, q\) DESC, id ASC LIMIT \$3 OFFSET \$4`).
+					WithArgs("(animal <-> farm) & orw:*", searchHighlightOptions, 10, 0).
+					WillReturnRows(pgxmock.NewRows(searchRowColumns).
+						AddRow(1, "Animal Farm", "George Orwell", nil, 1, testTime, testTime, nil, 0.5, "\x02Animal\x03 \x02Farm\x03", "George \x02Orwell\x03"))
+				mock.ExpectQuery("FROM book_authors").
+					WithArgs([]int{1}).
+					WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns).AddRow(1, 1, "Test Author", testTime, testTime))
+			},
+			want: []domain.BookHit{{
+				Book:            domain.Book{ID: 1, Title: "Animal Farm", Author: "George Orwell", Authors: []domain.Author{testAuthor}, Version: 1, CreatedAt: testTime, UpdatedAt: testTime},
+				Rank:            0.5,
+				TitleHighlight:  "\x02Animal\x03 \x02Farm\x03",
+				AuthorHighlight: "George \x02Orwell\x03",
+			}},
+		},
+		{
+			name: "no hits",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("FROM books, to_tsquery").
+					WithArgs("(animal <-> farm) & orw:*", searchHighlightOptions, 10, 0).
+					WillReturnRows(pgxmock.NewRows(searchRowColumns))
+			},
+			want: []domain.BookHit{},
+		},
+		{
+			name: "query error",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("FROM books, to_tsquery").
+					WithArgs("(animal <-> farm) & orw:*", searchHighlightOptions, 10, 0).
+					WillReturnError(pgx.ErrTxClosed)
+			},
+			want:    []domain.BookHit{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			s := NewPostgresBookSearch(mock)
+			got, err := s.SearchBooks(context.Background(), query, 0, 10)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostgresBookSearch.SearchBooks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresBookSearch.SearchBooks() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresBookSearch_CountSearchResults(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM books WHERE deleted_at IS NULL AND search @@ to_tsquery\('english', \$1\)`).
+		WithArgs("farm").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(4))
+
+	s := NewPostgresBookSearch(mock)
+	got, err := s.CountSearchResults(context.Background(), domain.SearchQuery{{Words: []string{"farm"}}})
+	if err != nil {
+		t.Fatalf("PostgresBookSearch.CountSearchResults() error = %v", err)
+	}
+	if got != 4 {
+		t.Errorf("PostgresBookSearch.CountSearchResults() = %d, want 4", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostgresBookSearch_FuzzySearchBooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(pgxmock.PgxPoolIface)
+		want    []domain.BookHit
+		wantErr bool
+	}{
+		{
+			name: "success",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec(`SELECT set_config\('pg_trgm.word_similarity_threshold', \$1, true\)`).
+					WithArgs("0.4").
+					WillReturnResult(pgxmock.NewResult("SELECT", 1))
+				mock.ExpectQuery(`SELECT (.+), greatest\(word_similarity\(\$1, title\), word_similarity\(\$1, author\)\) AS similarity, title, author`+
+					` FROM books WHERE deleted_at IS NULL AND \(\$1 <% title OR \$1 <% author\)`+
+					` ORDER BY similarity DESC, id ASC LIMIT \$2 OFFSET \$3`).
+					WithArgs("orwel", 10, 0).
+					WillReturnRows(pgxmock.NewRows(searchRowColumns).
+						AddRow(1, "Animal Farm", "George Orwell", nil, 1, testTime, testTime, nil, 0.8333, "Animal Farm", "George Orwell"))
+				mock.ExpectQuery("FROM book_authors").
+					WithArgs([]int{1}).
+					WillReturnRows(pgxmock.NewRows(bookAuthorRowColumns).AddRow(1, 1, "Test Author", testTime, testTime))
+				mock.ExpectCommit()
+			},
+			want: []domain.BookHit{{
+				Book:            domain.Book{ID: 1, Title: "Animal Farm", Author: "George Orwell", Authors: []domain.Author{testAuthor}, Version: 1, CreatedAt: testTime, UpdatedAt: testTime},
+				Rank:            0.8333,
+				TitleHighlight:  "Animal Farm",
+				AuthorHighlight: "George Orwell",
+			}},
+		},
+		{
+			name: "set threshold error",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec("set_config").
+					WithArgs("0.4").
+					WillReturnError(pgx.ErrTxClosed)
+				mock.ExpectRollback()
+			},
+			want:    []domain.BookHit{},
+			wantErr: true,
+		},
+		{
+			name: "query error",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec("set_config").
+					WithArgs("0.4").
+					WillReturnResult(pgxmock.NewResult("SELECT", 1))
+				mock.ExpectQuery("AS similarity").
+					WithArgs("orwel", 10, 0).
+					WillReturnError(pgx.ErrTxClosed)
+				mock.ExpectRollback()
+			},
+			want:    []domain.BookHit{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			s := NewPostgresBookSearch(mock)
+			got, err := s.FuzzySearchBooks(context.Background(), "orwel", 0.4, 0, 10)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostgresBookSearch.FuzzySearchBooks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresBookSearch.FuzzySearchBooks() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresBookSearch_CountFuzzySearchResults(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("set_config").
+		WithArgs("0.5").
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM books WHERE deleted_at IS NULL AND \(\$1 <% title OR \$1 <% author\)`).
+		WithArgs("gatsbi").
+		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectCommit()
+
+	s := NewPostgresBookSearch(mock)
+	got, err := s.CountFuzzySearchResults(context.Background(), "gatsbi", 0.5)
+	if err != nil {
+		t.Fatalf("PostgresBookSearch.CountFuzzySearchResults() error = %v", err)
+	}
+	if got != 2 {
+		t.Errorf("PostgresBookSearch.CountFuzzySearchResults() = %d, want 2", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostgresBookSearch_SuggestBooks(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		setup   func(pgxmock.PgxPoolIface)
+		want    []domain.Suggestion
+		wantErr bool
+	}{
+		{
+			name:   "success",
+			prefix: "an",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(`SELECT text, field FROM \(`+
+					`\(SELECT DISTINCT title AS text, 'title' AS field FROM books WHERE deleted_at IS NULL AND title ILIKE \$1 ORDER BY title LIMIT \$2\)`+
+					` UNION ALL `+
+					`\(SELECT name, 'author' FROM authors WHERE name ILIKE \$1 ORDER BY name LIMIT \$2\)`+
+					`\) s ORDER BY length\(text\), text, field LIMIT \$2`).
+					WithArgs("an%", 5).
+					WillReturnRows(pgxmock.NewRows([]string{"text", "field"}).
+						AddRow("Anne Frank", "author").
+						AddRow("Animal Farm", "title"))
+			},
+			want: []domain.Suggestion{{Text: "Anne Frank", Field: "author"}, {Text: "Animal Farm", Field: "title"}},
+		},
+		{
+			name:   "wildcards escaped",
+			prefix: "100%_",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UNION ALL").
+					WithArgs(`100\%\_%`, 5).
+					WillReturnRows(pgxmock.NewRows([]string{"text", "field"}))
+			},
+			want: []domain.Suggestion{},
+		},
+		{
+			name:   "query error",
+			prefix: "an",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UNION ALL").
+					WithArgs("an%", 5).
+					WillReturnError(pgx.ErrTxClosed)
+			},
+			want:    []domain.Suggestion{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			s := NewPostgresBookSearch(mock)
+			got, err := s.SuggestBooks(context.Background(), tt.prefix, 5)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PostgresBookSearch.SuggestBooks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresBookSearch.SuggestBooks() = %v, want %v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
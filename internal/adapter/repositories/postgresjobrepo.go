@@ -0,0 +1,263 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+	"io"
+	"time"
+
+	pgx "github.com/jackc/pgx/v5"
+	pgconn "github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	jobColumns = "id, kind, status, params, processed, result, error, attempts, max_attempts, run_at, artifact_name, artifact_type, created_at, updated_at, finished_at"
+
+	// jobFileChunkSize is the size of the chunks job files are stored in,
+	// and so the most of a file held in memory at once.
+	jobFileChunkSize = 1 << 20
+)
+
+// PostgresJobRepo stores jobs and their files. It uses the pool even when ctx
+// carries a transaction: a job's progress and outcome must commit on their
+// own, so they are seen while its books are still being written and are kept
+// when that write rolls back.
+type PostgresJobRepo struct {
+	db PgxIface
+}
+
+var _ out.JobRepository = &PostgresJobRepo{}
+
+func NewPostgresJobRepo(db PgxIface) *PostgresJobRepo {
+	return &PostgresJobRepo{db: db}
+}
+
+func (r *PostgresJobRepo) CreateJob(ctx context.Context, job domain.Job, input io.Reader) (domain.Job, error) {
+	var created domain.Job
+	err := withTx(ctx, r.db, func(tx pgx.Tx) error {
+		var err error
+		created, err = scanJob(tx.QueryRow(ctx,
+			"INSERT INTO jobs (kind, status, params, max_attempts) VALUES ($1, $2, $3, $4) RETURNING "+jobColumns,
+			string(job.Kind), string(job.Status), []byte(job.Params), job.MaxAttempts,
+		))
+		if err != nil || input == nil {
+			return err
+		}
+		w := &jobFileWriter{ctx: ctx, db: tx, id: created.ID, name: domain.JobInput}
+		if _, err := io.Copy(w, input); err != nil {
+			return err
+		}
+		return w.Close()
+	})
+	if err != nil {
+		return domain.Job{}, err
+	}
+	return created, nil
+}
+
+func (r *PostgresJobRepo) GetJob(ctx context.Context, id int) (domain.Job, error) {
+	job, err := scanJob(r.db.QueryRow(ctx, "SELECT "+jobColumns+" FROM jobs WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Job{}, domain.ErrJobNotFound
+	}
+	return job, err
+}
+
+// ClaimJob skips the jobs locked by concurrent claims, so workers never wait
+// on each other or claim the same job.
+func (r *PostgresJobRepo) ClaimJob(ctx context.Context, lease time.Duration) (domain.Job, bool, error) {
+	job, err := scanJob(r.db.QueryRow(ctx, `UPDATE jobs
+		SET status = 'running', attempts = attempts + 1, processed = 0,
+			locked_until = now() + $1 * interval '1 second', updated_at = now()
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE (status = 'queued' AND run_at <= now()) OR (status = 'running' AND locked_until < now())
+			ORDER BY run_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+jobColumns, lease.Seconds()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Job{}, false, nil
+	}
+	if err != nil {
+		return domain.Job{}, false, err
+	}
+	return job, true, nil
+}
+
+func (r *PostgresJobRepo) UpdateJobProgress(ctx context.Context, job domain.Job, lease time.Duration) error {
+	return jobUpdated(r.db.Exec(ctx, `UPDATE jobs
+		SET processed = $3, locked_until = now() + $4 * interval '1 second', updated_at = now()
+		WHERE id = $1 AND attempts = $2 AND status = 'running'`,
+		job.ID, job.Attempts, job.Processed, lease.Seconds()))
+}
+
+func (r *PostgresJobRepo) CompleteJob(ctx context.Context, job domain.Job) error {
+	var name, contentType pgtype.Text
+	if job.Artifact != nil {
+		name = pgtype.Text{String: job.Artifact.Filename, Valid: true}
+		contentType = pgtype.Text{String: job.Artifact.ContentType, Valid: true}
+	}
+	return jobUpdated(r.db.Exec(ctx, `UPDATE jobs
+		SET status = 'succeeded', processed = $3, result = $4, artifact_name = $5, artifact_type = $6,
+			error = NULL, locked_until = NULL, finished_at = now(), updated_at = now()
+		WHERE id = $1 AND attempts = $2 AND status = 'running'`,
+		job.ID, job.Attempts, job.Processed, []byte(job.Result), name, contentType))
+}
+
+func (r *PostgresJobRepo) FailJob(ctx context.Context, job domain.Job, retryAt *time.Time) error {
+	if retryAt == nil {
+		return jobUpdated(r.db.Exec(ctx, `UPDATE jobs
+			SET status = 'failed', processed = $3, error = $4,
+				locked_until = NULL, finished_at = now(), updated_at = now()
+			WHERE id = $1 AND attempts = $2 AND status = 'running'`,
+			job.ID, job.Attempts, job.Processed, job.Error))
+	}
+	return jobUpdated(r.db.Exec(ctx, `UPDATE jobs
+		SET status = 'queued', processed = $3, error = $4, run_at = $5,
+			locked_until = NULL, updated_at = now()
+		WHERE id = $1 AND attempts = $2 AND status = 'running'`,
+		job.ID, job.Attempts, job.Processed, job.Error, *retryAt))
+}
+
+func (r *PostgresJobRepo) ReleaseJob(ctx context.Context, job domain.Job) error {
+	return jobUpdated(r.db.Exec(ctx, `UPDATE jobs
+		SET status = 'queued', attempts = attempts - 1, locked_until = NULL, updated_at = now()
+		WHERE id = $1 AND attempts = $2 AND status = 'running'`,
+		job.ID, job.Attempts))
+}
+
+func (r *PostgresJobRepo) OpenJobFile(ctx context.Context, id int, name string) io.Reader {
+	return &jobFileReader{ctx: ctx, db: r.db, id: id, name: name}
+}
+
+func (r *PostgresJobRepo) CreateJobFile(ctx context.Context, id int, name string) (io.WriteCloser, error) {
+	if _, err := r.db.Exec(ctx, "DELETE FROM job_files WHERE job_id = $1 AND name = $2", id, name); err != nil {
+		return nil, err
+	}
+	return &jobFileWriter{ctx: ctx, db: r.db, id: id, name: name}, nil
+}
+
+// jobUpdated turns the result of a guarded job update into domain.ErrJobLost
+// when it matched no job.
+func jobUpdated(tag pgconn.CommandTag, err error) error {
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrJobLost
+	}
+	return nil
+}
+
+// execer is what jobFileWriter needs of a pool or transaction.
+type execer interface {
+	Exec(context.Context, string, ...any) (pgconn.CommandTag, error)
+}
+
+// jobFileWriter stores what is written to it as job file chunks of
+// jobFileChunkSize.
+type jobFileWriter struct {
+	ctx  context.Context
+	db   execer
+	id   int
+	name string
+	seq  int
+	buf  []byte
+}
+
+func (w *jobFileWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if w.buf == nil {
+			w.buf = make([]byte, 0, jobFileChunkSize)
+		}
+		k := min(len(p), jobFileChunkSize-len(w.buf))
+		w.buf = append(w.buf, p[:k]...)
+		p = p[k:]
+		if len(w.buf) == jobFileChunkSize {
+			if err := w.flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (w *jobFileWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	_, err := w.db.Exec(w.ctx, "INSERT INTO job_files (job_id, name, seq, data) VALUES ($1, $2, $3, $4)",
+		w.id, w.name, w.seq, w.buf)
+	w.seq++
+	w.buf = w.buf[:0]
+	return err
+}
+
+// Close stores the last, partial chunk.
+func (w *jobFileWriter) Close() error {
+	return w.flush()
+}
+
+// jobFileReader reads a job file one chunk at a time.
+type jobFileReader struct {
+	ctx  context.Context
+	db   PgxIface
+	id   int
+	name string
+	seq  int
+	buf  []byte
+	eof  bool
+}
+
+func (r *jobFileReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.eof {
+			return 0, io.EOF
+		}
+		err := r.db.QueryRow(r.ctx, "SELECT data FROM job_files WHERE job_id = $1 AND name = $2 AND seq = $3",
+			r.id, r.name, r.seq).Scan(&r.buf)
+		if errors.Is(err, pgx.ErrNoRows) {
+			r.eof = true
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		r.seq++
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func scanJob(row pgx.Row) (domain.Job, error) {
+	var job domain.Job
+	var kind, status string
+	var params, result []byte
+	var jobErr, artifactName, artifactType pgtype.Text
+	var finishedAt pgtype.Timestamptz
+	err := row.Scan(&job.ID, &kind, &status, &params, &job.Processed, &result, &jobErr,
+		&job.Attempts, &job.MaxAttempts, &job.RunAt, &artifactName, &artifactType,
+		&job.CreatedAt, &job.UpdatedAt, &finishedAt)
+	if err != nil {
+		return domain.Job{}, err
+	}
+	job.Kind = domain.JobKind(kind)
+	job.Status = domain.JobStatus(status)
+	job.Params = params
+	job.Result = result
+	job.Error = jobErr.String
+	if artifactName.Valid {
+		job.Artifact = &domain.JobArtifact{Filename: artifactName.String, ContentType: artifactType.String}
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	return job, nil
+}
@@ -0,0 +1,361 @@
+package repositories
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var jobRowColumns = []string{"id", "kind", "status", "params", "processed", "result", "error", "attempts", "max_attempts", "run_at", "artifact_name", "artifact_type", "created_at", "updated_at", "finished_at"}
+
+func TestPostgresJobRepo_CreateJob(t *testing.T) {
+	job := domain.Job{Kind: domain.JobBookImport, Status: domain.JobQueued, Params: json.RawMessage(`{"dry_run":true}`), MaxAttempts: 3}
+	want := domain.Job{ID: 1, Kind: domain.JobBookImport, Status: domain.JobQueued, Params: json.RawMessage(`{"dry_run":true}`), MaxAttempts: 3, RunAt: testTime, CreatedAt: testTime, UpdatedAt: testTime}
+	insertRow := func() *pgxmock.Rows {
+		return pgxmock.NewRows(jobRowColumns).
+			AddRow(1, "book_import", "queued", []byte(`{"dry_run":true}`), 0, nil, nil, 0, 3, testTime, nil, nil, testTime, testTime, nil)
+	}
+	jobInsertArgs := []any{"book_import", "queued", []byte(`{"dry_run":true}`), 3}
+	big := bytes.Repeat([]byte("x"), jobFileChunkSize+10)
+	tests := []struct {
+		name    string
+		input   io.Reader
+		setup   func(pgxmock.PgxPoolIface)
+		want    domain.Job
+		wantErr bool
+	}{
+		{
+			name: "without input",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO jobs").
+					WithArgs(jobInsertArgs...).
+					WillReturnRows(insertRow())
+				mock.ExpectCommit()
+			},
+			want: want,
+		},
+		{
+			name:  "input is stored in chunks",
+			input: bytes.NewReader(big),
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO jobs").WithArgs(jobInsertArgs...).WillReturnRows(insertRow())
+				mock.ExpectExec("INSERT INTO job_files").
+					WithArgs(1, domain.JobInput, 0, big[:jobFileChunkSize]).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectExec("INSERT INTO job_files").
+					WithArgs(1, domain.JobInput, 1, big[jobFileChunkSize:]).
+					WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+			},
+			want: want,
+		},
+		{
+			name:  "input error rolls back",
+			input: io.MultiReader(strings.NewReader("title"), &errReader{err: errors.New("upload cut off")}),
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectQuery("INSERT INTO jobs").WithArgs(jobInsertArgs...).WillReturnRows(insertRow())
+				mock.ExpectRollback()
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresJobRepo(mock)
+			got, err := r.CreateJob(context.Background(), job, tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PostgresJobRepo.CreateJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresJobRepo.CreateJob() = %+v, want %+v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresJobRepo_GetJob(t *testing.T) {
+	finished := testTime.Add(time.Minute)
+	tests := []struct {
+		name    string
+		setup   func(pgxmock.PgxPoolIface)
+		want    domain.Job
+		wantErr error
+	}{
+		{
+			name: "succeeded with artifact",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT (.+) FROM jobs WHERE id = \\$1").
+					WithArgs(1).
+					WillReturnRows(pgxmock.NewRows(jobRowColumns).
+						AddRow(1, "book_export", "succeeded", []byte(`{}`), 2, []byte(`{"books":2}`), nil, 1, 3, testTime, "books.csv", "text/csv", testTime, finished, finished))
+			},
+			want: domain.Job{
+				ID: 1, Kind: domain.JobBookExport, Status: domain.JobSucceeded, Params: json.RawMessage(`{}`),
+				Processed: 2, Result: json.RawMessage(`{"books":2}`), Attempts: 1, MaxAttempts: 3, RunAt: testTime,
+				Artifact:  &domain.JobArtifact{Filename: "books.csv", ContentType: "text/csv"},
+				CreatedAt: testTime, UpdatedAt: finished, FinishedAt: &finished,
+			},
+		},
+		{
+			name: "not found",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT (.+) FROM jobs WHERE id = \\$1").
+					WithArgs(1).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			wantErr: domain.ErrJobNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresJobRepo(mock)
+			got, err := r.GetJob(context.Background(), 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PostgresJobRepo.GetJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresJobRepo.GetJob() = %+v, want %+v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresJobRepo_ClaimJob(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(pgxmock.PgxPoolIface)
+		wantOK  bool
+		wantErr bool
+	}{
+		{
+			name: "claims the next job",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(`UPDATE jobs\s+SET status = 'running', attempts = attempts \+ 1(.+)FOR UPDATE SKIP LOCKED`).
+					WithArgs(300.0).
+					WillReturnRows(pgxmock.NewRows(jobRowColumns).
+						AddRow(1, "book_import", "running", []byte(`{}`), 0, nil, nil, 1, 3, testTime, nil, nil, testTime, testTime, nil))
+			},
+			wantOK: true,
+		},
+		{
+			name: "nothing to claim",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE jobs").WithArgs(300.0).WillReturnError(pgx.ErrNoRows)
+			},
+		},
+		{
+			name: "query error",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE jobs").WithArgs(300.0).WillReturnError(pgx.ErrTxClosed)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresJobRepo(mock)
+			job, ok, err := r.ClaimJob(context.Background(), 5*time.Minute)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PostgresJobRepo.ClaimJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ok != tt.wantOK || (ok && (job.ID != 1 || job.Status != domain.JobRunning)) {
+				t.Errorf("PostgresJobRepo.ClaimJob() = %+v, %v, want ok %v", job, ok, tt.wantOK)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresJobRepo_Updates(t *testing.T) {
+	job := domain.Job{ID: 1, Attempts: 2, Processed: 10, Error: "db error"}
+	retryAt := testTime.Add(time.Minute)
+	tests := []struct {
+		name    string
+		update  func(*PostgresJobRepo) error
+		setup   func(pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name:   "progress",
+			update: func(r *PostgresJobRepo) error { return r.UpdateJobProgress(context.Background(), job, time.Minute) },
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(`UPDATE jobs\s+SET processed = \$3`).
+					WithArgs(1, 2, 10, 60.0).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+		},
+		{
+			name:   "progress after the lease was lost",
+			update: func(r *PostgresJobRepo) error { return r.UpdateJobProgress(context.Background(), job, time.Minute) },
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("UPDATE jobs").
+					WithArgs(1, 2, 10, 60.0).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+			},
+			wantErr: domain.ErrJobLost,
+		},
+		{
+			name: "complete",
+			update: func(r *PostgresJobRepo) error {
+				done := job
+				done.Result = json.RawMessage(`{"books":10}`)
+				done.Artifact = &domain.JobArtifact{Filename: "books.csv", ContentType: "text/csv"}
+				return r.CompleteJob(context.Background(), done)
+			},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(`UPDATE jobs\s+SET status = 'succeeded'`).
+					WithArgs(1, 2, 10, []byte(`{"books":10}`), pgxmock.AnyArg(), pgxmock.AnyArg()).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+		},
+		{
+			name:   "fail for good",
+			update: func(r *PostgresJobRepo) error { return r.FailJob(context.Background(), job, nil) },
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(`UPDATE jobs\s+SET status = 'failed'`).
+					WithArgs(1, 2, 10, "db error").
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+		},
+		{
+			name:   "fail and retry",
+			update: func(r *PostgresJobRepo) error { return r.FailJob(context.Background(), job, &retryAt) },
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(`UPDATE jobs\s+SET status = 'queued', processed = \$3, error = \$4, run_at = \$5`).
+					WithArgs(1, 2, 10, "db error", retryAt).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+		},
+		{
+			name:   "release",
+			update: func(r *PostgresJobRepo) error { return r.ReleaseJob(context.Background(), job) },
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(`UPDATE jobs\s+SET status = 'queued', attempts = attempts - 1`).
+					WithArgs(1, 2).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			if err := tt.update(NewPostgresJobRepo(mock)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresJobRepo update error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresJobRepo_JobFiles(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectExec("DELETE FROM job_files WHERE job_id = \\$1 AND name = \\$2").
+		WithArgs(1, domain.JobOutput).
+		WillReturnResult(pgxmock.NewResult("DELETE", 2))
+	mock.ExpectExec("INSERT INTO job_files").
+		WithArgs(1, domain.JobOutput, 0, []byte("id\n1\n")).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	for seq, data := range []string{"id\n", "1\n"} {
+		mock.ExpectQuery("SELECT data FROM job_files").
+			WithArgs(1, domain.JobOutput, seq).
+			WillReturnRows(pgxmock.NewRows([]string{"data"}).AddRow([]byte(data)))
+	}
+	mock.ExpectQuery("SELECT data FROM job_files").
+		WithArgs(1, domain.JobOutput, 2).
+		WillReturnError(pgx.ErrNoRows)
+
+	r := NewPostgresJobRepo(mock)
+	w, err := r.CreateJobFile(context.Background(), 1, domain.JobOutput)
+	if err != nil {
+		t.Fatalf("PostgresJobRepo.CreateJobFile() error = %v", err)
+	}
+	io.WriteString(w, "id\n")
+	io.WriteString(w, "1\n")
+	if err := w.Close(); err != nil {
+		t.Fatalf("PostgresJobRepo.CreateJobFile() close error = %v", err)
+	}
+
+	got, err := io.ReadAll(r.OpenJobFile(context.Background(), 1, domain.JobOutput))
+	if err != nil {
+		t.Fatalf("PostgresJobRepo.OpenJobFile() error = %v", err)
+	}
+	if string(got) != "id\n1\n" {
+		t.Errorf("PostgresJobRepo.OpenJobFile() = %q, want %q", got, "id\n1\n")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// errReader fails every read with err.
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+	"time"
+
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const outboxColumns = "id, type, key, payload, attempts, error, created_at"
+
+type PostgresOutboxRepo struct {
+	db PgxIface
+}
+
+var _ out.OutboxRepository = &PostgresOutboxRepo{}
+
+func NewPostgresOutboxRepo(db PgxIface) *PostgresOutboxRepo {
+	return &PostgresOutboxRepo{db: db}
+}
+
+func (r *PostgresOutboxRepo) conn(ctx context.Context) PgxIface {
+	return dbFor(ctx, r.db)
+}
+
+// AddOutboxMessages inserts every message with a single statement, in order,
+// so their IDs follow the order they were given in.
+func (r *PostgresOutboxRepo) AddOutboxMessages(ctx context.Context, messages []domain.OutboxMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	n := len(messages)
+	types, keys, payloads := make([]string, n), make([]string, n), make([]string, n)
+	for i, msg := range messages {
+		types[i], keys[i], payloads[i] = msg.Type, msg.Key, string(msg.Payload)
+	}
+
+	_, err := r.conn(ctx).Exec(
+		ctx,
+		`INSERT INTO outbox (type, key, payload)
+		SELECT t.type, t.key, t.payload::jsonb
+		FROM unnest($1::text[], $2::text[], $3::text[]) WITH ORDINALITY AS t(type, key, payload, n)
+		ORDER BY t.n`,
+		types, keys, payloads,
+	)
+	return err
+}
+
+// ClaimOutboxMessages only considers the oldest pending message of each key.
+// The message ahead of a locked one is never due, so two relays cannot claim
+// messages of the same key.
+func (r *PostgresOutboxRepo) ClaimOutboxMessages(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	rows, err := r.conn(ctx).Query(ctx, `SELECT `+outboxColumns+` FROM outbox o
+		WHERE dead_at IS NULL AND next_attempt_at <= now()
+			AND NOT EXISTS (
+				SELECT 1 FROM outbox p WHERE p.key = o.key AND p.dead_at IS NULL AND p.id < o.id
+			)
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return []domain.OutboxMessage{}, err
+	}
+	defer rows.Close()
+
+	messages := []domain.OutboxMessage{}
+	for rows.Next() {
+		msg, err := scanOutboxMessage(rows)
+		if err != nil {
+			return []domain.OutboxMessage{}, err
+		}
+		messages = append(messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.OutboxMessage{}, err
+	}
+	return messages, nil
+}
+
+func (r *PostgresOutboxRepo) DeleteOutboxMessage(ctx context.Context, id int64) error {
+	_, err := r.conn(ctx).Exec(ctx, "DELETE FROM outbox WHERE id = $1", id)
+	return err
+}
+
+func (r *PostgresOutboxRepo) FailOutboxMessage(ctx context.Context, msg domain.OutboxMessage, retryAt *time.Time) error {
+	if retryAt == nil {
+		_, err := r.conn(ctx).Exec(ctx, "UPDATE outbox SET attempts = $2, error = $3, dead_at = now() WHERE id = $1",
+			msg.ID, msg.Attempts, msg.Error)
+		return err
+	}
+	_, err := r.conn(ctx).Exec(ctx, "UPDATE outbox SET attempts = $2, error = $3, next_attempt_at = $4 WHERE id = $1",
+		msg.ID, msg.Attempts, msg.Error, *retryAt)
+	return err
+}
+
+func scanOutboxMessage(row pgx.Row) (domain.OutboxMessage, error) {
+	var msg domain.OutboxMessage
+	var payload []byte
+	var msgErr pgtype.Text
+	err := row.Scan(&msg.ID, &msg.Type, &msg.Key, &payload, &msg.Attempts, &msgErr, &msg.CreatedAt)
+	if err != nil {
+		return domain.OutboxMessage{}, err
+	}
+	msg.Payload = payload
+	msg.Error = msgErr.String
+	return msg, nil
+}
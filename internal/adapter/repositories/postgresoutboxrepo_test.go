@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var outboxRowColumns = []string{"id", "type", "key", "payload", "attempts", "error", "created_at"}
+
+func TestPostgresOutboxRepo_AddOutboxMessages(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []domain.OutboxMessage
+		setup    func(pgxmock.PgxPoolIface)
+		wantErr  error
+	}{
+		{
+			name: "success",
+			messages: []domain.OutboxMessage{
+				{Type: "book.created", Key: "book:1", Payload: json.RawMessage(`{"book":{"id":1}}`)},
+				{Type: "book.deleted", Key: "book:1", Payload: json.RawMessage(`{"book":{"id":1}}`)},
+			},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("INSERT INTO outbox (.+) FROM unnest(.+) ORDER BY t.n").
+					WithArgs([]string{"book.created", "book.deleted"}, []string{"book:1", "book:1"},
+						[]string{`{"book":{"id":1}}`, `{"book":{"id":1}}`}).
+					WillReturnResult(pgxmock.NewResult("INSERT", 2))
+			},
+		},
+		{
+			name:  "no messages",
+			setup: func(mock pgxmock.PgxPoolIface) {},
+		},
+		{
+			name:     "db error",
+			messages: []domain.OutboxMessage{{Type: "book.created", Key: "book:1", Payload: json.RawMessage(`{}`)}},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("INSERT INTO outbox").
+					WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+					WillReturnError(pgx.ErrTxClosed)
+			},
+			wantErr: pgx.ErrTxClosed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresOutboxRepo(mock)
+			if err := r.AddOutboxMessages(context.Background(), tt.messages); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresOutboxRepo.AddOutboxMessages() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresOutboxRepo_ClaimOutboxMessages(t *testing.T) {
+	retried := "broker unavailable"
+
+	tests := []struct {
+		name    string
+		setup   func(pgxmock.PgxPoolIface)
+		want    []domain.OutboxMessage
+		wantErr error
+	}{
+		{
+			name: "success",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(`SELECT (.+) FROM outbox o WHERE dead_at IS NULL AND next_attempt_at <= now\(\) AND NOT EXISTS (.+) LIMIT \$1 FOR UPDATE SKIP LOCKED`).
+					WithArgs(10).
+					WillReturnRows(pgxmock.NewRows(outboxRowColumns).
+						AddRow(int64(1), "book.created", "book:1", []byte(`{"book":{"id":1}}`), 0, nil, testTime).
+						AddRow(int64(3), "book.updated", "book:2", []byte(`{"book":{"id":2}}`), 2, retried, testTime))
+			},
+			want: []domain.OutboxMessage{
+				{ID: 1, Type: "book.created", Key: "book:1", Payload: json.RawMessage(`{"book":{"id":1}}`), CreatedAt: testTime},
+				{ID: 3, Type: "book.updated", Key: "book:2", Payload: json.RawMessage(`{"book":{"id":2}}`), CreatedAt: testTime, Attempts: 2, Error: retried},
+			},
+		},
+		{
+			name: "none due",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT (.+) FROM outbox").
+					WithArgs(10).
+					WillReturnRows(pgxmock.NewRows(outboxRowColumns))
+			},
+			want: []domain.OutboxMessage{},
+		},
+		{
+			name: "db error",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT (.+) FROM outbox").
+					WithArgs(10).
+					WillReturnError(pgx.ErrTxClosed)
+			},
+			want:    []domain.OutboxMessage{},
+			wantErr: pgx.ErrTxClosed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresOutboxRepo(mock)
+			got, err := r.ClaimOutboxMessages(context.Background(), 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresOutboxRepo.ClaimOutboxMessages() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresOutboxRepo.ClaimOutboxMessages() = %+v, want %+v", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresOutboxRepo_FailOutboxMessage(t *testing.T) {
+	retryAt := testTime.Add(time.Minute)
+	msg := domain.OutboxMessage{ID: 7, Attempts: 2, Error: "broker unavailable"}
+
+	tests := []struct {
+		name    string
+		retryAt *time.Time
+		setup   func(pgxmock.PgxPoolIface)
+	}{
+		{
+			name:    "retry",
+			retryAt: &retryAt,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(`UPDATE outbox SET attempts = \$2, error = \$3, next_attempt_at = \$4 WHERE id = \$1`).
+					WithArgs(int64(7), 2, "broker unavailable", retryAt).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+		},
+		{
+			name: "dead letter",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(`UPDATE outbox SET attempts = \$2, error = \$3, dead_at = now\(\) WHERE id = \$1`).
+					WithArgs(int64(7), 2, "broker unavailable").
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresOutboxRepo(mock)
+			if err := r.FailOutboxMessage(context.Background(), msg, tt.retryAt); err != nil {
+				t.Errorf("PostgresOutboxRepo.FailOutboxMessage() error = %v", err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/out"
+
+	pgx "github.com/jackc/pgx/v5"
+)
+
+// txKey is the context key of the transaction started by PostgresTxManager.
+type txKey struct{}
+
+type PostgresTxManager struct {
+	db PgxIface
+}
+
+var _ out.TxManager = &PostgresTxManager{}
+
+func NewPostgresTxManager(db PgxIface) *PostgresTxManager {
+	return &PostgresTxManager{db: db}
+}
+
+func (m *PostgresTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+	return withTx(ctx, m.db, func(tx pgx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// dbFor returns the transaction of WithinTx that ctx carries, if any, and db
+// otherwise. The repositories written to by book writes reach the database
+// through it, by way of their conn method, so their statements join the
+// ambient transaction. A repository transaction begun on it becomes a
+// savepoint of the ambient one. PostgresBookSearch and PostgresJobRepo use
+// the pool directly and never join it.
+func dbFor(ctx context.Context, db PgxIface) PgxIface {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db
+}
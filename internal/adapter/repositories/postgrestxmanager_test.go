@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestPostgresTxManager_WithinTx(t *testing.T) {
+	purge := func(mock pgxmock.PgxPoolIface, id int, affected int64) {
+		mock.ExpectExec("DELETE FROM books WHERE id = \\$1 AND deleted_at IS NOT NULL").
+			WithArgs(id).
+			WillReturnResult(pgxmock.NewResult("DELETE", affected))
+	}
+	tests := []struct {
+		name    string
+		fn      func(ctx context.Context, m *PostgresTxManager, r *PostgresBookRepo) error
+		setup   func(pgxmock.PgxPoolIface)
+		wantErr error
+	}{
+		{
+			name: "commits when fn succeeds",
+			fn: func(ctx context.Context, _ *PostgresTxManager, r *PostgresBookRepo) error {
+				if err := r.PurgeBook(ctx, 1); err != nil {
+					return err
+				}
+				return r.PurgeBook(ctx, 2)
+			},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				purge(mock, 1, 1)
+				purge(mock, 2, 1)
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "rolls back when fn fails",
+			fn: func(ctx context.Context, _ *PostgresTxManager, r *PostgresBookRepo) error {
+				if err := r.PurgeBook(ctx, 1); err != nil {
+					return err
+				}
+				return r.PurgeBook(ctx, 2)
+			},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				purge(mock, 1, 1)
+				purge(mock, 2, 0)
+				mock.ExpectRollback()
+			},
+			wantErr: domain.ErrBookNotInTrash,
+		},
+		{
+			name: "nested calls join the outer transaction",
+			fn: func(ctx context.Context, m *PostgresTxManager, r *PostgresBookRepo) error {
+				return m.WithinTx(ctx, func(ctx context.Context) error {
+					return r.PurgeBook(ctx, 1)
+				})
+			},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				purge(mock, 1, 1)
+				mock.ExpectCommit()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			m := NewPostgresTxManager(mock)
+			r := NewPostgresBookRepo(mock)
+			err = m.WithinTx(context.Background(), func(ctx context.Context) error {
+				return tt.fn(ctx, m, r)
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresTxManager.WithinTx() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+	"time"
+
+	pgx "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	webhookColumns         = "id, url, events, secret, created_at, updated_at"
+	webhookDeliveryColumns = "id, webhook_id, message_id, event, payload, status, attempts, response_status, error, next_attempt_at, created_at, delivered_at"
+)
+
+type PostgresWebhookRepo struct {
+	db PgxIface
+}
+
+var _ out.WebhookRepository = &PostgresWebhookRepo{}
+
+func NewPostgresWebhookRepo(db PgxIface) *PostgresWebhookRepo {
+	return &PostgresWebhookRepo{db: db}
+}
+
+func (r *PostgresWebhookRepo) conn(ctx context.Context) PgxIface {
+	return dbFor(ctx, r.db)
+}
+
+func (r *PostgresWebhookRepo) CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	return scanWebhook(r.conn(ctx).QueryRow(
+		ctx,
+		"INSERT INTO webhooks (url, events, secret) VALUES ($1, $2, $3) RETURNING "+webhookColumns,
+		webhook.URL,
+		webhookEvents(webhook),
+		webhook.Secret,
+	))
+}
+
+func (r *PostgresWebhookRepo) GetWebhook(ctx context.Context, id int) (domain.Webhook, error) {
+	webhook, err := scanWebhook(r.conn(ctx).QueryRow(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE id = $1", id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Webhook{}, domain.ErrWebhookNotFound
+	}
+	return webhook, err
+}
+
+func (r *PostgresWebhookRepo) GetWebhooks(ctx context.Context, offset, limit int) ([]domain.Webhook, error) {
+	rows, err := r.conn(ctx).Query(
+		ctx,
+		"SELECT "+webhookColumns+" FROM webhooks ORDER BY id ASC LIMIT $1 OFFSET $2",
+		limit,
+		offset,
+	)
+	if err != nil {
+		return []domain.Webhook{}, err
+	}
+	defer rows.Close()
+
+	webhooks := []domain.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return []domain.Webhook{}, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.Webhook{}, err
+	}
+	return webhooks, nil
+}
+
+func (r *PostgresWebhookRepo) CountWebhooks(ctx context.Context) (int, error) {
+	var n int
+	if err := r.conn(ctx).QueryRow(ctx, "SELECT COUNT(*) FROM webhooks").Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r *PostgresWebhookRepo) UpdateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	updated, err := scanWebhook(r.conn(ctx).QueryRow(
+		ctx,
+		`UPDATE webhooks SET url = $2, events = $3, secret = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 RETURNING `+webhookColumns,
+		webhook.ID,
+		webhook.URL,
+		webhookEvents(webhook),
+		webhook.Secret,
+	))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Webhook{}, domain.ErrWebhookNotFound
+	}
+	return updated, err
+}
+
+func (r *PostgresWebhookRepo) DeleteWebhook(ctx context.Context, id int) error {
+	cmdTag, err := r.conn(ctx).Exec(ctx, "DELETE FROM webhooks WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *PostgresWebhookRepo) AddWebhookDeliveries(ctx context.Context, delivery domain.WebhookDelivery) error {
+	_, err := r.conn(ctx).Exec(
+		ctx,
+		`INSERT INTO webhook_deliveries (webhook_id, message_id, event, payload)
+		SELECT id, $1::bigint, $2::text, $3::jsonb FROM webhooks WHERE events = '{}' OR $2 = ANY(events)
+		ON CONFLICT (webhook_id, message_id) DO NOTHING`,
+		delivery.MessageID,
+		delivery.Event,
+		string(delivery.Payload),
+	)
+	return err
+}
+
+func (r *PostgresWebhookRepo) GetWebhookDeliveries(ctx context.Context, webhookID, offset, limit int) ([]domain.WebhookDelivery, error) {
+	rows, err := r.conn(ctx).Query(
+		ctx,
+		"SELECT "+webhookDeliveryColumns+" FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY id DESC LIMIT $2 OFFSET $3",
+		webhookID,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return []domain.WebhookDelivery{}, err
+	}
+	defer rows.Close()
+
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return []domain.WebhookDelivery{}, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return []domain.WebhookDelivery{}, err
+	}
+	return deliveries, nil
+}
+
+func (r *PostgresWebhookRepo) CountWebhookDeliveries(ctx context.Context, webhookID int) (int, error) {
+	var n int
+	if err := r.conn(ctx).QueryRow(ctx, "SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = $1", webhookID).Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// ClaimWebhookDelivery skips the deliveries locked by concurrent claims, so
+// workers never wait on each other or claim the same delivery.
+func (r *PostgresWebhookRepo) ClaimWebhookDelivery(ctx context.Context, lease time.Duration) (domain.WebhookDelivery, bool, error) {
+	delivery, err := scanWebhookDelivery(r.conn(ctx).QueryRow(ctx, `UPDATE webhook_deliveries
+		SET attempts = attempts + 1, next_attempt_at = now() + $1 * interval '1 second', updated_at = now()
+		WHERE id = (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at, id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+webhookDeliveryColumns, lease.Seconds()))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.WebhookDelivery{}, false, nil
+	}
+	if err != nil {
+		return domain.WebhookDelivery{}, false, err
+	}
+	return delivery, true, nil
+}
+
+func (r *PostgresWebhookRepo) CompleteWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	_, err := r.conn(ctx).Exec(ctx, `UPDATE webhook_deliveries
+		SET status = 'succeeded', response_status = $2, error = NULL, delivered_at = now(), updated_at = now()
+		WHERE id = $1`,
+		delivery.ID, responseStatus(delivery))
+	return err
+}
+
+func (r *PostgresWebhookRepo) FailWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery, retryAt *time.Time) error {
+	if retryAt == nil {
+		_, err := r.conn(ctx).Exec(ctx, `UPDATE webhook_deliveries
+			SET status = 'failed', response_status = $2, error = $3, updated_at = now()
+			WHERE id = $1`,
+			delivery.ID, responseStatus(delivery), delivery.Error)
+		return err
+	}
+	_, err := r.conn(ctx).Exec(ctx, `UPDATE webhook_deliveries
+		SET response_status = $2, error = $3, next_attempt_at = $4, updated_at = now()
+		WHERE id = $1`,
+		delivery.ID, responseStatus(delivery), delivery.Error, *retryAt)
+	return err
+}
+
+// webhookEvents stores the events of a webhook subscribed to every event as
+// an empty array rather than NULL.
+func webhookEvents(webhook domain.Webhook) []string {
+	if webhook.Events == nil {
+		return []string{}
+	}
+	return webhook.Events
+}
+
+// responseStatus stores a delivery that got no response with a NULL status.
+func responseStatus(delivery domain.WebhookDelivery) pgtype.Int4 {
+	return pgtype.Int4{Int32: int32(delivery.ResponseStatus), Valid: delivery.ResponseStatus != 0}
+}
+
+func scanWebhook(row pgx.Row) (domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := row.Scan(&webhook.ID, &webhook.URL, &webhook.Events, &webhook.Secret, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func scanWebhookDelivery(row pgx.Row) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	var status string
+	var payload []byte
+	var responseStatus *int
+	var deliveryErr pgtype.Text
+	var deliveredAt pgtype.Timestamptz
+	err := row.Scan(&delivery.ID, &delivery.WebhookID, &delivery.MessageID, &delivery.Event, &payload, &status,
+		&delivery.Attempts, &responseStatus, &deliveryErr, &delivery.NextAttemptAt, &delivery.CreatedAt, &deliveredAt)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	delivery.Payload = payload
+	delivery.Status = domain.DeliveryStatus(status)
+	if responseStatus != nil {
+		delivery.ResponseStatus = *responseStatus
+	}
+	delivery.Error = deliveryErr.String
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return delivery, nil
+}
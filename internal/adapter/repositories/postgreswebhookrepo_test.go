@@ -0,0 +1,259 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var (
+	webhookRowColumns         = []string{"id", "url", "events", "secret", "created_at", "updated_at"}
+	webhookDeliveryRowColumns = []string{"id", "webhook_id", "message_id", "event", "payload", "status", "attempts", "response_status", "error", "next_attempt_at", "created_at", "delivered_at"}
+)
+
+func TestPostgresWebhookRepo_CreateWebhook(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	// A webhook subscribed to every event is stored with an empty array.
+	mock.ExpectQuery(`INSERT INTO webhooks \(url, events, secret\) VALUES \(\$1, \$2, \$3\) RETURNING`).
+		WithArgs("https://example.com/hooks", []string{}, "s3cret").
+		WillReturnRows(pgxmock.NewRows(webhookRowColumns).
+			AddRow(1, "https://example.com/hooks", []string{}, "s3cret", testTime, testTime))
+
+	r := NewPostgresWebhookRepo(mock)
+	got, err := r.CreateWebhook(context.Background(), domain.Webhook{URL: "https://example.com/hooks", Secret: "s3cret"})
+	if err != nil {
+		t.Fatalf("PostgresWebhookRepo.CreateWebhook() error = %v", err)
+	}
+	want := domain.Webhook{ID: 1, URL: "https://example.com/hooks", Events: []string{}, Secret: "s3cret", CreatedAt: testTime, UpdatedAt: testTime}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PostgresWebhookRepo.CreateWebhook() = %+v, want %+v", got, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostgresWebhookRepo_NotFound(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(pgxmock.PgxPoolIface)
+		call  func(*PostgresWebhookRepo) error
+	}{
+		{
+			name: "get",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("SELECT (.+) FROM webhooks WHERE id = \\$1").
+					WithArgs(9).
+					WillReturnError(pgx.ErrNoRows)
+			},
+			call: func(r *PostgresWebhookRepo) error {
+				_, err := r.GetWebhook(context.Background(), 9)
+				return err
+			},
+		},
+		{
+			name: "update",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE webhooks SET url = \\$2, events = \\$3, secret = \\$4").
+					WithArgs(9, "https://example.com/hooks", []string{"book.created"}, "s3cret").
+					WillReturnError(pgx.ErrNoRows)
+			},
+			call: func(r *PostgresWebhookRepo) error {
+				_, err := r.UpdateWebhook(context.Background(), domain.Webhook{ID: 9, URL: "https://example.com/hooks", Events: []string{"book.created"}, Secret: "s3cret"})
+				return err
+			},
+		},
+		{
+			name: "delete",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec("DELETE FROM webhooks WHERE id = \\$1").
+					WithArgs(9).
+					WillReturnResult(pgxmock.NewResult("DELETE", 0))
+			},
+			call: func(r *PostgresWebhookRepo) error {
+				return r.DeleteWebhook(context.Background(), 9)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			if err := tt.call(NewPostgresWebhookRepo(mock)); !errors.Is(err, domain.ErrWebhookNotFound) {
+				t.Errorf("error = %v, want %v", err, domain.ErrWebhookNotFound)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresWebhookRepo_AddWebhookDeliveries(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mock.Close()
+
+	mock.ExpectExec(`INSERT INTO webhook_deliveries (.+) FROM webhooks WHERE events = '\{\}' OR \$2 = ANY\(events\) ON CONFLICT \(webhook_id, message_id\) DO NOTHING`).
+		WithArgs(int64(7), "book.created", `{"id":7}`).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	r := NewPostgresWebhookRepo(mock)
+	err = r.AddWebhookDeliveries(context.Background(), domain.WebhookDelivery{MessageID: 7, Event: "book.created", Payload: json.RawMessage(`{"id":7}`)})
+	if err != nil {
+		t.Errorf("PostgresWebhookRepo.AddWebhookDeliveries() error = %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostgresWebhookRepo_ClaimWebhookDelivery(t *testing.T) {
+	delivered := testTime.Add(time.Minute)
+	serverError, noContent := 500, 204
+
+	tests := []struct {
+		name    string
+		setup   func(pgxmock.PgxPoolIface)
+		want    domain.WebhookDelivery
+		wantOK  bool
+		wantErr error
+	}{
+		{
+			name: "claimed",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery(`UPDATE webhook_deliveries SET attempts = attempts \+ 1, next_attempt_at = now\(\) \+ \$1 (.+) FOR UPDATE SKIP LOCKED`).
+					WithArgs(60.0).
+					WillReturnRows(pgxmock.NewRows(webhookDeliveryRowColumns).
+						AddRow(int64(5), 1, int64(7), "book.created", []byte(`{"id":7}`), "pending", 2, &serverError, "webhook responded 500 Internal Server Error", testTime, testTime, nil))
+			},
+			want: domain.WebhookDelivery{
+				ID: 5, WebhookID: 1, MessageID: 7, Event: "book.created", Payload: json.RawMessage(`{"id":7}`),
+				Status: domain.DeliveryPending, Attempts: 2, ResponseStatus: 500, Error: "webhook responded 500 Internal Server Error",
+				NextAttemptAt: testTime, CreatedAt: testTime,
+			},
+			wantOK: true,
+		},
+		{
+			name: "succeeded delivery scans its delivery time",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE webhook_deliveries").
+					WithArgs(60.0).
+					WillReturnRows(pgxmock.NewRows(webhookDeliveryRowColumns).
+						AddRow(int64(5), 1, int64(7), "book.created", []byte(`{}`), "succeeded", 1, &noContent, nil, testTime, testTime, delivered))
+			},
+			want: domain.WebhookDelivery{
+				ID: 5, WebhookID: 1, MessageID: 7, Event: "book.created", Payload: json.RawMessage(`{}`),
+				Status: domain.DeliverySucceeded, Attempts: 1, ResponseStatus: 204,
+				NextAttemptAt: testTime, CreatedAt: testTime, DeliveredAt: &delivered,
+			},
+			wantOK: true,
+		},
+		{
+			name: "nothing due",
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectQuery("UPDATE webhook_deliveries").
+					WithArgs(60.0).
+					WillReturnError(pgx.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresWebhookRepo(mock)
+			got, ok, err := r.ClaimWebhookDelivery(context.Background(), time.Minute)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("PostgresWebhookRepo.ClaimWebhookDelivery() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PostgresWebhookRepo.ClaimWebhookDelivery() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresWebhookRepo_FailWebhookDelivery(t *testing.T) {
+	retryAt := testTime.Add(time.Minute)
+
+	tests := []struct {
+		name     string
+		delivery domain.WebhookDelivery
+		retryAt  *time.Time
+		setup    func(pgxmock.PgxPoolIface)
+	}{
+		{
+			name:     "retry",
+			delivery: domain.WebhookDelivery{ID: 5, ResponseStatus: 500, Error: "webhook responded 500 Internal Server Error"},
+			retryAt:  &retryAt,
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(`UPDATE webhook_deliveries SET response_status = \$2, error = \$3, next_attempt_at = \$4`).
+					WithArgs(int64(5), pgxmock.AnyArg(), "webhook responded 500 Internal Server Error", retryAt).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+		},
+		{
+			name:     "give up",
+			delivery: domain.WebhookDelivery{ID: 5, Error: "connection refused"},
+			setup: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectExec(`UPDATE webhook_deliveries SET status = 'failed', response_status = \$2, error = \$3`).
+					WithArgs(int64(5), pgxmock.AnyArg(), "connection refused").
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer mock.Close()
+
+			tt.setup(mock)
+
+			r := NewPostgresWebhookRepo(mock)
+			if err := r.FailWebhookDelivery(context.Background(), tt.delivery, tt.retryAt); err != nil {
+				t.Errorf("PostgresWebhookRepo.FailWebhookDelivery() error = %v", err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// The headers of a delivery besides Content-Type. SignatureHeader holds
+// Sign(secret, timestamp, body) and TimestampHeader the Unix time it was
+// signed at, so a receiver can reject replays of old deliveries.
+const (
+	SignatureHeader = "X-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+const (
+	// sendTimeout bounds a delivery, from connecting to reading the
+	// response.
+	sendTimeout = 10 * time.Second
+	// maxResponseSize is the most of a response read before the connection
+	// is given up.
+	maxResponseSize = 64 << 10
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrStaleSignature   = errors.New("webhook timestamp is outside the tolerance")
+	ErrPrivateAddress   = errors.New("webhook host resolves to a loopback, link-local or private address")
+)
+
+// HTTPSender is an out.WebhookSender posting deliveries as JSON. Redirects
+// are not followed: a 3xx response fails the delivery.
+type HTTPSender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+var _ out.WebhookSender = &HTTPSender{}
+
+// NewHTTPSender returns a sender that connects to webhooks directly, ignoring
+// proxy settings. Unless allowPrivate is set, it refuses to connect to an
+// address that is not domain.PublicAddr. The address is checked after the
+// host name is resolved, so a DNS record changed after the webhook was
+// validated cannot reach internal services either.
+func NewHTTPSender(allowPrivate bool) *HTTPSender {
+	dialer := &net.Dialer{Timeout: sendTimeout}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil || !domain.PublicAddr(addr.Addr()) {
+				return ErrPrivateAddress
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &HTTPSender{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   sendTimeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+func (s *HTTPSender) SendWebhook(ctx context.Context, webhook domain.Webhook, delivery domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := s.now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxResponseSize))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("webhook responded %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
+
+// Sign returns the signature of a delivery body sent at timestamp:
+// "sha256=" followed by the hex HMAC-SHA256, keyed with secret, of the Unix
+// timestamp, a dot and the body.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp.Unix())
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a delivery as its receiver would: signature must be the
+// signature of body at timestamp, the value of TimestampHeader, which must
+// be within tolerance of now.
+func Verify(secret, signature, timestamp string, body []byte, now time.Time, tolerance time.Duration) error {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	at := time.Unix(unix, 0)
+	if d := now.Sub(at); d > tolerance || d < -tolerance {
+		return ErrStaleSignature
+	}
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, at, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
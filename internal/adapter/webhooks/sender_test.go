@@ -0,0 +1,130 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPSender_SendWebhook(t *testing.T) {
+	body := []byte(`{"id":7,"type":"book.created","key":"book:1","payload":{"book":{"id":1}}}`)
+	delivery := domain.WebhookDelivery{ID: 3, Event: "book.created", Payload: body}
+
+	tests := []struct {
+		name       string
+		respond    func(http.ResponseWriter, *http.Request)
+		wantStatus int
+		wantErr    bool
+	}{
+		{
+			name:       "accepted",
+			respond:    func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) },
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name:       "server error",
+			respond:    func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+			wantStatus: http.StatusInternalServerError,
+			wantErr:    true,
+		},
+		{
+			name:       "redirect is not followed",
+			respond:    func(w http.ResponseWriter, r *http.Request) { http.Redirect(w, r, "/elsewhere", http.StatusFound) },
+			wantStatus: http.StatusFound,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			var gotBody []byte
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got == nil {
+					got = r
+					gotBody, _ = io.ReadAll(r.Body)
+				}
+				tt.respond(w, r)
+			}))
+			defer receiver.Close()
+
+			s := NewHTTPSender(true)
+			status, err := s.SendWebhook(context.Background(), domain.Webhook{URL: receiver.URL, Secret: "s3cret"}, delivery)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HTTPSender.SendWebhook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("HTTPSender.SendWebhook() status = %d, want %d", status, tt.wantStatus)
+			}
+
+			if got == nil {
+				t.Fatal("receiver got no request")
+			}
+			if string(gotBody) != string(body) {
+				t.Errorf("body = %s, want %s", gotBody, body)
+			}
+			if got.Header.Get(EventHeader) != "book.created" || got.Header.Get(DeliveryHeader) != "3" {
+				t.Errorf("unexpected headers %v", got.Header)
+			}
+			err = Verify("s3cret", got.Header.Get(SignatureHeader), got.Header.Get(TimestampHeader), gotBody, time.Now(), time.Minute)
+			if err != nil {
+				t.Errorf("Verify() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestHTTPSender_SendWebhook_PrivateAddress(t *testing.T) {
+	reached := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	defer receiver.Close()
+
+	// localhost passes as a host name and is only refused once resolved.
+	webhook := domain.Webhook{URL: strings.Replace(receiver.URL, "127.0.0.1", "localhost", 1), Secret: "s3cret"}
+	status, err := NewHTTPSender(false).SendWebhook(context.Background(), webhook, domain.WebhookDelivery{ID: 3, Payload: []byte(`{}`)})
+	if !errors.Is(err, ErrPrivateAddress) || status != 0 {
+		t.Errorf("HTTPSender.SendWebhook() = %d, %v, want ErrPrivateAddress", status, err)
+	}
+	if reached {
+		t.Error("receiver got a request")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":7}`)
+	signedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	signature := Sign("s3cret", signedAt, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+		now       time.Time
+		wantErr   error
+	}{
+		{name: "valid", secret: "s3cret", timestamp: timestamp, body: `{"id":7}`, now: signedAt.Add(time.Minute)},
+		{name: "wrong secret", secret: "other", timestamp: timestamp, body: `{"id":7}`, now: signedAt, wantErr: ErrInvalidSignature},
+		{name: "tampered body", secret: "s3cret", timestamp: timestamp, body: `{"id":8}`, now: signedAt, wantErr: ErrInvalidSignature},
+		{name: "altered timestamp", secret: "s3cret", timestamp: strconv.FormatInt(signedAt.Add(time.Second).Unix(), 10), body: `{"id":7}`, now: signedAt, wantErr: ErrInvalidSignature},
+		{name: "stale", secret: "s3cret", timestamp: timestamp, body: `{"id":7}`, now: signedAt.Add(10 * time.Minute), wantErr: ErrStaleSignature},
+		{name: "malformed timestamp", secret: "s3cret", timestamp: "yesterday", body: `{"id":7}`, now: signedAt, wantErr: ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, signature, tt.timestamp, []byte(tt.body), tt.now, 5*time.Minute)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
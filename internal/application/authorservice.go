@@ -0,0 +1,105 @@
+package application
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+)
+
+// AuthorService renames authors through the write path of BookService, as
+// a rename rewrites the byline of every book of the author.
+type AuthorService struct {
+	authorRepo out.AuthorRepository
+	bookRepo   out.BookRepository
+	bookWriter
+}
+
+var _ in.AuthorUseCase = &AuthorService{}
+
+func NewAuthorService(authorRepo out.AuthorRepository, bookRepo out.BookRepository, historyRepo out.BookHistoryRepository, outbox out.OutboxRepository, txManager out.TxManager, publisher out.EventPublisher) *AuthorService {
+	return &AuthorService{
+		authorRepo: authorRepo,
+		bookRepo:   bookRepo,
+		bookWriter: bookWriter{historyRepo: historyRepo, outbox: outbox, txManager: txManager, publisher: publisher},
+	}
+}
+
+func (s *AuthorService) CreateAuthor(ctx context.Context, author domain.Author) (domain.Author, error) {
+	if err := author.Validate(); err != nil {
+		return domain.Author{}, err
+	}
+	return s.authorRepo.CreateAuthor(ctx, author)
+}
+
+func (s *AuthorService) GetAuthor(ctx context.Context, id int) (domain.Author, error) {
+	return s.authorRepo.GetAuthor(ctx, id)
+}
+
+func (s *AuthorService) GetAuthors(ctx context.Context, page, perPage int) ([]domain.Author, int, error) {
+	offset, limit := paginate(page, perPage)
+	authors, err := s.authorRepo.GetAuthors(ctx, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.authorRepo.CountAuthors(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return authors, total, nil
+}
+
+func (s *AuthorService) GetAuthorBooks(ctx context.Context, id, page, perPage int) ([]domain.Book, int, error) {
+	if _, err := s.authorRepo.GetAuthor(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	offset, limit := paginate(page, perPage)
+	books, err := s.bookRepo.GetBooksByAuthor(ctx, id, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.bookRepo.CountBooksByAuthor(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	return books, total, nil
+}
+
+// UpdateAuthor renames the author and refreshes the byline of its books,
+// live or in the trash, recording each as an update of the book.
+func (s *AuthorService) UpdateAuthor(ctx context.Context, author domain.Author) error {
+	if err := author.Validate(); err != nil {
+		return err
+	}
+	return s.write(ctx, func(ctx context.Context) ([]domain.BookChange, error) {
+		before, err := s.bookRepo.LockBooksByAuthor(ctx, author.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.authorRepo.UpdateAuthor(ctx, author); err != nil {
+			return nil, err
+		}
+		if len(before) == 0 {
+			return nil, nil
+		}
+
+		ids := make([]int, len(before))
+		for i, book := range before {
+			ids[i] = book.ID
+		}
+		after, err := s.bookRepo.RefreshBylines(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		changes := make([]domain.BookChange, len(after))
+		for i := range after {
+			changes[i] = domain.NewBookChange(ctx, domain.BookActionUpdate, &before[i], after[i])
+		}
+		return changes, nil
+	})
+}
+
+func (s *AuthorService) DeleteAuthor(ctx context.Context, id int) error {
+	return s.authorRepo.DeleteAuthor(ctx, id)
+}
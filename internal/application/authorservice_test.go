@@ -0,0 +1,218 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestAuthorService_CreateAuthor(t *testing.T) {
+	tests := []struct {
+		name    string
+		author  domain.Author
+		setup   func(*mocks.MockAuthorRepository)
+		want    domain.Author
+		wantErr error
+	}{
+		{
+			name:   "success",
+			author: domain.Author{Name: "George Orwell"},
+			setup: func(m *mocks.MockAuthorRepository) {
+				m.EXPECT().CreateAuthor(gomock.Any(), domain.Author{Name: "George Orwell"}).
+					Return(domain.Author{ID: 1, Name: "George Orwell"}, nil)
+			},
+			want:    domain.Author{ID: 1, Name: "George Orwell"},
+			wantErr: nil,
+		},
+		{
+			name:    "validation error - blank name",
+			author:  domain.Author{Name: "   "},
+			setup:   func(m *mocks.MockAuthorRepository) {},
+			want:    domain.Author{},
+			wantErr: domain.ErrAuthorNameRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockAuthorRepository(ctrl)
+			tt.setup(mockRepo)
+
+			s := NewAuthorService(mockRepo, mocks.NewMockBookRepository(ctrl), newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+			got, err := s.CreateAuthor(context.Background(), tt.author)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AuthorService.CreateAuthor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AuthorService.CreateAuthor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorService_GetAuthors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAuthorRepository(ctrl)
+	want := []domain.Author{{ID: 11, Name: "George Orwell"}}
+	mockRepo.EXPECT().GetAuthors(gomock.Any(), 10, 10).Return(want, nil)
+	mockRepo.EXPECT().CountAuthors(gomock.Any()).Return(11, nil)
+
+	s := NewAuthorService(mockRepo, mocks.NewMockBookRepository(ctrl), newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+	got, total, err := s.GetAuthors(context.Background(), 2, 10)
+	if err != nil {
+		t.Fatalf("AuthorService.GetAuthors() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AuthorService.GetAuthors() = %v, want %v", got, want)
+	}
+	if total != 11 {
+		t.Errorf("AuthorService.GetAuthors() total = %v, want %v", total, 11)
+	}
+}
+
+func TestAuthorService_GetAuthorBooks(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        int
+		setup     func(*mocks.MockAuthorRepository, *mocks.MockBookRepository)
+		want      []domain.Book
+		wantTotal int
+		wantErr   error
+	}{
+		{
+			name: "success",
+			id:   1,
+			setup: func(a *mocks.MockAuthorRepository, m *mocks.MockBookRepository) {
+				a.EXPECT().GetAuthor(gomock.Any(), 1).Return(domain.Author{ID: 1, Name: "George Orwell"}, nil)
+				m.EXPECT().GetBooksByAuthor(gomock.Any(), 1, 0, 10).
+					Return([]domain.Book{{ID: 1, Title: "1984", Author: "George Orwell"}}, nil)
+				m.EXPECT().CountBooksByAuthor(gomock.Any(), 1).Return(1, nil)
+			},
+			want:      []domain.Book{{ID: 1, Title: "1984", Author: "George Orwell"}},
+			wantTotal: 1,
+			wantErr:   nil,
+		},
+		{
+			name: "author not found",
+			id:   999,
+			setup: func(a *mocks.MockAuthorRepository, m *mocks.MockBookRepository) {
+				a.EXPECT().GetAuthor(gomock.Any(), 999).Return(domain.Author{}, domain.ErrAuthorNotFound)
+			},
+			want:    nil,
+			wantErr: domain.ErrAuthorNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			authorRepo := mocks.NewMockAuthorRepository(ctrl)
+			bookRepo := mocks.NewMockBookRepository(ctrl)
+			tt.setup(authorRepo, bookRepo)
+
+			s := NewAuthorService(authorRepo, bookRepo, newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+			got, total, err := s.GetAuthorBooks(context.Background(), tt.id, 1, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AuthorService.GetAuthorBooks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AuthorService.GetAuthorBooks() = %v, want %v", got, tt.want)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("AuthorService.GetAuthorBooks() total = %v, want %v", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestAuthorService_UpdateAuthor(t *testing.T) {
+	before := domain.Book{ID: 3, Title: "1984", Author: "George Orwell", Version: 2,
+		Authors: []domain.Author{{ID: 1, Name: "George Orwell"}}}
+	after := domain.Book{ID: 3, Title: "1984", Author: "Eric Blair", Version: 3,
+		Authors: []domain.Author{{ID: 1, Name: "Eric Blair"}}}
+
+	tests := []struct {
+		name        string
+		author      domain.Author
+		setup       func(*mocks.MockAuthorRepository, *mocks.MockBookRepository)
+		wantChanges []domain.BookChange
+		wantErr     error
+	}{
+		{
+			name:   "success - records the new byline of each book",
+			author: domain.Author{ID: 1, Name: "Eric Blair"},
+			setup: func(a *mocks.MockAuthorRepository, b *mocks.MockBookRepository) {
+				gomock.InOrder(
+					b.EXPECT().LockBooksByAuthor(gomock.Any(), 1).Return([]domain.Book{before}, nil),
+					a.EXPECT().UpdateAuthor(gomock.Any(), domain.Author{ID: 1, Name: "Eric Blair"}).Return(nil),
+					b.EXPECT().RefreshBylines(gomock.Any(), []int{3}).Return([]domain.Book{after}, nil),
+				)
+			},
+			wantChanges: []domain.BookChange{
+				{BookID: 3, Version: 3, Action: domain.BookActionUpdate, Before: &before, After: after},
+			},
+		},
+		{
+			name:   "success - author without books",
+			author: domain.Author{ID: 2, Name: "Eric Blair"},
+			setup: func(a *mocks.MockAuthorRepository, b *mocks.MockBookRepository) {
+				b.EXPECT().LockBooksByAuthor(gomock.Any(), 2).Return([]domain.Book{}, nil)
+				a.EXPECT().UpdateAuthor(gomock.Any(), domain.Author{ID: 2, Name: "Eric Blair"}).Return(nil)
+			},
+		},
+		{
+			name:    "validation error - empty name",
+			author:  domain.Author{ID: 1, Name: ""},
+			setup:   func(a *mocks.MockAuthorRepository, b *mocks.MockBookRepository) {},
+			wantErr: domain.ErrAuthorNameRequired,
+		},
+		{
+			name:   "not found",
+			author: domain.Author{ID: 999, Name: "Eric Blair"},
+			setup: func(a *mocks.MockAuthorRepository, b *mocks.MockBookRepository) {
+				b.EXPECT().LockBooksByAuthor(gomock.Any(), 999).Return([]domain.Book{}, nil)
+				a.EXPECT().UpdateAuthor(gomock.Any(), domain.Author{ID: 999, Name: "Eric Blair"}).Return(domain.ErrAuthorNotFound)
+			},
+			wantErr: domain.ErrAuthorNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockAuthorRepository(ctrl)
+			bookRepo := mocks.NewMockBookRepository(ctrl)
+			tt.setup(mockRepo, bookRepo)
+
+			var gotChanges []domain.BookChange
+			mockHistory := mocks.NewMockBookHistoryRepository(ctrl)
+			mockHistory.EXPECT().AddBookChanges(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, changes []domain.BookChange) error {
+					gotChanges = changes
+					return nil
+				}).
+				MaxTimes(1)
+
+			s := NewAuthorService(mockRepo, bookRepo, mockHistory, newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+			if err := s.UpdateAuthor(context.Background(), tt.author); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AuthorService.UpdateAuthor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(gotChanges, tt.wantChanges) {
+				t.Errorf("AuthorService.UpdateAuthor() recorded %+v, want %+v", gotChanges, tt.wantChanges)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package application
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+	"time"
+)
+
+// BookAsOfService answers point-in-time reads from the book history, so books
+// deleted or purged since are still seen as they were.
+type BookAsOfService struct {
+	reader out.BookAsOfReader
+}
+
+var _ in.BookAsOfUseCase = &BookAsOfService{}
+
+func NewBookAsOfService(reader out.BookAsOfReader) *BookAsOfService {
+	return &BookAsOfService{reader: reader}
+}
+
+func (s *BookAsOfService) GetBookAsOf(ctx context.Context, id int, at time.Time) (domain.Book, error) {
+	return s.reader.GetBookAsOf(ctx, id, at)
+}
+
+func (s *BookAsOfService) GetBooksAsOf(ctx context.Context, criteria domain.BookCriteria, at time.Time, page, perPage int) ([]domain.Book, int, error) {
+	offset, limit := paginate(page, perPage)
+	books, err := s.reader.GetBooksAsOf(ctx, criteria, at, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.reader.CountBooksAsOf(ctx, criteria, at)
+	if err != nil {
+		return nil, 0, err
+	}
+	return books, total, nil
+}
@@ -0,0 +1,71 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestBookAsOfService_GetBooksAsOf(t *testing.T) {
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	criteria := domain.BookCriteria{Author: "George Orwell"}
+	books := []domain.Book{{ID: 1, Title: "Animal Farm"}}
+	errDB := errors.New("db error")
+
+	tests := []struct {
+		name      string
+		setup     func(*mocks.MockBookAsOfReader)
+		want      []domain.Book
+		wantTotal int
+		wantErr   error
+	}{
+		{
+			name: "success - second page",
+			setup: func(m *mocks.MockBookAsOfReader) {
+				m.EXPECT().GetBooksAsOf(gomock.Any(), criteria, at, 10, 10).Return(books, nil)
+				m.EXPECT().CountBooksAsOf(gomock.Any(), criteria, at).Return(11, nil)
+			},
+			want:      books,
+			wantTotal: 11,
+		},
+		{
+			name: "read error",
+			setup: func(m *mocks.MockBookAsOfReader) {
+				m.EXPECT().GetBooksAsOf(gomock.Any(), criteria, at, 10, 10).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "count error",
+			setup: func(m *mocks.MockBookAsOfReader) {
+				m.EXPECT().GetBooksAsOf(gomock.Any(), criteria, at, 10, 10).Return(books, nil)
+				m.EXPECT().CountBooksAsOf(gomock.Any(), criteria, at).Return(0, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockReader := mocks.NewMockBookAsOfReader(ctrl)
+			tt.setup(mockReader)
+
+			s := NewBookAsOfService(mockReader)
+			got, total, err := s.GetBooksAsOf(context.Background(), criteria, at, 2, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BookAsOfService.GetBooksAsOf() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) || total != tt.wantTotal {
+				t.Errorf("BookAsOfService.GetBooksAsOf() = %v, %d, want %v, %d", got, total, tt.want, tt.wantTotal)
+			}
+		})
+	}
+}
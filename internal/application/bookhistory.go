@@ -0,0 +1,49 @@
+package application
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/domain"
+)
+
+var _ in.BookHistoryUseCase = &BookService{}
+
+// GetBookHistory reports domain.ErrBookNotFound for a book with no history;
+// every book, including the ones written before the history was kept, has
+// one.
+func (s *BookService) GetBookHistory(ctx context.Context, id, page, perPage int) ([]domain.BookChange, int, error) {
+	offset, limit := paginate(page, perPage)
+	changes, err := s.historyRepo.GetBookHistory(ctx, id, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.historyRepo.CountBookHistory(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return nil, 0, domain.ErrBookNotFound
+	}
+	return changes, total, nil
+}
+
+func (s *BookService) GetBookVersion(ctx context.Context, id, version int) (domain.BookChange, error) {
+	return s.historyRepo.GetBookChange(ctx, id, version)
+}
+
+// RevertBook replaces the live book with its snapshot at version, validated
+// and with its authors resolved as in UpdateBook. The write bumps the version
+// like any other and is recorded as a revert.
+func (s *BookService) RevertBook(ctx context.Context, id, version, current int) (domain.Book, error) {
+	change, err := s.historyRepo.GetBookChange(ctx, id, version)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	if change.After.DeletedAt != nil {
+		return domain.Book{}, domain.ErrRevertToDeleted
+	}
+
+	book := change.After
+	book.Version = current
+	return s.replaceBook(ctx, book, domain.BookActionRevert)
+}
@@ -0,0 +1,318 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestBookService_RecordsAndPublishesChanges(t *testing.T) {
+	orwell := domain.Author{ID: 1, Name: "George Orwell"}
+	deletedAt := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	v1 := domain.Book{ID: 1, Title: "1984", Author: "George Orwell", Authors: []domain.Author{orwell}, Version: 1}
+	v2 := domain.Book{ID: 1, Title: "Nineteen Eighty-Four", Author: "George Orwell", Authors: []domain.Author{orwell}, Version: 2}
+	trashed := v1
+	trashed.Version, trashed.DeletedAt = 2, &deletedAt
+	restored := v1
+	restored.Version = 3
+	audit := domain.Audit{Actor: "alice", RequestID: "req-1"}
+	change := func(action domain.BookAction, before *domain.Book, after domain.Book) domain.BookChange {
+		return domain.BookChange{BookID: after.ID, Version: after.Version, Action: action, Before: before, After: after, Actor: "alice", RequestID: "req-1"}
+	}
+
+	tests := []struct {
+		name       string
+		setup      func(*mocks.MockBookRepository, *mocks.MockAuthorRepository)
+		write      func(context.Context, *BookService) error
+		want       []domain.BookChange
+		wantEvents []domain.Event
+	}{
+		{
+			name: "create",
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(orwell, nil)
+				m.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(v1, nil)
+			},
+			write: func(ctx context.Context, s *BookService) error {
+				_, err := s.CreateBook(ctx, domain.Book{Title: "1984", Author: "George Orwell"})
+				return err
+			},
+			want:       []domain.BookChange{change(domain.BookActionCreate, nil, v1)},
+			wantEvents: []domain.Event{domain.BookCreated{Book: v1}},
+		},
+		{
+			name: "batch records created books only",
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(orwell, nil)
+				m.EXPECT().CreateBooks(gomock.Any(), gomock.Any(), domain.BatchBestEffort).
+					Return([]domain.BatchResult{{Book: v1}, {Err: domain.ErrDuplicateISBN}}, nil)
+			},
+			write: func(ctx context.Context, s *BookService) error {
+				_, err := s.CreateBooks(ctx, []domain.Book{
+					{Title: "1984", Author: "George Orwell"},
+					{Title: "Animal Farm", Author: "George Orwell"},
+				}, domain.BatchBestEffort)
+				return err
+			},
+			want:       []domain.BookChange{change(domain.BookActionCreate, nil, v1)},
+			wantEvents: []domain.Event{domain.BookCreated{Book: v1}},
+		},
+		{
+			name: "update",
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(v1, nil)
+				a.EXPECT().GetAuthorsByIDs(gomock.Any(), []int{1}).Return([]domain.Author{orwell}, nil)
+				m.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).Return(v2, nil)
+			},
+			write: func(ctx context.Context, s *BookService) error {
+				_, err := s.UpdateBook(ctx, domain.Book{ID: 1, Title: "Nineteen Eighty-Four", Authors: []domain.Author{{ID: 1}}})
+				return err
+			},
+			want:       []domain.BookChange{change(domain.BookActionUpdate, &v1, v2)},
+			wantEvents: []domain.Event{domain.BookUpdated{Book: v2, Changed: []string{"title"}}},
+		},
+		{
+			name: "patch",
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(v1, nil)
+				m.EXPECT().PatchBook(gomock.Any(), 1, 0, gomock.Any()).Return(v2, nil)
+			},
+			write: func(ctx context.Context, s *BookService) error {
+				title := "Nineteen Eighty-Four"
+				_, err := s.PatchBook(ctx, 1, 0, domain.BookPatch{Title: &title})
+				return err
+			},
+			want:       []domain.BookChange{change(domain.BookActionUpdate, &v1, v2)},
+			wantEvents: []domain.Event{domain.BookUpdated{Book: v2, Changed: []string{"title"}}},
+		},
+		{
+			name: "patch without changes records nothing",
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(v1, nil)
+			},
+			write: func(ctx context.Context, s *BookService) error {
+				title := "1984"
+				_, err := s.PatchBook(ctx, 1, 0, domain.BookPatch{Title: &title})
+				return err
+			},
+		},
+		{
+			name: "delete",
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(v1, nil)
+				m.EXPECT().DeleteBook(gomock.Any(), 1, 1).Return(nil)
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(trashed, nil)
+			},
+			write: func(ctx context.Context, s *BookService) error {
+				return s.DeleteBook(ctx, 1, 1)
+			},
+			want:       []domain.BookChange{change(domain.BookActionDelete, &v1, trashed)},
+			wantEvents: []domain.Event{domain.BookDeleted{Book: trashed}},
+		},
+		{
+			name: "restore",
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(trashed, nil)
+				m.EXPECT().RestoreBook(gomock.Any(), 1).Return(restored, nil)
+			},
+			write: func(ctx context.Context, s *BookService) error {
+				_, err := s.RestoreBook(ctx, 1)
+				return err
+			},
+			want:       []domain.BookChange{change(domain.BookActionRestore, &trashed, restored)},
+			wantEvents: []domain.Event{domain.BookRestored{Book: restored}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockBookRepository(ctrl)
+			mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+			mockHistory := mocks.NewMockBookHistoryRepository(ctrl)
+			mockOutbox := mocks.NewMockOutboxRepository(ctrl)
+			mockPublisher := mocks.NewMockEventPublisher(ctrl)
+			tt.setup(mockRepo, mockAuthorRepo)
+			if tt.want != nil {
+				mockHistory.EXPECT().AddBookChanges(gomock.Any(), tt.want).Return(nil)
+				var events []any
+				var messages []domain.OutboxMessage
+				for _, event := range tt.wantEvents {
+					events = append(events, event)
+					msg, err := domain.NewOutboxMessage(event)
+					if err != nil {
+						t.Fatal(err)
+					}
+					messages = append(messages, msg)
+				}
+				mockOutbox.EXPECT().AddOutboxMessages(gomock.Any(), messages).Return(nil)
+				mockPublisher.EXPECT().Publish(gomock.Any(), events...)
+			}
+
+			s := NewBookService(mockRepo, mockAuthorRepo, mockHistory, mockOutbox, newTxManager(ctrl), mockPublisher)
+			if err := tt.write(domain.WithAudit(context.Background(), audit), s); err != nil {
+				t.Errorf("write error = %v", err)
+			}
+		})
+	}
+}
+
+func TestBookService_RestoreBook(t *testing.T) {
+	tests := []struct {
+		name    string
+		locked  domain.Book
+		lockErr error
+		wantErr error
+	}{
+		{name: "live book", locked: domain.Book{ID: 1}, wantErr: domain.ErrBookNotInTrash},
+		{name: "unknown book", lockErr: domain.ErrBookNotFound, wantErr: domain.ErrBookNotInTrash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockBookRepository(ctrl)
+			mockRepo.EXPECT().LockBook(gomock.Any(), 1).Return(tt.locked, tt.lockErr)
+
+			s := NewBookService(mockRepo, mocks.NewMockAuthorRepository(ctrl), newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+			if _, err := s.RestoreBook(context.Background(), 1); !errors.Is(err, tt.wantErr) {
+				t.Errorf("BookService.RestoreBook() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBookService_GetBookHistory(t *testing.T) {
+	changes := []domain.BookChange{{BookID: 1, Version: 2}, {BookID: 1, Version: 1}}
+	tests := []struct {
+		name      string
+		setup     func(*mocks.MockBookRepository, *mocks.MockBookHistoryRepository)
+		want      []domain.BookChange
+		wantTotal int
+		wantErr   error
+	}{
+		{
+			name: "history",
+			setup: func(m *mocks.MockBookRepository, h *mocks.MockBookHistoryRepository) {
+				h.EXPECT().GetBookHistory(gomock.Any(), 1, 0, 10).Return(changes, nil)
+				h.EXPECT().CountBookHistory(gomock.Any(), 1).Return(2, nil)
+			},
+			want:      changes,
+			wantTotal: 2,
+		},
+		{
+			name: "unknown book",
+			setup: func(m *mocks.MockBookRepository, h *mocks.MockBookHistoryRepository) {
+				h.EXPECT().GetBookHistory(gomock.Any(), 1, 0, 10).Return([]domain.BookChange{}, nil)
+				h.EXPECT().CountBookHistory(gomock.Any(), 1).Return(0, nil)
+			},
+			wantErr: domain.ErrBookNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockBookRepository(ctrl)
+			mockHistory := mocks.NewMockBookHistoryRepository(ctrl)
+			tt.setup(mockRepo, mockHistory)
+
+			s := NewBookService(mockRepo, mocks.NewMockAuthorRepository(ctrl), mockHistory, newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+			got, total, err := s.GetBookHistory(context.Background(), 1, 1, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BookService.GetBookHistory() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) || total != tt.wantTotal {
+				t.Errorf("BookService.GetBookHistory() = %v, %d, want %v, %d", got, total, tt.want, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestBookService_RevertBook(t *testing.T) {
+	orwell := domain.Author{ID: 1, Name: "George Orwell"}
+	deletedAt := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	v1 := domain.Book{ID: 1, Title: "1984", Author: "George Orwell", Authors: []domain.Author{orwell}, ISBN: "9780451524935", Version: 1}
+	v2 := domain.Book{ID: 1, Title: "Nineteen Eighty-Four", Author: "George Orwell", Authors: []domain.Author{orwell}, Version: 2}
+	v3 := v1
+	v3.Version = 3
+
+	tests := []struct {
+		name    string
+		current int
+		setup   func(*mocks.MockBookRepository, *mocks.MockAuthorRepository, *mocks.MockBookHistoryRepository)
+		want    domain.Book
+		wantErr error
+	}{
+		{
+			name:    "success",
+			current: 2,
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository, h *mocks.MockBookHistoryRepository) {
+				h.EXPECT().GetBookChange(gomock.Any(), 1, 1).Return(domain.BookChange{BookID: 1, Version: 1, Action: domain.BookActionCreate, After: v1}, nil)
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(v2, nil)
+				a.EXPECT().GetAuthorsByIDs(gomock.Any(), []int{1}).Return([]domain.Author{orwell}, nil)
+				reverted := v1
+				reverted.Version = 2
+				m.EXPECT().UpdateBook(gomock.Any(), reverted).Return(v3, nil)
+				h.EXPECT().AddBookChanges(gomock.Any(), []domain.BookChange{
+					{BookID: 1, Version: 3, Action: domain.BookActionRevert, Before: &v2, After: v3},
+				}).Return(nil)
+			},
+			want: v3,
+		},
+		{
+			name: "unknown version",
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository, h *mocks.MockBookHistoryRepository) {
+				h.EXPECT().GetBookChange(gomock.Any(), 1, 1).Return(domain.BookChange{}, domain.ErrBookVersionNotFound)
+			},
+			wantErr: domain.ErrBookVersionNotFound,
+		},
+		{
+			name: "version in the trash",
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository, h *mocks.MockBookHistoryRepository) {
+				trashed := v1
+				trashed.DeletedAt = &deletedAt
+				h.EXPECT().GetBookChange(gomock.Any(), 1, 1).Return(domain.BookChange{BookID: 1, Version: 1, Action: domain.BookActionDelete, After: trashed}, nil)
+			},
+			wantErr: domain.ErrRevertToDeleted,
+		},
+		{
+			name: "author deleted since",
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository, h *mocks.MockBookHistoryRepository) {
+				h.EXPECT().GetBookChange(gomock.Any(), 1, 1).Return(domain.BookChange{BookID: 1, Version: 1, Action: domain.BookActionCreate, After: v1}, nil)
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(v2, nil)
+				a.EXPECT().GetAuthorsByIDs(gomock.Any(), []int{1}).Return(nil, domain.ErrUnknownAuthor)
+			},
+			wantErr: domain.ErrUnknownAuthor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockBookRepository(ctrl)
+			mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+			mockHistory := mocks.NewMockBookHistoryRepository(ctrl)
+			tt.setup(mockRepo, mockAuthorRepo, mockHistory)
+
+			s := NewBookService(mockRepo, mockAuthorRepo, mockHistory, newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+			got, err := s.RevertBook(context.Background(), 1, 1, tt.current)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BookService.RevertBook() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookService.RevertBook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
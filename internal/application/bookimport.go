@@ -0,0 +1,125 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/domain"
+	"io"
+)
+
+// importChunkSize is the number of rows ImportBooks holds in memory and
+// writes per transaction.
+const importChunkSize = 500
+
+var _ in.BookImportUseCase = &BookService{}
+
+// ImportBooks writes the rows in best-effort batches of importChunkSize, so a
+// failed row never holds back the others and a large file is never held in
+// memory. A row whose ISBN is taken is skipped rather than failed, which makes
+// importing the same file twice harmless. A failure after a batch has been
+// written is a domain.ImportInterruptedError.
+func (s *BookService) ImportBooks(ctx context.Context, rows in.BookRowReader, dryRun bool) (domain.ImportReport, error) {
+	report := domain.ImportReport{DryRun: dryRun, Rows: []domain.ImportRowResult{}}
+	seen := make(map[string]bool)
+	// written is the last line of the batches written so far.
+	written := 0
+	interrupted := func(err error) error {
+		if written == 0 {
+			return err
+		}
+		return &domain.ImportInterruptedError{Line: written, Err: err}
+	}
+	chunk := make([]domain.ImportRow, 0, importChunkSize)
+	flush := func() error {
+		var books []domain.Book
+		for _, row := range chunk {
+			if row.Err == nil {
+				books = append(books, row.Book)
+			}
+		}
+		var results []domain.BatchResult
+		var err error
+		switch {
+		case len(books) == 0:
+		case dryRun:
+			results, err = s.checkBooks(ctx, books, seen)
+		default:
+			results, err = s.CreateBooks(ctx, books, domain.BatchBestEffort)
+		}
+		if err != nil {
+			return err
+		}
+		if !dryRun && len(books) > 0 {
+			written = chunk[len(chunk)-1].Line
+		}
+		for _, row := range chunk {
+			if row.Err != nil {
+				report.Add(row.Line, domain.BatchResult{Err: row.Err})
+				continue
+			}
+			report.Add(row.Line, results[0])
+			results = results[1:]
+		}
+		chunk = chunk[:0]
+		return nil
+	}
+
+	for {
+		row, err := rows.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return domain.ImportReport{}, interrupted(err)
+		}
+		chunk = append(chunk, row)
+		if len(chunk) == importChunkSize {
+			if err := flush(); err != nil {
+				return domain.ImportReport{}, interrupted(err)
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return domain.ImportReport{}, interrupted(err)
+	}
+	return report, nil
+}
+
+// checkBooks validates books the way CreateBooks would without writing them.
+// An ISBN that is taken, or in seen from earlier in the import, fails with
+// domain.ErrDuplicateISBN; the valid ISBNs are added to seen.
+func (s *BookService) checkBooks(ctx context.Context, books []domain.Book, seen map[string]bool) ([]domain.BatchResult, error) {
+	results := make([]domain.BatchResult, len(books))
+	var isbns []string
+	for i := range books {
+		if err := books[i].Validate(); err != nil {
+			results[i].Err = err
+			continue
+		}
+		if books[i].ISBN != "" {
+			isbns = append(isbns, books[i].ISBN)
+		}
+	}
+	if len(isbns) == 0 {
+		return results, nil
+	}
+
+	existing, err := s.bookRepo.FindExistingISBNs(ctx, isbns)
+	if err != nil {
+		return nil, err
+	}
+	for _, isbn := range existing {
+		seen[isbn] = true
+	}
+	for i, book := range books {
+		if results[i].Err != nil || book.ISBN == "" {
+			continue
+		}
+		if seen[book.ISBN] {
+			results[i].Err = domain.ErrDuplicateISBN
+		}
+		seen[book.ISBN] = true
+	}
+	return results, nil
+}
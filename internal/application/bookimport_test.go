@@ -0,0 +1,227 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"io"
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+// sliceRows reads import rows from a slice, then fails with err or io.EOF.
+type sliceRows struct {
+	rows []domain.ImportRow
+	err  error
+}
+
+func (r *sliceRows) Read() (domain.ImportRow, error) {
+	if len(r.rows) == 0 {
+		if r.err != nil {
+			return domain.ImportRow{}, r.err
+		}
+		return domain.ImportRow{}, io.EOF
+	}
+	row := r.rows[0]
+	r.rows = r.rows[1:]
+	return row, nil
+}
+
+func TestBookService_ImportBooks(t *testing.T) {
+	orwell := domain.Author{ID: 1, Name: "George Orwell"}
+	farm := domain.Book{Title: "Animal Farm", Author: "George Orwell"}
+	nineteen := domain.Book{Title: "1984", Author: "George Orwell", ISBN: "9780743273565"}
+	resolved := func(b domain.Book) domain.Book {
+		b.SetAuthors([]domain.Author{orwell})
+		return b
+	}
+	errParse := errors.New("bare quote")
+	errDB := errors.New("db error")
+
+	rows := func() []domain.ImportRow {
+		return []domain.ImportRow{
+			{Line: 2, Book: farm},
+			{Line: 3, Err: errParse},
+			{Line: 4, Book: domain.Book{Author: "George Orwell"}},
+			{Line: 6, Book: nineteen},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		rows    *sliceRows
+		dryRun  bool
+		setup   func(*mocks.MockBookRepository, *mocks.MockAuthorRepository)
+		want    []domain.ImportRowResult
+		wantErr error
+	}{
+		{
+			name: "import",
+			rows: &sliceRows{rows: rows()},
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(orwell, nil)
+				m.EXPECT().CreateBooks(gomock.Any(), []domain.Book{resolved(farm), resolved(nineteen)}, domain.BatchBestEffort).
+					Return([]domain.BatchResult{{Book: domain.Book{ID: 1}}, {Err: domain.ErrDuplicateISBN}}, nil)
+			},
+			want: []domain.ImportRowResult{
+				{Line: 2, Status: domain.ImportInserted, BookID: 1},
+				{Line: 3, Status: domain.ImportFailed, Err: errParse},
+				{Line: 4, Status: domain.ImportFailed, Err: domain.ErrTitleRequired},
+				{Line: 6, Status: domain.ImportSkipped, Err: domain.ErrDuplicateISBN},
+			},
+		},
+		{
+			name:   "dry run",
+			rows:   &sliceRows{rows: append(rows(), domain.ImportRow{Line: 7, Book: nineteen})},
+			dryRun: true,
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().FindExistingISBNs(gomock.Any(), []string{"9780743273565", "9780743273565"}).Return([]string{}, nil)
+			},
+			want: []domain.ImportRowResult{
+				{Line: 2, Status: domain.ImportInserted},
+				{Line: 3, Status: domain.ImportFailed, Err: errParse},
+				{Line: 4, Status: domain.ImportFailed, Err: domain.ErrTitleRequired},
+				{Line: 6, Status: domain.ImportInserted},
+				{Line: 7, Status: domain.ImportSkipped, Err: domain.ErrDuplicateISBN},
+			},
+		},
+		{
+			name:   "dry run - isbn taken",
+			rows:   &sliceRows{rows: []domain.ImportRow{{Line: 2, Book: nineteen}}},
+			dryRun: true,
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().FindExistingISBNs(gomock.Any(), []string{"9780743273565"}).Return([]string{"9780743273565"}, nil)
+			},
+			want: []domain.ImportRowResult{{Line: 2, Status: domain.ImportSkipped, Err: domain.ErrDuplicateISBN}},
+		},
+		{
+			name:  "empty file",
+			rows:  &sliceRows{},
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {},
+			want:  []domain.ImportRowResult{},
+		},
+		{
+			name:    "read error",
+			rows:    &sliceRows{rows: []domain.ImportRow{{Line: 2, Book: farm}}, err: errDB},
+			setup:   func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {},
+			wantErr: errDB,
+		},
+		{
+			name: "write error",
+			rows: &sliceRows{rows: []domain.ImportRow{{Line: 2, Book: farm}}},
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(orwell, nil)
+				m.EXPECT().CreateBooks(gomock.Any(), gomock.Any(), domain.BatchBestEffort).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockBookRepository(ctrl)
+			mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+			tt.setup(mockRepo, mockAuthorRepo)
+
+			s := NewBookService(mockRepo, mockAuthorRepo, newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+			got, err := s.ImportBooks(context.Background(), tt.rows, tt.dryRun)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BookService.ImportBooks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.DryRun != tt.dryRun || len(got.Rows) != len(tt.want) {
+				t.Fatalf("BookService.ImportBooks() = %+v, want rows %+v", got, tt.want)
+			}
+			for i, row := range got.Rows {
+				want := tt.want[i]
+				if !errors.Is(row.Err, want.Err) {
+					t.Errorf("BookService.ImportBooks() row %d error = %v, want %v", i, row.Err, want.Err)
+				}
+				row.Err, want.Err = nil, nil
+				if !reflect.DeepEqual(row, want) {
+					t.Errorf("BookService.ImportBooks() row %d = %+v, want %+v", i, row, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBookService_ImportBooks_Chunks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockBookRepository(ctrl)
+	mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+
+	rows := &sliceRows{}
+	for i := range importChunkSize + 1 {
+		rows.rows = append(rows.rows, domain.ImportRow{Line: i + 2, Book: domain.Book{Title: "Book", Author: "George Orwell"}})
+	}
+	mockAuthorRepo.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(domain.Author{ID: 1, Name: "George Orwell"}, nil).Times(2)
+	for _, n := range []int{importChunkSize, 1} {
+		mockRepo.EXPECT().CreateBooks(gomock.Any(), gomock.Len(n), domain.BatchBestEffort).
+			Return(make([]domain.BatchResult, n), nil)
+	}
+
+	s := NewBookService(mockRepo, mockAuthorRepo, newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+	got, err := s.ImportBooks(context.Background(), rows, false)
+	if err != nil {
+		t.Fatalf("BookService.ImportBooks() error = %v", err)
+	}
+	if got.Inserted != importChunkSize+1 {
+		t.Errorf("BookService.ImportBooks() inserted = %d, want %d", got.Inserted, importChunkSize+1)
+	}
+}
+
+func TestBookService_ImportBooks_Interrupted(t *testing.T) {
+	errDB := errors.New("db error")
+	tests := []struct {
+		name     string
+		dryRun   bool
+		wantLine int
+	}{
+		{name: "after a written batch", wantLine: importChunkSize + 1},
+		{name: "dry run writes nothing", dryRun: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockBookRepository(ctrl)
+			mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+
+			rows := &sliceRows{err: errDB}
+			for i := range importChunkSize {
+				rows.rows = append(rows.rows, domain.ImportRow{Line: i + 2, Book: domain.Book{Title: "Book", Author: "George Orwell"}})
+			}
+			if tt.dryRun {
+				mockRepo.EXPECT().FindExistingISBNs(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			} else {
+				mockAuthorRepo.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(domain.Author{ID: 1, Name: "George Orwell"}, nil)
+				mockRepo.EXPECT().CreateBooks(gomock.Any(), gomock.Len(importChunkSize), domain.BatchBestEffort).
+					Return(make([]domain.BatchResult, importChunkSize), nil)
+			}
+
+			s := NewBookService(mockRepo, mockAuthorRepo, newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+			_, err := s.ImportBooks(context.Background(), rows, tt.dryRun)
+			if !errors.Is(err, errDB) {
+				t.Fatalf("BookService.ImportBooks() error = %v, want %v", err, errDB)
+			}
+			var interrupted *domain.ImportInterruptedError
+			if errors.As(err, &interrupted) != (tt.wantLine != 0) {
+				t.Fatalf("BookService.ImportBooks() error = %v, want interrupted after line %d", err, tt.wantLine)
+			}
+			if interrupted != nil && interrupted.Line != tt.wantLine {
+				t.Errorf("BookService.ImportBooks() interrupted after line %d, want %d", interrupted.Line, tt.wantLine)
+			}
+		})
+	}
+}
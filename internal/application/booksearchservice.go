@@ -0,0 +1,70 @@
+package application
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+	"strings"
+)
+
+// maxSuggestions caps the suggestions returned for a prefix.
+const maxSuggestions = 20
+
+type BookSearchService struct {
+	searcher       out.BookSearcher
+	fuzzyThreshold float64
+}
+
+var _ in.BookSearchUseCase = &BookSearchService{}
+
+// NewBookSearchService returns a search service whose fuzzy searches match
+// at a trigram similarity above fuzzyThreshold.
+func NewBookSearchService(searcher out.BookSearcher, fuzzyThreshold float64) *BookSearchService {
+	return &BookSearchService{searcher: searcher, fuzzyThreshold: fuzzyThreshold}
+}
+
+func (s *BookSearchService) SearchBooks(ctx context.Context, q string, page, perPage int) ([]domain.BookHit, int, error) {
+	query, err := domain.ParseSearchQuery(q)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := paginate(page, perPage)
+	hits, err := s.searcher.SearchBooks(ctx, query, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.searcher.CountSearchResults(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	return hits, total, nil
+}
+
+func (s *BookSearchService) FuzzySearchBooks(ctx context.Context, q string, page, perPage int) ([]domain.BookHit, int, error) {
+	text, err := domain.NormalizeFuzzyQuery(q)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset, limit := paginate(page, perPage)
+	hits, err := s.searcher.FuzzySearchBooks(ctx, text, s.fuzzyThreshold, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.searcher.CountFuzzySearchResults(ctx, text, s.fuzzyThreshold)
+	if err != nil {
+		return nil, 0, err
+	}
+	return hits, total, nil
+}
+
+func (s *BookSearchService) SuggestBooks(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return []domain.Suggestion{}, nil
+	}
+	if limit <= 0 || limit > maxSuggestions {
+		limit = maxSuggestions
+	}
+	return s.searcher.SuggestBooks(ctx, prefix, limit)
+}
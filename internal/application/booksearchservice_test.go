@@ -0,0 +1,207 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestBookSearchService_SearchBooks(t *testing.T) {
+	farm := domain.SearchQuery{{Words: []string{"farm"}}}
+	hits := []domain.BookHit{{Book: domain.Book{ID: 1, Title: "Animal Farm"}, Rank: 0.5}}
+	errDB := errors.New("db error")
+
+	tests := []struct {
+		name      string
+		q         string
+		setup     func(*mocks.MockBookSearcher)
+		want      []domain.BookHit
+		wantTotal int
+		wantErr   error
+	}{
+		{
+			name: "success - second page",
+			q:    "Farm",
+			setup: func(m *mocks.MockBookSearcher) {
+				m.EXPECT().SearchBooks(gomock.Any(), farm, 10, 10).Return(hits, nil)
+				m.EXPECT().CountSearchResults(gomock.Any(), farm).Return(11, nil)
+			},
+			want:      hits,
+			wantTotal: 11,
+		},
+		{
+			name:    "no words",
+			q:       "***",
+			setup:   func(m *mocks.MockBookSearcher) {},
+			wantErr: domain.ErrSearchQueryRequired,
+		},
+		{
+			name: "search error",
+			q:    "farm",
+			setup: func(m *mocks.MockBookSearcher) {
+				m.EXPECT().SearchBooks(gomock.Any(), farm, 10, 10).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "count error",
+			q:    "farm",
+			setup: func(m *mocks.MockBookSearcher) {
+				m.EXPECT().SearchBooks(gomock.Any(), farm, 10, 10).Return(hits, nil)
+				m.EXPECT().CountSearchResults(gomock.Any(), farm).Return(0, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := mocks.NewMockBookSearcher(ctrl)
+			tt.setup(mockSearcher)
+
+			s := NewBookSearchService(mockSearcher, 0.5)
+			got, total, err := s.SearchBooks(context.Background(), tt.q, 2, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BookSearchService.SearchBooks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookSearchService.SearchBooks() = %v, want %v", got, tt.want)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("BookSearchService.SearchBooks() total = %v, want %v", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestBookSearchService_FuzzySearchBooks(t *testing.T) {
+	hits := []domain.BookHit{{Book: domain.Book{ID: 1, Title: "The Great Gatsby"}, Rank: 0.6}}
+	errDB := errors.New("db error")
+
+	tests := []struct {
+		name      string
+		q         string
+		setup     func(*mocks.MockBookSearcher)
+		want      []domain.BookHit
+		wantTotal int
+		wantErr   error
+	}{
+		{
+			name: "success - whitespace collapsed",
+			q:    "  great   gatsbi ",
+			setup: func(m *mocks.MockBookSearcher) {
+				m.EXPECT().FuzzySearchBooks(gomock.Any(), "great gatsbi", 0.5, 10, 10).Return(hits, nil)
+				m.EXPECT().CountFuzzySearchResults(gomock.Any(), "great gatsbi", 0.5).Return(11, nil)
+			},
+			want:      hits,
+			wantTotal: 11,
+		},
+		{
+			name:    "blank query",
+			q:       "   ",
+			setup:   func(m *mocks.MockBookSearcher) {},
+			wantErr: domain.ErrSearchQueryRequired,
+		},
+		{
+			name: "search error",
+			q:    "gatsbi",
+			setup: func(m *mocks.MockBookSearcher) {
+				m.EXPECT().FuzzySearchBooks(gomock.Any(), "gatsbi", 0.5, 10, 10).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "count error",
+			q:    "gatsbi",
+			setup: func(m *mocks.MockBookSearcher) {
+				m.EXPECT().FuzzySearchBooks(gomock.Any(), "gatsbi", 0.5, 10, 10).Return(hits, nil)
+				m.EXPECT().CountFuzzySearchResults(gomock.Any(), "gatsbi", 0.5).Return(0, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := mocks.NewMockBookSearcher(ctrl)
+			tt.setup(mockSearcher)
+
+			s := NewBookSearchService(mockSearcher, 0.5)
+			got, total, err := s.FuzzySearchBooks(context.Background(), tt.q, 2, 10)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BookSearchService.FuzzySearchBooks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookSearchService.FuzzySearchBooks() = %v, want %v", got, tt.want)
+			}
+			if total != tt.wantTotal {
+				t.Errorf("BookSearchService.FuzzySearchBooks() total = %v, want %v", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestBookSearchService_SuggestBooks(t *testing.T) {
+	suggestions := []domain.Suggestion{{Text: "Animal Farm", Field: "title"}}
+
+	tests := []struct {
+		name   string
+		prefix string
+		limit  int
+		setup  func(*mocks.MockBookSearcher)
+		want   []domain.Suggestion
+	}{
+		{
+			name:   "success",
+			prefix: " ani ",
+			limit:  5,
+			setup: func(m *mocks.MockBookSearcher) {
+				m.EXPECT().SuggestBooks(gomock.Any(), "ani", 5).Return(suggestions, nil)
+			},
+			want: suggestions,
+		},
+		{
+			name:   "limit capped",
+			prefix: "ani",
+			limit:  100,
+			setup: func(m *mocks.MockBookSearcher) {
+				m.EXPECT().SuggestBooks(gomock.Any(), "ani", maxSuggestions).Return(suggestions, nil)
+			},
+			want: suggestions,
+		},
+		{
+			name:   "blank prefix",
+			prefix: "  ",
+			limit:  5,
+			setup:  func(m *mocks.MockBookSearcher) {},
+			want:   []domain.Suggestion{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSearcher := mocks.NewMockBookSearcher(ctrl)
+			tt.setup(mockSearcher)
+
+			s := NewBookSearchService(mockSearcher, 0.5)
+			got, err := s.SuggestBooks(context.Background(), tt.prefix, tt.limit)
+			if err != nil {
+				t.Fatalf("BookSearchService.SuggestBooks() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookSearchService.SuggestBooks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
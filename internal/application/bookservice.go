@@ -2,53 +2,412 @@ package application
 
 import (
 	"context"
+	"errors"
 	"go-api-boilerplate/internal/application/port/in"
 	"go-api-boilerplate/internal/application/port/out"
 	"go-api-boilerplate/internal/domain"
+	"slices"
+	"time"
 )
 
+// BookService records every create, update, delete and restore in the book's
+// history, in the transaction of the write, and publishes it as a domain event
+// once the transaction has committed.
 type BookService struct {
-	bookRepo out.BookRepository
+	bookRepo   out.BookRepository
+	authorRepo out.AuthorRepository
+	bookWriter
 }
 
 var _ in.BookUseCase = &BookService{}
 
-func NewBookService(bookRepo out.BookRepository) *BookService {
-	return &BookService{bookRepo: bookRepo}
+func NewBookService(bookRepo out.BookRepository, authorRepo out.AuthorRepository, historyRepo out.BookHistoryRepository, outbox out.OutboxRepository, txManager out.TxManager, publisher out.EventPublisher) *BookService {
+	return &BookService{
+		bookRepo:   bookRepo,
+		authorRepo: authorRepo,
+		bookWriter: bookWriter{historyRepo: historyRepo, outbox: outbox, txManager: txManager, publisher: publisher},
+	}
 }
 
-func (s *BookService) CreateBook(ctx context.Context, book domain.Book) error {
+// CreateBook creates the book's author, when it is new, in the same
+// transaction as the book, so a book that fails to insert leaves no author
+// behind.
+func (s *BookService) CreateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
 	if err := book.Validate(); err != nil {
-		return err
+		return domain.Book{}, err
+	}
+	var created domain.Book
+	err := s.write(ctx, func(ctx context.Context) ([]domain.BookChange, error) {
+		if err := s.resolveAuthors(ctx, &book); err != nil {
+			return nil, err
+		}
+		var err error
+		created, err = s.bookRepo.CreateBook(ctx, book)
+		if err != nil {
+			return nil, err
+		}
+		return []domain.BookChange{domain.NewBookChange(ctx, domain.BookActionCreate, nil, created)}, nil
+	})
+	if err != nil {
+		return domain.Book{}, err
+	}
+	return created, nil
+}
+
+// errBatchFailed rolls back the transaction of an all-or-nothing batch in
+// which some book failed, along with the authors created for it.
+var errBatchFailed = errors.New("batch failed")
+
+// CreateBooks validates every book before writing any, so an all-or-nothing
+// batch with an invalid book never reaches the repository. An author named by
+// several books of the batch is looked up once. New authors are created in
+// the same transaction as the books, so a failed all-or-nothing batch leaves
+// none behind.
+func (s *BookService) CreateBooks(ctx context.Context, books []domain.Book, mode domain.BatchMode) ([]domain.BatchResult, error) {
+	results := make([]domain.BatchResult, len(books))
+	for i := range books {
+		results[i].Err = books[i].Validate()
+	}
+	if mode == domain.BatchAllOrNothing && domain.AnyFailed(results) {
+		return results, nil
+	}
+
+	err := s.write(ctx, func(ctx context.Context) ([]domain.BookChange, error) {
+		return s.createBatch(ctx, books, mode, results)
+	})
+	if errors.Is(err, errBatchFailed) {
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// createBatch resolves the authors of the valid books and creates them,
+// recording the outcome of each in results. It returns the creation of each
+// book that was created, or errBatchFailed when an all-or-nothing batch
+// fails.
+func (s *BookService) createBatch(ctx context.Context, books []domain.Book, mode domain.BatchMode, results []domain.BatchResult) ([]domain.BookChange, error) {
+	byName := make(map[string]domain.Author)
+	var pending []int
+	for i := range books {
+		if results[i].Err != nil {
+			continue
+		}
+		book := &books[i]
+		named := len(book.Authors) == 0
+		if author, ok := byName[book.Author]; ok && named {
+			book.SetAuthors([]domain.Author{author})
+		} else if err := s.resolveAuthors(ctx, book); err != nil {
+			if !errors.Is(err, domain.ErrUnknownAuthor) {
+				return nil, err
+			}
+			results[i].Err = err
+			continue
+		} else if named {
+			byName[book.Author] = book.Authors[0]
+		}
+		pending = append(pending, i)
+	}
+	if mode == domain.BatchAllOrNothing && domain.AnyFailed(results) {
+		return nil, errBatchFailed
+	}
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	batch := make([]domain.Book, len(pending))
+	for j, i := range pending {
+		batch[j] = books[i]
+	}
+	created, err := s.bookRepo.CreateBooks(ctx, batch, mode)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range pending {
+		results[i] = created[j]
+	}
+	if mode == domain.BatchAllOrNothing && domain.AnyFailed(results) {
+		return nil, errBatchFailed
+	}
+	var changes []domain.BookChange
+	for _, result := range created {
+		if result.Err == nil && result.Book.ID != 0 {
+			changes = append(changes, domain.NewBookChange(ctx, domain.BookActionCreate, nil, result.Book))
+		}
 	}
-	return s.bookRepo.CreateBook(ctx, book)
+	return changes, nil
 }
 
 func (s *BookService) GetBook(ctx context.Context, id int) (domain.Book, error) {
 	return s.bookRepo.GetBook(ctx, id)
 }
 
-func (s *BookService) GetBooks(ctx context.Context, page, perPage int) ([]domain.Book, error) {
-	if page < 1 {
-		page = 1
+func (s *BookService) GetBookByISBN(ctx context.Context, isbn string) (domain.Book, error) {
+	normalized, err := domain.NormalizeISBN(isbn)
+	if err != nil {
+		return domain.Book{}, err
 	}
-	if perPage <= 0 {
-		perPage = 10
+	return s.bookRepo.GetBookByISBN(ctx, normalized)
+}
+
+func (s *BookService) GetBooks(ctx context.Context, criteria domain.BookCriteria, page, perPage int) ([]domain.Book, int, error) {
+	offset, limit := paginate(page, perPage)
+	books, err := s.bookRepo.GetBooks(ctx, criteria, offset, limit)
+	if err != nil {
+		return nil, 0, err
 	}
+	total, err := s.bookRepo.CountBooks(ctx, criteria)
+	if err != nil {
+		return nil, 0, err
+	}
+	return books, total, nil
+}
 
-	offset := (page - 1) * perPage
-	limit := perPage
+func (s *BookService) ExportBooks(ctx context.Context, criteria domain.BookCriteria, fn func([]domain.Book) error) error {
+	return s.bookRepo.ExportBooks(ctx, criteria, fn)
+}
 
-	return s.bookRepo.GetBooks(ctx, offset, limit)
+// UpdateBook replaces the book's fields. A non-zero book.Version must match the
+// stored version, otherwise domain.ErrVersionConflict is returned.
+func (s *BookService) UpdateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
+	return s.replaceBook(ctx, book, domain.BookActionUpdate)
 }
 
-func (s *BookService) UpdateBook(ctx context.Context, book domain.Book) error {
+// replaceBook validates and writes the book, recording the write as action.
+func (s *BookService) replaceBook(ctx context.Context, book domain.Book, action domain.BookAction) (domain.Book, error) {
 	if err := book.Validate(); err != nil {
+		return domain.Book{}, err
+	}
+	var updated domain.Book
+	err := s.write(ctx, func(ctx context.Context) ([]domain.BookChange, error) {
+		before, err := s.lockLiveBook(ctx, book.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.resolveAuthors(ctx, &book); err != nil {
+			return nil, err
+		}
+		updated, err = s.bookRepo.UpdateBook(ctx, book)
+		if err != nil {
+			return nil, err
+		}
+		return []domain.BookChange{domain.NewBookChange(ctx, action, &before, updated)}, nil
+	})
+	if err != nil {
+		return domain.Book{}, err
+	}
+	return updated, nil
+}
+
+// PatchBook applies a partial update to the book and persists only the fields
+// that actually change. A non-zero version must match the stored version,
+// otherwise domain.ErrVersionConflict is returned.
+func (s *BookService) PatchBook(ctx context.Context, id, version int, patch domain.BookPatch) (domain.Book, error) {
+	var patched domain.Book
+	err := s.write(ctx, func(ctx context.Context) ([]domain.BookChange, error) {
+		var err error
+		var changes []domain.BookChange
+		patched, changes, err = s.patchBook(ctx, id, version, patch)
+		return changes, err
+	})
+	if err != nil {
+		return domain.Book{}, err
+	}
+	return patched, nil
+}
+
+func (s *BookService) patchBook(ctx context.Context, id, version int, patch domain.BookPatch) (domain.Book, []domain.BookChange, error) {
+	current, err := s.lockLiveBook(ctx, id)
+	if err != nil {
+		return domain.Book{}, nil, err
+	}
+	if version != 0 && version != current.Version {
+		return domain.Book{}, nil, domain.ErrVersionConflict
+	}
+
+	patched := patch.Apply(current)
+	if err := patched.Validate(); err != nil {
+		return domain.Book{}, nil, err
+	}
+
+	var changes domain.BookPatch
+	if patched.Title != current.Title {
+		changes.Title = &patched.Title
+	}
+	if patched.ISBN != current.ISBN {
+		changes.ISBN = &patched.ISBN
+	}
+	if patch.Author != nil || patch.Authors != nil {
+		if err := s.resolveAuthors(ctx, &patched); err != nil {
+			return domain.Book{}, nil, err
+		}
+		if patched.Author != current.Author || !slices.Equal(patched.AuthorIDs(), current.AuthorIDs()) {
+			changes.Author = &patched.Author
+			changes.Authors = patched.Authors
+		}
+	}
+	if changes.IsEmpty() {
+		return current, nil, nil
+	}
+
+	updated, err := s.bookRepo.PatchBook(ctx, id, version, changes)
+	if err != nil {
+		return domain.Book{}, nil, err
+	}
+	return updated, []domain.BookChange{domain.NewBookChange(ctx, domain.BookActionUpdate, &current, updated)}, nil
+}
+
+// DeleteBook moves the book to the trash. A non-zero version must match the
+// stored version, otherwise domain.ErrVersionConflict is returned.
+func (s *BookService) DeleteBook(ctx context.Context, id, version int) error {
+	return s.write(ctx, func(ctx context.Context) ([]domain.BookChange, error) {
+		before, err := s.lockLiveBook(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.bookRepo.DeleteBook(ctx, id, version); err != nil {
+			return nil, err
+		}
+		deleted, err := s.bookRepo.LockBook(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return []domain.BookChange{domain.NewBookChange(ctx, domain.BookActionDelete, &before, deleted)}, nil
+	})
+}
+
+func (s *BookService) GetTrash(ctx context.Context, page, perPage int) ([]domain.Book, int, error) {
+	offset, limit := paginate(page, perPage)
+	books, err := s.bookRepo.GetDeletedBooks(ctx, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.bookRepo.CountDeletedBooks(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return books, total, nil
+}
+
+func (s *BookService) RestoreBook(ctx context.Context, id int) (domain.Book, error) {
+	var restored domain.Book
+	err := s.write(ctx, func(ctx context.Context) ([]domain.BookChange, error) {
+		before, err := s.bookRepo.LockBook(ctx, id)
+		if errors.Is(err, domain.ErrBookNotFound) || (err == nil && before.DeletedAt == nil) {
+			return nil, domain.ErrBookNotInTrash
+		}
+		if err != nil {
+			return nil, err
+		}
+		restored, err = s.bookRepo.RestoreBook(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return []domain.BookChange{domain.NewBookChange(ctx, domain.BookActionRestore, &before, restored)}, nil
+	})
+	if err != nil {
+		return domain.Book{}, err
+	}
+	return restored, nil
+}
+
+// PurgeBook permanently deletes a book from the trash. Unlike the other
+// writes it does not go through write: the book's history ends with the
+// delete that trashed it, and purging adds no history row, outbox message or
+// event.
+func (s *BookService) PurgeBook(ctx context.Context, id int) error {
+	return s.bookRepo.PurgeBook(ctx, id)
+}
+
+// PurgeExpiredBooks permanently deletes books that have been in the trash for
+// longer than retention. As in PurgeBook, nothing is recorded in their
+// history or the outbox and no events are published.
+func (s *BookService) PurgeExpiredBooks(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.bookRepo.PurgeDeletedBooks(ctx, time.Now().Add(-retention))
+}
+
+// resolveAuthors loads the authors referenced by ID, or, when the book only
+// carries a byline, links it to the author with that name.
+func (s *BookService) resolveAuthors(ctx context.Context, book *domain.Book) error {
+	if len(book.Authors) > 0 {
+		authors, err := s.authorRepo.GetAuthorsByIDs(ctx, book.AuthorIDs())
+		if err != nil {
+			return err
+		}
+		book.SetAuthors(authors)
+		return nil
+	}
+
+	author, err := s.authorRepo.FindOrCreateAuthorByName(ctx, book.Author)
+	if err != nil {
 		return err
 	}
-	return s.bookRepo.UpdateBook(ctx, book)
+	book.SetAuthors([]domain.Author{author})
+	return nil
 }
 
-func (s *BookService) DeleteBook(ctx context.Context, id int) error {
-	return s.bookRepo.DeleteBook(ctx, id)
+// GetBooksByCursor returns up to limit books after the cursor, or before it
+// for a backward cursor, along with the cursors of the neighbouring pages.
+func (s *BookService) GetBooksByCursor(ctx context.Context, criteria domain.BookCriteria, cursor domain.Cursor, limit int) (domain.BookPage, error) {
+	if cursor.Sort == "" {
+		cursor.Sort = criteria.Sort.String()
+	}
+	if cursor.Sort != criteria.Sort.String() || (cursor.Backward && cursor.IsStart()) {
+		return domain.BookPage{}, domain.ErrInvalidCursor
+	}
+	_, limit = paginate(1, limit)
+
+	// Fetch one extra book to learn whether there is a page beyond this one.
+	books, err := s.bookRepo.GetBooksByCursor(ctx, criteria, cursor, limit+1)
+	if err != nil {
+		return domain.BookPage{}, err
+	}
+	more := len(books) > limit
+	if more {
+		if cursor.Backward {
+			books = books[1:]
+		} else {
+			books = books[:limit]
+		}
+	}
+
+	page := domain.BookPage{Books: books}
+	if len(books) == 0 {
+		return page, nil
+	}
+	first, last := books[0], books[len(books)-1]
+	if more || cursor.Backward {
+		next := domain.BookCursor(last, criteria.Sort, false)
+		page.Next = &next
+	}
+	if (more && cursor.Backward) || (!cursor.Backward && !cursor.IsStart()) {
+		prev := domain.BookCursor(first, criteria.Sort, true)
+		page.Prev = &prev
+	}
+	return page, nil
+}
+
+func paginate(page, perPage int) (offset, limit int) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = 10
+	}
+	return (page - 1) * perPage, perPage
+}
+
+// lockLiveBook locks the book for the rest of the transaction, returning
+// domain.ErrBookNotFound when it is in the trash.
+func (s *BookService) lockLiveBook(ctx context.Context, id int) (domain.Book, error) {
+	book, err := s.bookRepo.LockBook(ctx, id)
+	if err != nil {
+		return domain.Book{}, err
+	}
+	if book.DeletedAt != nil {
+		return domain.Book{}, domain.ErrBookNotFound
+	}
+	return book, nil
 }
@@ -7,11 +7,48 @@ import (
 	"go-api-boilerplate/mocks"
 	"reflect"
 	"testing"
+	"time"
 
 	"go.uber.org/mock/gomock"
 )
 
+// newTxManager returns a TxManager that runs every function it is given
+// straight away.
+func newTxManager(ctrl *gomock.Controller) *mocks.MockTxManager {
+	m := mocks.NewMockTxManager(ctrl)
+	m.EXPECT().WithinTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).
+		AnyTimes()
+	return m
+}
+
+// newHistoryRepo returns a BookHistoryRepository that accepts any change.
+func newHistoryRepo(ctrl *gomock.Controller) *mocks.MockBookHistoryRepository {
+	m := mocks.NewMockBookHistoryRepository(ctrl)
+	m.EXPECT().AddBookChanges(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return m
+}
+
+// newOutbox returns an OutboxRepository that accepts any message.
+func newOutbox(ctrl *gomock.Controller) *mocks.MockOutboxRepository {
+	m := mocks.NewMockOutboxRepository(ctrl)
+	m.EXPECT().AddOutboxMessages(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return m
+}
+
+// newPublisher returns an EventPublisher that accepts any event.
+func newPublisher(ctrl *gomock.Controller) *mocks.MockEventPublisher {
+	m := mocks.NewMockEventPublisher(ctrl)
+	m.EXPECT().Publish(gomock.Any(), gomock.Any()).AnyTimes()
+	return m
+}
+
 func TestBookService_CreateBook(t *testing.T) {
+	orwell := domain.Author{ID: 1, Name: "George Orwell"}
+	huxley := domain.Author{ID: 2, Name: "Aldous Huxley"}
+
 	type args struct {
 		ctx  context.Context
 		book domain.Book
@@ -19,27 +56,57 @@ func TestBookService_CreateBook(t *testing.T) {
 	tests := []struct {
 		name    string
 		args    args
-		setup   func(*mocks.MockBookRepository)
+		setup   func(*mocks.MockBookRepository, *mocks.MockAuthorRepository)
+		want    domain.Book
 		wantErr bool
 	}{
 		{
-			name: "success",
+			name: "success - author name",
 			args: args{
 				ctx:  context.Background(),
-				book: domain.Book{Title: "Test Book", Author: "Test Author"},
+				book: domain.Book{Title: "Test Book", Author: "George Orwell"},
 			},
-			setup: func(m *mocks.MockBookRepository) {
-				m.EXPECT().CreateBook(gomock.Any(), domain.Book{Title: "Test Book", Author: "Test Author"}).Return(nil)
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(orwell, nil)
+				m.EXPECT().CreateBook(gomock.Any(), domain.Book{Title: "Test Book", Author: "George Orwell", Authors: []domain.Author{orwell}}).
+					Return(domain.Book{ID: 1, Title: "Test Book", Author: "George Orwell", Authors: []domain.Author{orwell}}, nil)
+			},
+			want:    domain.Book{ID: 1, Title: "Test Book", Author: "George Orwell", Authors: []domain.Author{orwell}},
+			wantErr: false,
+		},
+		{
+			name: "success - author ids keep their order",
+			args: args{
+				ctx:  context.Background(),
+				book: domain.Book{Title: "Test Book", Authors: []domain.Author{{ID: 2}, {ID: 1}}},
 			},
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().GetAuthorsByIDs(gomock.Any(), []int{2, 1}).Return([]domain.Author{huxley, orwell}, nil)
+				m.EXPECT().CreateBook(gomock.Any(), domain.Book{Title: "Test Book", Author: "Aldous Huxley, George Orwell", Authors: []domain.Author{huxley, orwell}}).
+					Return(domain.Book{ID: 1, Title: "Test Book", Author: "Aldous Huxley, George Orwell", Authors: []domain.Author{huxley, orwell}}, nil)
+			},
+			want:    domain.Book{ID: 1, Title: "Test Book", Author: "Aldous Huxley, George Orwell", Authors: []domain.Author{huxley, orwell}},
 			wantErr: false,
 		},
+		{
+			name: "unknown author id",
+			args: args{
+				ctx:  context.Background(),
+				book: domain.Book{Title: "Test Book", Authors: []domain.Author{{ID: 99}}},
+			},
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().GetAuthorsByIDs(gomock.Any(), []int{99}).Return(nil, domain.ErrUnknownAuthor)
+			},
+			want:    domain.Book{},
+			wantErr: true,
+		},
 		{
 			name: "validation error - empty title",
 			args: args{
 				ctx:  context.Background(),
 				book: domain.Book{Title: "", Author: "Test Author"},
 			},
-			setup:   func(m *mocks.MockBookRepository) {},
+			setup:   func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {},
 			wantErr: true,
 		},
 		{
@@ -48,17 +115,32 @@ func TestBookService_CreateBook(t *testing.T) {
 				ctx:  context.Background(),
 				book: domain.Book{Title: "Test Book", Author: ""},
 			},
-			setup:   func(m *mocks.MockBookRepository) {},
+			setup:   func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {},
 			wantErr: true,
 		},
+		{
+			name: "success - isbn normalized before saving",
+			args: args{
+				ctx:  context.Background(),
+				book: domain.Book{Title: "Test Book", Author: "George Orwell", ISBN: "0-7432-7356-7"},
+			},
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(orwell, nil)
+				m.EXPECT().CreateBook(gomock.Any(), domain.Book{Title: "Test Book", Author: "George Orwell", Authors: []domain.Author{orwell}, ISBN: "9780743273565"}).
+					Return(domain.Book{ID: 1, Title: "Test Book", Author: "George Orwell", Authors: []domain.Author{orwell}, ISBN: "9780743273565"}, nil)
+			},
+			want:    domain.Book{ID: 1, Title: "Test Book", Author: "George Orwell", Authors: []domain.Author{orwell}, ISBN: "9780743273565"},
+			wantErr: false,
+		},
 		{
 			name: "repository error",
 			args: args{
 				ctx:  context.Background(),
-				book: domain.Book{Title: "Test Book", Author: "Test Author"},
+				book: domain.Book{Title: "Test Book", Author: "George Orwell"},
 			},
-			setup: func(m *mocks.MockBookRepository) {
-				m.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(orwell, nil)
+				m.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(domain.Book{}, errors.New("db error"))
 			},
 			wantErr: true,
 		},
@@ -69,11 +151,199 @@ func TestBookService_CreateBook(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockRepo := mocks.NewMockBookRepository(ctrl)
-			tt.setup(mockRepo)
+			mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+			tt.setup(mockRepo, mockAuthorRepo)
 
-			s := NewBookService(mockRepo)
-			if err := s.CreateBook(tt.args.ctx, tt.args.book); (err != nil) != tt.wantErr {
+			s := NewBookService(mockRepo, mockAuthorRepo, newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+			got, err := s.CreateBook(tt.args.ctx, tt.args.book)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("BookService.CreateBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookService.CreateBook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookService_CreateBook_WithinTx(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	type txKey struct{}
+	inTx := gomock.Cond(func(ctx context.Context) bool { return ctx.Value(txKey{}) != nil })
+	errTx := errors.New("commit failed")
+
+	mockTx := mocks.NewMockTxManager(ctrl)
+	mockTx.EXPECT().WithinTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			if err := fn(context.WithValue(ctx, txKey{}, true)); err != nil {
+				return err
+			}
+			return errTx
+		})
+	mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+	mockAuthorRepo.EXPECT().FindOrCreateAuthorByName(inTx, "George Orwell").Return(domain.Author{ID: 1, Name: "George Orwell"}, nil)
+	mockRepo := mocks.NewMockBookRepository(ctrl)
+	mockRepo.EXPECT().CreateBook(inTx, gomock.Any()).Return(domain.Book{ID: 1}, nil)
+
+	// Nothing is published for a write that did not commit.
+	s := NewBookService(mockRepo, mockAuthorRepo, newHistoryRepo(ctrl), newOutbox(ctrl), mockTx, mocks.NewMockEventPublisher(ctrl))
+	got, err := s.CreateBook(context.Background(), domain.Book{Title: "1984", Author: "George Orwell"})
+	if !errors.Is(err, errTx) {
+		t.Errorf("BookService.CreateBook() error = %v, want %v", err, errTx)
+	}
+	if got.ID != 0 {
+		t.Errorf("BookService.CreateBook() = %+v, want no book", got)
+	}
+}
+
+func TestBookService_CreateBooks(t *testing.T) {
+	orwell := domain.Author{ID: 1, Name: "George Orwell"}
+	farm := domain.Book{Title: "Animal Farm", Author: "George Orwell", Authors: []domain.Author{orwell}}
+	nineteen := domain.Book{Title: "1984", Author: "George Orwell", Authors: []domain.Author{orwell}}
+	created := func(id int, b domain.Book) domain.BatchResult {
+		b.ID = id
+		return domain.BatchResult{Book: b}
+	}
+	errDB := errors.New("db error")
+
+	tests := []struct {
+		name  string
+		books []domain.Book
+		mode  domain.BatchMode
+		setup func(*mocks.MockBookRepository, *mocks.MockAuthorRepository)
+		want  []domain.BatchResult
+		// wantRollback is whether the transaction is rolled back, with any
+		// author created for the batch.
+		wantRollback bool
+		wantErr      error
+	}{
+		{
+			name:  "success - author looked up once",
+			books: []domain.Book{{Title: "Animal Farm", Author: "George Orwell"}, {Title: "1984", Author: "George Orwell"}},
+			mode:  domain.BatchAllOrNothing,
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(orwell, nil)
+				m.EXPECT().CreateBooks(gomock.Any(), []domain.Book{farm, nineteen}, domain.BatchAllOrNothing).
+					Return([]domain.BatchResult{created(1, farm), created(2, nineteen)}, nil)
+			},
+			want: []domain.BatchResult{created(1, farm), created(2, nineteen)},
+		},
+		{
+			name:  "all or nothing - invalid book writes nothing",
+			books: []domain.Book{{Title: "Animal Farm", Author: "George Orwell"}, {Author: "George Orwell"}},
+			mode:  domain.BatchAllOrNothing,
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {},
+			want:  []domain.BatchResult{{}, {Err: domain.ErrTitleRequired}},
+		},
+		{
+			name:  "all or nothing - unknown author writes nothing",
+			books: []domain.Book{{Title: "Animal Farm", Authors: []domain.Author{{ID: 1}}}, {Title: "1984", Authors: []domain.Author{{ID: 99}}}},
+			mode:  domain.BatchAllOrNothing,
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().GetAuthorsByIDs(gomock.Any(), []int{1}).Return([]domain.Author{orwell}, nil)
+				a.EXPECT().GetAuthorsByIDs(gomock.Any(), []int{99}).Return(nil, domain.ErrUnknownAuthor)
+			},
+			want:         []domain.BatchResult{{}, {Err: domain.ErrUnknownAuthor}},
+			wantRollback: true,
+		},
+		{
+			name:  "all or nothing - duplicate isbn drops new authors",
+			books: []domain.Book{{Title: "Animal Farm", Author: "George Orwell"}, {Title: "1984", Author: "George Orwell"}},
+			mode:  domain.BatchAllOrNothing,
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(orwell, nil)
+				m.EXPECT().CreateBooks(gomock.Any(), []domain.Book{farm, nineteen}, domain.BatchAllOrNothing).
+					Return([]domain.BatchResult{{}, {Err: domain.ErrDuplicateISBN}}, nil)
+			},
+			want:         []domain.BatchResult{{}, {Err: domain.ErrDuplicateISBN}},
+			wantRollback: true,
+		},
+		{
+			name:  "best effort - valid books created",
+			books: []domain.Book{{Title: "", Author: "George Orwell"}, {Title: "1984", Author: "George Orwell", ISBN: "bad"}, {Title: "Animal Farm", Author: "George Orwell"}},
+			mode:  domain.BatchBestEffort,
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(orwell, nil)
+				m.EXPECT().CreateBooks(gomock.Any(), []domain.Book{farm}, domain.BatchBestEffort).
+					Return([]domain.BatchResult{created(3, farm)}, nil)
+			},
+			want: []domain.BatchResult{{Err: domain.ErrTitleRequired}, {Err: domain.ErrInvalidISBN}, created(3, farm)},
+		},
+		{
+			name:  "best effort - repository reports duplicate isbn",
+			books: []domain.Book{{Title: "Animal Farm", Author: "George Orwell"}},
+			mode:  domain.BatchBestEffort,
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(orwell, nil)
+				m.EXPECT().CreateBooks(gomock.Any(), []domain.Book{farm}, domain.BatchBestEffort).
+					Return([]domain.BatchResult{{Err: domain.ErrDuplicateISBN}}, nil)
+			},
+			want: []domain.BatchResult{{Err: domain.ErrDuplicateISBN}},
+		},
+		{
+			name:  "best effort - nothing valid",
+			books: []domain.Book{{Author: "George Orwell"}},
+			mode:  domain.BatchBestEffort,
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {},
+			want:  []domain.BatchResult{{Err: domain.ErrTitleRequired}},
+		},
+		{
+			name:  "author lookup error",
+			books: []domain.Book{{Title: "Animal Farm", Author: "George Orwell"}},
+			mode:  domain.BatchBestEffort,
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(domain.Author{}, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:  "repository error",
+			books: []domain.Book{{Title: "Animal Farm", Author: "George Orwell"}},
+			mode:  domain.BatchAllOrNothing,
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				a.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(orwell, nil)
+				m.EXPECT().CreateBooks(gomock.Any(), gomock.Any(), domain.BatchAllOrNothing).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockBookRepository(ctrl)
+			mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+			tt.setup(mockRepo, mockAuthorRepo)
+
+			rolledBack := false
+			mockTx := mocks.NewMockTxManager(ctrl)
+			mockTx.EXPECT().WithinTx(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+					err := fn(ctx)
+					rolledBack = err != nil
+					return err
+				}).
+				AnyTimes()
+
+			s := NewBookService(mockRepo, mockAuthorRepo, newHistoryRepo(ctrl), newOutbox(ctrl), mockTx, newPublisher(ctrl))
+			got, err := s.CreateBooks(context.Background(), tt.books, tt.mode)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BookService.CreateBooks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && rolledBack != tt.wantRollback {
+				t.Errorf("BookService.CreateBooks() rolled back = %v, want %v", rolledBack, tt.wantRollback)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("BookService.CreateBooks() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !errors.Is(got[i].Err, tt.want[i].Err) || !reflect.DeepEqual(got[i].Book, tt.want[i].Book) {
+					t.Errorf("BookService.CreateBooks()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
 			}
 		})
 	}
@@ -124,7 +394,7 @@ func TestBookService_GetBook(t *testing.T) {
 			mockRepo := mocks.NewMockBookRepository(ctrl)
 			tt.setup(mockRepo)
 
-			s := NewBookService(mockRepo)
+			s := NewBookService(mockRepo, mocks.NewMockAuthorRepository(ctrl), newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
 			got, err := s.GetBook(tt.args.ctx, tt.args.id)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("BookService.GetBook() error = %v, wantErr %v", err, tt.wantErr)
@@ -137,18 +407,89 @@ func TestBookService_GetBook(t *testing.T) {
 	}
 }
 
-func TestBookService_GetBooks(t *testing.T) {
+func TestBookService_GetBookByISBN(t *testing.T) {
 	type args struct {
-		ctx     context.Context
-		page    int
-		perPage int
+		ctx  context.Context
+		isbn string
 	}
 	tests := []struct {
 		name    string
 		args    args
 		setup   func(*mocks.MockBookRepository)
-		want    []domain.Book
+		want    domain.Book
 		wantErr bool
+	}{
+		{
+			name: "success - normalizes isbn-10",
+			args: args{
+				ctx:  context.Background(),
+				isbn: "0-7432-7356-7",
+			},
+			setup: func(m *mocks.MockBookRepository) {
+				m.EXPECT().GetBookByISBN(gomock.Any(), "9780743273565").
+					Return(domain.Book{ID: 1, Title: "Test Book", Author: "Test Author", ISBN: "9780743273565"}, nil)
+			},
+			want:    domain.Book{ID: 1, Title: "Test Book", Author: "Test Author", ISBN: "9780743273565"},
+			wantErr: false,
+		},
+		{
+			name: "invalid isbn",
+			args: args{
+				ctx:  context.Background(),
+				isbn: "123",
+			},
+			setup:   func(m *mocks.MockBookRepository) {},
+			want:    domain.Book{},
+			wantErr: true,
+		},
+		{
+			name: "not found",
+			args: args{
+				ctx:  context.Background(),
+				isbn: "9780743273565",
+			},
+			setup: func(m *mocks.MockBookRepository) {
+				m.EXPECT().GetBookByISBN(gomock.Any(), "9780743273565").Return(domain.Book{}, domain.ErrBookNotFound)
+			},
+			want:    domain.Book{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockBookRepository(ctrl)
+			tt.setup(mockRepo)
+
+			s := NewBookService(mockRepo, mocks.NewMockAuthorRepository(ctrl), newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+			got, err := s.GetBookByISBN(tt.args.ctx, tt.args.isbn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("BookService.GetBookByISBN() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookService.GetBookByISBN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookService_GetBooks(t *testing.T) {
+	type args struct {
+		ctx      context.Context
+		criteria domain.BookCriteria
+		page     int
+		perPage  int
+	}
+	tests := []struct {
+		name      string
+		args      args
+		setup     func(*mocks.MockBookRepository)
+		want      []domain.Book
+		wantTotal int
+		wantErr   bool
 	}{
 		{
 			name: "success - first page",
@@ -158,16 +499,18 @@ func TestBookService_GetBooks(t *testing.T) {
 				perPage: 10,
 			},
 			setup: func(m *mocks.MockBookRepository) {
-				m.EXPECT().GetBooks(gomock.Any(), 0, 10).Return([]domain.Book{
+				m.EXPECT().GetBooks(gomock.Any(), domain.BookCriteria{}, 0, 10).Return([]domain.Book{
 					{ID: 1, Title: "Book 1", Author: "Author 1"},
 					{ID: 2, Title: "Book 2", Author: "Author 2"},
 				}, nil)
+				m.EXPECT().CountBooks(gomock.Any(), domain.BookCriteria{}).Return(2, nil)
 			},
 			want: []domain.Book{
 				{ID: 1, Title: "Book 1", Author: "Author 1"},
 				{ID: 2, Title: "Book 2", Author: "Author 2"},
 			},
-			wantErr: false,
+			wantTotal: 2,
+			wantErr:   false,
 		},
 		{
 			name: "success - second page",
@@ -177,10 +520,12 @@ func TestBookService_GetBooks(t *testing.T) {
 				perPage: 10,
 			},
 			setup: func(m *mocks.MockBookRepository) {
-				m.EXPECT().GetBooks(gomock.Any(), 10, 10).Return([]domain.Book{}, nil)
+				m.EXPECT().GetBooks(gomock.Any(), domain.BookCriteria{}, 10, 10).Return([]domain.Book{}, nil)
+				m.EXPECT().CountBooks(gomock.Any(), domain.BookCriteria{}).Return(2, nil)
 			},
-			want:    []domain.Book{},
-			wantErr: false,
+			want:      []domain.Book{},
+			wantTotal: 2,
+			wantErr:   false,
 		},
 		{
 			name: "default pagination - invalid page",
@@ -190,10 +535,12 @@ func TestBookService_GetBooks(t *testing.T) {
 				perPage: 10,
 			},
 			setup: func(m *mocks.MockBookRepository) {
-				m.EXPECT().GetBooks(gomock.Any(), 0, 10).Return([]domain.Book{}, nil)
+				m.EXPECT().GetBooks(gomock.Any(), domain.BookCriteria{}, 0, 10).Return([]domain.Book{}, nil)
+				m.EXPECT().CountBooks(gomock.Any(), domain.BookCriteria{}).Return(2, nil)
 			},
-			want:    []domain.Book{},
-			wantErr: false,
+			want:      []domain.Book{},
+			wantTotal: 2,
+			wantErr:   false,
 		},
 		{
 			name: "default pagination - invalid perPage",
@@ -203,10 +550,29 @@ func TestBookService_GetBooks(t *testing.T) {
 				perPage: 0,
 			},
 			setup: func(m *mocks.MockBookRepository) {
-				m.EXPECT().GetBooks(gomock.Any(), 0, 10).Return([]domain.Book{}, nil)
+				m.EXPECT().GetBooks(gomock.Any(), domain.BookCriteria{}, 0, 10).Return([]domain.Book{}, nil)
+				m.EXPECT().CountBooks(gomock.Any(), domain.BookCriteria{}).Return(2, nil)
 			},
-			want:    []domain.Book{},
-			wantErr: false,
+			want:      []domain.Book{},
+			wantTotal: 2,
+			wantErr:   false,
+		},
+		{
+			name: "success - criteria reach the list and the count",
+			args: args{
+				ctx:      context.Background(),
+				criteria: domain.BookCriteria{Author: "George Orwell", Sort: domain.BookSort{{Field: "title"}}},
+				page:     1,
+				perPage:  10,
+			},
+			setup: func(m *mocks.MockBookRepository) {
+				criteria := domain.BookCriteria{Author: "George Orwell", Sort: domain.BookSort{{Field: "title"}}}
+				m.EXPECT().GetBooks(gomock.Any(), criteria, 0, 10).Return([]domain.Book{{ID: 2, Title: "1984"}}, nil)
+				m.EXPECT().CountBooks(gomock.Any(), criteria).Return(1, nil)
+			},
+			want:      []domain.Book{{ID: 2, Title: "1984"}},
+			wantTotal: 1,
+			wantErr:   false,
 		},
 		{
 			name: "repository error",
@@ -216,7 +582,21 @@ func TestBookService_GetBooks(t *testing.T) {
 				perPage: 10,
 			},
 			setup: func(m *mocks.MockBookRepository) {
-				m.EXPECT().GetBooks(gomock.Any(), 0, 10).Return(nil, errors.New("db error"))
+				m.EXPECT().GetBooks(gomock.Any(), domain.BookCriteria{}, 0, 10).Return(nil, errors.New("db error"))
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "count error",
+			args: args{
+				ctx:     context.Background(),
+				page:    1,
+				perPage: 10,
+			},
+			setup: func(m *mocks.MockBookRepository) {
+				m.EXPECT().GetBooks(gomock.Any(), domain.BookCriteria{}, 0, 10).Return([]domain.Book{}, nil)
+				m.EXPECT().CountBooks(gomock.Any(), domain.BookCriteria{}).Return(0, errors.New("db error"))
 			},
 			want:    nil,
 			wantErr: true,
@@ -230,8 +610,8 @@ func TestBookService_GetBooks(t *testing.T) {
 			mockRepo := mocks.NewMockBookRepository(ctrl)
 			tt.setup(mockRepo)
 
-			s := NewBookService(mockRepo)
-			got, err := s.GetBooks(tt.args.ctx, tt.args.page, tt.args.perPage)
+			s := NewBookService(mockRepo, mocks.NewMockAuthorRepository(ctrl), newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+			got, total, err := s.GetBooks(tt.args.ctx, tt.args.criteria, tt.args.page, tt.args.perPage)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("BookService.GetBooks() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -239,11 +619,167 @@ func TestBookService_GetBooks(t *testing.T) {
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("BookService.GetBooks() = %v, want %v", got, tt.want)
 			}
+			if total != tt.wantTotal {
+				t.Errorf("BookService.GetBooks() total = %v, want %v", total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestBookService_GetBooksByCursor(t *testing.T) {
+	books := func(ids ...int) []domain.Book {
+		out := make([]domain.Book, len(ids))
+		for i, id := range ids {
+			out[i] = domain.Book{ID: id}
+		}
+		return out
+	}
+	after := func(id int) *domain.Cursor {
+		c := domain.BookCursor(domain.Book{ID: id}, nil, false)
+		return &c
+	}
+	before := func(id int) *domain.Cursor {
+		c := domain.BookCursor(domain.Book{ID: id}, nil, true)
+		return &c
+	}
+
+	byTitle := domain.BookCriteria{Sort: domain.BookSort{{Field: "title", Desc: true}}}
+	titled := []domain.Book{{ID: 3, Title: "C"}, {ID: 1, Title: "B"}, {ID: 2, Title: "A"}}
+	titleCursor := func(b domain.Book, backward bool) *domain.Cursor {
+		c := domain.BookCursor(b, byTitle.Sort, backward)
+		return &c
+	}
+
+	tests := []struct {
+		name     string
+		criteria domain.BookCriteria
+		cursor   domain.Cursor
+		setup    func(*mocks.MockBookRepository)
+		want     domain.BookPage
+		wantErr  error
+	}{
+		{
+			name:   "first page with more",
+			cursor: domain.Cursor{},
+			setup: func(m *mocks.MockBookRepository) {
+				m.EXPECT().GetBooksByCursor(gomock.Any(), domain.BookCriteria{}, domain.Cursor{Sort: domain.SortByID}, 3).Return(books(1, 2, 3), nil)
+			},
+			want: domain.BookPage{Books: books(1, 2), Next: after(2)},
+		},
+		{
+			name:   "middle page",
+			cursor: *after(2),
+			setup: func(m *mocks.MockBookRepository) {
+				m.EXPECT().GetBooksByCursor(gomock.Any(), domain.BookCriteria{}, *after(2), 3).Return(books(3, 4, 5), nil)
+			},
+			want: domain.BookPage{Books: books(3, 4), Next: after(4), Prev: before(3)},
+		},
+		{
+			name:   "last page",
+			cursor: *after(4),
+			setup: func(m *mocks.MockBookRepository) {
+				m.EXPECT().GetBooksByCursor(gomock.Any(), domain.BookCriteria{}, *after(4), 3).Return(books(5), nil)
+			},
+			want: domain.BookPage{Books: books(5), Prev: before(5)},
+		},
+		{
+			name:   "backward page with more",
+			cursor: *before(5),
+			setup: func(m *mocks.MockBookRepository) {
+				m.EXPECT().GetBooksByCursor(gomock.Any(), domain.BookCriteria{}, *before(5), 3).Return(books(2, 3, 4), nil)
+			},
+			want: domain.BookPage{Books: books(3, 4), Next: after(4), Prev: before(3)},
+		},
+		{
+			name:   "backward to the first page",
+			cursor: *before(3),
+			setup: func(m *mocks.MockBookRepository) {
+				m.EXPECT().GetBooksByCursor(gomock.Any(), domain.BookCriteria{}, *before(3), 3).Return(books(1, 2), nil)
+			},
+			want: domain.BookPage{Books: books(1, 2), Next: after(2)},
+		},
+		{
+			name:     "sorted first page",
+			criteria: byTitle,
+			cursor:   domain.Cursor{},
+			setup: func(m *mocks.MockBookRepository) {
+				m.EXPECT().GetBooksByCursor(gomock.Any(), byTitle, domain.Cursor{Sort: "-title"}, 3).Return(titled, nil)
+			},
+			want: domain.BookPage{Books: titled[:2], Next: titleCursor(titled[1], false)},
+		},
+		{
+			name:     "sorted middle page",
+			criteria: byTitle,
+			cursor:   *titleCursor(titled[0], false),
+			setup: func(m *mocks.MockBookRepository) {
+				m.EXPECT().GetBooksByCursor(gomock.Any(), byTitle, *titleCursor(titled[0], false), 3).Return(titled[1:], nil)
+			},
+			want: domain.BookPage{Books: titled[1:], Prev: titleCursor(titled[1], true)},
+		},
+		{
+			name:     "cursor from another sort",
+			criteria: byTitle,
+			cursor:   *after(2),
+			setup:    func(m *mocks.MockBookRepository) {},
+			wantErr:  domain.ErrInvalidCursor,
+		},
+		{
+			name:    "backward without key",
+			cursor:  domain.Cursor{Backward: true},
+			setup:   func(m *mocks.MockBookRepository) {},
+			wantErr: domain.ErrInvalidCursor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockBookRepository(ctrl)
+			tt.setup(mockRepo)
+
+			s := NewBookService(mockRepo, mocks.NewMockAuthorRepository(ctrl), newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+			got, err := s.GetBooksByCursor(context.Background(), tt.criteria, tt.cursor, 2)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("BookService.GetBooksByCursor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookService.GetBooksByCursor() = %+v, want %+v", got, tt.want)
+			}
 		})
 	}
 }
 
+func TestBookService_ExportBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockBookRepository(ctrl)
+	criteria := domain.BookCriteria{Author: "Test Author"}
+	want := []domain.Book{{ID: 1, Title: "Test Book", Author: "Test Author"}}
+	mockRepo.EXPECT().ExportBooks(gomock.Any(), criteria, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ domain.BookCriteria, fn func([]domain.Book) error) error {
+			return fn(want)
+		})
+
+	s := NewBookService(mockRepo, mocks.NewMockAuthorRepository(ctrl), newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+	var got []domain.Book
+	err := s.ExportBooks(context.Background(), criteria, func(books []domain.Book) error {
+		got = append(got, books...)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("BookService.ExportBooks() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BookService.ExportBooks() = %v, want %v", got, want)
+	}
+}
+
 func TestBookService_UpdateBook(t *testing.T) {
+	orwell := domain.Author{ID: 1, Name: "George Orwell"}
+
 	type args struct {
 		ctx  context.Context
 		book domain.Book
@@ -251,27 +787,57 @@ func TestBookService_UpdateBook(t *testing.T) {
 	tests := []struct {
 		name    string
 		args    args
-		setup   func(*mocks.MockBookRepository)
+		setup   func(*mocks.MockBookRepository, *mocks.MockAuthorRepository)
+		want    domain.Book
 		wantErr bool
 	}{
 		{
 			name: "success",
 			args: args{
 				ctx:  context.Background(),
-				book: domain.Book{ID: 1, Title: "Updated Book", Author: "Updated Author"},
+				book: domain.Book{ID: 1, Title: "Updated Book", Authors: []domain.Author{{ID: 1}}},
 			},
-			setup: func(m *mocks.MockBookRepository) {
-				m.EXPECT().UpdateBook(gomock.Any(), domain.Book{ID: 1, Title: "Updated Book", Author: "Updated Author"}).Return(nil)
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(domain.Book{ID: 1, Title: "Old Book", Author: "George Orwell", Authors: []domain.Author{orwell}, Version: 1}, nil)
+				a.EXPECT().GetAuthorsByIDs(gomock.Any(), []int{1}).Return([]domain.Author{orwell}, nil)
+				m.EXPECT().UpdateBook(gomock.Any(), domain.Book{ID: 1, Title: "Updated Book", Author: "George Orwell", Authors: []domain.Author{orwell}}).
+					Return(domain.Book{ID: 1, Title: "Updated Book", Author: "George Orwell", Authors: []domain.Author{orwell}, Version: 2}, nil)
 			},
+			want:    domain.Book{ID: 1, Title: "Updated Book", Author: "George Orwell", Authors: []domain.Author{orwell}, Version: 2},
 			wantErr: false,
 		},
+		{
+			name: "version conflict",
+			args: args{
+				ctx:  context.Background(),
+				book: domain.Book{ID: 1, Title: "Updated Book", Authors: []domain.Author{{ID: 1}}, Version: 1},
+			},
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(domain.Book{ID: 1, Title: "Old Book", Author: "George Orwell", Authors: []domain.Author{orwell}, Version: 1}, nil)
+				a.EXPECT().GetAuthorsByIDs(gomock.Any(), []int{1}).Return([]domain.Author{orwell}, nil)
+				m.EXPECT().UpdateBook(gomock.Any(), domain.Book{ID: 1, Title: "Updated Book", Author: "George Orwell", Authors: []domain.Author{orwell}, Version: 1}).
+					Return(domain.Book{}, domain.ErrVersionConflict)
+			},
+			wantErr: true,
+		},
+		{
+			name: "book in the trash",
+			args: args{
+				ctx:  context.Background(),
+				book: domain.Book{ID: 1, Title: "Updated Book", Author: "George Orwell"},
+			},
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(domain.Book{ID: 1, DeletedAt: &time.Time{}}, nil)
+			},
+			wantErr: true,
+		},
 		{
 			name: "validation error - empty title",
 			args: args{
 				ctx:  context.Background(),
 				book: domain.Book{ID: 1, Title: "", Author: "Updated Author"},
 			},
-			setup:   func(m *mocks.MockBookRepository) {},
+			setup:   func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {},
 			wantErr: true,
 		},
 		{
@@ -280,17 +846,28 @@ func TestBookService_UpdateBook(t *testing.T) {
 				ctx:  context.Background(),
 				book: domain.Book{ID: 1, Title: "Updated Book", Author: ""},
 			},
-			setup:   func(m *mocks.MockBookRepository) {},
+			setup:   func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {},
+			wantErr: true,
+		},
+		{
+			name: "validation error - duplicate author",
+			args: args{
+				ctx:  context.Background(),
+				book: domain.Book{ID: 1, Title: "Updated Book", Authors: []domain.Author{{ID: 1}, {ID: 1}}},
+			},
+			setup:   func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {},
 			wantErr: true,
 		},
 		{
 			name: "repository error",
 			args: args{
 				ctx:  context.Background(),
-				book: domain.Book{ID: 1, Title: "Updated Book", Author: "Updated Author"},
+				book: domain.Book{ID: 1, Title: "Updated Book", Author: "George Orwell"},
 			},
-			setup: func(m *mocks.MockBookRepository) {
-				m.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(domain.Book{ID: 1, Title: "Old Book", Author: "George Orwell", Authors: []domain.Author{orwell}, Version: 1}, nil)
+				a.EXPECT().FindOrCreateAuthorByName(gomock.Any(), "George Orwell").Return(orwell, nil)
+				m.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).Return(domain.Book{}, errors.New("db error"))
 			},
 			wantErr: true,
 		},
@@ -301,11 +878,112 @@ func TestBookService_UpdateBook(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockRepo := mocks.NewMockBookRepository(ctrl)
-			tt.setup(mockRepo)
+			mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+			tt.setup(mockRepo, mockAuthorRepo)
 
-			s := NewBookService(mockRepo)
-			if err := s.UpdateBook(tt.args.ctx, tt.args.book); (err != nil) != tt.wantErr {
+			s := NewBookService(mockRepo, mockAuthorRepo, newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+			got, err := s.UpdateBook(tt.args.ctx, tt.args.book)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("BookService.UpdateBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookService.UpdateBook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookService_PatchBook(t *testing.T) {
+	orwell := domain.Author{ID: 1, Name: "George Orwell"}
+	huxley := domain.Author{ID: 2, Name: "Aldous Huxley"}
+	current := domain.Book{ID: 1, Title: "1984", Author: "George Orwell", Authors: []domain.Author{orwell}, Version: 3}
+	title := "Animal Farm"
+	sameTitle := "1984"
+	empty := ""
+
+	tests := []struct {
+		name    string
+		version int
+		patch   domain.BookPatch
+		setup   func(*mocks.MockBookRepository, *mocks.MockAuthorRepository)
+		want    domain.Book
+		wantErr error
+	}{
+		{
+			name:  "title changed",
+			patch: domain.BookPatch{Title: &title},
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(current, nil)
+				m.EXPECT().PatchBook(gomock.Any(), 1, 0, domain.BookPatch{Title: &title}).
+					Return(domain.Book{ID: 1, Title: "Animal Farm", Author: "George Orwell", Authors: []domain.Author{orwell}, Version: 4}, nil)
+			},
+			want: domain.Book{ID: 1, Title: "Animal Farm", Author: "George Orwell", Authors: []domain.Author{orwell}, Version: 4},
+		},
+		{
+			name:    "authors changed",
+			version: 3,
+			patch:   domain.BookPatch{Authors: []domain.Author{{ID: 2}}},
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(current, nil)
+				a.EXPECT().GetAuthorsByIDs(gomock.Any(), []int{2}).Return([]domain.Author{huxley}, nil)
+				byline := "Aldous Huxley"
+				m.EXPECT().PatchBook(gomock.Any(), 1, 3, domain.BookPatch{Author: &byline, Authors: []domain.Author{huxley}}).
+					Return(domain.Book{ID: 1, Title: "1984", Author: "Aldous Huxley", Authors: []domain.Author{huxley}, Version: 4}, nil)
+			},
+			want: domain.Book{ID: 1, Title: "1984", Author: "Aldous Huxley", Authors: []domain.Author{huxley}, Version: 4},
+		},
+		{
+			name:  "unchanged values are not written",
+			patch: domain.BookPatch{Title: &sameTitle},
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(current, nil)
+			},
+			want: current,
+		},
+		{
+			name:    "version conflict",
+			version: 2,
+			patch:   domain.BookPatch{Title: &title},
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(current, nil)
+			},
+			wantErr: domain.ErrVersionConflict,
+		},
+		{
+			name:  "validation error - title cleared",
+			patch: domain.BookPatch{Title: &empty},
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(current, nil)
+			},
+			wantErr: domain.ErrTitleRequired,
+		},
+		{
+			name:  "book not found",
+			patch: domain.BookPatch{Title: &title},
+			setup: func(m *mocks.MockBookRepository, a *mocks.MockAuthorRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(domain.Book{}, domain.ErrBookNotFound)
+			},
+			wantErr: domain.ErrBookNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockBookRepository(ctrl)
+			mockAuthorRepo := mocks.NewMockAuthorRepository(ctrl)
+			tt.setup(mockRepo, mockAuthorRepo)
+
+			s := NewBookService(mockRepo, mockAuthorRepo, newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+			got, err := s.PatchBook(context.Background(), 1, tt.version, tt.patch)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("BookService.PatchBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookService.PatchBook() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -313,8 +991,9 @@ func TestBookService_UpdateBook(t *testing.T) {
 
 func TestBookService_DeleteBook(t *testing.T) {
 	type args struct {
-		ctx context.Context
-		id  int
+		ctx     context.Context
+		id      int
+		version int
 	}
 	tests := []struct {
 		name    string
@@ -329,10 +1008,25 @@ func TestBookService_DeleteBook(t *testing.T) {
 				id:  1,
 			},
 			setup: func(m *mocks.MockBookRepository) {
-				m.EXPECT().DeleteBook(gomock.Any(), 1).Return(nil)
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(domain.Book{ID: 1, Version: 1}, nil)
+				m.EXPECT().DeleteBook(gomock.Any(), 1, 0).Return(nil)
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(domain.Book{ID: 1, Version: 2, DeletedAt: &time.Time{}}, nil)
 			},
 			wantErr: false,
 		},
+		{
+			name: "version conflict",
+			args: args{
+				ctx:     context.Background(),
+				id:      1,
+				version: 2,
+			},
+			setup: func(m *mocks.MockBookRepository) {
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(domain.Book{ID: 1, Version: 3}, nil)
+				m.EXPECT().DeleteBook(gomock.Any(), 1, 2).Return(domain.ErrVersionConflict)
+			},
+			wantErr: true,
+		},
 		{
 			name: "not found",
 			args: args{
@@ -340,7 +1034,7 @@ func TestBookService_DeleteBook(t *testing.T) {
 				id:  999,
 			},
 			setup: func(m *mocks.MockBookRepository) {
-				m.EXPECT().DeleteBook(gomock.Any(), 999).Return(domain.ErrBookNotFound)
+				m.EXPECT().LockBook(gomock.Any(), 999).Return(domain.Book{}, domain.ErrBookNotFound)
 			},
 			wantErr: true,
 		},
@@ -351,7 +1045,8 @@ func TestBookService_DeleteBook(t *testing.T) {
 				id:  1,
 			},
 			setup: func(m *mocks.MockBookRepository) {
-				m.EXPECT().DeleteBook(gomock.Any(), 1).Return(errors.New("db error"))
+				m.EXPECT().LockBook(gomock.Any(), 1).Return(domain.Book{ID: 1, Version: 1}, nil)
+				m.EXPECT().DeleteBook(gomock.Any(), 1, 0).Return(errors.New("db error"))
 			},
 			wantErr: true,
 		},
@@ -364,10 +1059,56 @@ func TestBookService_DeleteBook(t *testing.T) {
 			mockRepo := mocks.NewMockBookRepository(ctrl)
 			tt.setup(mockRepo)
 
-			s := NewBookService(mockRepo)
-			if err := s.DeleteBook(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+			s := NewBookService(mockRepo, mocks.NewMockAuthorRepository(ctrl), newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+			if err := s.DeleteBook(tt.args.ctx, tt.args.id, tt.args.version); (err != nil) != tt.wantErr {
 				t.Errorf("BookService.DeleteBook() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestBookService_GetTrash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockBookRepository(ctrl)
+	want := []domain.Book{{ID: 1, Title: "Test Book", Author: "Test Author"}}
+	mockRepo.EXPECT().GetDeletedBooks(gomock.Any(), 20, 10).Return(want, nil)
+	mockRepo.EXPECT().CountDeletedBooks(gomock.Any()).Return(21, nil)
+
+	s := NewBookService(mockRepo, mocks.NewMockAuthorRepository(ctrl), newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+	got, total, err := s.GetTrash(context.Background(), 3, 10)
+	if err != nil {
+		t.Fatalf("BookService.GetTrash() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BookService.GetTrash() = %v, want %v", got, want)
+	}
+	if total != 21 {
+		t.Errorf("BookService.GetTrash() total = %v, want %v", total, 21)
+	}
+}
+
+func TestBookService_PurgeExpiredBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockBookRepository(ctrl)
+	retention := 30 * 24 * time.Hour
+	mockRepo.EXPECT().PurgeDeletedBooks(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, before time.Time) (int64, error) {
+			if age := time.Since(before); age < retention || age > retention+time.Minute {
+				t.Errorf("PurgeDeletedBooks() before = %v, want about %v ago", before, retention)
+			}
+			return 2, nil
+		})
+
+	s := NewBookService(mockRepo, mocks.NewMockAuthorRepository(ctrl), newHistoryRepo(ctrl), newOutbox(ctrl), newTxManager(ctrl), newPublisher(ctrl))
+	got, err := s.PurgeExpiredBooks(context.Background(), retention)
+	if err != nil {
+		t.Fatalf("BookService.PurgeExpiredBooks() error = %v", err)
+	}
+	if got != 2 {
+		t.Errorf("BookService.PurgeExpiredBooks() = %v, want %v", got, 2)
+	}
+}
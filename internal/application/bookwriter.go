@@ -0,0 +1,51 @@
+package application
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+)
+
+// bookWriter is the path every write of a book takes, whichever service
+// makes it, so that no write escapes the history, the outbox or the event
+// bus.
+type bookWriter struct {
+	historyRepo out.BookHistoryRepository
+	outbox      out.OutboxRepository
+	txManager   out.TxManager
+	publisher   out.EventPublisher
+}
+
+// write runs fn in a transaction and, in the same transaction, adds the
+// changes it returns to the history of their books and their events to the
+// outbox, to be relayed to other services. Once the transaction has
+// committed, the events are published in-process; a subscriber cannot fail
+// the write.
+func (w bookWriter) write(ctx context.Context, fn func(ctx context.Context) ([]domain.BookChange, error)) error {
+	var events []domain.Event
+	err := w.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		changes, err := fn(ctx)
+		if err != nil || len(changes) == 0 {
+			return err
+		}
+		if err := w.historyRepo.AddBookChanges(ctx, changes); err != nil {
+			return err
+		}
+		events = make([]domain.Event, len(changes))
+		messages := make([]domain.OutboxMessage, len(changes))
+		for i, change := range changes {
+			events[i] = change.Event()
+			if messages[i], err = domain.NewOutboxMessage(events[i]); err != nil {
+				return err
+			}
+		}
+		return w.outbox.AddOutboxMessages(ctx, messages)
+	})
+	if err != nil {
+		return err
+	}
+	if len(events) > 0 {
+		w.publisher.Publish(ctx, events...)
+	}
+	return nil
+}
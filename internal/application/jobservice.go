@@ -0,0 +1,149 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+	"io"
+	"time"
+)
+
+const (
+	// jobLease is how long a claimed job stays with its worker without
+	// reporting progress before other workers may take it over.
+	jobLease = 5 * time.Minute
+	// jobRetryBase is the wait before the first retry of a failed job. It
+	// doubles with every further attempt up to jobRetryMax.
+	jobRetryBase = 10 * time.Second
+	jobRetryMax  = time.Hour
+	// jobReleaseTimeout bounds handing a job back when a worker stops.
+	jobReleaseTimeout = 5 * time.Second
+)
+
+type JobService struct {
+	jobRepo     out.JobRepository
+	runners     map[domain.JobKind]in.JobRunner
+	maxAttempts int
+}
+
+var _ in.JobUseCase = &JobService{}
+
+// NewJobService returns a job service that runs each kind of job with its
+// runner and attempts a job up to maxAttempts times.
+func NewJobService(jobRepo out.JobRepository, runners map[domain.JobKind]in.JobRunner, maxAttempts int) *JobService {
+	return &JobService{jobRepo: jobRepo, runners: runners, maxAttempts: maxAttempts}
+}
+
+func (s *JobService) SubmitJob(ctx context.Context, kind domain.JobKind, params json.RawMessage, input io.Reader) (domain.Job, error) {
+	if _, ok := s.runners[kind]; !ok {
+		return domain.Job{}, fmt.Errorf("unknown job kind %q", kind)
+	}
+	if params == nil {
+		params = json.RawMessage("{}")
+	}
+	job := domain.Job{Kind: kind, Status: domain.JobQueued, Params: params, MaxAttempts: s.maxAttempts}
+	return s.jobRepo.CreateJob(ctx, job, input)
+}
+
+func (s *JobService) GetJob(ctx context.Context, id int) (domain.Job, error) {
+	return s.jobRepo.GetJob(ctx, id)
+}
+
+func (s *JobService) OpenJobArtifact(ctx context.Context, id int) (domain.Job, io.Reader, error) {
+	job, err := s.jobRepo.GetJob(ctx, id)
+	if err != nil {
+		return domain.Job{}, nil, err
+	}
+	if job.Status != domain.JobSucceeded || job.Artifact == nil {
+		return domain.Job{}, nil, domain.ErrJobArtifactNotFound
+	}
+	return job, s.jobRepo.OpenJobFile(ctx, id, domain.JobOutput), nil
+}
+
+// RunNextJob reports a failed attempt on the job rather than returning it;
+// the error is only set when the job's state could not be recorded.
+func (s *JobService) RunNextJob(ctx context.Context) (bool, error) {
+	job, ok, err := s.jobRepo.ClaimJob(ctx, jobLease)
+	if err != nil || !ok {
+		return false, err
+	}
+	if job.Attempts > job.MaxAttempts {
+		// The lease of the last attempt ran out, so its worker most likely
+		// died mid-run.
+		if job.Error == "" {
+			job.Error = fmt.Sprintf("abandoned after %d attempts", job.MaxAttempts)
+		}
+		return true, s.jobRepo.FailJob(ctx, job, nil)
+	}
+
+	outcome, err := s.runJob(ctx, &job)
+	switch {
+	case ctx.Err() != nil:
+		// The worker is stopping: hand the job to another one.
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jobReleaseTimeout)
+		defer cancel()
+		return true, s.jobRepo.ReleaseJob(releaseCtx, job)
+	case errors.Is(err, domain.ErrJobLost):
+		return true, err
+	case err != nil:
+		job.Error = err.Error()
+		return true, s.jobRepo.FailJob(ctx, job, retryAt(job, err))
+	}
+	job.Result = outcome.Result
+	job.Artifact = outcome.Artifact
+	return true, s.jobRepo.CompleteJob(ctx, job)
+}
+
+// runJob runs one attempt of job, keeping job.Processed up to date. A failure
+// to record progress stops the attempt.
+func (s *JobService) runJob(ctx context.Context, job *domain.Job) (domain.JobOutcome, error) {
+	runner, ok := s.runners[job.Kind]
+	if !ok {
+		return domain.JobOutcome{}, &domain.PermanentJobError{Err: fmt.Errorf("unknown job kind %q", job.Kind)}
+	}
+
+	// The writes of a job are recorded in the book history as made by it.
+	runCtx, stop := context.WithCancelCause(domain.WithAudit(ctx, domain.Audit{Actor: fmt.Sprintf("job:%d", job.ID)}))
+	defer stop(nil)
+	output, err := s.jobRepo.CreateJobFile(runCtx, job.ID, domain.JobOutput)
+	if err != nil {
+		return domain.JobOutcome{}, err
+	}
+	progress := func(processed int) {
+		job.Processed = processed
+		if err := s.jobRepo.UpdateJobProgress(runCtx, *job, jobLease); err != nil {
+			stop(err)
+		}
+	}
+
+	input := s.jobRepo.OpenJobFile(runCtx, job.ID, domain.JobInput)
+	outcome, err := runner.RunJob(runCtx, *job, input, output, progress)
+	if err == nil {
+		err = output.Close()
+	}
+	if cause := context.Cause(runCtx); cause != nil && ctx.Err() == nil {
+		return domain.JobOutcome{}, cause
+	}
+	return outcome, err
+}
+
+// retryAt returns when to retry job after its attempt failed with err, or nil
+// when it must not be retried.
+func retryAt(job domain.Job, err error) *time.Time {
+	var permanent *domain.PermanentJobError
+	if errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts {
+		return nil
+	}
+	at := time.Now().Add(retryDelay(job.Attempts))
+	return &at
+}
+
+// retryDelay is the wait before retrying a job, an outbox message or a
+// webhook delivery whose attempt-th attempt failed.
+func retryDelay(attempt int) time.Duration {
+	return min(jobRetryBase<<min(attempt-1, 16), jobRetryMax)
+}
@@ -0,0 +1,284 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+// nopWriteCloser is a job output that keeps what is written to it.
+type nopWriteCloser struct {
+	bytes.Buffer
+}
+
+func (*nopWriteCloser) Close() error { return nil }
+
+// runnerFunc adapts a function to in.JobRunner.
+type runnerFunc func(ctx context.Context, job domain.Job, input io.Reader, output io.Writer, progress func(int)) (domain.JobOutcome, error)
+
+func (f runnerFunc) RunJob(ctx context.Context, job domain.Job, input io.Reader, output io.Writer, progress func(int)) (domain.JobOutcome, error) {
+	return f(ctx, job, input, output, progress)
+}
+
+func TestJobService_SubmitJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockJobRepository(ctrl)
+	input := strings.NewReader("title,author\n")
+	mockRepo.EXPECT().CreateJob(gomock.Any(), domain.Job{
+		Kind: domain.JobBookImport, Status: domain.JobQueued, Params: json.RawMessage("{}"), MaxAttempts: 3,
+	}, input).Return(domain.Job{ID: 1}, nil)
+
+	s := NewJobService(mockRepo, map[domain.JobKind]in.JobRunner{domain.JobBookImport: runnerFunc(nil)}, 3)
+	got, err := s.SubmitJob(context.Background(), domain.JobBookImport, nil, input)
+	if err != nil {
+		t.Fatalf("JobService.SubmitJob() error = %v", err)
+	}
+	if got.ID != 1 {
+		t.Errorf("JobService.SubmitJob() = %+v, want job 1", got)
+	}
+
+	if _, err := s.SubmitJob(context.Background(), domain.JobBookExport, nil, nil); err == nil {
+		t.Error("JobService.SubmitJob() with an unknown kind succeeded")
+	}
+}
+
+func TestJobService_OpenJobArtifact(t *testing.T) {
+	artifact := &domain.JobArtifact{Filename: "books.csv", ContentType: "text/csv"}
+	tests := []struct {
+		name    string
+		job     domain.Job
+		getErr  error
+		wantErr error
+	}{
+		{name: "succeeded", job: domain.Job{ID: 1, Status: domain.JobSucceeded, Artifact: artifact}},
+		{name: "still running", job: domain.Job{ID: 1, Status: domain.JobRunning}, wantErr: domain.ErrJobArtifactNotFound},
+		{name: "succeeded without artifact", job: domain.Job{ID: 1, Status: domain.JobSucceeded}, wantErr: domain.ErrJobArtifactNotFound},
+		{name: "not found", getErr: domain.ErrJobNotFound, wantErr: domain.ErrJobNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockJobRepository(ctrl)
+			mockRepo.EXPECT().GetJob(gomock.Any(), 1).Return(tt.job, tt.getErr)
+			if tt.wantErr == nil {
+				mockRepo.EXPECT().OpenJobFile(gomock.Any(), 1, domain.JobOutput).Return(strings.NewReader("id\n"))
+			}
+
+			s := NewJobService(mockRepo, nil, 3)
+			job, r, err := s.OpenJobArtifact(context.Background(), 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("JobService.OpenJobArtifact() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (job.Artifact != artifact || r == nil) {
+				t.Errorf("JobService.OpenJobArtifact() = %+v, %v", job, r)
+			}
+		})
+	}
+}
+
+func TestJobService_RunNextJob(t *testing.T) {
+	errDB := errors.New("db error")
+	claimed := domain.Job{ID: 1, Kind: domain.JobBookExport, Status: domain.JobRunning, Attempts: 1, MaxAttempts: 3}
+	succeed := runnerFunc(func(_ context.Context, _ domain.Job, _ io.Reader, output io.Writer, progress func(int)) (domain.JobOutcome, error) {
+		progress(5)
+		io.WriteString(output, "id\n")
+		return domain.JobOutcome{Result: json.RawMessage(`{"books":5}`), Artifact: &domain.JobArtifact{Filename: "books.csv"}}, nil
+	})
+	fail := func(err error) runnerFunc {
+		return func(context.Context, domain.Job, io.Reader, io.Writer, func(int)) (domain.JobOutcome, error) {
+			return domain.JobOutcome{}, err
+		}
+	}
+	expectRun := func(m *mocks.MockJobRepository, job domain.Job) {
+		m.EXPECT().ClaimJob(gomock.Any(), jobLease).Return(job, true, nil)
+		m.EXPECT().CreateJobFile(gomock.Any(), job.ID, domain.JobOutput).Return(&nopWriteCloser{}, nil)
+		m.EXPECT().OpenJobFile(gomock.Any(), job.ID, domain.JobInput).Return(strings.NewReader(""))
+	}
+	tests := []struct {
+		name    string
+		runner  in.JobRunner
+		setup   func(*mocks.MockJobRepository)
+		wantRan bool
+		wantErr error
+	}{
+		{
+			name: "no runnable job",
+			setup: func(m *mocks.MockJobRepository) {
+				m.EXPECT().ClaimJob(gomock.Any(), jobLease).Return(domain.Job{}, false, nil)
+			},
+		},
+		{
+			name: "claim error",
+			setup: func(m *mocks.MockJobRepository) {
+				m.EXPECT().ClaimJob(gomock.Any(), jobLease).Return(domain.Job{}, false, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:   "success",
+			runner: succeed,
+			setup: func(m *mocks.MockJobRepository) {
+				expectRun(m, claimed)
+				progressed := claimed
+				progressed.Processed = 5
+				m.EXPECT().UpdateJobProgress(gomock.Any(), progressed, jobLease).Return(nil)
+				done := progressed
+				done.Result = json.RawMessage(`{"books":5}`)
+				done.Artifact = &domain.JobArtifact{Filename: "books.csv"}
+				m.EXPECT().CompleteJob(gomock.Any(), done).Return(nil)
+			},
+			wantRan: true,
+		},
+		{
+			name:   "failure is retried with backoff",
+			runner: fail(errDB),
+			setup: func(m *mocks.MockJobRepository) {
+				expectRun(m, claimed)
+				failed := claimed
+				failed.Error = "db error"
+				m.EXPECT().FailJob(gomock.Any(), failed, gomock.Not(gomock.Nil())).
+					DoAndReturn(func(_ context.Context, _ domain.Job, retryAt *time.Time) error {
+						if d := time.Until(*retryAt); d <= 0 || d > jobRetryBase {
+							t.Errorf("FailJob() retry in %v, want within %v", d, jobRetryBase)
+						}
+						return nil
+					})
+			},
+			wantRan: true,
+		},
+		{
+			name:   "permanent failure is not retried",
+			runner: fail(&domain.PermanentJobError{Err: errors.New("bad header")}),
+			setup: func(m *mocks.MockJobRepository) {
+				expectRun(m, claimed)
+				failed := claimed
+				failed.Error = "bad header"
+				m.EXPECT().FailJob(gomock.Any(), failed, nil).Return(nil)
+			},
+			wantRan: true,
+		},
+		{
+			name:   "last attempt is not retried",
+			runner: fail(errDB),
+			setup: func(m *mocks.MockJobRepository) {
+				last := claimed
+				last.Attempts = 3
+				expectRun(m, last)
+				last.Error = "db error"
+				m.EXPECT().FailJob(gomock.Any(), last, nil).Return(nil)
+			},
+			wantRan: true,
+		},
+		{
+			name: "abandoned job fails without running",
+			setup: func(m *mocks.MockJobRepository) {
+				abandoned := claimed
+				abandoned.Attempts = 4
+				m.EXPECT().ClaimJob(gomock.Any(), jobLease).Return(abandoned, true, nil)
+				abandoned.Error = "abandoned after 3 attempts"
+				m.EXPECT().FailJob(gomock.Any(), abandoned, nil).Return(nil)
+			},
+			wantRan: true,
+		},
+		{
+			name: "unknown kind fails for good",
+			setup: func(m *mocks.MockJobRepository) {
+				unknown := claimed
+				unknown.Kind = "reindex"
+				m.EXPECT().ClaimJob(gomock.Any(), jobLease).Return(unknown, true, nil)
+				unknown.Error = `unknown job kind "reindex"`
+				m.EXPECT().FailJob(gomock.Any(), unknown, nil).Return(nil)
+			},
+			wantRan: true,
+		},
+		{
+			name:   "lost lease stops the attempt",
+			runner: succeed,
+			setup: func(m *mocks.MockJobRepository) {
+				expectRun(m, claimed)
+				m.EXPECT().UpdateJobProgress(gomock.Any(), gomock.Any(), jobLease).Return(domain.ErrJobLost)
+			},
+			wantRan: true,
+			wantErr: domain.ErrJobLost,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockJobRepository(ctrl)
+			tt.setup(mockRepo)
+
+			s := NewJobService(mockRepo, map[domain.JobKind]in.JobRunner{domain.JobBookExport: tt.runner}, 3)
+			ran, err := s.RunNextJob(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("JobService.RunNextJob() error = %v, want %v", err, tt.wantErr)
+			}
+			if ran != tt.wantRan {
+				t.Errorf("JobService.RunNextJob() ran = %v, want %v", ran, tt.wantRan)
+			}
+		})
+	}
+}
+
+func TestJobService_RunNextJob_ReleasesOnShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	job := domain.Job{ID: 1, Kind: domain.JobBookExport, Status: domain.JobRunning, Attempts: 1, MaxAttempts: 3}
+	mockRepo := mocks.NewMockJobRepository(ctrl)
+	mockRepo.EXPECT().ClaimJob(gomock.Any(), jobLease).Return(job, true, nil)
+	mockRepo.EXPECT().CreateJobFile(gomock.Any(), 1, domain.JobOutput).Return(&nopWriteCloser{}, nil)
+	mockRepo.EXPECT().OpenJobFile(gomock.Any(), 1, domain.JobInput).Return(strings.NewReader(""))
+	mockRepo.EXPECT().ReleaseJob(gomock.Any(), job).DoAndReturn(func(ctx context.Context, _ domain.Job) error {
+		if ctx.Err() != nil {
+			t.Error("ReleaseJob() called with a cancelled context")
+		}
+		return nil
+	})
+
+	runner := runnerFunc(func(ctx context.Context, _ domain.Job, _ io.Reader, _ io.Writer, _ func(int)) (domain.JobOutcome, error) {
+		cancel()
+		return domain.JobOutcome{}, ctx.Err()
+	})
+	s := NewJobService(mockRepo, map[domain.JobKind]in.JobRunner{domain.JobBookExport: runner}, 3)
+	if ran, err := s.RunNextJob(ctx); !ran || err != nil {
+		t.Errorf("JobService.RunNextJob() = %v, %v, want true, nil", ran, err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{4, 80 * time.Second},
+		{10, time.Hour},
+		{100, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempt); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
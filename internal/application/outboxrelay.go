@@ -0,0 +1,80 @@
+package application
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+	"time"
+)
+
+const (
+	// outboxBatchSize is the most messages relayed in one transaction.
+	outboxBatchSize = 100
+	// outboxPublishTimeout bounds publishing one message, which holds the
+	// batch's locks meanwhile.
+	outboxPublishTimeout = 30 * time.Second
+)
+
+// OutboxRelay publishes the messages of the outbox. A message is deleted in
+// the transaction that claimed it once published, so one published just
+// before a crash is published again: delivery is at least once.
+type OutboxRelay struct {
+	outbox      out.OutboxRepository
+	publisher   out.MessagePublisher
+	txManager   out.TxManager
+	maxAttempts int
+}
+
+var _ in.OutboxRelayUseCase = &OutboxRelay{}
+
+// NewOutboxRelay returns a relay that attempts to publish a message up to
+// maxAttempts times before dead-lettering it.
+func NewOutboxRelay(outbox out.OutboxRepository, publisher out.MessagePublisher, txManager out.TxManager, maxAttempts int) *OutboxRelay {
+	return &OutboxRelay{outbox: outbox, publisher: publisher, txManager: txManager, maxAttempts: maxAttempts}
+}
+
+func (r *OutboxRelay) RelayMessages(ctx context.Context) (int, error) {
+	var relayed int
+	err := r.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		messages, err := r.outbox.ClaimOutboxMessages(ctx, outboxBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, msg := range messages {
+			if err := r.relay(ctx, msg); err != nil {
+				return err
+			}
+		}
+		relayed = len(messages)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return relayed, nil
+}
+
+// relay publishes msg and deletes it, or records the failed attempt. Only a
+// failure to update the outbox is returned.
+func (r *OutboxRelay) relay(ctx context.Context, msg domain.OutboxMessage) error {
+	publishCtx, cancel := context.WithTimeout(ctx, outboxPublishTimeout)
+	err := r.publisher.Publish(publishCtx, msg)
+	cancel()
+	if err == nil {
+		return r.outbox.DeleteOutboxMessage(ctx, msg.ID)
+	}
+	if ctx.Err() != nil {
+		// Stopping: leave the message as it was for the next relay.
+		return ctx.Err()
+	}
+
+	msg.Attempts++
+	msg.Error = err.Error()
+	var retryAt *time.Time
+	if msg.Attempts < r.maxAttempts {
+		at := time.Now().Add(retryDelay(msg.Attempts))
+		retryAt = &at
+	}
+	return r.outbox.FailOutboxMessage(ctx, msg, retryAt)
+}
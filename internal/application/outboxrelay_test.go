@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestOutboxRelay_RelayMessages(t *testing.T) {
+	errDB := errors.New("db error")
+	errBroker := errors.New("broker unavailable")
+	created := domain.OutboxMessage{ID: 1, Type: "book.created", Key: "book:1"}
+	updated := domain.OutboxMessage{ID: 2, Type: "book.updated", Key: "book:2", Attempts: 1, Error: "timeout"}
+
+	tests := []struct {
+		name        string
+		setup       func(*mocks.MockOutboxRepository, *mocks.MockMessagePublisher)
+		wantRelayed int
+		wantErr     error
+	}{
+		{
+			name: "nothing due",
+			setup: func(o *mocks.MockOutboxRepository, p *mocks.MockMessagePublisher) {
+				o.EXPECT().ClaimOutboxMessages(gomock.Any(), outboxBatchSize).Return([]domain.OutboxMessage{}, nil)
+			},
+		},
+		{
+			name: "claim error",
+			setup: func(o *mocks.MockOutboxRepository, p *mocks.MockMessagePublisher) {
+				o.EXPECT().ClaimOutboxMessages(gomock.Any(), outboxBatchSize).Return([]domain.OutboxMessage{}, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "published messages are deleted in order",
+			setup: func(o *mocks.MockOutboxRepository, p *mocks.MockMessagePublisher) {
+				o.EXPECT().ClaimOutboxMessages(gomock.Any(), outboxBatchSize).Return([]domain.OutboxMessage{created, updated}, nil)
+				gomock.InOrder(
+					p.EXPECT().Publish(gomock.Any(), created).Return(nil),
+					o.EXPECT().DeleteOutboxMessage(gomock.Any(), int64(1)).Return(nil),
+					p.EXPECT().Publish(gomock.Any(), updated).Return(nil),
+					o.EXPECT().DeleteOutboxMessage(gomock.Any(), int64(2)).Return(nil),
+				)
+			},
+			wantRelayed: 2,
+		},
+		{
+			name: "failure is retried with backoff without holding up other keys",
+			setup: func(o *mocks.MockOutboxRepository, p *mocks.MockMessagePublisher) {
+				o.EXPECT().ClaimOutboxMessages(gomock.Any(), outboxBatchSize).Return([]domain.OutboxMessage{created, updated}, nil)
+				p.EXPECT().Publish(gomock.Any(), created).Return(errBroker)
+				failed := created
+				failed.Attempts, failed.Error = 1, "broker unavailable"
+				o.EXPECT().FailOutboxMessage(gomock.Any(), failed, gomock.Not(gomock.Nil())).
+					DoAndReturn(func(_ context.Context, _ domain.OutboxMessage, retryAt *time.Time) error {
+						if d := time.Until(*retryAt); d <= 0 || d > jobRetryBase {
+							t.Errorf("FailOutboxMessage() retry in %v, want within %v", d, jobRetryBase)
+						}
+						return nil
+					})
+				p.EXPECT().Publish(gomock.Any(), updated).Return(nil)
+				o.EXPECT().DeleteOutboxMessage(gomock.Any(), int64(2)).Return(nil)
+			},
+			wantRelayed: 2,
+		},
+		{
+			name: "last attempt is dead-lettered",
+			setup: func(o *mocks.MockOutboxRepository, p *mocks.MockMessagePublisher) {
+				last := updated
+				last.Attempts = 2
+				o.EXPECT().ClaimOutboxMessages(gomock.Any(), outboxBatchSize).Return([]domain.OutboxMessage{last}, nil)
+				p.EXPECT().Publish(gomock.Any(), last).Return(errBroker)
+				last.Attempts, last.Error = 3, "broker unavailable"
+				o.EXPECT().FailOutboxMessage(gomock.Any(), last, nil).Return(nil)
+			},
+			wantRelayed: 1,
+		},
+		{
+			name: "outbox error rolls the batch back",
+			setup: func(o *mocks.MockOutboxRepository, p *mocks.MockMessagePublisher) {
+				o.EXPECT().ClaimOutboxMessages(gomock.Any(), outboxBatchSize).Return([]domain.OutboxMessage{created, updated}, nil)
+				p.EXPECT().Publish(gomock.Any(), created).Return(nil)
+				o.EXPECT().DeleteOutboxMessage(gomock.Any(), int64(1)).Return(errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockOutbox := mocks.NewMockOutboxRepository(ctrl)
+			mockPublisher := mocks.NewMockMessagePublisher(ctrl)
+			tt.setup(mockOutbox, mockPublisher)
+
+			r := NewOutboxRelay(mockOutbox, mockPublisher, newTxManager(ctrl), 3)
+			relayed, err := r.RelayMessages(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("OutboxRelay.RelayMessages() error = %v, want %v", err, tt.wantErr)
+			}
+			if relayed != tt.wantRelayed {
+				t.Errorf("OutboxRelay.RelayMessages() relayed = %d, want %d", relayed, tt.wantRelayed)
+			}
+		})
+	}
+}
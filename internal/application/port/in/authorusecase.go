@@ -0,0 +1,16 @@
+package in
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+)
+
+type AuthorUseCase interface {
+	CreateAuthor(ctx context.Context, author domain.Author) (domain.Author, error)
+	GetAuthor(ctx context.Context, id int) (domain.Author, error)
+	// GetAuthors returns a page of authors and the total number of authors.
+	GetAuthors(ctx context.Context, page, perPage int) ([]domain.Author, int, error)
+	GetAuthorBooks(ctx context.Context, id, page, perPage int) ([]domain.Book, int, error)
+	UpdateAuthor(ctx context.Context, author domain.Author) error
+	DeleteAuthor(ctx context.Context, id int) error
+}
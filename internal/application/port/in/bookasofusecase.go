@@ -0,0 +1,15 @@
+package in
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+	"time"
+)
+
+// BookAsOfUseCase reads the catalog as it stood at a past time.
+type BookAsOfUseCase interface {
+	GetBookAsOf(ctx context.Context, id int, at time.Time) (domain.Book, error)
+	// GetBooksAsOf returns a page of the books that were live at at and the
+	// total number of them, filtered and sorted as GetBooks.
+	GetBooksAsOf(ctx context.Context, criteria domain.BookCriteria, at time.Time, page, perPage int) ([]domain.Book, int, error)
+}
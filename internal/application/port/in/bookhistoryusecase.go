@@ -0,0 +1,18 @@
+package in
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+)
+
+type BookHistoryUseCase interface {
+	// GetBookHistory returns a page of the book's changes, latest first, and
+	// the total number of changes.
+	GetBookHistory(ctx context.Context, id, page, perPage int) ([]domain.BookChange, int, error)
+	// GetBookVersion returns the change that brought the book to version.
+	GetBookVersion(ctx context.Context, id, version int) (domain.BookChange, error)
+	// RevertBook writes the book back as it was at version. A non-zero
+	// current must match the stored version, otherwise
+	// domain.ErrVersionConflict is returned.
+	RevertBook(ctx context.Context, id, version, current int) (domain.Book, error)
+}
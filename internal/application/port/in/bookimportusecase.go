@@ -0,0 +1,20 @@
+package in
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+)
+
+// BookRowReader reads the books of an import one row at a time. Read returns
+// io.EOF after the last row. A row that cannot be parsed is returned with its
+// Err set, and any other error aborts the import.
+type BookRowReader interface {
+	Read() (domain.ImportRow, error)
+}
+
+type BookImportUseCase interface {
+	// ImportBooks validates and inserts every row read from rows, in
+	// transactions of a bounded number of rows, and reports each row. With
+	// dryRun nothing is written.
+	ImportBooks(ctx context.Context, rows BookRowReader, dryRun bool) (domain.ImportReport, error)
+}
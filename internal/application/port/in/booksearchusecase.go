@@ -0,0 +1,19 @@
+package in
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+)
+
+type BookSearchUseCase interface {
+	// SearchBooks returns a page of the books matching q, most relevant
+	// first, and the total number of matching books. See
+	// domain.ParseSearchQuery for the query syntax.
+	SearchBooks(ctx context.Context, q string, page, perPage int) ([]domain.BookHit, int, error)
+	// FuzzySearchBooks is SearchBooks for misspelled queries: it matches
+	// titles and authors by trigram similarity instead of by exact words.
+	FuzzySearchBooks(ctx context.Context, q string, page, perPage int) ([]domain.BookHit, int, error)
+	// SuggestBooks returns up to limit book titles and author names that
+	// complete prefix.
+	SuggestBooks(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error)
+}
@@ -3,12 +3,31 @@ package in
 import (
 	"context"
 	"go-api-boilerplate/internal/domain"
+	"time"
 )
 
 type BookUseCase interface {
-	CreateBook(ctx context.Context, book domain.Book) error
+	CreateBook(ctx context.Context, book domain.Book) (domain.Book, error)
+	// CreateBooks validates and creates a batch of books, returning one
+	// result per book in order. Failed items are reported in the results;
+	// the error is reserved for failures of the batch as a whole.
+	CreateBooks(ctx context.Context, books []domain.Book, mode domain.BatchMode) ([]domain.BatchResult, error)
 	GetBook(ctx context.Context, id int) (domain.Book, error)
-	GetBooks(ctx context.Context, page, perPage int) ([]domain.Book, error)
-	UpdateBook(ctx context.Context, book domain.Book) error
-	DeleteBook(ctx context.Context, id int) error
+	GetBookByISBN(ctx context.Context, isbn string) (domain.Book, error)
+	// GetBooks returns a page of the books matching criteria and the total
+	// number of matching books.
+	GetBooks(ctx context.Context, criteria domain.BookCriteria, page, perPage int) ([]domain.Book, int, error)
+	// GetBooksByCursor returns a page of the books matching criteria next to
+	// the cursor. The cursor must come from a list with the same sort.
+	GetBooksByCursor(ctx context.Context, criteria domain.BookCriteria, cursor domain.Cursor, limit int) (domain.BookPage, error)
+	// ExportBooks calls fn with successive chunks of the books matching
+	// criteria, in list order, until they run out or fn fails.
+	ExportBooks(ctx context.Context, criteria domain.BookCriteria, fn func([]domain.Book) error) error
+	UpdateBook(ctx context.Context, book domain.Book) (domain.Book, error)
+	PatchBook(ctx context.Context, id, version int, patch domain.BookPatch) (domain.Book, error)
+	DeleteBook(ctx context.Context, id, version int) error
+	GetTrash(ctx context.Context, page, perPage int) ([]domain.Book, int, error)
+	RestoreBook(ctx context.Context, id int) (domain.Book, error)
+	PurgeBook(ctx context.Context, id int) error
+	PurgeExpiredBooks(ctx context.Context, retention time.Duration) (int64, error)
 }
@@ -0,0 +1,30 @@
+package in
+
+import (
+	"context"
+	"encoding/json"
+	"go-api-boilerplate/internal/domain"
+	"io"
+)
+
+// JobRunner runs one attempt of a kind of job. It reads the job's upload, if
+// any, from input, writes its artifact to output, and reports the number of
+// items handled so far through progress. An error wrapped in a
+// *domain.PermanentJobError fails the job without retrying it.
+type JobRunner interface {
+	RunJob(ctx context.Context, job domain.Job, input io.Reader, output io.Writer, progress func(processed int)) (domain.JobOutcome, error)
+}
+
+type JobUseCase interface {
+	// SubmitJob queues a job of kind with the given params and input file,
+	// read from input when not nil.
+	SubmitJob(ctx context.Context, kind domain.JobKind, params json.RawMessage, input io.Reader) (domain.Job, error)
+	GetJob(ctx context.Context, id int) (domain.Job, error)
+	// OpenJobArtifact returns a succeeded job and a reader of the file it
+	// produced, or domain.ErrJobArtifactNotFound when there is none (yet).
+	OpenJobArtifact(ctx context.Context, id int) (domain.Job, io.Reader, error)
+	// RunNextJob claims the next runnable job and runs one attempt of it.
+	// ran is false when no job was runnable. When ctx is cancelled mid-run
+	// the job is released for another worker.
+	RunNextJob(ctx context.Context) (ran bool, err error)
+}
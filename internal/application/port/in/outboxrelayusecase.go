@@ -0,0 +1,10 @@
+package in
+
+import "context"
+
+type OutboxRelayUseCase interface {
+	// RelayMessages publishes a batch of due outbox messages, deleting the
+	// ones published and scheduling the others for a retry or
+	// dead-lettering them. relayed is the number of messages handled.
+	RelayMessages(ctx context.Context) (relayed int, err error)
+}
@@ -0,0 +1,28 @@
+package in
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+)
+
+type WebhookUseCase interface {
+	// CreateWebhook generates a secret for the webhook when it has none.
+	CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error)
+	GetWebhook(ctx context.Context, id int) (domain.Webhook, error)
+	// GetWebhooks returns a page of webhooks and the total number of
+	// webhooks.
+	GetWebhooks(ctx context.Context, page, perPage int) ([]domain.Webhook, int, error)
+	// UpdateWebhook replaces the URL and events of a webhook, and its secret
+	// when webhook has one.
+	UpdateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int) error
+	// GetWebhookDeliveries returns a page of the deliveries of a webhook,
+	// latest first, and their total number.
+	GetWebhookDeliveries(ctx context.Context, id, page, perPage int) ([]domain.WebhookDelivery, int, error)
+	// EnqueueWebhookDeliveries queues msg for every webhook subscribed to
+	// it.
+	EnqueueWebhookDeliveries(ctx context.Context, msg domain.OutboxMessage) error
+	// DeliverNextWebhook claims the next due delivery and attempts it.
+	// delivered is false when no delivery was due.
+	DeliverNextWebhook(ctx context.Context) (delivered bool, err error)
+}
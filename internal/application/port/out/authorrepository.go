@@ -0,0 +1,23 @@
+package out
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+)
+
+type AuthorRepository interface {
+	CreateAuthor(ctx context.Context, author domain.Author) (domain.Author, error)
+	GetAuthor(ctx context.Context, id int) (domain.Author, error)
+	// GetAuthorsByIDs returns the authors in the order of ids, or
+	// domain.ErrUnknownAuthor if any of them does not exist.
+	GetAuthorsByIDs(ctx context.Context, ids []int) ([]domain.Author, error)
+	GetAuthors(ctx context.Context, offset, limit int) ([]domain.Author, error)
+	CountAuthors(ctx context.Context) (int, error)
+	// FindOrCreateAuthorByName returns the oldest author with exactly this
+	// name, creating one if there is none.
+	FindOrCreateAuthorByName(ctx context.Context, name string) (domain.Author, error)
+	// UpdateAuthor renames the author. The bylines of its books are left to
+	// BookRepository.RefreshBylines.
+	UpdateAuthor(ctx context.Context, author domain.Author) error
+	DeleteAuthor(ctx context.Context, id int) error
+}
@@ -0,0 +1,18 @@
+package out
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+	"time"
+)
+
+// BookAsOfReader reads books as they were at a past time.
+type BookAsOfReader interface {
+	// GetBookAsOf returns the book as it was at at, or domain.ErrBookNotFound
+	// when it did not exist yet or was in the trash.
+	GetBookAsOf(ctx context.Context, id int, at time.Time) (domain.Book, error)
+	// GetBooksAsOf returns the books that were live at at and matched
+	// criteria as they were then.
+	GetBooksAsOf(ctx context.Context, criteria domain.BookCriteria, at time.Time, offset, limit int) ([]domain.Book, error)
+	CountBooksAsOf(ctx context.Context, criteria domain.BookCriteria, at time.Time) (int, error)
+}
@@ -0,0 +1,19 @@
+package out
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+)
+
+// BookHistoryRepository stores the history of every book.
+type BookHistoryRepository interface {
+	// AddBookChanges records changes. It is called in the transaction of
+	// the writes they describe.
+	AddBookChanges(ctx context.Context, changes []domain.BookChange) error
+	// GetBookHistory returns the changes of a book, latest first.
+	GetBookHistory(ctx context.Context, bookID, offset, limit int) ([]domain.BookChange, error)
+	CountBookHistory(ctx context.Context, bookID int) (int, error)
+	// GetBookChange returns the change that brought the book to version, or
+	// domain.ErrBookVersionNotFound.
+	GetBookChange(ctx context.Context, bookID, version int) (domain.BookChange, error)
+}
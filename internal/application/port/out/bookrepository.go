@@ -3,12 +3,48 @@ package out
 import (
 	"context"
 	"go-api-boilerplate/internal/domain"
+	"time"
 )
 
 type BookRepository interface {
-	CreateBook(ctx context.Context, book domain.Book) error
+	CreateBook(ctx context.Context, book domain.Book) (domain.Book, error)
+	// CreateBooks inserts valid books with resolved authors in one
+	// transaction and returns one result per book in order. A book whose
+	// ISBN is taken fails with domain.ErrDuplicateISBN; in all-or-nothing
+	// mode that rolls back the whole batch.
+	CreateBooks(ctx context.Context, books []domain.Book, mode domain.BatchMode) ([]domain.BatchResult, error)
 	GetBook(ctx context.Context, id int) (domain.Book, error)
-	GetBooks(ctx context.Context, offset, limit int) ([]domain.Book, error)
-	UpdateBook(ctx context.Context, book domain.Book) error
-	DeleteBook(ctx context.Context, id int) error
+	// LockBook returns the book, live or in the trash, and locks it against
+	// other writes until the transaction of ctx ends.
+	LockBook(ctx context.Context, id int) (domain.Book, error)
+	GetBookByISBN(ctx context.Context, isbn string) (domain.Book, error)
+	// FindExistingISBNs returns those of isbns that belong to live books.
+	FindExistingISBNs(ctx context.Context, isbns []string) ([]string, error)
+	// GetBooks returns the live books matching criteria, in criteria.Sort
+	// order.
+	GetBooks(ctx context.Context, criteria domain.BookCriteria, offset, limit int) ([]domain.Book, error)
+	// GetBooksByCursor returns up to limit books matching criteria next to the
+	// cursor, in list order. It returns domain.ErrInvalidCursor if the key does
+	// not fit criteria.Sort.
+	GetBooksByCursor(ctx context.Context, criteria domain.BookCriteria, cursor domain.Cursor, limit int) ([]domain.Book, error)
+	CountBooks(ctx context.Context, criteria domain.BookCriteria) (int, error)
+	// ExportBooks calls fn with successive chunks of the live books matching
+	// criteria, in criteria.Sort order, until they run out or fn fails.
+	ExportBooks(ctx context.Context, criteria domain.BookCriteria, fn func([]domain.Book) error) error
+	GetBooksByAuthor(ctx context.Context, authorID, offset, limit int) ([]domain.Book, error)
+	// LockBooksByAuthor returns the books linked to the author, live or in
+	// the trash, in id order, and locks them as LockBook does.
+	LockBooksByAuthor(ctx context.Context, authorID int) ([]domain.Book, error)
+	CountBooksByAuthor(ctx context.Context, authorID int) (int, error)
+	UpdateBook(ctx context.Context, book domain.Book) (domain.Book, error)
+	PatchBook(ctx context.Context, id, version int, patch domain.BookPatch) (domain.Book, error)
+	// RefreshBylines rewrites the byline of the books from the current names
+	// of their authors, bumps their versions and returns them in id order.
+	RefreshBylines(ctx context.Context, ids []int) ([]domain.Book, error)
+	DeleteBook(ctx context.Context, id, version int) error
+	GetDeletedBooks(ctx context.Context, offset, limit int) ([]domain.Book, error)
+	CountDeletedBooks(ctx context.Context) (int, error)
+	RestoreBook(ctx context.Context, id int) (domain.Book, error)
+	PurgeBook(ctx context.Context, id int) error
+	PurgeDeletedBooks(ctx context.Context, before time.Time) (int64, error)
 }
@@ -0,0 +1,21 @@
+package out
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+)
+
+// BookSearcher finds live books by the words of their title and author.
+type BookSearcher interface {
+	// SearchBooks returns the books matching query, most relevant first.
+	SearchBooks(ctx context.Context, query domain.SearchQuery, offset, limit int) ([]domain.BookHit, error)
+	CountSearchResults(ctx context.Context, query domain.SearchQuery) (int, error)
+	// FuzzySearchBooks returns the books whose title or author holds words
+	// similar to text, most similar first. Books whose similarity does not
+	// exceed threshold are left out.
+	FuzzySearchBooks(ctx context.Context, text string, threshold float64, offset, limit int) ([]domain.BookHit, error)
+	CountFuzzySearchResults(ctx context.Context, text string, threshold float64) (int, error)
+	// SuggestBooks returns up to limit book titles and author names that
+	// start with prefix, ignoring case, shortest first.
+	SuggestBooks(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error)
+}
@@ -0,0 +1,14 @@
+package out
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+)
+
+// EventPublisher announces committed changes to whoever subscribed to them.
+type EventPublisher interface {
+	// Publish hands the events to the subscribers in order. It does not
+	// report their failures: a change has happened whether or not every
+	// subscriber managed to react to it.
+	Publish(ctx context.Context, events ...domain.Event)
+}
@@ -0,0 +1,38 @@
+package out
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+	"io"
+	"time"
+)
+
+// JobRepository stores jobs and their files. The updates made by a worker
+// are guarded by the job's Attempts, and fail with domain.ErrJobLost once
+// another worker has claimed the job since.
+type JobRepository interface {
+	// CreateJob queues job along with its input file, read from input when
+	// not nil, in one transaction.
+	CreateJob(ctx context.Context, job domain.Job, input io.Reader) (domain.Job, error)
+	GetJob(ctx context.Context, id int) (domain.Job, error)
+	// ClaimJob marks the next runnable job as running by this worker for
+	// lease and counts the attempt. A job is runnable when it is queued and
+	// due, or running with an expired lease. ok is false when no job is.
+	ClaimJob(ctx context.Context, lease time.Duration) (job domain.Job, ok bool, err error)
+	// UpdateJobProgress records job.Processed and extends the lease.
+	UpdateJobProgress(ctx context.Context, job domain.Job, lease time.Duration) error
+	// CompleteJob records job.Processed, job.Result and job.Artifact and
+	// marks the job succeeded.
+	CompleteJob(ctx context.Context, job domain.Job) error
+	// FailJob records job.Processed and job.Error. With a nil retryAt the
+	// job is marked failed; otherwise it is queued to run again then.
+	FailJob(ctx context.Context, job domain.Job, retryAt *time.Time) error
+	// ReleaseJob queues a running job again without counting the attempt.
+	ReleaseJob(ctx context.Context, job domain.Job) error
+	// OpenJobFile reads the named file of a job. A missing file reads as
+	// empty.
+	OpenJobFile(ctx context.Context, id int, name string) io.Reader
+	// CreateJobFile replaces the named file of a job with what is written
+	// to the returned writer until it is closed.
+	CreateJobFile(ctx context.Context, id int, name string) (io.WriteCloser, error)
+}
@@ -0,0 +1,14 @@
+package out
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+)
+
+// MessagePublisher delivers outbox messages to other services, such as a
+// message broker.
+type MessagePublisher interface {
+	// Publish delivers msg, returning once it has been accepted. A message
+	// may be published again after an error or a crash.
+	Publish(ctx context.Context, msg domain.OutboxMessage) error
+}
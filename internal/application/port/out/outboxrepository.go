@@ -0,0 +1,27 @@
+package out
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+	"time"
+)
+
+// OutboxRepository stores the messages waiting to be relayed to other
+// services. Messages are added in the transaction of the change they
+// announce, and claimed, deleted and failed in the transaction of the relay
+// handling them.
+type OutboxRepository interface {
+	AddOutboxMessages(ctx context.Context, messages []domain.OutboxMessage) error
+	// ClaimOutboxMessages locks up to limit messages that are due, for the
+	// rest of the transaction ctx carries. Only the oldest pending message of
+	// a key is due, so a key is held up by its failing message, and keys
+	// locked by another relay are skipped.
+	ClaimOutboxMessages(ctx context.Context, limit int) ([]domain.OutboxMessage, error)
+	// DeleteOutboxMessage removes a message that has been relayed.
+	DeleteOutboxMessage(ctx context.Context, id int64) error
+	// FailOutboxMessage records msg.Attempts and msg.Error. With a nil
+	// retryAt the message is dead-lettered: it is kept but no longer relayed,
+	// and no longer holds up the later messages of its key. Otherwise it is
+	// relayed again then.
+	FailOutboxMessage(ctx context.Context, msg domain.OutboxMessage, retryAt *time.Time) error
+}
@@ -0,0 +1,11 @@
+package out
+
+import "context"
+
+// TxManager runs use cases that span several repository calls atomically.
+type TxManager interface {
+	// WithinTx runs fn in a transaction that is committed when fn succeeds
+	// and rolled back otherwise. Repositories called with the ctx passed to
+	// fn take part in the transaction. Nested calls join the outer one.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
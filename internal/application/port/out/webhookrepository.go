@@ -0,0 +1,37 @@
+package out
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+	"time"
+)
+
+// WebhookRepository stores webhooks and their deliveries. Deleting a webhook
+// deletes its deliveries.
+type WebhookRepository interface {
+	CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error)
+	GetWebhook(ctx context.Context, id int) (domain.Webhook, error)
+	GetWebhooks(ctx context.Context, offset, limit int) ([]domain.Webhook, error)
+	CountWebhooks(ctx context.Context) (int, error)
+	// UpdateWebhook replaces the URL, events and secret of a webhook.
+	UpdateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int) error
+	// AddWebhookDeliveries queues delivery for every webhook subscribed to
+	// delivery.Event. A message already queued for a webhook is skipped, so
+	// adding the deliveries of a message again is harmless.
+	AddWebhookDeliveries(ctx context.Context, delivery domain.WebhookDelivery) error
+	// GetWebhookDeliveries returns the deliveries of a webhook, latest first.
+	GetWebhookDeliveries(ctx context.Context, webhookID, offset, limit int) ([]domain.WebhookDelivery, error)
+	CountWebhookDeliveries(ctx context.Context, webhookID int) (int, error)
+	// ClaimWebhookDelivery counts an attempt of the next due delivery and
+	// holds it back for lease, during which no other worker claims it. ok is
+	// false when no delivery is due.
+	ClaimWebhookDelivery(ctx context.Context, lease time.Duration) (delivery domain.WebhookDelivery, ok bool, err error)
+	// CompleteWebhookDelivery records delivery.ResponseStatus and marks the
+	// delivery succeeded.
+	CompleteWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) error
+	// FailWebhookDelivery records delivery.ResponseStatus and delivery.Error.
+	// With a nil retryAt the delivery is marked failed; otherwise it is
+	// attempted again then.
+	FailWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery, retryAt *time.Time) error
+}
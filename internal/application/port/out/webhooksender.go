@@ -0,0 +1,14 @@
+package out
+
+import (
+	"context"
+	"go-api-boilerplate/internal/domain"
+)
+
+// WebhookSender posts deliveries to webhooks.
+type WebhookSender interface {
+	// SendWebhook posts delivery to webhook, signed with its secret. status
+	// is the status of the response, zero when there was none; any status
+	// but a 2xx one is an error.
+	SendWebhook(ctx context.Context, webhook domain.Webhook, delivery domain.WebhookDelivery) (status int, err error)
+}
@@ -0,0 +1,145 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/application/port/out"
+	"go-api-boilerplate/internal/domain"
+	"time"
+)
+
+// webhookLease is how long a claimed delivery stays with its worker before
+// other workers may attempt it again. It outlasts the sender's timeout.
+const webhookLease = time.Minute
+
+type WebhookService struct {
+	webhookRepo  out.WebhookRepository
+	sender       out.WebhookSender
+	maxAttempts  int
+	allowPrivate bool
+}
+
+var _ in.WebhookUseCase = &WebhookService{}
+
+// NewWebhookService returns a webhook service that attempts a delivery up to
+// maxAttempts times, backing off between attempts like jobs do. Webhook URLs
+// may name private addresses only when allowPrivate is set.
+func NewWebhookService(webhookRepo out.WebhookRepository, sender out.WebhookSender, maxAttempts int, allowPrivate bool) *WebhookService {
+	return &WebhookService{webhookRepo: webhookRepo, sender: sender, maxAttempts: maxAttempts, allowPrivate: allowPrivate}
+}
+
+func (s *WebhookService) CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	if err := webhook.Validate(s.allowPrivate); err != nil {
+		return domain.Webhook{}, err
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = rand.Text()
+	}
+	return s.webhookRepo.CreateWebhook(ctx, webhook)
+}
+
+func (s *WebhookService) GetWebhook(ctx context.Context, id int) (domain.Webhook, error) {
+	return s.webhookRepo.GetWebhook(ctx, id)
+}
+
+func (s *WebhookService) GetWebhooks(ctx context.Context, page, perPage int) ([]domain.Webhook, int, error) {
+	offset, limit := paginate(page, perPage)
+	webhooks, err := s.webhookRepo.GetWebhooks(ctx, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.webhookRepo.CountWebhooks(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return webhooks, total, nil
+}
+
+func (s *WebhookService) UpdateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	if err := webhook.Validate(s.allowPrivate); err != nil {
+		return domain.Webhook{}, err
+	}
+	if webhook.Secret == "" {
+		current, err := s.webhookRepo.GetWebhook(ctx, webhook.ID)
+		if err != nil {
+			return domain.Webhook{}, err
+		}
+		webhook.Secret = current.Secret
+	}
+	return s.webhookRepo.UpdateWebhook(ctx, webhook)
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id int) error {
+	return s.webhookRepo.DeleteWebhook(ctx, id)
+}
+
+func (s *WebhookService) GetWebhookDeliveries(ctx context.Context, id, page, perPage int) ([]domain.WebhookDelivery, int, error) {
+	if _, err := s.webhookRepo.GetWebhook(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	offset, limit := paginate(page, perPage)
+	deliveries, err := s.webhookRepo.GetWebhookDeliveries(ctx, id, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.webhookRepo.CountWebhookDeliveries(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// EnqueueWebhookDeliveries posts the whole message, so receivers get its ID
+// to tell redeliveries apart.
+func (s *WebhookService) EnqueueWebhookDeliveries(ctx context.Context, msg domain.OutboxMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return s.webhookRepo.AddWebhookDeliveries(ctx, domain.WebhookDelivery{MessageID: msg.ID, Event: msg.Type, Payload: body})
+}
+
+func (s *WebhookService) DeliverNextWebhook(ctx context.Context) (bool, error) {
+	delivery, ok, err := s.webhookRepo.ClaimWebhookDelivery(ctx, webhookLease)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	// A delivery claimed more often than allowed was never reported on: its
+	// workers died or stopped mid-attempt.
+	if delivery.Attempts > s.maxAttempts {
+		delivery.Error = fmt.Sprintf("abandoned after %d attempts", s.maxAttempts)
+		return true, s.webhookRepo.FailWebhookDelivery(ctx, delivery, nil)
+	}
+
+	webhook, err := s.webhookRepo.GetWebhook(ctx, delivery.WebhookID)
+	if errors.Is(err, domain.ErrWebhookNotFound) {
+		// Deleted since, along with its deliveries.
+		return true, nil
+	}
+	if err != nil {
+		return true, err
+	}
+
+	delivery.ResponseStatus, err = s.sender.SendWebhook(ctx, webhook, delivery)
+	if err == nil {
+		return true, s.webhookRepo.CompleteWebhookDelivery(ctx, delivery)
+	}
+	if ctx.Err() != nil {
+		// Stopping: the delivery is attempted again once its lease runs out.
+		return true, ctx.Err()
+	}
+
+	delivery.Error = err.Error()
+	var retryAt *time.Time
+	if delivery.Attempts < s.maxAttempts {
+		at := time.Now().Add(retryDelay(delivery.Attempts))
+		retryAt = &at
+	}
+	return true, s.webhookRepo.FailWebhookDelivery(ctx, delivery, retryAt)
+}
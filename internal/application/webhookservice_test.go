@@ -0,0 +1,275 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"go-api-boilerplate/mocks"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		webhook    domain.Webhook
+		setup      func(*mocks.MockWebhookRepository)
+		wantSecret string
+		wantErr    error
+	}{
+		{
+			name:    "keeps the given secret",
+			webhook: domain.Webhook{URL: "https://example.com/hooks", Events: []string{"book.created"}, Secret: "s3cret"},
+			setup: func(m *mocks.MockWebhookRepository) {
+				m.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, w domain.Webhook) (domain.Webhook, error) { return w, nil })
+			},
+			wantSecret: "s3cret",
+		},
+		{
+			name:    "generates a secret",
+			webhook: domain.Webhook{URL: "https://example.com/hooks"},
+			setup: func(m *mocks.MockWebhookRepository) {
+				m.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, w domain.Webhook) (domain.Webhook, error) { return w, nil })
+			},
+		},
+		{
+			name:    "invalid url",
+			webhook: domain.Webhook{URL: "ftp://example.com/hooks"},
+			setup:   func(m *mocks.MockWebhookRepository) {},
+			wantErr: domain.ErrWebhookURLInvalid,
+		},
+		{
+			name:    "unknown event",
+			webhook: domain.Webhook{URL: "https://example.com/hooks", Events: []string{"author.created"}},
+			setup:   func(m *mocks.MockWebhookRepository) {},
+			wantErr: domain.ErrUnknownEvent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockWebhookRepository(ctrl)
+			tt.setup(mockRepo)
+
+			s := NewWebhookService(mockRepo, mocks.NewMockWebhookSender(ctrl), 3, false)
+			got, err := s.CreateWebhook(context.Background(), tt.webhook)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WebhookService.CreateWebhook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if tt.wantSecret != "" && got.Secret != tt.wantSecret {
+				t.Errorf("WebhookService.CreateWebhook() secret = %q, want %q", got.Secret, tt.wantSecret)
+			}
+			if tt.wantSecret == "" && len(got.Secret) < 16 {
+				t.Errorf("WebhookService.CreateWebhook() secret = %q, want a generated one", got.Secret)
+			}
+		})
+	}
+}
+
+func TestWebhookService_UpdateWebhook(t *testing.T) {
+	current := domain.Webhook{ID: 1, URL: "https://example.com/old", Secret: "old"}
+
+	tests := []struct {
+		name    string
+		webhook domain.Webhook
+		setup   func(*mocks.MockWebhookRepository)
+		wantErr error
+	}{
+		{
+			name:    "keeps the secret when none is given",
+			webhook: domain.Webhook{ID: 1, URL: "https://example.com/new"},
+			setup: func(m *mocks.MockWebhookRepository) {
+				m.EXPECT().GetWebhook(gomock.Any(), 1).Return(current, nil)
+				m.EXPECT().UpdateWebhook(gomock.Any(), domain.Webhook{ID: 1, URL: "https://example.com/new", Secret: "old"}).
+					Return(domain.Webhook{ID: 1}, nil)
+			},
+		},
+		{
+			name:    "rotates the secret",
+			webhook: domain.Webhook{ID: 1, URL: "https://example.com/new", Secret: "new"},
+			setup: func(m *mocks.MockWebhookRepository) {
+				m.EXPECT().UpdateWebhook(gomock.Any(), domain.Webhook{ID: 1, URL: "https://example.com/new", Secret: "new"}).
+					Return(domain.Webhook{ID: 1}, nil)
+			},
+		},
+		{
+			name:    "unknown webhook",
+			webhook: domain.Webhook{ID: 9, URL: "https://example.com/new"},
+			setup: func(m *mocks.MockWebhookRepository) {
+				m.EXPECT().GetWebhook(gomock.Any(), 9).Return(domain.Webhook{}, domain.ErrWebhookNotFound)
+			},
+			wantErr: domain.ErrWebhookNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockWebhookRepository(ctrl)
+			tt.setup(mockRepo)
+
+			s := NewWebhookService(mockRepo, mocks.NewMockWebhookSender(ctrl), 3, false)
+			if _, err := s.UpdateWebhook(context.Background(), tt.webhook); !errors.Is(err, tt.wantErr) {
+				t.Errorf("WebhookService.UpdateWebhook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookService_EnqueueWebhookDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	msg := domain.OutboxMessage{ID: 7, Type: "book.created", Key: "book:1", Payload: json.RawMessage(`{"book":{"id":1}}`), CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	mockRepo := mocks.NewMockWebhookRepository(ctrl)
+	mockRepo.EXPECT().AddWebhookDeliveries(gomock.Any(), domain.WebhookDelivery{
+		MessageID: 7,
+		Event:     "book.created",
+		Payload:   json.RawMessage(`{"id":7,"type":"book.created","key":"book:1","payload":{"book":{"id":1}},"created_at":"2025-01-01T00:00:00Z"}`),
+	}).Return(nil)
+
+	s := NewWebhookService(mockRepo, mocks.NewMockWebhookSender(ctrl), 3, false)
+	if err := s.EnqueueWebhookDeliveries(context.Background(), msg); err != nil {
+		t.Errorf("WebhookService.EnqueueWebhookDeliveries() error = %v", err)
+	}
+}
+
+func TestWebhookService_DeliverNextWebhook(t *testing.T) {
+	errDB := errors.New("db error")
+	webhook := domain.Webhook{ID: 1, URL: "https://example.com/hooks", Secret: "s3cret"}
+	claimed := domain.WebhookDelivery{ID: 5, WebhookID: 1, Event: "book.created", Status: domain.DeliveryPending, Attempts: 1}
+
+	tests := []struct {
+		name          string
+		setup         func(*mocks.MockWebhookRepository, *mocks.MockWebhookSender)
+		wantDelivered bool
+		wantErr       error
+	}{
+		{
+			name: "nothing due",
+			setup: func(m *mocks.MockWebhookRepository, s *mocks.MockWebhookSender) {
+				m.EXPECT().ClaimWebhookDelivery(gomock.Any(), webhookLease).Return(domain.WebhookDelivery{}, false, nil)
+			},
+		},
+		{
+			name: "claim error",
+			setup: func(m *mocks.MockWebhookRepository, s *mocks.MockWebhookSender) {
+				m.EXPECT().ClaimWebhookDelivery(gomock.Any(), webhookLease).Return(domain.WebhookDelivery{}, false, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "success",
+			setup: func(m *mocks.MockWebhookRepository, s *mocks.MockWebhookSender) {
+				m.EXPECT().ClaimWebhookDelivery(gomock.Any(), webhookLease).Return(claimed, true, nil)
+				m.EXPECT().GetWebhook(gomock.Any(), 1).Return(webhook, nil)
+				s.EXPECT().SendWebhook(gomock.Any(), webhook, claimed).Return(204, nil)
+				done := claimed
+				done.ResponseStatus = 204
+				m.EXPECT().CompleteWebhookDelivery(gomock.Any(), done).Return(nil)
+			},
+			wantDelivered: true,
+		},
+		{
+			name: "failure is retried with backoff",
+			setup: func(m *mocks.MockWebhookRepository, s *mocks.MockWebhookSender) {
+				m.EXPECT().ClaimWebhookDelivery(gomock.Any(), webhookLease).Return(claimed, true, nil)
+				m.EXPECT().GetWebhook(gomock.Any(), 1).Return(webhook, nil)
+				s.EXPECT().SendWebhook(gomock.Any(), webhook, claimed).Return(500, errors.New("webhook responded 500 Internal Server Error"))
+				failed := claimed
+				failed.ResponseStatus, failed.Error = 500, "webhook responded 500 Internal Server Error"
+				m.EXPECT().FailWebhookDelivery(gomock.Any(), failed, gomock.Not(gomock.Nil())).
+					DoAndReturn(func(_ context.Context, _ domain.WebhookDelivery, retryAt *time.Time) error {
+						if d := time.Until(*retryAt); d <= 0 || d > jobRetryBase {
+							t.Errorf("FailWebhookDelivery() retry in %v, want within %v", d, jobRetryBase)
+						}
+						return nil
+					})
+			},
+			wantDelivered: true,
+		},
+		{
+			name: "last attempt is not retried",
+			setup: func(m *mocks.MockWebhookRepository, s *mocks.MockWebhookSender) {
+				last := claimed
+				last.Attempts = 3
+				m.EXPECT().ClaimWebhookDelivery(gomock.Any(), webhookLease).Return(last, true, nil)
+				m.EXPECT().GetWebhook(gomock.Any(), 1).Return(webhook, nil)
+				s.EXPECT().SendWebhook(gomock.Any(), webhook, last).Return(0, errors.New("connection refused"))
+				last.Error = "connection refused"
+				m.EXPECT().FailWebhookDelivery(gomock.Any(), last, nil).Return(nil)
+			},
+			wantDelivered: true,
+		},
+		{
+			name: "abandoned delivery fails without sending",
+			setup: func(m *mocks.MockWebhookRepository, s *mocks.MockWebhookSender) {
+				abandoned := claimed
+				abandoned.Attempts = 4
+				m.EXPECT().ClaimWebhookDelivery(gomock.Any(), webhookLease).Return(abandoned, true, nil)
+				abandoned.Error = "abandoned after 3 attempts"
+				m.EXPECT().FailWebhookDelivery(gomock.Any(), abandoned, nil).Return(nil)
+			},
+			wantDelivered: true,
+		},
+		{
+			name: "webhook deleted since",
+			setup: func(m *mocks.MockWebhookRepository, s *mocks.MockWebhookSender) {
+				m.EXPECT().ClaimWebhookDelivery(gomock.Any(), webhookLease).Return(claimed, true, nil)
+				m.EXPECT().GetWebhook(gomock.Any(), 1).Return(domain.Webhook{}, domain.ErrWebhookNotFound)
+			},
+			wantDelivered: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockWebhookRepository(ctrl)
+			mockSender := mocks.NewMockWebhookSender(ctrl)
+			tt.setup(mockRepo, mockSender)
+
+			s := NewWebhookService(mockRepo, mockSender, 3, false)
+			delivered, err := s.DeliverNextWebhook(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("WebhookService.DeliverNextWebhook() error = %v, want %v", err, tt.wantErr)
+			}
+			if delivered != tt.wantDelivered {
+				t.Errorf("WebhookService.DeliverNextWebhook() delivered = %v, want %v", delivered, tt.wantDelivered)
+			}
+		})
+	}
+}
+
+func TestWebhookService_GetWebhookDeliveries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	deliveries := []domain.WebhookDelivery{{ID: 2, WebhookID: 1}, {ID: 1, WebhookID: 1}}
+	mockRepo := mocks.NewMockWebhookRepository(ctrl)
+	mockRepo.EXPECT().GetWebhook(gomock.Any(), 1).Return(domain.Webhook{ID: 1}, nil)
+	mockRepo.EXPECT().GetWebhookDeliveries(gomock.Any(), 1, 10, 10).Return(deliveries, nil)
+	mockRepo.EXPECT().CountWebhookDeliveries(gomock.Any(), 1).Return(12, nil)
+
+	s := NewWebhookService(mockRepo, mocks.NewMockWebhookSender(ctrl), 3, false)
+	got, total, err := s.GetWebhookDeliveries(context.Background(), 1, 2, 10)
+	if err != nil {
+		t.Fatalf("WebhookService.GetWebhookDeliveries() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, deliveries) || total != 12 {
+		t.Errorf("WebhookService.GetWebhookDeliveries() = %v, %d, want %v, 12", got, total, deliveries)
+	}
+}
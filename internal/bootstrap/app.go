@@ -2,12 +2,21 @@ package bootstrap
 
 import (
 	"context"
+	"go-api-boilerplate/internal/adapter/events"
 	"go-api-boilerplate/internal/adapter/handlers"
+	"go-api-boilerplate/internal/adapter/messaging"
 	"go-api-boilerplate/internal/adapter/repositories"
+	"go-api-boilerplate/internal/adapter/webhooks"
 	"go-api-boilerplate/internal/application"
+	"go-api-boilerplate/internal/application/port/in"
 	"go-api-boilerplate/internal/config"
+	"go-api-boilerplate/internal/domain"
 	"go-api-boilerplate/internal/http/routes"
+	"go-api-boilerplate/internal/http/util"
 	"go-api-boilerplate/internal/infra"
+	"go-api-boilerplate/migrations"
+	"log"
+	"sync"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -16,6 +25,12 @@ import (
 type App struct {
 	Router *gin.Engine
 	db     *pgxpool.Pool
+	bus    *events.Bus
+	// messages is where the outbox is relayed to besides webhooks, nil when
+	// it is nowhere else.
+	messages *messaging.NDJSONPublisher
+	stop     context.CancelFunc
+	workers  *sync.WaitGroup
 }
 
 func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
@@ -24,10 +39,54 @@ func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
 		return nil, err
 	}
 
+	if cfg.Database.AutoMigrate {
+		migrator, err := infra.NewMigrator(db, migrations.FS)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		if err := migrator.Up(ctx); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	// Domain events. Subscribers reacting to catalog changes, such as cache
+	// invalidation, search indexing or notifications, register here.
+	bus := events.NewBus()
+	if cfg.Debug {
+		bus.Subscribe("log", events.Log)
+	}
+
+	messages, err := newMessagePublisher(cfg.Outbox)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	// Dependency Injection
 	bookRepo := repositories.NewPostgresBookRepo(db)
-	bookService := application.NewBookService(bookRepo)
-	bookHandler := handlers.NewBookHandler(bookService)
+	authorRepo := repositories.NewPostgresAuthorRepo(db)
+	txManager := repositories.NewPostgresTxManager(db)
+	historyRepo := repositories.NewPostgresBookHistoryRepo(db)
+	outboxRepo := repositories.NewPostgresOutboxRepo(db)
+	bookService := application.NewBookService(bookRepo, authorRepo, historyRepo, outboxRepo, txManager, bus)
+	authorService := application.NewAuthorService(authorRepo, bookRepo, historyRepo, outboxRepo, txManager, bus)
+	asOfService := application.NewBookAsOfService(historyRepo)
+	searchService := application.NewBookSearchService(repositories.NewPostgresBookSearch(db), cfg.Search.FuzzyThreshold)
+	pages := util.NewPaginator(util.PaginationStyle(cfg.HTTP.PaginationStyle))
+	bookHandler := handlers.NewBookHandler(bookService, asOfService, util.NewCursorCodec(cfg.HTTP.CursorSecret), pages)
+	authorHandler := handlers.NewAuthorHandler(authorService, pages)
+	searchHandler := handlers.NewSearchHandler(searchService, pages)
+	jobService := application.NewJobService(repositories.NewPostgresJobRepo(db), map[domain.JobKind]in.JobRunner{
+		domain.JobBookImport: handlers.NewBookImportJob(bookService),
+		domain.JobBookExport: handlers.NewBookExportJob(bookService),
+	}, cfg.Jobs.MaxAttempts)
+	importHandler := handlers.NewImportHandler(bookService, jobService)
+	jobHandler := handlers.NewJobHandler(jobService)
+	historyHandler := handlers.NewHistoryHandler(bookService, pages)
+	webhookService := application.NewWebhookService(repositories.NewPostgresWebhookRepo(db), webhooks.NewHTTPSender(cfg.Webhooks.AllowPrivate), cfg.Webhooks.MaxAttempts, cfg.Webhooks.AllowPrivate)
+	webhookHandler := handlers.NewWebhookHandler(webhookService, pages)
 
 	// Setup Router
 	router := gin.New()
@@ -35,11 +94,42 @@ func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
 	if cfg.Debug {
 		router.Use(gin.Logger())
 	}
-	routes.SetupRoutes(router, bookHandler)
+	routes.SetupRoutes(router, cfg, bookHandler, authorHandler, searchHandler, importHandler, jobHandler, historyHandler, webhookHandler)
 
-	return &App{Router: router, db: db}, nil
+	// Background jobs
+	jobCtx, stop := context.WithCancel(context.Background())
+	workers := &sync.WaitGroup{}
+	if retention := cfg.Trash.Retention(); retention > 0 {
+		workers.Go(func() { runTrashRetention(jobCtx, bookService, retention, trashPurgeInterval) })
+	}
+	for range cfg.Jobs.Workers {
+		workers.Go(func() { runJobWorker(jobCtx, jobService, jobPollInterval) })
+	}
+	for range cfg.Webhooks.Workers {
+		workers.Go(func() { runWebhookWorker(jobCtx, webhookService, webhookPollInterval) })
+	}
+	publisher := messaging.Fanout{messaging.NewWebhookPublisher(webhookService)}
+	if messages != nil {
+		publisher = append(publisher, messages)
+	}
+	relay := application.NewOutboxRelay(outboxRepo, publisher, txManager, cfg.Outbox.MaxAttempts)
+	workers.Go(func() { runOutboxRelay(jobCtx, relay, outboxPollInterval) })
+
+	return &App{Router: router, db: db, bus: bus, messages: messages, stop: stop, workers: workers}, nil
 }
 
+// Close stops the trash purge, the job workers, the outbox relay and the
+// webhook workers, waiting for them to hand back what they were handling,
+// lets the asynchronous event subscribers catch up, and closes the outbox
+// file and the database pool.
 func (a *App) Close() {
+	a.stop()
+	a.workers.Wait()
+	a.bus.Close()
+	if a.messages != nil {
+		if err := a.messages.Close(); err != nil {
+			log.Printf("[OUTBOX_RELAY]: %v\n", err)
+		}
+	}
 	a.db.Close()
 }
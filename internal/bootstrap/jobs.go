@@ -0,0 +1,32 @@
+package bootstrap
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/in"
+	"log"
+	"time"
+)
+
+// jobPollInterval is how long an idle worker waits before looking for a job
+// again.
+const jobPollInterval = time.Second
+
+// runJobWorker runs jobs one after another, polling for new ones while there
+// are none, until ctx is cancelled.
+func runJobWorker(ctx context.Context, jobs in.JobUseCase, pollInterval time.Duration) {
+	for {
+		ran, err := jobs.RunNextJob(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[JOB_WORKER]: %v\n", err)
+		}
+		if ran && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
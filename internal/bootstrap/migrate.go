@@ -0,0 +1,78 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api-boilerplate/internal/config"
+	"go-api-boilerplate/internal/infra"
+	"go-api-boilerplate/migrations"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+const migrateUsage = "usage: migrate up|down|status|goto <version>"
+
+// RunMigrate executes the `migrate` subcommand with the given arguments.
+func RunMigrate(ctx context.Context, cfg *config.Config, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	db, err := infra.NewPostgresPool(ctx, cfg.Database.Postgres, cfg.Debug)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := infra.NewMigrator(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		if err := migrator.Up(ctx); err != nil {
+			return err
+		}
+	case "down":
+		if err := migrator.Down(ctx); err != nil {
+			return err
+		}
+	case "goto":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", args[1], err)
+		}
+		if err := migrator.Goto(ctx, version); err != nil {
+			return err
+		}
+	case "status":
+	default:
+		return errors.New(migrateUsage)
+	}
+
+	return printMigrationStatus(ctx, migrator, out)
+}
+
+func printMigrationStatus(ctx context.Context, migrator *infra.Migrator, out io.Writer) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, s := range statuses {
+		appliedAt := "pending"
+		if s.Applied {
+			appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,48 @@
+package bootstrap
+
+import (
+	"context"
+	"go-api-boilerplate/internal/adapter/messaging"
+	"go-api-boilerplate/internal/application/port/in"
+	"go-api-boilerplate/internal/config"
+	"log"
+	"os"
+	"time"
+)
+
+// outboxPollInterval is how long the relay waits before looking for messages
+// again once the outbox is drained.
+const outboxPollInterval = time.Second
+
+// newMessagePublisher returns the publisher cfg relays the outbox to besides
+// webhooks, or nil when there is none.
+func newMessagePublisher(cfg config.Outbox) (*messaging.NDJSONPublisher, error) {
+	switch {
+	case cfg.Publisher != "ndjson":
+		return nil, nil
+	case cfg.File == "":
+		return messaging.NewNDJSONPublisher(os.Stdout), nil
+	default:
+		return messaging.OpenNDJSONPublisher(cfg.File)
+	}
+}
+
+// runOutboxRelay relays outbox messages batch after batch, polling for new
+// ones while there are none, until ctx is cancelled.
+func runOutboxRelay(ctx context.Context, relay in.OutboxRelayUseCase, pollInterval time.Duration) {
+	for {
+		relayed, err := relay.RelayMessages(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[OUTBOX_RELAY]: %v\n", err)
+		}
+		if relayed > 0 && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
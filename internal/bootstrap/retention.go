@@ -0,0 +1,32 @@
+package bootstrap
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/in"
+	"log"
+	"time"
+)
+
+const trashPurgeInterval = time.Hour
+
+// runTrashRetention purges expired books from the trash right away and then
+// once per interval until ctx is cancelled.
+func runTrashRetention(ctx context.Context, books in.BookUseCase, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := books.PurgeExpiredBooks(ctx, retention)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[TRASH_RETENTION]: %v\n", err)
+		} else if purged > 0 {
+			log.Printf("[TRASH_RETENTION]: purged %d book(s)\n", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package bootstrap
+
+import (
+	"context"
+	"go-api-boilerplate/internal/application/port/in"
+	"log"
+	"time"
+)
+
+// webhookPollInterval is how long an idle worker waits before looking for a
+// due delivery again.
+const webhookPollInterval = time.Second
+
+// runWebhookWorker attempts deliveries one after another, polling for due
+// ones while there are none, until ctx is cancelled.
+func runWebhookWorker(ctx context.Context, webhooks in.WebhookUseCase, pollInterval time.Duration) {
+	for {
+		delivered, err := webhooks.DeliverNextWebhook(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[WEBHOOK_WORKER]: %v\n", err)
+		}
+		if delivered && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package config
+
+// Admin configures access to the /admin endpoints. They are disabled while
+// Token is empty.
+type Admin struct {
+	Token string `mapstructure:"ADMIN_TOKEN"`
+}
@@ -1,12 +1,21 @@
 package config
 
 import (
+	"fmt"
+
 	"github.com/spf13/viper"
 )
 
 type Config struct {
 	Debug    bool
 	Database Database
+	HTTP     HTTP
+	Admin    Admin
+	Trash    Trash
+	Search   Search
+	Jobs     Jobs
+	Outbox   Outbox
+	Webhooks Webhooks
 }
 
 func LoadConfig() (*Config, error) {
@@ -18,6 +27,47 @@ func LoadConfig() (*Config, error) {
 
 	_ = viper.ReadInConfig()
 
+	viper.SetDefault("PAGINATION_STYLE", "headers")
+	switch style := viper.GetString("PAGINATION_STYLE"); style {
+	case "headers", "envelope":
+	default:
+		return nil, fmt.Errorf("PAGINATION_STYLE must be headers or envelope, got %q", style)
+	}
+
+	viper.SetDefault("SEARCH_FUZZY_THRESHOLD", 0.5)
+	if t := viper.GetFloat64("SEARCH_FUZZY_THRESHOLD"); t <= 0 || t > 1 {
+		return nil, fmt.Errorf("SEARCH_FUZZY_THRESHOLD must be above 0 and at most 1, got %v", t)
+	}
+
+	viper.SetDefault("JOB_WORKERS", 2)
+	if n := viper.GetInt("JOB_WORKERS"); n < 0 {
+		return nil, fmt.Errorf("JOB_WORKERS must not be negative, got %d", n)
+	}
+	viper.SetDefault("JOB_MAX_ATTEMPTS", 3)
+	if n := viper.GetInt("JOB_MAX_ATTEMPTS"); n < 1 {
+		return nil, fmt.Errorf("JOB_MAX_ATTEMPTS must be at least 1, got %d", n)
+	}
+
+	viper.SetDefault("OUTBOX_PUBLISHER", "none")
+	switch publisher := viper.GetString("OUTBOX_PUBLISHER"); publisher {
+	case "none", "ndjson":
+	default:
+		return nil, fmt.Errorf("OUTBOX_PUBLISHER must be none or ndjson, got %q", publisher)
+	}
+	viper.SetDefault("OUTBOX_MAX_ATTEMPTS", 10)
+	if n := viper.GetInt("OUTBOX_MAX_ATTEMPTS"); n < 1 {
+		return nil, fmt.Errorf("OUTBOX_MAX_ATTEMPTS must be at least 1, got %d", n)
+	}
+
+	viper.SetDefault("WEBHOOK_WORKERS", 2)
+	if n := viper.GetInt("WEBHOOK_WORKERS"); n < 0 {
+		return nil, fmt.Errorf("WEBHOOK_WORKERS must not be negative, got %d", n)
+	}
+	viper.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	if n := viper.GetInt("WEBHOOK_MAX_ATTEMPTS"); n < 1 {
+		return nil, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be at least 1, got %d", n)
+	}
+
 	return &Config{
 		Debug: viper.GetBool("DEBUG"),
 		Database: Database{
@@ -29,6 +79,35 @@ func LoadConfig() (*Config, error) {
 				DBName:   viper.GetString("POSTGRES_DBNAME"),
 				Schema:   viper.GetString("POSTGRES_SCHEMA"),
 			},
+			AutoMigrate: viper.GetBool("DB_AUTO_MIGRATE"),
+		},
+		HTTP: HTTP{
+			RequireIfMatch:  viper.GetBool("REQUIRE_IF_MATCH"),
+			CursorSecret:    viper.GetString("CURSOR_SECRET"),
+			PaginationStyle: viper.GetString("PAGINATION_STYLE"),
+		},
+		Admin: Admin{
+			Token: viper.GetString("ADMIN_TOKEN"),
+		},
+		Trash: Trash{
+			RetentionDays: viper.GetInt("TRASH_RETENTION_DAYS"),
+		},
+		Search: Search{
+			FuzzyThreshold: viper.GetFloat64("SEARCH_FUZZY_THRESHOLD"),
+		},
+		Jobs: Jobs{
+			Workers:     viper.GetInt("JOB_WORKERS"),
+			MaxAttempts: viper.GetInt("JOB_MAX_ATTEMPTS"),
+		},
+		Outbox: Outbox{
+			Publisher:   viper.GetString("OUTBOX_PUBLISHER"),
+			File:        viper.GetString("OUTBOX_FILE"),
+			MaxAttempts: viper.GetInt("OUTBOX_MAX_ATTEMPTS"),
+		},
+		Webhooks: Webhooks{
+			Workers:      viper.GetInt("WEBHOOK_WORKERS"),
+			MaxAttempts:  viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+			AllowPrivate: viper.GetBool("WEBHOOK_ALLOW_PRIVATE"),
 		},
 	}, nil
 }
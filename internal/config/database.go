@@ -1,7 +1,8 @@
 package config
 
 type Database struct {
-	Postgres Postgres `mapstructure:"postgres"`
+	Postgres    Postgres `mapstructure:"postgres"`
+	AutoMigrate bool     `mapstructure:"DB_AUTO_MIGRATE"`
 }
 
 type Postgres struct {
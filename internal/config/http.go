@@ -0,0 +1,11 @@
+package config
+
+// HTTP configures request handling. With RequireIfMatch set, updates and
+// deletes must send an If-Match header. CursorSecret signs pagination
+// cursors; when empty a random secret is used for the life of the process.
+// PaginationStyle is "headers" or "envelope", see util.PaginationStyle.
+type HTTP struct {
+	RequireIfMatch  bool   `mapstructure:"REQUIRE_IF_MATCH"`
+	CursorSecret    string `mapstructure:"CURSOR_SECRET"`
+	PaginationStyle string `mapstructure:"PAGINATION_STYLE"`
+}
@@ -0,0 +1,10 @@
+package config
+
+// Jobs configures background jobs. Workers is the number of jobs this process
+// runs at once; with zero it runs none and leaves them to other instances. A
+// failed job is retried with backoff until it has been attempted MaxAttempts
+// times.
+type Jobs struct {
+	Workers     int `mapstructure:"JOB_WORKERS"`
+	MaxAttempts int `mapstructure:"JOB_MAX_ATTEMPTS"`
+}
@@ -0,0 +1,13 @@
+package config
+
+// Outbox configures relaying the outbox to other services. Messages are
+// always queued for the webhooks subscribed to them, and Publisher is where
+// else they go: with ndjson they are written as lines of JSON to File, or to
+// stdout when File is empty; with none nowhere else. A message that fails to
+// publish is retried with backoff until it has been attempted MaxAttempts
+// times, then dead-lettered.
+type Outbox struct {
+	Publisher   string `mapstructure:"OUTBOX_PUBLISHER"`
+	File        string `mapstructure:"OUTBOX_FILE"`
+	MaxAttempts int    `mapstructure:"OUTBOX_MAX_ATTEMPTS"`
+}
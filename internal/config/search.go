@@ -0,0 +1,8 @@
+package config
+
+// Search configures book search. FuzzyThreshold is the trigram word
+// similarity, between 0 and 1, that a title or author must reach to match a
+// fuzzy search.
+type Search struct {
+	FuzzyThreshold float64 `mapstructure:"SEARCH_FUZZY_THRESHOLD"`
+}
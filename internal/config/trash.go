@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+// Trash configures how long soft-deleted books are kept. A RetentionDays of
+// zero keeps them until they are restored or purged by hand.
+type Trash struct {
+	RetentionDays int `mapstructure:"TRASH_RETENTION_DAYS"`
+}
+
+func (t Trash) Retention() time.Duration {
+	return time.Duration(t.RetentionDays) * 24 * time.Hour
+}
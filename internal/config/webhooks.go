@@ -0,0 +1,13 @@
+package config
+
+// Webhooks configures delivering webhooks. Workers is the number of
+// deliveries this process attempts at once; with zero it attempts none and
+// leaves them to other instances. A failed delivery is retried with backoff
+// until it has been attempted MaxAttempts times. AllowPrivate lets webhooks
+// point at loopback, link-local and private addresses, which is only meant
+// for local development and tests.
+type Webhooks struct {
+	Workers      int  `mapstructure:"WEBHOOK_WORKERS"`
+	MaxAttempts  int  `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	AllowPrivate bool `mapstructure:"WEBHOOK_ALLOW_PRIVATE"`
+}
@@ -5,12 +5,24 @@ import "errors"
 var (
 	ErrValidation = errors.New("validation error")
 	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+
+	// Errors of the middlewares guarding routes.
+	ErrIfMatchRequired   = errors.New("the If-Match header is required")
+	ErrAdminDisabled     = errors.New("admin access is disabled")
+	ErrInvalidAdminToken = errors.New("invalid admin token")
 )
 
 type ErrorCode string
 
 const (
-	ErrValidationCode      ErrorCode = "VALIDATION_ERROR"
-	ErrNotFoundCode        ErrorCode = "NOT_FOUND"
-	ErrInternalServerError ErrorCode = "INTERNAL_SERVER_ERROR"
+	ErrValidationCode           ErrorCode = "VALIDATION_ERROR"
+	ErrNotFoundCode             ErrorCode = "NOT_FOUND"
+	ErrConflictCode             ErrorCode = "CONFLICT"
+	ErrUnauthorizedCode         ErrorCode = "UNAUTHORIZED"
+	ErrForbiddenCode            ErrorCode = "FORBIDDEN"
+	ErrPreconditionFailedCode   ErrorCode = "PRECONDITION_FAILED"
+	ErrPreconditionRequiredCode ErrorCode = "PRECONDITION_REQUIRED"
+	ErrUnsupportedMediaTypeCode ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	ErrInternalServerError      ErrorCode = "INTERNAL_SERVER_ERROR"
 )
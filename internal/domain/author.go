@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrAuthorNameRequired = errors.New("name is required")
+	ErrAuthorNotFound     = errors.New("author not found")
+	ErrAuthorHasBooks     = errors.New("author is still linked to books")
+	ErrUnknownAuthor      = errors.New("a linked author does not exist")
+)
+
+type Author struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks the author's fields and returns a *ValidationError listing
+// every failed rule.
+func (a *Author) Validate() error {
+	var verr ValidationError
+	if strings.TrimSpace(a.Name) == "" {
+		verr.add("name", "required", ErrAuthorNameRequired)
+	}
+	return verr.orNil()
+}
+
+// Byline joins the author names in order, e.g. "Neil Gaiman, Terry Pratchett".
+func Byline(authors []Author) string {
+	names := make([]string, len(authors))
+	for i, a := range authors {
+		names[i] = a.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,30 @@
+package domain
+
+// BatchMode decides what happens to a batch write when some of its items
+// fail.
+type BatchMode string
+
+const (
+	// BatchAllOrNothing writes nothing unless every item succeeds.
+	BatchAllOrNothing BatchMode = "all_or_nothing"
+	// BatchBestEffort writes the items that succeed and reports the others.
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+// BatchResult is the outcome of one item of a batch write. Err is set when
+// the item failed. Otherwise Book is the stored book, or the zero Book when
+// the item was valid but an all-or-nothing batch was rolled back.
+type BatchResult struct {
+	Book Book
+	Err  error
+}
+
+// AnyFailed reports whether any item of results failed.
+func AnyFailed(results []BatchResult) bool {
+	for _, r := range results {
+		if r.Err != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -2,26 +2,111 @@ package domain
 
 import (
 	"errors"
+	"time"
 )
 
 var (
-	ErrTitleRequired  = errors.New("title is required")
-	ErrAuthorRequired = errors.New("author is required")
-	ErrBookNotFound   = errors.New("book not found")
+	ErrTitleRequired   = errors.New("title is required")
+	ErrAuthorRequired  = errors.New("author is required")
+	ErrDuplicateAuthor = errors.New("an author is listed more than once")
+	ErrInvalidISBN     = errors.New("isbn is invalid")
+	ErrBookNotFound    = errors.New("book not found")
+	ErrBookNotInTrash  = errors.New("book not found in trash")
+	ErrDuplicateISBN   = errors.New("a book with this isbn already exists")
+	ErrVersionConflict = errors.New("book has been modified since it was read")
 )
 
+// Book is a catalog entry. Authors holds the linked authors in byline order,
+// and Author is the byline text derived from them. Version is bumped on every
+// write, and DeletedAt is set while the book sits in the trash.
 type Book struct {
-	ID     int    `json:"id"`
-	Title  string `json:"title"`
-	Author string `json:"author"`
+	ID        int        `json:"id"`
+	Title     string     `json:"title"`
+	Author    string     `json:"author"`
+	Authors   []Author   `json:"authors"`
+	ISBN      string     `json:"isbn,omitempty"`
+	Version   int        `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
+// Validate checks the book's fields and returns a *ValidationError listing
+// every failed rule. A valid non-empty ISBN is normalized to its bare ISBN-13
+// form.
 func (b *Book) Validate() error {
+	var verr ValidationError
 	if b.Title == "" {
-		return ErrTitleRequired
+		verr.add("title", "required", ErrTitleRequired)
 	}
-	if b.Author == "" {
-		return ErrAuthorRequired
+	if b.Author == "" && len(b.Authors) == 0 {
+		verr.add("author", "required", ErrAuthorRequired)
 	}
-	return nil
+	seen := make(map[int]bool, len(b.Authors))
+	for _, a := range b.Authors {
+		if seen[a.ID] {
+			verr.add("author_ids", "unique", ErrDuplicateAuthor)
+			break
+		}
+		seen[a.ID] = true
+	}
+	if b.ISBN != "" {
+		isbn, err := NormalizeISBN(b.ISBN)
+		if err != nil {
+			verr.add("isbn", "isbn", err)
+		} else {
+			b.ISBN = isbn
+		}
+	}
+	return verr.orNil()
+}
+
+// SetAuthors links the given authors and derives the byline from them.
+func (b *Book) SetAuthors(authors []Author) {
+	b.Authors = authors
+	b.Author = Byline(authors)
+}
+
+// AuthorIDs returns the IDs of the linked authors in order.
+func (b *Book) AuthorIDs() []int {
+	ids := make([]int, len(b.Authors))
+	for i, a := range b.Authors {
+		ids[i] = a.ID
+	}
+	return ids
+}
+
+// BookPatch is a partial update of a book. Nil fields are left unchanged.
+// Setting Author or Authors replaces the book's authors as a whole; Authors
+// takes precedence when both are set.
+type BookPatch struct {
+	Title   *string
+	Author  *string
+	Authors []Author
+	ISBN    *string
+}
+
+// IsEmpty reports whether the patch changes nothing.
+func (p BookPatch) IsEmpty() bool {
+	return p.Title == nil && p.Author == nil && p.Authors == nil && p.ISBN == nil
+}
+
+// Apply returns a copy of b with the patch applied. The result still needs
+// to be validated.
+func (p BookPatch) Apply(b Book) Book {
+	if p.Title != nil {
+		b.Title = *p.Title
+	}
+	switch {
+	case p.Authors != nil:
+		b.Author = ""
+		b.Authors = p.Authors
+	case p.Author != nil:
+		b.Author = *p.Author
+		b.Authors = nil
+	}
+	if p.ISBN != nil {
+		b.ISBN = *p.ISBN
+	}
+	return b
 }
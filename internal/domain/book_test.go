@@ -1,39 +1,164 @@
 package domain
 
-import "testing"
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
 
 func TestBook_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		b          *Book
+		wantFields []FieldError
+	}{
+		{
+			name:       "valid book",
+			b:          &Book{Title: "Test Book", Author: "Test Author"},
+			wantFields: nil,
+		},
+		{
+			name:       "empty title",
+			b:          &Book{Title: "", Author: "Test Author"},
+			wantFields: []FieldError{{Field: "title", Rule: "required", Err: ErrTitleRequired}},
+		},
+		{
+			name:       "empty author",
+			b:          &Book{Title: "Test Book", Author: ""},
+			wantFields: []FieldError{{Field: "author", Rule: "required", Err: ErrAuthorRequired}},
+		},
+		{
+			name:       "valid book with isbn",
+			b:          &Book{Title: "Test Book", Author: "Test Author", ISBN: "0-7432-7356-7"},
+			wantFields: nil,
+		},
+		{
+			name:       "invalid isbn",
+			b:          &Book{Title: "Test Book", Author: "Test Author", ISBN: "0-7432-7356-8"},
+			wantFields: []FieldError{{Field: "isbn", Rule: "isbn", Err: ErrInvalidISBN}},
+		},
+		{
+			name: "every failed rule is reported",
+			b:    &Book{Title: "", Author: "", ISBN: "123"},
+			wantFields: []FieldError{
+				{Field: "title", Rule: "required", Err: ErrTitleRequired},
+				{Field: "author", Rule: "required", Err: ErrAuthorRequired},
+				{Field: "isbn", Rule: "isbn", Err: ErrInvalidISBN},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.b.Validate()
+			if tt.wantFields == nil {
+				if err != nil {
+					t.Errorf("Book.Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Book.Validate() error = %v, want *ValidationError", err)
+			}
+			if !reflect.DeepEqual(verr.Fields, tt.wantFields) {
+				t.Errorf("Book.Validate() fields = %v, want %v", verr.Fields, tt.wantFields)
+			}
+			for _, f := range tt.wantFields {
+				if !errors.Is(err, f.Err) {
+					t.Errorf("Book.Validate() error does not match %v", f.Err)
+				}
+			}
+		})
+	}
+}
+
+func TestBook_Validate_NormalizesISBN(t *testing.T) {
+	b := &Book{Title: "Test Book", Author: "Test Author", ISBN: "0-7432-7356-7"}
+	if err := b.Validate(); err != nil {
+		t.Fatalf("Book.Validate() error = %v", err)
+	}
+	if b.ISBN != "9780743273565" {
+		t.Errorf("Book.Validate() ISBN = %v, want %v", b.ISBN, "9780743273565")
+	}
+}
+
+func TestBook_Validate_Authors(t *testing.T) {
 	tests := []struct {
 		name    string
 		b       *Book
 		wantErr error
 	}{
 		{
-			name:    "valid book",
-			b:       &Book{Title: "Test Book", Author: "Test Author"},
+			name:    "linked authors without byline",
+			b:       &Book{Title: "Test Book", Authors: []Author{{ID: 1}, {ID: 2}}},
 			wantErr: nil,
 		},
 		{
-			name:    "empty title",
-			b:       &Book{Title: "", Author: "Test Author"},
-			wantErr: ErrTitleRequired,
+			name:    "duplicate author",
+			b:       &Book{Title: "Test Book", Authors: []Author{{ID: 1}, {ID: 1}}},
+			wantErr: ErrDuplicateAuthor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.b.Validate()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Book.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBook_SetAuthors(t *testing.T) {
+	b := &Book{Title: "Good Omens"}
+	b.SetAuthors([]Author{{ID: 2, Name: "Neil Gaiman"}, {ID: 1, Name: "Terry Pratchett"}})
+	if b.Author != "Neil Gaiman, Terry Pratchett" {
+		t.Errorf("Book.SetAuthors() Author = %v, want %v", b.Author, "Neil Gaiman, Terry Pratchett")
+	}
+}
+
+func TestBookPatch_Apply(t *testing.T) {
+	title := "Animal Farm"
+	author := "Eric Blair"
+	isbn := ""
+	orwell := Author{ID: 1, Name: "George Orwell"}
+	book := Book{ID: 1, Title: "1984", Author: "George Orwell", Authors: []Author{orwell}, ISBN: "9780451524935", Version: 3}
+
+	tests := []struct {
+		name  string
+		patch BookPatch
+		want  Book
+	}{
+		{
+			name:  "empty patch",
+			patch: BookPatch{},
+			want:  book,
 		},
 		{
-			name:    "empty author",
-			b:       &Book{Title: "Test Book", Author: ""},
-			wantErr: ErrAuthorRequired,
+			name:  "title only",
+			patch: BookPatch{Title: &title},
+			want:  Book{ID: 1, Title: "Animal Farm", Author: "George Orwell", Authors: []Author{orwell}, ISBN: "9780451524935", Version: 3},
 		},
 		{
-			name:    "empty title and author",
-			b:       &Book{Title: "", Author: ""},
-			wantErr: ErrTitleRequired,
+			name:  "author name replaces linked authors",
+			patch: BookPatch{Author: &author},
+			want:  Book{ID: 1, Title: "1984", Author: "Eric Blair", ISBN: "9780451524935", Version: 3},
+		},
+		{
+			name:  "author ids replace linked authors",
+			patch: BookPatch{Authors: []Author{{ID: 2}}},
+			want:  Book{ID: 1, Title: "1984", Authors: []Author{{ID: 2}}, ISBN: "9780451524935", Version: 3},
+		},
+		{
+			name:  "clear isbn",
+			patch: BookPatch{ISBN: &isbn},
+			want:  Book{ID: 1, Title: "1984", Author: "George Orwell", Authors: []Author{orwell}, Version: 3},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.b.Validate()
-			if err != tt.wantErr {
-				t.Errorf("Book.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			if got := tt.patch.Apply(book); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookPatch.Apply() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrInvalidSort = errors.New("sort must list sortable fields, each at most once")
+
+// BookSortFields are the fields the book list can be sorted by.
+var BookSortFields = []string{"id", "title", "author", "created_at", "updated_at"}
+
+// SortField orders a list by one field, descending when Desc is set.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// BookSort orders the book list by its fields in turn. Books that tie on
+// every field are ordered by ascending ID, so the order is total. A nil
+// BookSort is the default order by ID.
+type BookSort []SortField
+
+// ParseBookSort reads a comma-separated list of fields, each prefixed with
+// "-" for descending order, such as "-created_at,title". Only BookSortFields
+// are accepted.
+func ParseBookSort(s string) (BookSort, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var sort BookSort
+	for _, part := range strings.Split(s, ",") {
+		f := SortField{Field: strings.TrimSpace(part)}
+		if field, ok := strings.CutPrefix(f.Field, "-"); ok {
+			f.Field, f.Desc = field, true
+		}
+		if !slices.Contains(BookSortFields, f.Field) || sort.has(f.Field) {
+			var verr ValidationError
+			verr.add("sort", "sort", ErrInvalidSort)
+			return nil, &verr
+		}
+		sort = append(sort, f)
+	}
+	return sort, nil
+}
+
+// String formats the sort the way ParseBookSort reads it.
+func (s BookSort) String() string {
+	if len(s) == 0 {
+		return SortByID
+	}
+	parts := make([]string, len(s))
+	for i, f := range s {
+		parts[i] = f.Field
+		if f.Desc {
+			parts[i] = "-" + f.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Keys returns the fields that decide the order: the sort fields followed by
+// id unless it is one of them.
+func (s BookSort) Keys() BookSort {
+	if s.has(SortByID) {
+		return s
+	}
+	return append(slices.Clip(s), SortField{Field: SortByID})
+}
+
+func (s BookSort) has(field string) bool {
+	return slices.ContainsFunc(s, func(f SortField) bool { return f.Field == field })
+}
+
+// BookCriteria selects and orders the live books of a list. Zero fields do
+// not filter.
+type BookCriteria struct {
+	// Author matches books linked to an author of that name, ignoring case.
+	Author string
+	// TitleContains matches books whose title contains it, ignoring case.
+	TitleContains string
+	// CreatedAfter and CreatedBefore bound the creation time, exclusively.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	Sort          BookSort
+}
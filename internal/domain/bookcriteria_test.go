@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseBookSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		sort    string
+		want    BookSort
+		wantErr error
+	}{
+		{
+			name: "empty",
+			sort: "",
+			want: nil,
+		},
+		{
+			name: "mixed directions",
+			sort: "-created_at,title",
+			want: BookSort{{Field: "created_at", Desc: true}, {Field: "title"}},
+		},
+		{
+			name: "spaces around fields",
+			sort: "author, -id",
+			want: BookSort{{Field: "author"}, {Field: "id", Desc: true}},
+		},
+		{
+			name:    "unsortable field",
+			sort:    "isbn",
+			wantErr: ErrInvalidSort,
+		},
+		{
+			name:    "field listed twice",
+			sort:    "title,-title",
+			wantErr: ErrInvalidSort,
+		},
+		{
+			name:    "empty field",
+			sort:    "title,",
+			wantErr: ErrInvalidSort,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBookSort(tt.sort)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseBookSort() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseBookSort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookCursor(t *testing.T) {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 600, time.FixedZone("CET", 3600))
+	b := Book{ID: 7, Title: "1984", CreatedAt: created}
+
+	tests := []struct {
+		name string
+		sort BookSort
+		want Cursor
+	}{
+		{
+			name: "default sort",
+			sort: nil,
+			want: Cursor{Sort: SortByID, Key: []string{"7"}},
+		},
+		{
+			name: "id tie-breaker is appended",
+			sort: BookSort{{Field: "created_at", Desc: true}, {Field: "title"}},
+			want: Cursor{Sort: "-created_at,title", Key: []string{"2025-01-02T02:04:05.0000006Z", "1984", "7"}},
+		},
+		{
+			name: "explicit id",
+			sort: BookSort{{Field: "id", Desc: true}},
+			want: Cursor{Sort: "-id", Key: []string{"7"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BookCursor(b, tt.sort, false); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookCursor() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
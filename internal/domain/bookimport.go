@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ImportRow is one row of a book import. Line is where the row starts in the
+// source file, and Err is set when the row could not be read as a book.
+type ImportRow struct {
+	Line int
+	Book Book
+	Err  error
+}
+
+type ImportStatus string
+
+const (
+	ImportInserted ImportStatus = "inserted"
+	// ImportSkipped marks a row whose ISBN is already in the catalog, or
+	// earlier in the same import.
+	ImportSkipped ImportStatus = "skipped"
+	ImportFailed  ImportStatus = "failed"
+)
+
+// ImportRowResult is the outcome of one row of an import. BookID is zero
+// unless the row was inserted for real, and Err is set unless it was inserted.
+type ImportRowResult struct {
+	Line   int
+	Status ImportStatus
+	BookID int
+	Err    error
+}
+
+// ImportInterruptedError is an import that failed after writing the rows up
+// to Line, which stay written. Running it again from the start would insert
+// those rows again unless their ISBNs are set.
+type ImportInterruptedError struct {
+	Line int
+	Err  error
+}
+
+func (e *ImportInterruptedError) Error() string {
+	return fmt.Sprintf("import interrupted after line %d: %v", e.Line, e.Err)
+}
+
+func (e *ImportInterruptedError) Unwrap() error { return e.Err }
+
+// ImportReport sums up an import row by row. In a dry run nothing is written,
+// and Inserted counts the rows that would have been inserted.
+type ImportReport struct {
+	DryRun   bool
+	Inserted int
+	Skipped  int
+	Failed   int
+	Rows     []ImportRowResult
+}
+
+// Add records the outcome of the row at line, given as the result of writing
+// its book in a batch.
+func (r *ImportReport) Add(line int, result BatchResult) {
+	row := ImportRowResult{Line: line, BookID: result.Book.ID, Err: result.Err}
+	switch {
+	case result.Err == nil:
+		row.Status = ImportInserted
+		r.Inserted++
+	case errors.Is(result.Err, ErrDuplicateISBN):
+		row.Status = ImportSkipped
+		r.Skipped++
+	default:
+		row.Status = ImportFailed
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, row)
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestImportReport_Add(t *testing.T) {
+	errParse := errors.New("bare quote")
+
+	var r ImportReport
+	r.Add(2, BatchResult{Book: Book{ID: 7}})
+	r.Add(3, BatchResult{Err: ErrDuplicateISBN})
+	r.Add(4, BatchResult{Err: &ValidationError{Fields: []FieldError{{Field: "isbn", Rule: "isbn", Err: ErrDuplicateISBN}}}})
+	r.Add(5, BatchResult{Err: errParse})
+	r.Add(6, BatchResult{})
+
+	want := ImportReport{
+		Inserted: 2,
+		Skipped:  2,
+		Failed:   1,
+		Rows: []ImportRowResult{
+			{Line: 2, Status: ImportInserted, BookID: 7},
+			{Line: 3, Status: ImportSkipped, Err: ErrDuplicateISBN},
+			{Line: 4, Status: ImportSkipped, Err: r.Rows[2].Err},
+			{Line: 5, Status: ImportFailed, Err: errParse},
+			{Line: 6, Status: ImportInserted},
+		},
+	}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("ImportReport = %+v, want %+v", r, want)
+	}
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("cursor is invalid")
+
+// SortByID orders books by ascending ID, the default list order. It is the
+// String of a nil BookSort.
+const SortByID = "id"
+
+// Cursor marks a position in a list ordered by Sort. Key holds the sort
+// values of the row at the page boundary, and Backward selects the rows
+// before that row instead of the rows after it. A zero Cursor starts at the
+// beginning of the list.
+type Cursor struct {
+	Sort     string
+	Key      []string
+	Backward bool
+}
+
+// IsStart reports whether the cursor points at the beginning of the list.
+func (c Cursor) IsStart() bool {
+	return len(c.Key) == 0
+}
+
+// BookCursor returns a cursor next to b in a list ordered by sort. The key
+// holds b's value of every field in sort.Keys().
+func BookCursor(b Book, sort BookSort, backward bool) Cursor {
+	keys := sort.Keys()
+	key := make([]string, len(keys))
+	for i, f := range keys {
+		switch f.Field {
+		case "id":
+			key[i] = strconv.Itoa(b.ID)
+		case "title":
+			key[i] = b.Title
+		case "author":
+			key[i] = b.Author
+		case "created_at":
+			key[i] = b.CreatedAt.UTC().Format(time.RFC3339Nano)
+		case "updated_at":
+			key[i] = b.UpdatedAt.UTC().Format(time.RFC3339Nano)
+		}
+	}
+	return Cursor{Sort: sort.String(), Key: key, Backward: backward}
+}
+
+// BookPage is a page of books with the cursors of its neighbouring pages. A
+// nil cursor means there is no page in that direction.
+type BookPage struct {
+	Books []Book
+	Next  *Cursor
+	Prev  *Cursor
+}
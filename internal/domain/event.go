@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"slices"
+	"strconv"
+)
+
+// Event is a change to the catalog, published once it has been committed.
+type Event interface {
+	// EventName names the kind of event, such as "book.created".
+	EventName() string
+	// EventKey names what the event is about, such as "book:42". Events
+	// with the same key must be handled in the order they were published.
+	EventKey() string
+}
+
+// BookCreated is published for every book created, including by a batch or
+// an import.
+type BookCreated struct {
+	Book Book `json:"book"`
+}
+
+// BookUpdated is published when a book is replaced, patched or reverted.
+// Changed lists the fields the write changed: title, authors or isbn.
+type BookUpdated struct {
+	Book    Book     `json:"book"`
+	Changed []string `json:"changed"`
+}
+
+// BookDeleted is published when a book is moved to the trash. Book is the
+// book as it sits in the trash.
+type BookDeleted struct {
+	Book Book `json:"book"`
+}
+
+// BookRestored is published when a book is moved out of the trash.
+type BookRestored struct {
+	Book Book `json:"book"`
+}
+
+// EventNames names every kind of event, in the order of their life cycle.
+var EventNames = []string{
+	BookCreated{}.EventName(),
+	BookUpdated{}.EventName(),
+	BookDeleted{}.EventName(),
+	BookRestored{}.EventName(),
+}
+
+func (BookCreated) EventName() string  { return "book.created" }
+func (BookUpdated) EventName() string  { return "book.updated" }
+func (BookDeleted) EventName() string  { return "book.deleted" }
+func (BookRestored) EventName() string { return "book.restored" }
+
+func (e BookCreated) EventKey() string  { return bookEventKey(e.Book) }
+func (e BookUpdated) EventKey() string  { return bookEventKey(e.Book) }
+func (e BookDeleted) EventKey() string  { return bookEventKey(e.Book) }
+func (e BookRestored) EventKey() string { return bookEventKey(e.Book) }
+
+func bookEventKey(book Book) string {
+	return "book:" + strconv.Itoa(book.ID)
+}
+
+// Event returns the event announcing the change.
+func (c BookChange) Event() Event {
+	switch c.Action {
+	case BookActionCreate:
+		return BookCreated{Book: c.After}
+	case BookActionDelete:
+		return BookDeleted{Book: c.After}
+	case BookActionRestore:
+		return BookRestored{Book: c.After}
+	default: // an update or a revert
+		var changed []string
+		if c.Before != nil {
+			changed = ChangedFields(*c.Before, c.After)
+		}
+		return BookUpdated{Book: c.After, Changed: changed}
+	}
+}
+
+// ChangedFields names the fields that differ between two versions of a book.
+// The byline follows the authors, so it is not listed on its own: a new
+// byline, as when an author is renamed, is a change of the authors.
+func ChangedFields(before, after Book) []string {
+	changed := []string{}
+	if before.Title != after.Title {
+		changed = append(changed, "title")
+	}
+	if !slices.Equal(before.AuthorIDs(), after.AuthorIDs()) || before.Author != after.Author {
+		changed = append(changed, "authors")
+	}
+	if before.ISBN != after.ISBN {
+		changed = append(changed, "isbn")
+	}
+	return changed
+}
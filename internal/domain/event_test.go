@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBookChange_Event(t *testing.T) {
+	orwell, huxley := Author{ID: 1, Name: "George Orwell"}, Author{ID: 2, Name: "Aldous Huxley"}
+	v1 := Book{ID: 1, Title: "1984", Authors: []Author{orwell}, Version: 1}
+
+	tests := []struct {
+		name   string
+		change BookChange
+		want   Event
+	}{
+		{
+			name:   "create",
+			change: BookChange{Action: BookActionCreate, After: v1},
+			want:   BookCreated{Book: v1},
+		},
+		{
+			name:   "update of every field",
+			change: BookChange{Action: BookActionUpdate, Before: &v1, After: Book{ID: 1, Title: "Brave New World", Authors: []Author{huxley}, ISBN: "9780060850524"}},
+			want:   BookUpdated{Book: Book{ID: 1, Title: "Brave New World", Authors: []Author{huxley}, ISBN: "9780060850524"}, Changed: []string{"title", "authors", "isbn"}},
+		},
+		{
+			name:   "revert of the authors' order",
+			change: BookChange{Action: BookActionRevert, Before: &Book{Authors: []Author{orwell, huxley}}, After: Book{Authors: []Author{huxley, orwell}}},
+			want:   BookUpdated{Book: Book{Authors: []Author{huxley, orwell}}, Changed: []string{"authors"}},
+		},
+		{
+			name:   "delete",
+			change: BookChange{Action: BookActionDelete, Before: &v1, After: v1},
+			want:   BookDeleted{Book: v1},
+		},
+		{
+			name:   "restore",
+			change: BookChange{Action: BookActionRestore, Before: &v1, After: v1},
+			want:   BookRestored{Book: v1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.change.Event(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BookChange.Event() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrBookVersionNotFound = errors.New("book version not found")
+	ErrRevertToDeleted     = errors.New("cannot revert to a version of the book in the trash")
+)
+
+// BookAction is the kind of write recorded in a book's history.
+type BookAction string
+
+const (
+	BookActionCreate  BookAction = "create"
+	BookActionUpdate  BookAction = "update"
+	BookActionDelete  BookAction = "delete"
+	BookActionRestore BookAction = "restore"
+	BookActionRevert  BookAction = "revert"
+)
+
+// BookChange is an entry of a book's history: the write that brought the book
+// to Version. Before is nil for the write that created the book. Changes are
+// never altered once recorded.
+type BookChange struct {
+	BookID    int
+	Version   int
+	Action    BookAction
+	Before    *Book
+	After     Book
+	Actor     string
+	RequestID string
+	CreatedAt time.Time
+}
+
+// Audit names who makes the changes of a request, and the request itself.
+type Audit struct {
+	Actor     string
+	RequestID string
+}
+
+type auditKey struct{}
+
+// WithAudit returns a copy of ctx carrying audit.
+func WithAudit(ctx context.Context, audit Audit) context.Context {
+	return context.WithValue(ctx, auditKey{}, audit)
+}
+
+// AuditFrom returns the Audit ctx carries, or the zero Audit.
+func AuditFrom(ctx context.Context) Audit {
+	audit, _ := ctx.Value(auditKey{}).(Audit)
+	return audit
+}
+
+// NewBookChange records the write that turned before into after, made on
+// behalf of the Audit ctx carries.
+func NewBookChange(ctx context.Context, action BookAction, before *Book, after Book) BookChange {
+	audit := AuditFrom(ctx)
+	return BookChange{
+		BookID:    after.ID,
+		Version:   after.Version,
+		Action:    action,
+		Before:    before,
+		After:     after,
+		Actor:     audit.Actor,
+		RequestID: audit.RequestID,
+	}
+}
@@ -0,0 +1,68 @@
+package domain
+
+import "strings"
+
+// NormalizeISBN validates an ISBN-10 or ISBN-13, with or without hyphens or
+// spaces, and returns it as a bare 13-digit ISBN-13.
+func NormalizeISBN(isbn string) (string, error) {
+	digits := strings.NewReplacer("-", "", " ", "").Replace(isbn)
+
+	switch len(digits) {
+	case 10:
+		if !isValidISBN10(digits) {
+			return "", ErrInvalidISBN
+		}
+		body := "978" + digits[:9]
+		return body + string(isbn13CheckDigit(body)), nil
+	case 13:
+		if !isValidISBN13(digits) {
+			return "", ErrInvalidISBN
+		}
+		return digits, nil
+	default:
+		return "", ErrInvalidISBN
+	}
+}
+
+func isValidISBN10(s string) bool {
+	sum := 0
+	for i := 0; i < 10; i++ {
+		var d int
+		switch {
+		case s[i] >= '0' && s[i] <= '9':
+			d = int(s[i] - '0')
+		case i == 9 && (s[i] == 'X' || s[i] == 'x'):
+			d = 10
+		default:
+			return false
+		}
+		sum += d * (10 - i)
+	}
+	return sum%11 == 0
+}
+
+func isValidISBN13(s string) bool {
+	for i := 0; i < 13; i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	if !strings.HasPrefix(s, "978") && !strings.HasPrefix(s, "979") {
+		return false
+	}
+	return isbn13CheckDigit(s[:12]) == s[12]
+}
+
+// isbn13CheckDigit computes the check digit for the first 12 digits of an
+// ISBN-13, weighting digits alternately by 1 and 3.
+func isbn13CheckDigit(body string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(body[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
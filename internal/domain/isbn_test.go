@@ -0,0 +1,74 @@
+package domain
+
+import "testing"
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		name    string
+		isbn    string
+		want    string
+		wantErr error
+	}{
+		{
+			name: "isbn-13",
+			isbn: "9780743273565",
+			want: "9780743273565",
+		},
+		{
+			name: "isbn-13 with hyphens",
+			isbn: "978-0-7432-7356-5",
+			want: "9780743273565",
+		},
+		{
+			name: "isbn-10",
+			isbn: "0743273567",
+			want: "9780743273565",
+		},
+		{
+			name: "isbn-10 with hyphens and X check digit",
+			isbn: "0-8044-2957-X",
+			want: "9780804429573",
+		},
+		{
+			name: "isbn-10 with spaces",
+			isbn: "0 7432 7356 7",
+			want: "9780743273565",
+		},
+		{
+			name:    "isbn-13 bad checksum",
+			isbn:    "9780743273566",
+			wantErr: ErrInvalidISBN,
+		},
+		{
+			name:    "isbn-10 bad checksum",
+			isbn:    "0743273568",
+			wantErr: ErrInvalidISBN,
+		},
+		{
+			name:    "isbn-13 unknown prefix",
+			isbn:    "1234567890128",
+			wantErr: ErrInvalidISBN,
+		},
+		{
+			name:    "non-digit characters",
+			isbn:    "97807432735AB",
+			wantErr: ErrInvalidISBN,
+		},
+		{
+			name:    "wrong length",
+			isbn:    "12345",
+			wantErr: ErrInvalidISBN,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeISBN(tt.isbn)
+			if err != tt.wantErr {
+				t.Errorf("NormalizeISBN() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeISBN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
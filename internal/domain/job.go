@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	ErrJobNotFound         = errors.New("job not found")
+	ErrJobArtifactNotFound = errors.New("job has no artifact")
+	// ErrJobLost is returned when a job is updated by a worker whose lease
+	// ran out, after which another worker may have claimed the job.
+	ErrJobLost = errors.New("job was claimed by another worker")
+)
+
+type JobKind string
+
+const (
+	JobBookImport JobKind = "book_import"
+	JobBookExport JobKind = "book_export"
+)
+
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// The files a job can hold: the upload it reads and the artifact it writes.
+const (
+	JobInput  = "input"
+	JobOutput = "output"
+)
+
+// Job is a unit of background work. Params and Result are kind-specific JSON
+// documents. Processed counts the items handled by the current attempt, and
+// Error holds the failure of the last attempt. A queued job runs once RunAt
+// has come.
+type Job struct {
+	ID          int             `json:"id"`
+	Kind        JobKind         `json:"kind"`
+	Status      JobStatus       `json:"status"`
+	Params      json.RawMessage `json:"params"`
+	Processed   int             `json:"processed"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"`
+	Artifact    *JobArtifact    `json:"artifact,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+}
+
+// JobArtifact describes the file a job produced.
+type JobArtifact struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+}
+
+// JobOutcome is what a successful job attempt produced. Artifact is set when
+// the attempt wrote a file.
+type JobOutcome struct {
+	Result   json.RawMessage
+	Artifact *JobArtifact
+}
+
+// PermanentJobError marks a job failure that retrying cannot fix, such as
+// invalid input, so the job fails without using up its attempts.
+type PermanentJobError struct {
+	Err error
+}
+
+func (e *PermanentJobError) Error() string { return e.Err.Error() }
+
+func (e *PermanentJobError) Unwrap() error { return e.Err }
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxMessage is an event waiting in the outbox to be relayed to other
+// services. It is written in the transaction of the change it announces, so
+// it exists exactly when the change does. Messages with the same Key are
+// relayed in the order of their IDs; consumers may see a message more than
+// once and can tell the copies apart by ID.
+type OutboxMessage struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	Key       string          `json:"key"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+	// Attempts counts the failed attempts to relay the message, and Error
+	// holds why the last one failed.
+	Attempts int    `json:"-"`
+	Error    string `json:"-"`
+}
+
+// NewOutboxMessage returns the message relaying event.
+func NewOutboxMessage(event Event) (OutboxMessage, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return OutboxMessage{}, err
+	}
+	return OutboxMessage{Type: event.EventName(), Key: event.EventKey(), Payload: payload}, nil
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewOutboxMessage(t *testing.T) {
+	book := Book{ID: 42, Title: "1984", Author: "George Orwell", Version: 2}
+
+	got, err := NewOutboxMessage(BookUpdated{Book: book, Changed: []string{"title"}})
+	if err != nil {
+		t.Fatalf("NewOutboxMessage() error = %v", err)
+	}
+	want := OutboxMessage{
+		Type:    "book.updated",
+		Key:     "book:42",
+		Payload: []byte(`{"book":{"id":42,"title":"1984","author":"George Orwell","authors":null,"version":2,"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z"},"changed":["title"]}`),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewOutboxMessage() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+var ErrSearchQueryRequired = errors.New("search query must contain at least one word")
+
+// HighlightStart and HighlightEnd surround the matched words in the
+// highlights of a BookHit.
+const (
+	HighlightStart = "\x02"
+	HighlightEnd   = "\x03"
+)
+
+// SearchTerm is one term of a search query: a word, or a phrase whose words
+// must appear next to each other in order. With Prefix set, the last word
+// also matches longer words that start with it.
+type SearchTerm struct {
+	Words  []string
+	Prefix bool
+}
+
+// SearchQuery matches the books that contain every one of its terms.
+type SearchQuery []SearchTerm
+
+// ParseSearchQuery reads a search the way users type it. Double quotes
+// group words into a phrase, and a trailing * makes a word a prefix, as in
+// `"animal farm" orw*`. Punctuation separates words and is otherwise
+// ignored, so a query never carries engine syntax.
+func ParseSearchQuery(q string) (SearchQuery, error) {
+	var query SearchQuery
+	for i, part := range strings.Split(q, `"`) {
+		// Odd parts sit between a pair of quotes.
+		if i%2 == 1 {
+			query = query.add(part)
+			continue
+		}
+		for _, token := range strings.Fields(part) {
+			query = query.add(token)
+		}
+	}
+	if len(query) == 0 {
+		var verr ValidationError
+		verr.add("q", "required", ErrSearchQueryRequired)
+		return nil, &verr
+	}
+	return query, nil
+}
+
+// add appends the words of text as a single term.
+func (q SearchQuery) add(text string) SearchQuery {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return q
+	}
+	return append(q, SearchTerm{Words: words, Prefix: strings.HasSuffix(strings.TrimSpace(text), "*")})
+}
+
+// NormalizeFuzzyQuery prepares q for a fuzzy search, which compares the text
+// as typed against titles and author names and needs no syntax.
+func NormalizeFuzzyQuery(q string) (string, error) {
+	text := strings.Join(strings.Fields(q), " ")
+	if text == "" {
+		var verr ValidationError
+		verr.add("q", "required", ErrSearchQueryRequired)
+		return "", &verr
+	}
+	return text, nil
+}
+
+// BookHit is a book found by a search. Rank orders hits by relevance, higher
+// first; for a fuzzy search it is the similarity between 0 and 1. The
+// highlights are the book's title and author with the matched words marked by
+// HighlightStart and HighlightEnd.
+type BookHit struct {
+	Book            Book
+	Rank            float64
+	TitleHighlight  string
+	AuthorHighlight string
+}
+
+// Suggestion completes a prefix typed by a user with a book title or an
+// author name. Field is "title" or "author".
+type Suggestion struct {
+	Text  string
+	Field string
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		q       string
+		want    SearchQuery
+		wantErr error
+	}{
+		{
+			name: "words",
+			q:    "Animal  Farm",
+			want: SearchQuery{{Words: []string{"animal"}}, {Words: []string{"farm"}}},
+		},
+		{
+			name: "phrase and prefix",
+			q:    `"animal farm" orw*`,
+			want: SearchQuery{{Words: []string{"animal", "farm"}}, {Words: []string{"orw"}, Prefix: true}},
+		},
+		{
+			name: "prefix phrase",
+			q:    `"brave new wor*"`,
+			want: SearchQuery{{Words: []string{"brave", "new", "wor"}, Prefix: true}},
+		},
+		{
+			name: "unclosed quote",
+			q:    `1984 "nineteen eighty`,
+			want: SearchQuery{{Words: []string{"1984"}}, {Words: []string{"nineteen", "eighty"}}},
+		},
+		{
+			name: "engine syntax is dropped",
+			q:    "cats & !dogs | o'brien:*",
+			want: SearchQuery{{Words: []string{"cats"}}, {Words: []string{"dogs"}}, {Words: []string{"o", "brien"}, Prefix: true}},
+		},
+		{
+			name:    "no words",
+			q:       ` "" * & `,
+			wantErr: ErrSearchQueryRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSearchQuery(tt.q)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseSearchQuery() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSearchQuery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package domain
+
+import "strings"
+
+// FieldError is a validation rule that a single field failed. Field is the
+// field's JSON name and Err is the sentinel error for the failure.
+type FieldError struct {
+	Field string
+	Rule  string
+	Err   error
+}
+
+// ValidationError collects every rule an entity failed. It unwraps to the
+// sentinel errors of its fields, so errors.Is still matches each of them.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Fields))
+	for i, f := range e.Fields {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+func (e *ValidationError) add(field, rule string, err error) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Rule: rule, Err: err})
+}
+
+// orNil returns e, or nil when no rule failed.
+func (e *ValidationError) orNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrWebhookNotFound   = errors.New("webhook not found")
+	ErrWebhookURLInvalid = errors.New("url must be an absolute http or https URL")
+	ErrWebhookURLPrivate = errors.New("url must not point at a loopback, link-local or private address")
+	ErrUnknownEvent      = errors.New("unknown event")
+)
+
+// Webhook subscribes an HTTP endpoint to events. Events names the events
+// delivered to it, every one when empty. Secret is the key deliveries are
+// signed with.
+type Webhook struct {
+	ID        int
+	URL       string
+	Events    []string
+	Secret    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Validate checks the webhook's fields and returns a *ValidationError listing
+// every failed rule. Unless allowPrivate is set, the URL must not name
+// localhost or an IP address that is not public; a host name resolving to
+// one is only refused when a delivery connects to it.
+func (w *Webhook) Validate(allowPrivate bool) error {
+	var verr ValidationError
+	u, err := url.Parse(w.URL)
+	switch {
+	case err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "":
+		verr.add("url", "url", ErrWebhookURLInvalid)
+	case !allowPrivate && privateHost(u.Hostname()):
+		verr.add("url", "public", ErrWebhookURLPrivate)
+	}
+	for _, event := range w.Events {
+		if !slices.Contains(EventNames, event) {
+			verr.add("events", "oneof", ErrUnknownEvent)
+			break
+		}
+	}
+	return verr.orNil()
+}
+
+// privateHost reports whether host is localhost or an IP address that is not
+// public.
+func privateHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	addr, err := netip.ParseAddr(host)
+	return err == nil && !PublicAddr(addr)
+}
+
+// PublicAddr reports whether webhooks may be delivered to addr: it must be a
+// global unicast address outside the private ranges, so not loopback,
+// link-local (cloud metadata services among them), multicast or unspecified.
+// IPv4-mapped IPv6 addresses are judged as IPv4.
+func PublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// WebhookDelivery is an outbox message posted, or to be posted, to a
+// webhook. Payload is the request body. Attempts counts the attempts so far,
+// and ResponseStatus and Error describe how the last one went; ResponseStatus
+// is zero when no response came back. A pending delivery is attempted at
+// NextAttemptAt.
+type WebhookDelivery struct {
+	ID             int64
+	WebhookID      int
+	MessageID      int64
+	Event          string
+	Payload        json.RawMessage
+	Status         DeliveryStatus
+	Attempts       int
+	ResponseStatus int
+	Error          string
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWebhook_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		allowPrivate bool
+		wantErr      error
+	}{
+		{name: "public host", url: "https://partner.example.com/hooks"},
+		{name: "public ip", url: "http://203.0.113.7:8080/hooks"},
+		{name: "not http", url: "ftp://partner.example.com/hooks", wantErr: ErrWebhookURLInvalid},
+		{name: "relative", url: "/hooks", wantErr: ErrWebhookURLInvalid},
+		{name: "localhost", url: "http://LocalHost:8080/hooks", wantErr: ErrWebhookURLPrivate},
+		{name: "loopback", url: "http://127.0.0.1/hooks", wantErr: ErrWebhookURLPrivate},
+		{name: "ipv6 loopback", url: "http://[::1]/hooks", wantErr: ErrWebhookURLPrivate},
+		{name: "cloud metadata", url: "http://169.254.169.254/latest/meta-data", wantErr: ErrWebhookURLPrivate},
+		{name: "private", url: "http://10.0.0.5/hooks", wantErr: ErrWebhookURLPrivate},
+		{name: "ipv4-mapped private", url: "http://[::ffff:192.168.1.1]/hooks", wantErr: ErrWebhookURLPrivate},
+		{name: "unspecified", url: "http://0.0.0.0/hooks", wantErr: ErrWebhookURLPrivate},
+		{name: "private allowed", url: "http://127.0.0.1/hooks", allowPrivate: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := Webhook{URL: tt.url}
+			if err := w.Validate(tt.allowPrivate); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Webhook.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"go-api-boilerplate/internal/constant"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth only lets through requests carrying "Authorization: Bearer <token>".
+// An empty token disables the guarded routes entirely.
+func AdminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			_ = c.Error(constant.ErrAdminDisabled)
+			c.Abort()
+			return
+		}
+
+		given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			_ = c.Error(constant.ErrInvalidAdminToken)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
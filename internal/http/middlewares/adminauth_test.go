@@ -0,0 +1,89 @@
+package middlewares
+
+import (
+	"errors"
+	"go-api-boilerplate/internal/constant"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// lastError returns a middleware that stores the last error the rest of the
+// chain reports in err, where ErrorHandler would resolve it.
+func lastError(err *error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if last := c.Errors.Last(); last != nil {
+			*err = last.Err
+		}
+	}
+}
+
+func TestAdminAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		header  string
+		wantErr error
+	}{
+		{
+			name:   "valid token",
+			token:  "secret",
+			header: "Bearer secret",
+		},
+		{
+			name:    "missing header",
+			token:   "secret",
+			header:  "",
+			wantErr: constant.ErrInvalidAdminToken,
+		},
+		{
+			name:    "wrong token",
+			token:   "secret",
+			header:  "Bearer guess",
+			wantErr: constant.ErrInvalidAdminToken,
+		},
+		{
+			name:    "not a bearer token",
+			token:   "secret",
+			header:  "secret",
+			wantErr: constant.ErrInvalidAdminToken,
+		},
+		{
+			name:    "admin access disabled",
+			token:   "",
+			header:  "Bearer ",
+			wantErr: constant.ErrAdminDisabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			var err error
+			reached := false
+			r := gin.New()
+			r.Use(lastError(&err), AdminAuth(tt.token))
+			r.GET("/test", func(c *gin.Context) {
+				reached = true
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			r.ServeHTTP(w, req)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("AdminAuth() error = %v, want %v", err, tt.wantErr)
+			}
+			if reached != (tt.wantErr == nil) {
+				t.Errorf("AdminAuth() reached the handler = %v, want %v", reached, tt.wantErr == nil)
+			}
+		})
+	}
+}
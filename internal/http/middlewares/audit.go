@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"go-api-boilerplate/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxRequestIDLength bounds the client request IDs that are kept.
+	maxRequestIDLength = 128
+	// anonymousActor is the actor of requests without an X-Actor header.
+	anonymousActor = "anonymous"
+)
+
+// Audit tags the request context with the domain.Audit the book history
+// records. The request ID comes from X-Request-ID, or is generated when the
+// header is missing or too long, and is echoed in the response. The actor is
+// taken from X-Actor, which the gateway in front of the API is trusted to set.
+func Audit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader("X-Request-ID")
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = rand.Text()
+		}
+		actor := c.GetHeader("X-Actor")
+		if actor == "" {
+			actor = anonymousActor
+		}
+
+		c.Header("X-Request-ID", requestID)
+		ctx := domain.WithAudit(c.Request.Context(), domain.Audit{Actor: actor, RequestID: requestID})
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"go-api-boilerplate/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAudit(t *testing.T) {
+	tests := []struct {
+		name          string
+		requestID     string
+		actor         string
+		wantRequestID string
+		wantActor     string
+	}{
+		{
+			name:          "headers are kept",
+			requestID:     "req-1",
+			actor:         "alice",
+			wantRequestID: "req-1",
+			wantActor:     "alice",
+		},
+		{
+			name:      "missing headers",
+			wantActor: "anonymous",
+		},
+		{
+			name:      "request ID too long",
+			requestID: strings.Repeat("x", 129),
+			actor:     "alice",
+			wantActor: "alice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			var got domain.Audit
+			r := gin.New()
+			r.Use(Audit())
+			r.GET("/test", func(c *gin.Context) {
+				got = domain.AuditFrom(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.requestID != "" {
+				req.Header.Set("X-Request-ID", tt.requestID)
+			}
+			if tt.actor != "" {
+				req.Header.Set("X-Actor", tt.actor)
+			}
+			r.ServeHTTP(w, req)
+
+			if got.Actor != tt.wantActor {
+				t.Errorf("Audit() actor = %q, want %q", got.Actor, tt.wantActor)
+			}
+			if tt.wantRequestID != "" && got.RequestID != tt.wantRequestID {
+				t.Errorf("Audit() request ID = %q, want %q", got.RequestID, tt.wantRequestID)
+			}
+			if tt.wantRequestID == "" && (got.RequestID == "" || got.RequestID == tt.requestID) {
+				t.Errorf("Audit() request ID = %q, want a generated one", got.RequestID)
+			}
+			if h := w.Header().Get("X-Request-ID"); h != got.RequestID {
+				t.Errorf("Audit() X-Request-ID = %q, want %q", h, got.RequestID)
+			}
+		})
+	}
+}
@@ -1,16 +1,16 @@
 package middlewares
 
 import (
-	"errors"
-	"go-api-boilerplate/internal/constant"
 	"go-api-boilerplate/internal/http/util"
 	"log"
-	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
-func ErrorHandler() gin.HandlerFunc {
+// ErrorHandler reports the last error added to the context with the status
+// and code the registry maps it to. Errors the registry does not know are
+// logged and reported as a generic internal error.
+func ErrorHandler(registry *util.ErrorRegistry) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
 
@@ -19,12 +19,10 @@ func ErrorHandler() gin.HandlerFunc {
 			return
 		}
 
-		log.Printf("[INTERNAL_ERROR]: %v\n", lastErr.Err)
-		util.NewError(
-			c,
-			http.StatusInternalServerError,
-			constant.ErrInternalServerError,
-			errors.New("an unexpected error occurred"),
-		)
+		status, res, ok := registry.Resolve(lastErr.Err)
+		if !ok {
+			log.Printf("[INTERNAL_ERROR]: %v\n", lastErr.Err)
+		}
+		c.JSON(status, res)
 	}
 }
@@ -3,6 +3,7 @@ package middlewares
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"go-api-boilerplate/internal/constant"
 	"go-api-boilerplate/internal/http/util"
 	"net/http"
@@ -17,6 +18,10 @@ func init() {
 }
 
 func TestErrorHandler(t *testing.T) {
+	errMissing := errors.New("thing not found")
+	registry := util.NewErrorRegistry().
+		Register(errMissing, http.StatusNotFound, constant.ErrNotFoundCode)
+
 	tests := []struct {
 		name           string
 		setupHandler   func(*gin.Context)
@@ -43,6 +48,36 @@ func TestErrorHandler(t *testing.T) {
 			expectedCode:   constant.ErrInternalServerError,
 			expectedMsg:    "an unexpected error occurred",
 		},
+		{
+			name: "registered error",
+			setupHandler: func(c *gin.Context) {
+				c.Error(errMissing)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectError:    true,
+			expectedCode:   constant.ErrNotFoundCode,
+			expectedMsg:    "thing not found",
+		},
+		{
+			name: "wrapped registered error - context is not exposed",
+			setupHandler: func(c *gin.Context) {
+				c.Error(fmt.Errorf("select from things where secret = 1: %w", errMissing))
+			},
+			expectedStatus: http.StatusNotFound,
+			expectError:    true,
+			expectedCode:   constant.ErrNotFoundCode,
+			expectedMsg:    "thing not found",
+		},
+		{
+			name: "request error",
+			setupHandler: func(c *gin.Context) {
+				c.Error(util.BadRequest(errors.New("invalid input")))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectError:    true,
+			expectedCode:   constant.ErrValidationCode,
+			expectedMsg:    "invalid input",
+		},
 		{
 			name: "multiple errors - should handle last error",
 			setupHandler: func(c *gin.Context) {
@@ -63,7 +98,7 @@ func TestErrorHandler(t *testing.T) {
 
 			// Create a router to test the middleware
 			r := gin.New()
-			r.Use(ErrorHandler())
+			r.Use(ErrorHandler(registry))
 			r.GET("/test", func(c *gin.Context) {
 				tt.setupHandler(c)
 			})
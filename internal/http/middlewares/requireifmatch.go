@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"go-api-boilerplate/internal/constant"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireIfMatch rejects requests without an If-Match header, so clients
+// cannot overwrite changes they have not seen.
+func RequireIfMatch() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("If-Match") == "" {
+			_ = c.Error(constant.ErrIfMatchRequired)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
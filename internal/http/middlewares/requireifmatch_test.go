@@ -0,0 +1,61 @@
+package middlewares
+
+import (
+	"errors"
+	"go-api-boilerplate/internal/constant"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		wantErr error
+	}{
+		{
+			name:   "with If-Match",
+			header: `"1"`,
+		},
+		{
+			name:   "with wildcard",
+			header: "*",
+		},
+		{
+			name:    "without If-Match",
+			header:  "",
+			wantErr: constant.ErrIfMatchRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+
+			var err error
+			reached := false
+			r := gin.New()
+			r.Use(lastError(&err), RequireIfMatch())
+			r.PUT("/test", func(c *gin.Context) {
+				reached = true
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPut, "/test", nil)
+			if tt.header != "" {
+				req.Header.Set("If-Match", tt.header)
+			}
+			r.ServeHTTP(w, req)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RequireIfMatch() error = %v, want %v", err, tt.wantErr)
+			}
+			if reached != (tt.wantErr == nil) {
+				t.Errorf("RequireIfMatch() reached the handler = %v, want %v", reached, tt.wantErr == nil)
+			}
+		})
+	}
+}
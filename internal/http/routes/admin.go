@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"go-api-boilerplate/internal/adapter/handlers"
+	"go-api-boilerplate/internal/http/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAdminRoutes(router *gin.Engine, adminToken string, bookHandler *handlers.BookHandler) {
+	admin := router.Group("/admin", middlewares.AdminAuth(adminToken))
+	admin.DELETE("/books/:id", bookHandler.PurgeBook)
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"go-api-boilerplate/internal/adapter/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupAuthorRoutes(router *gin.Engine, authorHandler *handlers.AuthorHandler) {
+	router.POST("/authors", authorHandler.CreateAuthor)
+	router.GET("/authors/:id", authorHandler.GetAuthor)
+	router.GET("/authors/:id/books", authorHandler.GetAuthorBooks)
+	router.GET("/authors", authorHandler.GetAuthors)
+	router.PUT("/authors/:id", authorHandler.UpdateAuthor)
+	router.DELETE("/authors/:id", authorHandler.DeleteAuthor)
+}
@@ -2,14 +2,46 @@ package routes
 
 import (
 	"go-api-boilerplate/internal/adapter/handlers"
+	"go-api-boilerplate/internal/http/middlewares"
+	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupBookRoutes(router *gin.Engine, bookHandler *handlers.BookHandler) {
+func SetupBookRoutes(router *gin.Engine, bookHandler *handlers.BookHandler, requireIfMatch bool) {
+	// Writes that replace or remove an existing version of a book
+	guarded := []gin.HandlerFunc{}
+	if requireIfMatch {
+		guarded = append(guarded, middlewares.RequireIfMatch())
+	}
+
 	router.POST("/books", bookHandler.CreateBook)
+	router.POST("/books:method", customMethods(map[string]gin.HandlerFunc{
+		"batch": bookHandler.CreateBooks,
+	}))
 	router.GET("/books/:id", bookHandler.GetBook)
+	router.GET("/books/isbn/:isbn", bookHandler.GetBookByISBN)
 	router.GET("/books", bookHandler.GetBooks)
-	router.PUT("/books/:id", bookHandler.UpdateBook)
-	router.DELETE("/books/:id", bookHandler.DeleteBook)
+	router.GET("/books/trash", bookHandler.GetTrash)
+	router.GET("/books/export", bookHandler.ExportBooks)
+	router.PUT("/books/:id", append(guarded, bookHandler.UpdateBook)...)
+	router.PATCH("/books/:id", append(guarded, bookHandler.PatchBook)...)
+	router.DELETE("/books/:id", append(guarded, bookHandler.DeleteBook)...)
+	router.POST("/books/:id/restore", bookHandler.RestoreBook)
+}
+
+// customMethods dispatches custom methods such as POST /books:batch by the
+// name after the colon. Gin only matches a literal colon in a route once the
+// engine is started with Run, so the name is captured as a parameter instead.
+func customMethods(handlers map[string]gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name, ok := strings.CutPrefix(c.Param("method"), ":")
+		handler, found := handlers[name]
+		if !ok || !found {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		handler(c)
+	}
 }
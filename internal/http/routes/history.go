@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"go-api-boilerplate/internal/adapter/handlers"
+	"go-api-boilerplate/internal/http/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupHistoryRoutes(router *gin.Engine, historyHandler *handlers.HistoryHandler, requireIfMatch bool) {
+	// A revert replaces the current version like PUT /books/:id
+	guarded := []gin.HandlerFunc{}
+	if requireIfMatch {
+		guarded = append(guarded, middlewares.RequireIfMatch())
+	}
+
+	router.GET("/books/:id/history", historyHandler.GetBookHistory)
+	router.GET("/books/:id/history/:version", historyHandler.GetBookVersion)
+	router.POST("/books/:id/revert/:version", append(guarded, historyHandler.RevertBook)...)
+}
@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"go-api-boilerplate/internal/adapter/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupImportRoutes(router *gin.Engine, importHandler *handlers.ImportHandler) {
+	router.POST("/books/import", importHandler.ImportBooks)
+}
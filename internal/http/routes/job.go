@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"go-api-boilerplate/internal/adapter/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupJobRoutes(router *gin.Engine, jobHandler *handlers.JobHandler) {
+	router.GET("/jobs/:id", jobHandler.GetJob)
+	router.GET("/jobs/:id/artifact", jobHandler.GetJobArtifact)
+	router.POST("/books/export", jobHandler.ExportBooks)
+}
@@ -2,15 +2,24 @@ package routes
 
 import (
 	"go-api-boilerplate/internal/adapter/handlers"
+	"go-api-boilerplate/internal/config"
 	"go-api-boilerplate/internal/http/middlewares"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(router *gin.Engine, bookHandler *handlers.BookHandler) {
+func SetupRoutes(router *gin.Engine, cfg *config.Config, bookHandler *handlers.BookHandler, authorHandler *handlers.AuthorHandler, searchHandler *handlers.SearchHandler, importHandler *handlers.ImportHandler, jobHandler *handlers.JobHandler, historyHandler *handlers.HistoryHandler, webhookHandler *handlers.WebhookHandler) {
 	// Set up middlewares
-	router.Use(middlewares.ErrorHandler())
+	router.Use(middlewares.Audit())
+	router.Use(middlewares.ErrorHandler(handlers.ErrorRegistry()))
 
 	// Set up routes
-	SetupBookRoutes(router, bookHandler)
+	SetupBookRoutes(router, bookHandler, cfg.HTTP.RequireIfMatch)
+	SetupAuthorRoutes(router, authorHandler)
+	SetupSearchRoutes(router, searchHandler)
+	SetupImportRoutes(router, importHandler)
+	SetupJobRoutes(router, jobHandler)
+	SetupHistoryRoutes(router, historyHandler, cfg.HTTP.RequireIfMatch)
+	SetupWebhookRoutes(router, cfg.Admin.Token, webhookHandler)
+	SetupAdminRoutes(router, cfg.Admin.Token, bookHandler)
 }
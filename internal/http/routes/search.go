@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"go-api-boilerplate/internal/adapter/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupSearchRoutes(router *gin.Engine, searchHandler *handlers.SearchHandler) {
+	router.GET("/books/search", searchHandler.SearchBooks)
+	router.GET("/books/suggest", searchHandler.SuggestBooks)
+}
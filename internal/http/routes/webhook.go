@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"go-api-boilerplate/internal/adapter/handlers"
+	"go-api-boilerplate/internal/http/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupWebhookRoutes(router *gin.Engine, adminToken string, webhookHandler *handlers.WebhookHandler) {
+	// Webhooks hold secrets and make the server call out, so they are
+	// managed with the admin token
+	webhooks := router.Group("/webhooks", middlewares.AdminAuth(adminToken))
+	webhooks.POST("", webhookHandler.CreateWebhook)
+	webhooks.GET("", webhookHandler.GetWebhooks)
+	webhooks.GET("/:id", webhookHandler.GetWebhook)
+	webhooks.PUT("/:id", webhookHandler.UpdateWebhook)
+	webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+	webhooks.GET("/:id/deliveries", webhookHandler.GetWebhookDeliveries)
+}
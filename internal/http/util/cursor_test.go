@@ -0,0 +1,45 @@
+package util
+
+import (
+	"errors"
+	"go-api-boilerplate/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCursorCodec(t *testing.T) {
+	codec := NewCursorCodec("secret")
+	cursor := domain.Cursor{Sort: "id", Key: []string{"42"}, Backward: true}
+
+	token := codec.Encode(cursor)
+	got, err := codec.Decode(token)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, cursor) {
+		t.Errorf("Decode() = %v, want %v", got, cursor)
+	}
+
+	payload, sig, _ := strings.Cut(token, ".")
+	forged := NewCursorCodec("secret").Encode(domain.Cursor{Sort: "id", Key: []string{"1"}})
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "no signature", token: payload},
+		{name: "not base64", token: "!!!." + sig},
+		{name: "payload swapped", token: forgedPayload + "." + sig},
+		{name: "signed with another secret", token: NewCursorCodec("other").Encode(cursor)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := codec.Decode(tt.token); !errors.Is(err, domain.ErrInvalidCursor) {
+				t.Errorf("Decode() error = %v, want %v", err, domain.ErrInvalidCursor)
+			}
+		})
+	}
+}
@@ -9,6 +9,15 @@ import (
 type HTTPError struct {
 	Code    constant.ErrorCode `json:"code" example:"ERROR_CODE"`
 	Message string             `json:"message" example:"Error message"`
+	Errors  []FieldError       `json:"errors,omitempty"`
+}
+
+// FieldError describes a validation rule that a single field failed. Field
+// is the field's name in the request, e.g. its JSON name.
+type FieldError struct {
+	Field   string `json:"field" example:"title"`
+	Rule    string `json:"rule" example:"required"`
+	Message string `json:"message" example:"title is required"`
 }
 
 func NewError(c *gin.Context, status int, code constant.ErrorCode, err error) {
@@ -0,0 +1,117 @@
+package util
+
+import (
+	"errors"
+	"go-api-boilerplate/internal/constant"
+	"net/http"
+)
+
+// RequestError is an error caused by the request itself, such as a body that
+// fails to bind. Its fields are returned to the client as is, and so is its
+// message when it has no fields.
+type RequestError struct {
+	Status int
+	Code   constant.ErrorCode
+	Err    error
+	Fields []FieldError
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// BadRequest reports err as a validation error. Binding errors are broken
+// down into the fields that failed; a RequestError is returned unchanged.
+func BadRequest(err error) error {
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr
+	}
+	return &RequestError{
+		Status: http.StatusBadRequest,
+		Code:   constant.ErrValidationCode,
+		Err:    err,
+		Fields: bindingFieldErrors(err),
+	}
+}
+
+// InvalidField reports a single field that failed rule.
+func InvalidField(field, rule, message string) error {
+	return &RequestError{
+		Status: http.StatusBadRequest,
+		Code:   constant.ErrValidationCode,
+		Err:    errors.New(message),
+		Fields: []FieldError{{Field: field, Rule: rule, Message: message}},
+	}
+}
+
+// ErrorResolver resolves errors that need more than a fixed status and code.
+// It returns ok false for errors it does not handle.
+type ErrorResolver func(err error) (status int, res HTTPError, ok bool)
+
+type errorMapping struct {
+	target error
+	status int
+	code   constant.ErrorCode
+}
+
+// ErrorRegistry maps errors to the HTTP status and code they are reported
+// with.
+type ErrorRegistry struct {
+	resolvers []ErrorResolver
+	mappings  []errorMapping
+}
+
+func NewErrorRegistry() *ErrorRegistry {
+	return &ErrorRegistry{}
+}
+
+// Register maps target, and any error wrapping it, to status and code.
+// Mappings are tried in the order they were registered.
+func (r *ErrorRegistry) Register(target error, status int, code constant.ErrorCode) *ErrorRegistry {
+	r.mappings = append(r.mappings, errorMapping{target: target, status: status, code: code})
+	return r
+}
+
+// RegisterFunc adds a resolver that is tried before the mapped errors.
+func (r *ErrorRegistry) RegisterFunc(resolve ErrorResolver) *ErrorRegistry {
+	r.resolvers = append(r.resolvers, resolve)
+	return r
+}
+
+// fieldsMessage is the message of a RequestError that lists its fields, whose
+// own message may be raw binding output.
+const fieldsMessage = "request validation failed"
+
+// Resolve returns the status and body to report err with. A registered error
+// is reported with the target's own message, so context added by wrapping
+// never reaches the client. Unknown errors are reported as an internal error
+// with a generic message, and ok is false.
+func (r *ErrorRegistry) Resolve(err error) (status int, res HTTPError, ok bool) {
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		message := reqErr.Error()
+		if len(reqErr.Fields) > 0 {
+			message = fieldsMessage
+		}
+		return reqErr.Status, HTTPError{Code: reqErr.Code, Message: message, Errors: reqErr.Fields}, true
+	}
+	for _, resolve := range r.resolvers {
+		if status, res, ok := resolve(err); ok {
+			return status, res, true
+		}
+	}
+	for _, m := range r.mappings {
+		if errors.Is(err, m.target) {
+			return m.status, HTTPError{Code: m.code, Message: m.target.Error()}, true
+		}
+	}
+	return http.StatusInternalServerError, HTTPError{
+		Code:    constant.ErrInternalServerError,
+		Message: "an unexpected error occurred",
+	}, false
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ETag formats a resource version as a strong entity tag, e.g. "3".
+func ETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// IfMatchVersion reads the version a client expects from an If-Match header.
+// It returns 0 when the header is empty or "*", meaning any version will do.
+// ok is false when the header can never match a current version: weak tags,
+// lists of several tags and tags that are not versions.
+func IfMatchVersion(header string) (version int, ok bool) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err != nil || !strings.HasPrefix(header, `"`) {
+		return 0, false
+	}
+	version, err = strconv.Atoi(unquoted)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
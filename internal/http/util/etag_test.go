@@ -0,0 +1,36 @@
+package util
+
+import "testing"
+
+func TestETag(t *testing.T) {
+	if got := ETag(3); got != `"3"` {
+		t.Errorf("ETag() = %v, want %v", got, `"3"`)
+	}
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		wantVersion int
+		wantOK      bool
+	}{
+		{name: "absent", header: "", wantVersion: 0, wantOK: true},
+		{name: "any", header: "*", wantVersion: 0, wantOK: true},
+		{name: "strong tag", header: `"3"`, wantVersion: 3, wantOK: true},
+		{name: "surrounding spaces", header: ` "3" `, wantVersion: 3, wantOK: true},
+		{name: "weak tag", header: `W/"3"`, wantOK: false},
+		{name: "unquoted", header: `3`, wantOK: false},
+		{name: "list", header: `"3", "4"`, wantOK: false},
+		{name: "not a version", header: `"abc"`, wantOK: false},
+		{name: "zero", header: `"0"`, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, ok := IfMatchVersion(tt.header)
+			if version != tt.wantVersion || ok != tt.wantOK {
+				t.Errorf("IfMatchVersion() = (%v, %v), want (%v, %v)", version, ok, tt.wantVersion, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package util
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaginationStyle selects how list endpoints report where a page sits in the
+// full list.
+type PaginationStyle string
+
+const (
+	// PaginationHeaders keeps the body a bare array and reports the total in
+	// X-Total-Count and the neighbouring pages in an RFC 8288 Link header.
+	PaginationHeaders PaginationStyle = "headers"
+	// PaginationEnvelope wraps the items in a PageRes.
+	PaginationEnvelope PaginationStyle = "envelope"
+)
+
+// PageRes is a page of a list together with its position in the full list.
+type PageRes struct {
+	Data    any  `json:"data"`
+	Page    int  `json:"page" example:"1"`
+	PerPage int  `json:"per_page" example:"10"`
+	Total   int  `json:"total" example:"42"`
+	HasNext bool `json:"has_next" example:"true"`
+}
+
+// Paginator writes page-numbered list responses in one PaginationStyle.
+type Paginator struct {
+	style PaginationStyle
+}
+
+func NewPaginator(style PaginationStyle) *Paginator {
+	return &Paginator{style: style}
+}
+
+// Write responds with items as page number page, of size perPage, of a list
+// holding total items.
+func (p *Paginator) Write(c *gin.Context, items any, page, perPage, total int) {
+	res := PageRes{
+		Data:    items,
+		Page:    page,
+		PerPage: perPage,
+		Total:   total,
+		HasNext: page*perPage < total,
+	}
+	if p.style == PaginationEnvelope {
+		c.JSON(http.StatusOK, res)
+		return
+	}
+
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	c.Header("Link", pageLinks(c.Request.URL, res))
+	c.JSON(http.StatusOK, items)
+}
+
+// pageLinks builds the Link header value for the pages around res. Targets
+// are relative to the request URL and keep its other query parameters.
+func pageLinks(u *url.URL, res PageRes) string {
+	last := max(1, (res.Total+res.PerPage-1)/res.PerPage)
+	link := func(page int, rel string) string {
+		q := u.Query()
+		q.Set("page", strconv.Itoa(page))
+		q.Set("per_page", strconv.Itoa(res.PerPage))
+		target := url.URL{Path: u.Path, RawQuery: q.Encode()}
+		return fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel)
+	}
+
+	links := []string{link(1, "first")}
+	if res.Page > 1 {
+		links = append(links, link(min(res.Page-1, last), "prev"))
+	}
+	if res.HasNext {
+		links = append(links, link(res.Page+1, "next"))
+	}
+	links = append(links, link(last, "last"))
+	return strings.Join(links, ", ")
+}
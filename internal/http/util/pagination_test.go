@@ -0,0 +1,115 @@
+package util
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPaginator_Headers(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		page     int
+		perPage  int
+		total    int
+		wantLink string
+	}{
+		{
+			name:    "first page",
+			target:  "/books",
+			page:    1,
+			perPage: 2,
+			total:   5,
+			wantLink: `</books?page=1&per_page=2>; rel="first", ` +
+				`</books?page=2&per_page=2>; rel="next", ` +
+				`</books?page=3&per_page=2>; rel="last"`,
+		},
+		{
+			name:    "middle page keeps other parameters",
+			target:  "/books?q=go&page=2&per_page=2",
+			page:    2,
+			perPage: 2,
+			total:   5,
+			wantLink: `</books?page=1&per_page=2&q=go>; rel="first", ` +
+				`</books?page=1&per_page=2&q=go>; rel="prev", ` +
+				`</books?page=3&per_page=2&q=go>; rel="next", ` +
+				`</books?page=3&per_page=2&q=go>; rel="last"`,
+		},
+		{
+			name:    "past the end",
+			target:  "/books?page=9",
+			page:    9,
+			perPage: 10,
+			total:   15,
+			wantLink: `</books?page=1&per_page=10>; rel="first", ` +
+				`</books?page=2&per_page=10>; rel="prev", ` +
+				`</books?page=2&per_page=10>; rel="last"`,
+		},
+		{
+			name:    "empty list",
+			target:  "/books",
+			page:    1,
+			perPage: 10,
+			total:   0,
+			wantLink: `</books?page=1&per_page=10>; rel="first", ` +
+				`</books?page=1&per_page=10>; rel="last"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, tt.target, nil)
+
+			NewPaginator(PaginationHeaders).Write(c, []int{1}, tt.page, tt.perPage, tt.total)
+
+			if got := w.Header().Get("Link"); got != tt.wantLink {
+				t.Errorf("Link = %q, want %q", got, tt.wantLink)
+			}
+			if got := w.Body.String(); got != "[1]" {
+				t.Errorf("body = %s, want [1]", got)
+			}
+		})
+	}
+}
+
+func TestPaginator_TotalCount(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/books", nil)
+
+	NewPaginator(PaginationHeaders).Write(c, []int{}, 1, 10, 42)
+
+	if got := w.Header().Get("X-Total-Count"); got != "42" {
+		t.Errorf("X-Total-Count = %q, want 42", got)
+	}
+}
+
+func TestPaginator_Envelope(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/books?page=2&per_page=2", nil)
+
+	NewPaginator(PaginationEnvelope).Write(c, []int{3, 4}, 2, 2, 5)
+
+	if got := w.Header().Get("Link"); got != "" {
+		t.Errorf("Link = %q, want none", got)
+	}
+	var res struct {
+		Data    []int `json:"data"`
+		Page    int   `json:"page"`
+		PerPage int   `json:"per_page"`
+		Total   int   `json:"total"`
+		HasNext bool  `json:"has_next"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(res.Data) != 2 || res.Page != 2 || res.PerPage != 2 || res.Total != 5 || !res.HasNext {
+		t.Errorf("response = %+v, want page 2 of 5 items with a next page", res)
+	}
+}
@@ -0,0 +1,100 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report binding failures under the names clients use, not Go field names.
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(requestFieldName)
+	}
+}
+
+// requestFieldName returns the json, form or uri name of a request field.
+func requestFieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "form", "uri"} {
+		name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// bindingFieldErrors breaks a binding error down into the fields that failed.
+// It returns nil for errors that are not tied to a field, such as malformed
+// JSON.
+func bindingFieldErrors(err error) []FieldError {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		fields := make([]FieldError, len(verrs))
+		for i, fe := range verrs {
+			fields[i] = FieldError{Field: fe.Field(), Rule: fe.Tag(), Message: ruleMessage(fe)}
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("%s must be of type %s", typeErr.Field, typeErr.Type),
+		}}
+	}
+	return nil
+}
+
+func ruleMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", fe.Field())
+	case "required_without":
+		return fmt.Sprintf("%s is required when %s is not set", fe.Field(), snakeCase(fe.Param()))
+	case "excluded_with":
+		return fmt.Sprintf("%s cannot be set together with %s", fe.Field(), snakeCase(fe.Param()))
+	case "min":
+		if isCollection(fe.Kind()) {
+			return fmt.Sprintf("%s must have at least %s elements", fe.Field(), fe.Param())
+		}
+		return fmt.Sprintf("%s must be at least %s", fe.Field(), fe.Param())
+	case "max":
+		if isCollection(fe.Kind()) {
+			return fmt.Sprintf("%s must have at most %s elements", fe.Field(), fe.Param())
+		}
+		return fmt.Sprintf("%s must be at most %s", fe.Field(), fe.Param())
+	default:
+		return fmt.Sprintf("%s failed the %s rule", fe.Field(), fe.Tag())
+	}
+}
+
+func isCollection(k reflect.Kind) bool {
+	return k == reflect.Slice || k == reflect.Array || k == reflect.Map || k == reflect.String
+}
+
+// snakeCase turns the Go field name a cross-field rule refers to into its
+// request name, e.g. AuthorIDs into author_ids.
+func snakeCase(name string) string {
+	var b strings.Builder
+	var prev rune
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) && unicode.IsLower(prev) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+		prev = r
+	}
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package util
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBadRequest_BindingErrors(t *testing.T) {
+	type req struct {
+		Title     string `json:"title" binding:"required"`
+		Author    string `json:"author" binding:"required_without=AuthorIDs,excluded_with=AuthorIDs"`
+		AuthorIDs []int  `json:"author_ids" binding:"omitempty,dive,min=1"`
+		PerPage   int    `json:"per_page" binding:"omitempty,max=100"`
+	}
+	tests := []struct {
+		name       string
+		body       string
+		wantFields []FieldError
+	}{
+		{
+			name: "every failed field is reported",
+			body: `{"per_page":500}`,
+			wantFields: []FieldError{
+				{Field: "title", Rule: "required", Message: "title is required"},
+				{Field: "author", Rule: "required_without", Message: "author is required when author_ids is not set"},
+				{Field: "per_page", Rule: "max", Message: "per_page must be at most 100"},
+			},
+		},
+		{
+			name: "cross-field and element rules",
+			body: `{"title":"Good Omens","author":"Neil Gaiman","author_ids":[0]}`,
+			wantFields: []FieldError{
+				{Field: "author", Rule: "excluded_with", Message: "author cannot be set together with author_ids"},
+				{Field: "author_ids[0]", Rule: "min", Message: "author_ids[0] must be at least 1"},
+			},
+		},
+		{
+			name: "wrong type",
+			body: `{"title":1}`,
+			wantFields: []FieldError{
+				{Field: "title", Rule: "type", Message: "title must be of type string"},
+			},
+		},
+		{
+			name:       "malformed json",
+			body:       `{"title":`,
+			wantFields: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			var r req
+			err := c.ShouldBindJSON(&r)
+			if err == nil {
+				t.Fatal("ShouldBindJSON() error = nil")
+			}
+
+			var reqErr *RequestError
+			if !errors.As(BadRequest(err), &reqErr) {
+				t.Fatalf("BadRequest() did not return a *RequestError")
+			}
+			if reqErr.Status != http.StatusBadRequest {
+				t.Errorf("BadRequest() status = %v, want %v", reqErr.Status, http.StatusBadRequest)
+			}
+			if !reflect.DeepEqual(reqErr.Fields, tt.wantFields) {
+				t.Errorf("BadRequest() fields = %v, want %v", reqErr.Fields, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"Title":     "title",
+		"AuthorIDs": "author_ids",
+		"PerPage":   "per_page",
+		"ISBN":      "isbn",
+	}
+	for in, want := range tests {
+		if got := snakeCase(in); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package infra
+
+import (
+	"go-api-boilerplate/migrations"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       fstest.MapFS
+		wantVersions []int64
+		wantErr      bool
+	}{
+		{
+			name: "sorted by version",
+			source: fstest.MapFS{
+				"000002_add_column.up.sql":     {Data: []byte("ALTER TABLE t ADD COLUMN c INT;")},
+				"000002_add_column.down.sql":   {Data: []byte("ALTER TABLE t DROP COLUMN c;")},
+				"000001_create_table.up.sql":   {Data: []byte("CREATE TABLE t (id INT);")},
+				"000001_create_table.down.sql": {Data: []byte("DROP TABLE t;")},
+				"README.md":                    {Data: []byte("ignored")},
+			},
+			wantVersions: []int64{1, 2},
+		},
+		{
+			name:         "empty source",
+			source:       fstest.MapFS{},
+			wantVersions: []int64{},
+		},
+		{
+			name: "missing down file",
+			source: fstest.MapFS{
+				"000001_create_table.up.sql": {Data: []byte("CREATE TABLE t (id INT);")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "conflicting names for one version",
+			source: fstest.MapFS{
+				"000001_create_table.up.sql": {Data: []byte("CREATE TABLE t (id INT);")},
+				"000001_other_name.down.sql": {Data: []byte("DROP TABLE t;")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadMigrations(tt.source)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadMigrations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.wantVersions) {
+				t.Fatalf("LoadMigrations() returned %d migrations, want %d", len(got), len(tt.wantVersions))
+			}
+			for i, m := range got {
+				if m.Version != tt.wantVersions[i] {
+					t.Errorf("LoadMigrations()[%d].Version = %d, want %d", i, m.Version, tt.wantVersions[i])
+				}
+				if m.Up == "" || m.Down == "" {
+					t.Errorf("LoadMigrations()[%d] is missing up or down SQL", i)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	got, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("LoadMigrations() error = %v", err)
+	}
+	if len(got) == 0 {
+		t.Fatal("LoadMigrations() found no embedded migrations")
+	}
+	for i, m := range got {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %s has version %d, want contiguous version %d", m.Name, m.Version, i+1)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+ALTER TABLE books DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE books ALTER COLUMN created_at TYPE TIMESTAMP;
@@ -0,0 +1,2 @@
+ALTER TABLE books ALTER COLUMN created_at TYPE TIMESTAMPTZ;
+ALTER TABLE books ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
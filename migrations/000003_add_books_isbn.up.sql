@@ -0,0 +1,2 @@
+ALTER TABLE books ADD COLUMN isbn VARCHAR(13);
+ALTER TABLE books ADD CONSTRAINT books_isbn_key UNIQUE (isbn);
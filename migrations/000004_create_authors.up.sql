@@ -0,0 +1,30 @@
+CREATE TABLE authors (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX authors_name_idx ON authors (name);
+
+CREATE TABLE book_authors (
+    book_id INTEGER NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    author_id INTEGER NOT NULL REFERENCES authors (id) ON DELETE RESTRICT,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (book_id, author_id),
+    UNIQUE (book_id, position)
+);
+
+CREATE INDEX book_authors_author_id_idx ON book_authors (author_id);
+
+-- Turn the existing free-text author of every book into an author row,
+-- sharing one row between books with the same (trimmed) author string.
+INSERT INTO authors (name)
+SELECT DISTINCT btrim(author) FROM books ORDER BY 1;
+
+INSERT INTO book_authors (book_id, author_id, position)
+SELECT b.id, a.id, 1
+FROM books b
+JOIN authors a ON a.name = btrim(b.author);
+
+UPDATE books SET author = btrim(author) WHERE author <> btrim(author);
@@ -0,0 +1,8 @@
+-- Trashed books would otherwise come back as live rows.
+DELETE FROM books WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS books_deleted_at_idx;
+DROP INDEX IF EXISTS books_isbn_key;
+ALTER TABLE books ADD CONSTRAINT books_isbn_key UNIQUE (isbn);
+
+ALTER TABLE books DROP COLUMN IF EXISTS deleted_at;
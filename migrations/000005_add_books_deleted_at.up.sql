@@ -0,0 +1,8 @@
+ALTER TABLE books ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- ISBNs only need to be unique among live books, so a trashed copy does not
+-- block re-adding the same edition.
+ALTER TABLE books DROP CONSTRAINT books_isbn_key;
+CREATE UNIQUE INDEX books_isbn_key ON books (isbn) WHERE deleted_at IS NULL;
+
+CREATE INDEX books_deleted_at_idx ON books (deleted_at) WHERE deleted_at IS NOT NULL;
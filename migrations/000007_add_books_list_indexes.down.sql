@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS authors_lower_name_idx;
+DROP INDEX IF EXISTS books_created_at_idx;
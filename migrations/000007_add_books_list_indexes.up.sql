@@ -0,0 +1,3 @@
+-- Support the book list filters and the created_at sort.
+CREATE INDEX books_created_at_idx ON books (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX authors_lower_name_idx ON authors (lower(name));
@@ -0,0 +1,8 @@
+-- Full-text search over title and author. Title words rank above author
+-- words.
+ALTER TABLE books ADD COLUMN search tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', title), 'A') ||
+    setweight(to_tsvector('english', author), 'B')
+) STORED;
+
+CREATE INDEX books_search_idx ON books USING GIN (search);
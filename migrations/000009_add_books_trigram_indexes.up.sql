@@ -0,0 +1,7 @@
+-- Trigram indexes serve fuzzy matching (<%) and prefix suggestions
+-- (ILIKE 'prefix%') on titles and author names.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX books_title_trgm_idx ON books USING GIN (title gin_trgm_ops) WHERE deleted_at IS NULL;
+CREATE INDEX books_author_trgm_idx ON books USING GIN (author gin_trgm_ops) WHERE deleted_at IS NULL;
+CREATE INDEX authors_name_trgm_idx ON authors USING GIN (name gin_trgm_ops);
@@ -0,0 +1,32 @@
+-- Background jobs. Workers claim queued jobs whose run_at has come, and
+-- running jobs whose lease (locked_until) ran out because their worker died.
+CREATE TABLE jobs (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    params JSONB NOT NULL DEFAULT '{}',
+    processed INTEGER NOT NULL DEFAULT 0,
+    result JSONB,
+    error TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMPTZ,
+    artifact_name VARCHAR(255),
+    artifact_type VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX jobs_claim_idx ON jobs (run_at) WHERE status IN ('queued', 'running');
+
+-- The uploaded input and produced artifact of a job, split into chunks so
+-- neither has to be held in memory whole.
+CREATE TABLE job_files (
+    job_id INTEGER NOT NULL REFERENCES jobs (id) ON DELETE CASCADE,
+    name VARCHAR(20) NOT NULL,
+    seq INTEGER NOT NULL,
+    data BYTEA NOT NULL,
+    PRIMARY KEY (job_id, name, seq)
+);
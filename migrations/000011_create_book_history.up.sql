@@ -0,0 +1,49 @@
+-- The history of every book: one row per version, holding the book before
+-- and after the write that produced it. Rows outlive the books they describe.
+CREATE TABLE book_history (
+    book_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    before JSONB,
+    after JSONB NOT NULL,
+    actor TEXT NOT NULL DEFAULT '',
+    request_id TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (book_id, version)
+);
+
+-- Recorded history is never rewritten.
+CREATE FUNCTION book_history_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'book_history rows cannot be changed';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER book_history_immutable
+    BEFORE UPDATE OR DELETE ON book_history
+    FOR EACH ROW EXECUTE FUNCTION book_history_immutable();
+
+-- Books written before the history existed start it with a create at their
+-- current version, so point-in-time reads find them.
+INSERT INTO book_history (book_id, version, action, after, created_at)
+SELECT b.id, b.version, 'create', jsonb_strip_nulls(jsonb_build_object(
+    'id', b.id,
+    'title', b.title,
+    'author', b.author,
+    'authors', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object(
+            'id', a.id,
+            'name', a.name,
+            'created_at', a.created_at,
+            'updated_at', a.updated_at
+        ) ORDER BY ba.position)
+        FROM book_authors ba JOIN authors a ON a.id = ba.author_id
+        WHERE ba.book_id = b.id
+    ), '[]'::jsonb),
+    'isbn', b.isbn,
+    'version', b.version,
+    'created_at', b.created_at,
+    'updated_at', b.updated_at,
+    'deleted_at', b.deleted_at
+)), b.created_at
+FROM books b;
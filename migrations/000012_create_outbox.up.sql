@@ -0,0 +1,16 @@
+-- Events waiting to be relayed to other services, written in the transaction
+-- of the change they announce. Relayed rows are deleted; rows that kept
+-- failing are dead-lettered (dead_at set) and kept for inspection.
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    type TEXT NOT NULL,
+    key TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dead_at TIMESTAMPTZ
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (key, id) WHERE dead_at IS NULL;
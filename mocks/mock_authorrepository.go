@@ -0,0 +1,160 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/out/authorrepository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/out/authorrepository.go -destination=mocks/mock_authorrepository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "go-api-boilerplate/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuthorRepository is a mock of AuthorRepository interface.
+type MockAuthorRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthorRepositoryMockRecorder is the mock recorder for MockAuthorRepository.
+type MockAuthorRepositoryMockRecorder struct {
+	mock *MockAuthorRepository
+}
+
+// NewMockAuthorRepository creates a new mock instance.
+func NewMockAuthorRepository(ctrl *gomock.Controller) *MockAuthorRepository {
+	mock := &MockAuthorRepository{ctrl: ctrl}
+	mock.recorder = &MockAuthorRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorRepository) EXPECT() *MockAuthorRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountAuthors mocks base method.
+func (m *MockAuthorRepository) CountAuthors(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAuthors", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAuthors indicates an expected call of CountAuthors.
+func (mr *MockAuthorRepositoryMockRecorder) CountAuthors(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAuthors", reflect.TypeOf((*MockAuthorRepository)(nil).CountAuthors), ctx)
+}
+
+// CreateAuthor mocks base method.
+func (m *MockAuthorRepository) CreateAuthor(ctx context.Context, author domain.Author) (domain.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthor", ctx, author)
+	ret0, _ := ret[0].(domain.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuthor indicates an expected call of CreateAuthor.
+func (mr *MockAuthorRepositoryMockRecorder) CreateAuthor(ctx, author any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthor", reflect.TypeOf((*MockAuthorRepository)(nil).CreateAuthor), ctx, author)
+}
+
+// DeleteAuthor mocks base method.
+func (m *MockAuthorRepository) DeleteAuthor(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuthor", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAuthor indicates an expected call of DeleteAuthor.
+func (mr *MockAuthorRepositoryMockRecorder) DeleteAuthor(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthor", reflect.TypeOf((*MockAuthorRepository)(nil).DeleteAuthor), ctx, id)
+}
+
+// FindOrCreateAuthorByName mocks base method.
+func (m *MockAuthorRepository) FindOrCreateAuthorByName(ctx context.Context, name string) (domain.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOrCreateAuthorByName", ctx, name)
+	ret0, _ := ret[0].(domain.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOrCreateAuthorByName indicates an expected call of FindOrCreateAuthorByName.
+func (mr *MockAuthorRepositoryMockRecorder) FindOrCreateAuthorByName(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOrCreateAuthorByName", reflect.TypeOf((*MockAuthorRepository)(nil).FindOrCreateAuthorByName), ctx, name)
+}
+
+// GetAuthor mocks base method.
+func (m *MockAuthorRepository) GetAuthor(ctx context.Context, id int) (domain.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthor", ctx, id)
+	ret0, _ := ret[0].(domain.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthor indicates an expected call of GetAuthor.
+func (mr *MockAuthorRepositoryMockRecorder) GetAuthor(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthor", reflect.TypeOf((*MockAuthorRepository)(nil).GetAuthor), ctx, id)
+}
+
+// GetAuthors mocks base method.
+func (m *MockAuthorRepository) GetAuthors(ctx context.Context, offset, limit int) ([]domain.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthors", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthors indicates an expected call of GetAuthors.
+func (mr *MockAuthorRepositoryMockRecorder) GetAuthors(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthors", reflect.TypeOf((*MockAuthorRepository)(nil).GetAuthors), ctx, offset, limit)
+}
+
+// GetAuthorsByIDs mocks base method.
+func (m *MockAuthorRepository) GetAuthorsByIDs(ctx context.Context, ids []int) ([]domain.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorsByIDs", ctx, ids)
+	ret0, _ := ret[0].([]domain.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorsByIDs indicates an expected call of GetAuthorsByIDs.
+func (mr *MockAuthorRepositoryMockRecorder) GetAuthorsByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorsByIDs", reflect.TypeOf((*MockAuthorRepository)(nil).GetAuthorsByIDs), ctx, ids)
+}
+
+// UpdateAuthor mocks base method.
+func (m *MockAuthorRepository) UpdateAuthor(ctx context.Context, author domain.Author) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAuthor", ctx, author)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAuthor indicates an expected call of UpdateAuthor.
+func (mr *MockAuthorRepositoryMockRecorder) UpdateAuthor(ctx, author any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthor", reflect.TypeOf((*MockAuthorRepository)(nil).UpdateAuthor), ctx, author)
+}
@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/in/authorusecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/in/authorusecase.go -destination=mocks/mock_authorusecase.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "go-api-boilerplate/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAuthorUseCase is a mock of AuthorUseCase interface.
+type MockAuthorUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorUseCaseMockRecorder
+	isgomock struct{}
+}
+
+// MockAuthorUseCaseMockRecorder is the mock recorder for MockAuthorUseCase.
+type MockAuthorUseCaseMockRecorder struct {
+	mock *MockAuthorUseCase
+}
+
+// NewMockAuthorUseCase creates a new mock instance.
+func NewMockAuthorUseCase(ctrl *gomock.Controller) *MockAuthorUseCase {
+	mock := &MockAuthorUseCase{ctrl: ctrl}
+	mock.recorder = &MockAuthorUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorUseCase) EXPECT() *MockAuthorUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CreateAuthor mocks base method.
+func (m *MockAuthorUseCase) CreateAuthor(ctx context.Context, author domain.Author) (domain.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthor", ctx, author)
+	ret0, _ := ret[0].(domain.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuthor indicates an expected call of CreateAuthor.
+func (mr *MockAuthorUseCaseMockRecorder) CreateAuthor(ctx, author any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthor", reflect.TypeOf((*MockAuthorUseCase)(nil).CreateAuthor), ctx, author)
+}
+
+// DeleteAuthor mocks base method.
+func (m *MockAuthorUseCase) DeleteAuthor(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuthor", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAuthor indicates an expected call of DeleteAuthor.
+func (mr *MockAuthorUseCaseMockRecorder) DeleteAuthor(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthor", reflect.TypeOf((*MockAuthorUseCase)(nil).DeleteAuthor), ctx, id)
+}
+
+// GetAuthor mocks base method.
+func (m *MockAuthorUseCase) GetAuthor(ctx context.Context, id int) (domain.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthor", ctx, id)
+	ret0, _ := ret[0].(domain.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthor indicates an expected call of GetAuthor.
+func (mr *MockAuthorUseCaseMockRecorder) GetAuthor(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthor", reflect.TypeOf((*MockAuthorUseCase)(nil).GetAuthor), ctx, id)
+}
+
+// GetAuthorBooks mocks base method.
+func (m *MockAuthorUseCase) GetAuthorBooks(ctx context.Context, id, page, perPage int) ([]domain.Book, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorBooks", ctx, id, page, perPage)
+	ret0, _ := ret[0].([]domain.Book)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAuthorBooks indicates an expected call of GetAuthorBooks.
+func (mr *MockAuthorUseCaseMockRecorder) GetAuthorBooks(ctx, id, page, perPage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorBooks", reflect.TypeOf((*MockAuthorUseCase)(nil).GetAuthorBooks), ctx, id, page, perPage)
+}
+
+// GetAuthors mocks base method.
+func (m *MockAuthorUseCase) GetAuthors(ctx context.Context, page, perPage int) ([]domain.Author, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthors", ctx, page, perPage)
+	ret0, _ := ret[0].([]domain.Author)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAuthors indicates an expected call of GetAuthors.
+func (mr *MockAuthorUseCaseMockRecorder) GetAuthors(ctx, page, perPage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthors", reflect.TypeOf((*MockAuthorUseCase)(nil).GetAuthors), ctx, page, perPage)
+}
+
+// UpdateAuthor mocks base method.
+func (m *MockAuthorUseCase) UpdateAuthor(ctx context.Context, author domain.Author) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAuthor", ctx, author)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAuthor indicates an expected call of UpdateAuthor.
+func (mr *MockAuthorUseCaseMockRecorder) UpdateAuthor(ctx, author any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthor", reflect.TypeOf((*MockAuthorUseCase)(nil).UpdateAuthor), ctx, author)
+}
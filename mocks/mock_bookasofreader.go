@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/out/bookasofreader.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/out/bookasofreader.go -destination=mocks/mock_bookasofreader.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "go-api-boilerplate/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBookAsOfReader is a mock of BookAsOfReader interface.
+type MockBookAsOfReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookAsOfReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockBookAsOfReaderMockRecorder is the mock recorder for MockBookAsOfReader.
+type MockBookAsOfReaderMockRecorder struct {
+	mock *MockBookAsOfReader
+}
+
+// NewMockBookAsOfReader creates a new mock instance.
+func NewMockBookAsOfReader(ctrl *gomock.Controller) *MockBookAsOfReader {
+	mock := &MockBookAsOfReader{ctrl: ctrl}
+	mock.recorder = &MockBookAsOfReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookAsOfReader) EXPECT() *MockBookAsOfReaderMockRecorder {
+	return m.recorder
+}
+
+// CountBooksAsOf mocks base method.
+func (m *MockBookAsOfReader) CountBooksAsOf(ctx context.Context, criteria domain.BookCriteria, at time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBooksAsOf", ctx, criteria, at)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBooksAsOf indicates an expected call of CountBooksAsOf.
+func (mr *MockBookAsOfReaderMockRecorder) CountBooksAsOf(ctx, criteria, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBooksAsOf", reflect.TypeOf((*MockBookAsOfReader)(nil).CountBooksAsOf), ctx, criteria, at)
+}
+
+// GetBookAsOf mocks base method.
+func (m *MockBookAsOfReader) GetBookAsOf(ctx context.Context, id int, at time.Time) (domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookAsOf", ctx, id, at)
+	ret0, _ := ret[0].(domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookAsOf indicates an expected call of GetBookAsOf.
+func (mr *MockBookAsOfReaderMockRecorder) GetBookAsOf(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookAsOf", reflect.TypeOf((*MockBookAsOfReader)(nil).GetBookAsOf), ctx, id, at)
+}
+
+// GetBooksAsOf mocks base method.
+func (m *MockBookAsOfReader) GetBooksAsOf(ctx context.Context, criteria domain.BookCriteria, at time.Time, offset, limit int) ([]domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooksAsOf", ctx, criteria, at, offset, limit)
+	ret0, _ := ret[0].([]domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBooksAsOf indicates an expected call of GetBooksAsOf.
+func (mr *MockBookAsOfReaderMockRecorder) GetBooksAsOf(ctx, criteria, at, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksAsOf", reflect.TypeOf((*MockBookAsOfReader)(nil).GetBooksAsOf), ctx, criteria, at, offset, limit)
+}
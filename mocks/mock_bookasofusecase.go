@@ -0,0 +1,74 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/in/bookasofusecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/in/bookasofusecase.go -destination=mocks/mock_bookasofusecase.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "go-api-boilerplate/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBookAsOfUseCase is a mock of BookAsOfUseCase interface.
+type MockBookAsOfUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookAsOfUseCaseMockRecorder
+	isgomock struct{}
+}
+
+// MockBookAsOfUseCaseMockRecorder is the mock recorder for MockBookAsOfUseCase.
+type MockBookAsOfUseCaseMockRecorder struct {
+	mock *MockBookAsOfUseCase
+}
+
+// NewMockBookAsOfUseCase creates a new mock instance.
+func NewMockBookAsOfUseCase(ctrl *gomock.Controller) *MockBookAsOfUseCase {
+	mock := &MockBookAsOfUseCase{ctrl: ctrl}
+	mock.recorder = &MockBookAsOfUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookAsOfUseCase) EXPECT() *MockBookAsOfUseCaseMockRecorder {
+	return m.recorder
+}
+
+// GetBookAsOf mocks base method.
+func (m *MockBookAsOfUseCase) GetBookAsOf(ctx context.Context, id int, at time.Time) (domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookAsOf", ctx, id, at)
+	ret0, _ := ret[0].(domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookAsOf indicates an expected call of GetBookAsOf.
+func (mr *MockBookAsOfUseCaseMockRecorder) GetBookAsOf(ctx, id, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookAsOf", reflect.TypeOf((*MockBookAsOfUseCase)(nil).GetBookAsOf), ctx, id, at)
+}
+
+// GetBooksAsOf mocks base method.
+func (m *MockBookAsOfUseCase) GetBooksAsOf(ctx context.Context, criteria domain.BookCriteria, at time.Time, page, perPage int) ([]domain.Book, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooksAsOf", ctx, criteria, at, page, perPage)
+	ret0, _ := ret[0].([]domain.Book)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBooksAsOf indicates an expected call of GetBooksAsOf.
+func (mr *MockBookAsOfUseCaseMockRecorder) GetBooksAsOf(ctx, criteria, at, page, perPage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksAsOf", reflect.TypeOf((*MockBookAsOfUseCase)(nil).GetBooksAsOf), ctx, criteria, at, page, perPage)
+}
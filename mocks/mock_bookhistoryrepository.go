@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/out/bookhistoryrepository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/out/bookhistoryrepository.go -destination=mocks/mock_bookhistoryrepository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "go-api-boilerplate/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBookHistoryRepository is a mock of BookHistoryRepository interface.
+type MockBookHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookHistoryRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockBookHistoryRepositoryMockRecorder is the mock recorder for MockBookHistoryRepository.
+type MockBookHistoryRepositoryMockRecorder struct {
+	mock *MockBookHistoryRepository
+}
+
+// NewMockBookHistoryRepository creates a new mock instance.
+func NewMockBookHistoryRepository(ctrl *gomock.Controller) *MockBookHistoryRepository {
+	mock := &MockBookHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockBookHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookHistoryRepository) EXPECT() *MockBookHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddBookChanges mocks base method.
+func (m *MockBookHistoryRepository) AddBookChanges(ctx context.Context, changes []domain.BookChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBookChanges", ctx, changes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddBookChanges indicates an expected call of AddBookChanges.
+func (mr *MockBookHistoryRepositoryMockRecorder) AddBookChanges(ctx, changes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBookChanges", reflect.TypeOf((*MockBookHistoryRepository)(nil).AddBookChanges), ctx, changes)
+}
+
+// CountBookHistory mocks base method.
+func (m *MockBookHistoryRepository) CountBookHistory(ctx context.Context, bookID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBookHistory", ctx, bookID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBookHistory indicates an expected call of CountBookHistory.
+func (mr *MockBookHistoryRepositoryMockRecorder) CountBookHistory(ctx, bookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBookHistory", reflect.TypeOf((*MockBookHistoryRepository)(nil).CountBookHistory), ctx, bookID)
+}
+
+// GetBookChange mocks base method.
+func (m *MockBookHistoryRepository) GetBookChange(ctx context.Context, bookID, version int) (domain.BookChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookChange", ctx, bookID, version)
+	ret0, _ := ret[0].(domain.BookChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookChange indicates an expected call of GetBookChange.
+func (mr *MockBookHistoryRepositoryMockRecorder) GetBookChange(ctx, bookID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookChange", reflect.TypeOf((*MockBookHistoryRepository)(nil).GetBookChange), ctx, bookID, version)
+}
+
+// GetBookHistory mocks base method.
+func (m *MockBookHistoryRepository) GetBookHistory(ctx context.Context, bookID, offset, limit int) ([]domain.BookChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookHistory", ctx, bookID, offset, limit)
+	ret0, _ := ret[0].([]domain.BookChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookHistory indicates an expected call of GetBookHistory.
+func (mr *MockBookHistoryRepositoryMockRecorder) GetBookHistory(ctx, bookID, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookHistory", reflect.TypeOf((*MockBookHistoryRepository)(nil).GetBookHistory), ctx, bookID, offset, limit)
+}
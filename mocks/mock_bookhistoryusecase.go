@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/in/bookhistoryusecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/in/bookhistoryusecase.go -destination=mocks/mock_bookhistoryusecase.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "go-api-boilerplate/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBookHistoryUseCase is a mock of BookHistoryUseCase interface.
+type MockBookHistoryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookHistoryUseCaseMockRecorder
+	isgomock struct{}
+}
+
+// MockBookHistoryUseCaseMockRecorder is the mock recorder for MockBookHistoryUseCase.
+type MockBookHistoryUseCaseMockRecorder struct {
+	mock *MockBookHistoryUseCase
+}
+
+// NewMockBookHistoryUseCase creates a new mock instance.
+func NewMockBookHistoryUseCase(ctrl *gomock.Controller) *MockBookHistoryUseCase {
+	mock := &MockBookHistoryUseCase{ctrl: ctrl}
+	mock.recorder = &MockBookHistoryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookHistoryUseCase) EXPECT() *MockBookHistoryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// GetBookHistory mocks base method.
+func (m *MockBookHistoryUseCase) GetBookHistory(ctx context.Context, id, page, perPage int) ([]domain.BookChange, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookHistory", ctx, id, page, perPage)
+	ret0, _ := ret[0].([]domain.BookChange)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBookHistory indicates an expected call of GetBookHistory.
+func (mr *MockBookHistoryUseCaseMockRecorder) GetBookHistory(ctx, id, page, perPage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookHistory", reflect.TypeOf((*MockBookHistoryUseCase)(nil).GetBookHistory), ctx, id, page, perPage)
+}
+
+// GetBookVersion mocks base method.
+func (m *MockBookHistoryUseCase) GetBookVersion(ctx context.Context, id, version int) (domain.BookChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookVersion", ctx, id, version)
+	ret0, _ := ret[0].(domain.BookChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookVersion indicates an expected call of GetBookVersion.
+func (mr *MockBookHistoryUseCaseMockRecorder) GetBookVersion(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookVersion", reflect.TypeOf((*MockBookHistoryUseCase)(nil).GetBookVersion), ctx, id, version)
+}
+
+// RevertBook mocks base method.
+func (m *MockBookHistoryUseCase) RevertBook(ctx context.Context, id, version, current int) (domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevertBook", ctx, id, version, current)
+	ret0, _ := ret[0].(domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevertBook indicates an expected call of RevertBook.
+func (mr *MockBookHistoryUseCaseMockRecorder) RevertBook(ctx, id, version, current any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevertBook", reflect.TypeOf((*MockBookHistoryUseCase)(nil).RevertBook), ctx, id, version, current)
+}
@@ -0,0 +1,97 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/in/bookimportusecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/in/bookimportusecase.go -destination=mocks/mock_bookimportusecase.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	in "go-api-boilerplate/internal/application/port/in"
+	domain "go-api-boilerplate/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBookRowReader is a mock of BookRowReader interface.
+type MockBookRowReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookRowReaderMockRecorder
+	isgomock struct{}
+}
+
+// MockBookRowReaderMockRecorder is the mock recorder for MockBookRowReader.
+type MockBookRowReaderMockRecorder struct {
+	mock *MockBookRowReader
+}
+
+// NewMockBookRowReader creates a new mock instance.
+func NewMockBookRowReader(ctrl *gomock.Controller) *MockBookRowReader {
+	mock := &MockBookRowReader{ctrl: ctrl}
+	mock.recorder = &MockBookRowReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookRowReader) EXPECT() *MockBookRowReaderMockRecorder {
+	return m.recorder
+}
+
+// Read mocks base method.
+func (m *MockBookRowReader) Read() (domain.ImportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Read")
+	ret0, _ := ret[0].(domain.ImportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Read indicates an expected call of Read.
+func (mr *MockBookRowReaderMockRecorder) Read() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Read", reflect.TypeOf((*MockBookRowReader)(nil).Read))
+}
+
+// MockBookImportUseCase is a mock of BookImportUseCase interface.
+type MockBookImportUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookImportUseCaseMockRecorder
+	isgomock struct{}
+}
+
+// MockBookImportUseCaseMockRecorder is the mock recorder for MockBookImportUseCase.
+type MockBookImportUseCaseMockRecorder struct {
+	mock *MockBookImportUseCase
+}
+
+// NewMockBookImportUseCase creates a new mock instance.
+func NewMockBookImportUseCase(ctrl *gomock.Controller) *MockBookImportUseCase {
+	mock := &MockBookImportUseCase{ctrl: ctrl}
+	mock.recorder = &MockBookImportUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookImportUseCase) EXPECT() *MockBookImportUseCaseMockRecorder {
+	return m.recorder
+}
+
+// ImportBooks mocks base method.
+func (m *MockBookImportUseCase) ImportBooks(ctx context.Context, rows in.BookRowReader, dryRun bool) (domain.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportBooks", ctx, rows, dryRun)
+	ret0, _ := ret[0].(domain.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportBooks indicates an expected call of ImportBooks.
+func (mr *MockBookImportUseCaseMockRecorder) ImportBooks(ctx, rows, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportBooks", reflect.TypeOf((*MockBookImportUseCase)(nil).ImportBooks), ctx, rows, dryRun)
+}
@@ -13,6 +13,7 @@ import (
 	context "context"
 	domain "go-api-boilerplate/internal/domain"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -41,12 +42,58 @@ func (m *MockBookRepository) EXPECT() *MockBookRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountBooks mocks base method.
+func (m *MockBookRepository) CountBooks(ctx context.Context, criteria domain.BookCriteria) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBooks", ctx, criteria)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBooks indicates an expected call of CountBooks.
+func (mr *MockBookRepositoryMockRecorder) CountBooks(ctx, criteria any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBooks", reflect.TypeOf((*MockBookRepository)(nil).CountBooks), ctx, criteria)
+}
+
+// CountBooksByAuthor mocks base method.
+func (m *MockBookRepository) CountBooksByAuthor(ctx context.Context, authorID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBooksByAuthor", ctx, authorID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBooksByAuthor indicates an expected call of CountBooksByAuthor.
+func (mr *MockBookRepositoryMockRecorder) CountBooksByAuthor(ctx, authorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBooksByAuthor", reflect.TypeOf((*MockBookRepository)(nil).CountBooksByAuthor), ctx, authorID)
+}
+
+// CountDeletedBooks mocks base method.
+func (m *MockBookRepository) CountDeletedBooks(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDeletedBooks", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDeletedBooks indicates an expected call of CountDeletedBooks.
+func (mr *MockBookRepositoryMockRecorder) CountDeletedBooks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDeletedBooks", reflect.TypeOf((*MockBookRepository)(nil).CountDeletedBooks), ctx)
+}
+
 // CreateBook mocks base method.
-func (m *MockBookRepository) CreateBook(ctx context.Context, book domain.Book) error {
+func (m *MockBookRepository) CreateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateBook", ctx, book)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CreateBook indicates an expected call of CreateBook.
@@ -55,18 +102,62 @@ func (mr *MockBookRepositoryMockRecorder) CreateBook(ctx, book any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBook", reflect.TypeOf((*MockBookRepository)(nil).CreateBook), ctx, book)
 }
 
+// CreateBooks mocks base method.
+func (m *MockBookRepository) CreateBooks(ctx context.Context, books []domain.Book, mode domain.BatchMode) ([]domain.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBooks", ctx, books, mode)
+	ret0, _ := ret[0].([]domain.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBooks indicates an expected call of CreateBooks.
+func (mr *MockBookRepositoryMockRecorder) CreateBooks(ctx, books, mode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBooks", reflect.TypeOf((*MockBookRepository)(nil).CreateBooks), ctx, books, mode)
+}
+
 // DeleteBook mocks base method.
-func (m *MockBookRepository) DeleteBook(ctx context.Context, id int) error {
+func (m *MockBookRepository) DeleteBook(ctx context.Context, id, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteBook", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteBook", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteBook indicates an expected call of DeleteBook.
-func (mr *MockBookRepositoryMockRecorder) DeleteBook(ctx, id any) *gomock.Call {
+func (mr *MockBookRepositoryMockRecorder) DeleteBook(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookRepository)(nil).DeleteBook), ctx, id, version)
+}
+
+// ExportBooks mocks base method.
+func (m *MockBookRepository) ExportBooks(ctx context.Context, criteria domain.BookCriteria, fn func([]domain.Book) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBooks", ctx, criteria, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportBooks indicates an expected call of ExportBooks.
+func (mr *MockBookRepositoryMockRecorder) ExportBooks(ctx, criteria, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBooks", reflect.TypeOf((*MockBookRepository)(nil).ExportBooks), ctx, criteria, fn)
+}
+
+// FindExistingISBNs mocks base method.
+func (m *MockBookRepository) FindExistingISBNs(ctx context.Context, isbns []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExistingISBNs", ctx, isbns)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExistingISBNs indicates an expected call of FindExistingISBNs.
+func (mr *MockBookRepositoryMockRecorder) FindExistingISBNs(ctx, isbns any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookRepository)(nil).DeleteBook), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExistingISBNs", reflect.TypeOf((*MockBookRepository)(nil).FindExistingISBNs), ctx, isbns)
 }
 
 // GetBook mocks base method.
@@ -84,29 +175,194 @@ func (mr *MockBookRepositoryMockRecorder) GetBook(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBook", reflect.TypeOf((*MockBookRepository)(nil).GetBook), ctx, id)
 }
 
+// GetBookByISBN mocks base method.
+func (m *MockBookRepository) GetBookByISBN(ctx context.Context, isbn string) (domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByISBN", ctx, isbn)
+	ret0, _ := ret[0].(domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByISBN indicates an expected call of GetBookByISBN.
+func (mr *MockBookRepositoryMockRecorder) GetBookByISBN(ctx, isbn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByISBN", reflect.TypeOf((*MockBookRepository)(nil).GetBookByISBN), ctx, isbn)
+}
+
 // GetBooks mocks base method.
-func (m *MockBookRepository) GetBooks(ctx context.Context, offset, limit int) ([]domain.Book, error) {
+func (m *MockBookRepository) GetBooks(ctx context.Context, criteria domain.BookCriteria, offset, limit int) ([]domain.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBooks", ctx, offset, limit)
+	ret := m.ctrl.Call(m, "GetBooks", ctx, criteria, offset, limit)
 	ret0, _ := ret[0].([]domain.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetBooks indicates an expected call of GetBooks.
-func (mr *MockBookRepositoryMockRecorder) GetBooks(ctx, offset, limit any) *gomock.Call {
+func (mr *MockBookRepositoryMockRecorder) GetBooks(ctx, criteria, offset, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockBookRepository)(nil).GetBooks), ctx, offset, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockBookRepository)(nil).GetBooks), ctx, criteria, offset, limit)
 }
 
-// UpdateBook mocks base method.
-func (m *MockBookRepository) UpdateBook(ctx context.Context, book domain.Book) error {
+// GetBooksByAuthor mocks base method.
+func (m *MockBookRepository) GetBooksByAuthor(ctx context.Context, authorID, offset, limit int) ([]domain.Book, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateBook", ctx, book)
+	ret := m.ctrl.Call(m, "GetBooksByAuthor", ctx, authorID, offset, limit)
+	ret0, _ := ret[0].([]domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBooksByAuthor indicates an expected call of GetBooksByAuthor.
+func (mr *MockBookRepositoryMockRecorder) GetBooksByAuthor(ctx, authorID, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksByAuthor", reflect.TypeOf((*MockBookRepository)(nil).GetBooksByAuthor), ctx, authorID, offset, limit)
+}
+
+// GetBooksByCursor mocks base method.
+func (m *MockBookRepository) GetBooksByCursor(ctx context.Context, criteria domain.BookCriteria, cursor domain.Cursor, limit int) ([]domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooksByCursor", ctx, criteria, cursor, limit)
+	ret0, _ := ret[0].([]domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBooksByCursor indicates an expected call of GetBooksByCursor.
+func (mr *MockBookRepositoryMockRecorder) GetBooksByCursor(ctx, criteria, cursor, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksByCursor", reflect.TypeOf((*MockBookRepository)(nil).GetBooksByCursor), ctx, criteria, cursor, limit)
+}
+
+// GetDeletedBooks mocks base method.
+func (m *MockBookRepository) GetDeletedBooks(ctx context.Context, offset, limit int) ([]domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedBooks", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedBooks indicates an expected call of GetDeletedBooks.
+func (mr *MockBookRepositoryMockRecorder) GetDeletedBooks(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedBooks", reflect.TypeOf((*MockBookRepository)(nil).GetDeletedBooks), ctx, offset, limit)
+}
+
+// LockBook mocks base method.
+func (m *MockBookRepository) LockBook(ctx context.Context, id int) (domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockBook", ctx, id)
+	ret0, _ := ret[0].(domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockBook indicates an expected call of LockBook.
+func (mr *MockBookRepositoryMockRecorder) LockBook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockBook", reflect.TypeOf((*MockBookRepository)(nil).LockBook), ctx, id)
+}
+
+// LockBooksByAuthor mocks base method.
+func (m *MockBookRepository) LockBooksByAuthor(ctx context.Context, authorID int) ([]domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockBooksByAuthor", ctx, authorID)
+	ret0, _ := ret[0].([]domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockBooksByAuthor indicates an expected call of LockBooksByAuthor.
+func (mr *MockBookRepositoryMockRecorder) LockBooksByAuthor(ctx, authorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockBooksByAuthor", reflect.TypeOf((*MockBookRepository)(nil).LockBooksByAuthor), ctx, authorID)
+}
+
+// PatchBook mocks base method.
+func (m *MockBookRepository) PatchBook(ctx context.Context, id, version int, patch domain.BookPatch) (domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchBook", ctx, id, version, patch)
+	ret0, _ := ret[0].(domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchBook indicates an expected call of PatchBook.
+func (mr *MockBookRepositoryMockRecorder) PatchBook(ctx, id, version, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchBook", reflect.TypeOf((*MockBookRepository)(nil).PatchBook), ctx, id, version, patch)
+}
+
+// PurgeBook mocks base method.
+func (m *MockBookRepository) PurgeBook(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeBook", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// PurgeBook indicates an expected call of PurgeBook.
+func (mr *MockBookRepositoryMockRecorder) PurgeBook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeBook", reflect.TypeOf((*MockBookRepository)(nil).PurgeBook), ctx, id)
+}
+
+// PurgeDeletedBooks mocks base method.
+func (m *MockBookRepository) PurgeDeletedBooks(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedBooks", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedBooks indicates an expected call of PurgeDeletedBooks.
+func (mr *MockBookRepositoryMockRecorder) PurgeDeletedBooks(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedBooks", reflect.TypeOf((*MockBookRepository)(nil).PurgeDeletedBooks), ctx, before)
+}
+
+// RefreshBylines mocks base method.
+func (m *MockBookRepository) RefreshBylines(ctx context.Context, ids []int) ([]domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshBylines", ctx, ids)
+	ret0, _ := ret[0].([]domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshBylines indicates an expected call of RefreshBylines.
+func (mr *MockBookRepositoryMockRecorder) RefreshBylines(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshBylines", reflect.TypeOf((*MockBookRepository)(nil).RefreshBylines), ctx, ids)
+}
+
+// RestoreBook mocks base method.
+func (m *MockBookRepository) RestoreBook(ctx context.Context, id int) (domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBook", ctx, id)
+	ret0, _ := ret[0].(domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBook indicates an expected call of RestoreBook.
+func (mr *MockBookRepositoryMockRecorder) RestoreBook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBook", reflect.TypeOf((*MockBookRepository)(nil).RestoreBook), ctx, id)
+}
+
+// UpdateBook mocks base method.
+func (m *MockBookRepository) UpdateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBook", ctx, book)
+	ret0, _ := ret[0].(domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // UpdateBook indicates an expected call of UpdateBook.
 func (mr *MockBookRepositoryMockRecorder) UpdateBook(ctx, book any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
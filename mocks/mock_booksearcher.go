@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/out/booksearcher.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/out/booksearcher.go -destination=mocks/mock_booksearcher.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "go-api-boilerplate/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBookSearcher is a mock of BookSearcher interface.
+type MockBookSearcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookSearcherMockRecorder
+	isgomock struct{}
+}
+
+// MockBookSearcherMockRecorder is the mock recorder for MockBookSearcher.
+type MockBookSearcherMockRecorder struct {
+	mock *MockBookSearcher
+}
+
+// NewMockBookSearcher creates a new mock instance.
+func NewMockBookSearcher(ctrl *gomock.Controller) *MockBookSearcher {
+	mock := &MockBookSearcher{ctrl: ctrl}
+	mock.recorder = &MockBookSearcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookSearcher) EXPECT() *MockBookSearcherMockRecorder {
+	return m.recorder
+}
+
+// CountFuzzySearchResults mocks base method.
+func (m *MockBookSearcher) CountFuzzySearchResults(ctx context.Context, text string, threshold float64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountFuzzySearchResults", ctx, text, threshold)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountFuzzySearchResults indicates an expected call of CountFuzzySearchResults.
+func (mr *MockBookSearcherMockRecorder) CountFuzzySearchResults(ctx, text, threshold any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFuzzySearchResults", reflect.TypeOf((*MockBookSearcher)(nil).CountFuzzySearchResults), ctx, text, threshold)
+}
+
+// CountSearchResults mocks base method.
+func (m *MockBookSearcher) CountSearchResults(ctx context.Context, query domain.SearchQuery) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSearchResults", ctx, query)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSearchResults indicates an expected call of CountSearchResults.
+func (mr *MockBookSearcherMockRecorder) CountSearchResults(ctx, query any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSearchResults", reflect.TypeOf((*MockBookSearcher)(nil).CountSearchResults), ctx, query)
+}
+
+// FuzzySearchBooks mocks base method.
+func (m *MockBookSearcher) FuzzySearchBooks(ctx context.Context, text string, threshold float64, offset, limit int) ([]domain.BookHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FuzzySearchBooks", ctx, text, threshold, offset, limit)
+	ret0, _ := ret[0].([]domain.BookHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FuzzySearchBooks indicates an expected call of FuzzySearchBooks.
+func (mr *MockBookSearcherMockRecorder) FuzzySearchBooks(ctx, text, threshold, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FuzzySearchBooks", reflect.TypeOf((*MockBookSearcher)(nil).FuzzySearchBooks), ctx, text, threshold, offset, limit)
+}
+
+// SearchBooks mocks base method.
+func (m *MockBookSearcher) SearchBooks(ctx context.Context, query domain.SearchQuery, offset, limit int) ([]domain.BookHit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchBooks", ctx, query, offset, limit)
+	ret0, _ := ret[0].([]domain.BookHit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchBooks indicates an expected call of SearchBooks.
+func (mr *MockBookSearcherMockRecorder) SearchBooks(ctx, query, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBooks", reflect.TypeOf((*MockBookSearcher)(nil).SearchBooks), ctx, query, offset, limit)
+}
+
+// SuggestBooks mocks base method.
+func (m *MockBookSearcher) SuggestBooks(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestBooks", ctx, prefix, limit)
+	ret0, _ := ret[0].([]domain.Suggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestBooks indicates an expected call of SuggestBooks.
+func (mr *MockBookSearcherMockRecorder) SuggestBooks(ctx, prefix, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestBooks", reflect.TypeOf((*MockBookSearcher)(nil).SuggestBooks), ctx, prefix, limit)
+}
@@ -0,0 +1,89 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/in/booksearchusecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/in/booksearchusecase.go -destination=mocks/mock_booksearchusecase.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "go-api-boilerplate/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBookSearchUseCase is a mock of BookSearchUseCase interface.
+type MockBookSearchUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookSearchUseCaseMockRecorder
+	isgomock struct{}
+}
+
+// MockBookSearchUseCaseMockRecorder is the mock recorder for MockBookSearchUseCase.
+type MockBookSearchUseCaseMockRecorder struct {
+	mock *MockBookSearchUseCase
+}
+
+// NewMockBookSearchUseCase creates a new mock instance.
+func NewMockBookSearchUseCase(ctrl *gomock.Controller) *MockBookSearchUseCase {
+	mock := &MockBookSearchUseCase{ctrl: ctrl}
+	mock.recorder = &MockBookSearchUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookSearchUseCase) EXPECT() *MockBookSearchUseCaseMockRecorder {
+	return m.recorder
+}
+
+// FuzzySearchBooks mocks base method.
+func (m *MockBookSearchUseCase) FuzzySearchBooks(ctx context.Context, q string, page, perPage int) ([]domain.BookHit, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FuzzySearchBooks", ctx, q, page, perPage)
+	ret0, _ := ret[0].([]domain.BookHit)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FuzzySearchBooks indicates an expected call of FuzzySearchBooks.
+func (mr *MockBookSearchUseCaseMockRecorder) FuzzySearchBooks(ctx, q, page, perPage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FuzzySearchBooks", reflect.TypeOf((*MockBookSearchUseCase)(nil).FuzzySearchBooks), ctx, q, page, perPage)
+}
+
+// SearchBooks mocks base method.
+func (m *MockBookSearchUseCase) SearchBooks(ctx context.Context, q string, page, perPage int) ([]domain.BookHit, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchBooks", ctx, q, page, perPage)
+	ret0, _ := ret[0].([]domain.BookHit)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchBooks indicates an expected call of SearchBooks.
+func (mr *MockBookSearchUseCaseMockRecorder) SearchBooks(ctx, q, page, perPage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBooks", reflect.TypeOf((*MockBookSearchUseCase)(nil).SearchBooks), ctx, q, page, perPage)
+}
+
+// SuggestBooks mocks base method.
+func (m *MockBookSearchUseCase) SuggestBooks(ctx context.Context, prefix string, limit int) ([]domain.Suggestion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SuggestBooks", ctx, prefix, limit)
+	ret0, _ := ret[0].([]domain.Suggestion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SuggestBooks indicates an expected call of SuggestBooks.
+func (mr *MockBookSearchUseCaseMockRecorder) SuggestBooks(ctx, prefix, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SuggestBooks", reflect.TypeOf((*MockBookSearchUseCase)(nil).SuggestBooks), ctx, prefix, limit)
+}
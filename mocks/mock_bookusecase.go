@@ -13,6 +13,7 @@ import (
 	context "context"
 	domain "go-api-boilerplate/internal/domain"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -42,11 +43,12 @@ func (m *MockBookUseCase) EXPECT() *MockBookUseCaseMockRecorder {
 }
 
 // CreateBook mocks base method.
-func (m *MockBookUseCase) CreateBook(ctx context.Context, book domain.Book) error {
+func (m *MockBookUseCase) CreateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateBook", ctx, book)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CreateBook indicates an expected call of CreateBook.
@@ -55,18 +57,47 @@ func (mr *MockBookUseCaseMockRecorder) CreateBook(ctx, book any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBook", reflect.TypeOf((*MockBookUseCase)(nil).CreateBook), ctx, book)
 }
 
+// CreateBooks mocks base method.
+func (m *MockBookUseCase) CreateBooks(ctx context.Context, books []domain.Book, mode domain.BatchMode) ([]domain.BatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBooks", ctx, books, mode)
+	ret0, _ := ret[0].([]domain.BatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBooks indicates an expected call of CreateBooks.
+func (mr *MockBookUseCaseMockRecorder) CreateBooks(ctx, books, mode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBooks", reflect.TypeOf((*MockBookUseCase)(nil).CreateBooks), ctx, books, mode)
+}
+
 // DeleteBook mocks base method.
-func (m *MockBookUseCase) DeleteBook(ctx context.Context, id int) error {
+func (m *MockBookUseCase) DeleteBook(ctx context.Context, id, version int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteBook", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteBook", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteBook indicates an expected call of DeleteBook.
-func (mr *MockBookUseCaseMockRecorder) DeleteBook(ctx, id any) *gomock.Call {
+func (mr *MockBookUseCaseMockRecorder) DeleteBook(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookUseCase)(nil).DeleteBook), ctx, id, version)
+}
+
+// ExportBooks mocks base method.
+func (m *MockBookUseCase) ExportBooks(ctx context.Context, criteria domain.BookCriteria, fn func([]domain.Book) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBooks", ctx, criteria, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportBooks indicates an expected call of ExportBooks.
+func (mr *MockBookUseCaseMockRecorder) ExportBooks(ctx, criteria, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookUseCase)(nil).DeleteBook), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBooks", reflect.TypeOf((*MockBookUseCase)(nil).ExportBooks), ctx, criteria, fn)
 }
 
 // GetBook mocks base method.
@@ -84,29 +115,136 @@ func (mr *MockBookUseCaseMockRecorder) GetBook(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBook", reflect.TypeOf((*MockBookUseCase)(nil).GetBook), ctx, id)
 }
 
+// GetBookByISBN mocks base method.
+func (m *MockBookUseCase) GetBookByISBN(ctx context.Context, isbn string) (domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByISBN", ctx, isbn)
+	ret0, _ := ret[0].(domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByISBN indicates an expected call of GetBookByISBN.
+func (mr *MockBookUseCaseMockRecorder) GetBookByISBN(ctx, isbn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByISBN", reflect.TypeOf((*MockBookUseCase)(nil).GetBookByISBN), ctx, isbn)
+}
+
 // GetBooks mocks base method.
-func (m *MockBookUseCase) GetBooks(ctx context.Context, page, perPage int) ([]domain.Book, error) {
+func (m *MockBookUseCase) GetBooks(ctx context.Context, criteria domain.BookCriteria, page, perPage int) ([]domain.Book, int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBooks", ctx, page, perPage)
+	ret := m.ctrl.Call(m, "GetBooks", ctx, criteria, page, perPage)
 	ret0, _ := ret[0].([]domain.Book)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetBooks indicates an expected call of GetBooks.
+func (mr *MockBookUseCaseMockRecorder) GetBooks(ctx, criteria, page, perPage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockBookUseCase)(nil).GetBooks), ctx, criteria, page, perPage)
+}
+
+// GetBooksByCursor mocks base method.
+func (m *MockBookUseCase) GetBooksByCursor(ctx context.Context, criteria domain.BookCriteria, cursor domain.Cursor, limit int) (domain.BookPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBooksByCursor", ctx, criteria, cursor, limit)
+	ret0, _ := ret[0].(domain.BookPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetBooks indicates an expected call of GetBooks.
-func (mr *MockBookUseCaseMockRecorder) GetBooks(ctx, page, perPage any) *gomock.Call {
+// GetBooksByCursor indicates an expected call of GetBooksByCursor.
+func (mr *MockBookUseCaseMockRecorder) GetBooksByCursor(ctx, criteria, cursor, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooks", reflect.TypeOf((*MockBookUseCase)(nil).GetBooks), ctx, page, perPage)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBooksByCursor", reflect.TypeOf((*MockBookUseCase)(nil).GetBooksByCursor), ctx, criteria, cursor, limit)
 }
 
-// UpdateBook mocks base method.
-func (m *MockBookUseCase) UpdateBook(ctx context.Context, book domain.Book) error {
+// GetTrash mocks base method.
+func (m *MockBookUseCase) GetTrash(ctx context.Context, page, perPage int) ([]domain.Book, int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateBook", ctx, book)
+	ret := m.ctrl.Call(m, "GetTrash", ctx, page, perPage)
+	ret0, _ := ret[0].([]domain.Book)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTrash indicates an expected call of GetTrash.
+func (mr *MockBookUseCaseMockRecorder) GetTrash(ctx, page, perPage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTrash", reflect.TypeOf((*MockBookUseCase)(nil).GetTrash), ctx, page, perPage)
+}
+
+// PatchBook mocks base method.
+func (m *MockBookUseCase) PatchBook(ctx context.Context, id, version int, patch domain.BookPatch) (domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchBook", ctx, id, version, patch)
+	ret0, _ := ret[0].(domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchBook indicates an expected call of PatchBook.
+func (mr *MockBookUseCaseMockRecorder) PatchBook(ctx, id, version, patch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchBook", reflect.TypeOf((*MockBookUseCase)(nil).PatchBook), ctx, id, version, patch)
+}
+
+// PurgeBook mocks base method.
+func (m *MockBookUseCase) PurgeBook(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeBook", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// PurgeBook indicates an expected call of PurgeBook.
+func (mr *MockBookUseCaseMockRecorder) PurgeBook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeBook", reflect.TypeOf((*MockBookUseCase)(nil).PurgeBook), ctx, id)
+}
+
+// PurgeExpiredBooks mocks base method.
+func (m *MockBookUseCase) PurgeExpiredBooks(ctx context.Context, retention time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredBooks", ctx, retention)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredBooks indicates an expected call of PurgeExpiredBooks.
+func (mr *MockBookUseCaseMockRecorder) PurgeExpiredBooks(ctx, retention any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredBooks", reflect.TypeOf((*MockBookUseCase)(nil).PurgeExpiredBooks), ctx, retention)
+}
+
+// RestoreBook mocks base method.
+func (m *MockBookUseCase) RestoreBook(ctx context.Context, id int) (domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBook", ctx, id)
+	ret0, _ := ret[0].(domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBook indicates an expected call of RestoreBook.
+func (mr *MockBookUseCaseMockRecorder) RestoreBook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBook", reflect.TypeOf((*MockBookUseCase)(nil).RestoreBook), ctx, id)
+}
+
+// UpdateBook mocks base method.
+func (m *MockBookUseCase) UpdateBook(ctx context.Context, book domain.Book) (domain.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBook", ctx, book)
+	ret0, _ := ret[0].(domain.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // UpdateBook indicates an expected call of UpdateBook.
 func (mr *MockBookUseCaseMockRecorder) UpdateBook(ctx, book any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
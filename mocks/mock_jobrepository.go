@@ -0,0 +1,175 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/out/jobrepository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/out/jobrepository.go -destination=mocks/mock_jobrepository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "go-api-boilerplate/internal/domain"
+	io "io"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJobRepository is a mock of JobRepository interface.
+type MockJobRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockJobRepositoryMockRecorder is the mock recorder for MockJobRepository.
+type MockJobRepositoryMockRecorder struct {
+	mock *MockJobRepository
+}
+
+// NewMockJobRepository creates a new mock instance.
+func NewMockJobRepository(ctrl *gomock.Controller) *MockJobRepository {
+	mock := &MockJobRepository{ctrl: ctrl}
+	mock.recorder = &MockJobRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobRepository) EXPECT() *MockJobRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimJob mocks base method.
+func (m *MockJobRepository) ClaimJob(ctx context.Context, lease time.Duration) (domain.Job, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimJob", ctx, lease)
+	ret0, _ := ret[0].(domain.Job)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ClaimJob indicates an expected call of ClaimJob.
+func (mr *MockJobRepositoryMockRecorder) ClaimJob(ctx, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimJob", reflect.TypeOf((*MockJobRepository)(nil).ClaimJob), ctx, lease)
+}
+
+// CompleteJob mocks base method.
+func (m *MockJobRepository) CompleteJob(ctx context.Context, job domain.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteJob indicates an expected call of CompleteJob.
+func (mr *MockJobRepositoryMockRecorder) CompleteJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteJob", reflect.TypeOf((*MockJobRepository)(nil).CompleteJob), ctx, job)
+}
+
+// CreateJob mocks base method.
+func (m *MockJobRepository) CreateJob(ctx context.Context, job domain.Job, input io.Reader) (domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", ctx, job, input)
+	ret0, _ := ret[0].(domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJob indicates an expected call of CreateJob.
+func (mr *MockJobRepositoryMockRecorder) CreateJob(ctx, job, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockJobRepository)(nil).CreateJob), ctx, job, input)
+}
+
+// CreateJobFile mocks base method.
+func (m *MockJobRepository) CreateJobFile(ctx context.Context, id int, name string) (io.WriteCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJobFile", ctx, id, name)
+	ret0, _ := ret[0].(io.WriteCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJobFile indicates an expected call of CreateJobFile.
+func (mr *MockJobRepositoryMockRecorder) CreateJobFile(ctx, id, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJobFile", reflect.TypeOf((*MockJobRepository)(nil).CreateJobFile), ctx, id, name)
+}
+
+// FailJob mocks base method.
+func (m *MockJobRepository) FailJob(ctx context.Context, job domain.Job, retryAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailJob", ctx, job, retryAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailJob indicates an expected call of FailJob.
+func (mr *MockJobRepositoryMockRecorder) FailJob(ctx, job, retryAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailJob", reflect.TypeOf((*MockJobRepository)(nil).FailJob), ctx, job, retryAt)
+}
+
+// GetJob mocks base method.
+func (m *MockJobRepository) GetJob(ctx context.Context, id int) (domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJob", ctx, id)
+	ret0, _ := ret[0].(domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJob indicates an expected call of GetJob.
+func (mr *MockJobRepositoryMockRecorder) GetJob(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockJobRepository)(nil).GetJob), ctx, id)
+}
+
+// OpenJobFile mocks base method.
+func (m *MockJobRepository) OpenJobFile(ctx context.Context, id int, name string) io.Reader {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenJobFile", ctx, id, name)
+	ret0, _ := ret[0].(io.Reader)
+	return ret0
+}
+
+// OpenJobFile indicates an expected call of OpenJobFile.
+func (mr *MockJobRepositoryMockRecorder) OpenJobFile(ctx, id, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenJobFile", reflect.TypeOf((*MockJobRepository)(nil).OpenJobFile), ctx, id, name)
+}
+
+// ReleaseJob mocks base method.
+func (m *MockJobRepository) ReleaseJob(ctx context.Context, job domain.Job) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseJob indicates an expected call of ReleaseJob.
+func (mr *MockJobRepositoryMockRecorder) ReleaseJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseJob", reflect.TypeOf((*MockJobRepository)(nil).ReleaseJob), ctx, job)
+}
+
+// UpdateJobProgress mocks base method.
+func (m *MockJobRepository) UpdateJobProgress(ctx context.Context, job domain.Job, lease time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJobProgress", ctx, job, lease)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateJobProgress indicates an expected call of UpdateJobProgress.
+func (mr *MockJobRepositoryMockRecorder) UpdateJobProgress(ctx, job, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJobProgress", reflect.TypeOf((*MockJobRepository)(nil).UpdateJobProgress), ctx, job, lease)
+}
@@ -0,0 +1,144 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/in/jobusecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/in/jobusecase.go -destination=mocks/mock_jobusecase.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	json "encoding/json"
+	domain "go-api-boilerplate/internal/domain"
+	io "io"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockJobRunner is a mock of JobRunner interface.
+type MockJobRunner struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobRunnerMockRecorder
+	isgomock struct{}
+}
+
+// MockJobRunnerMockRecorder is the mock recorder for MockJobRunner.
+type MockJobRunnerMockRecorder struct {
+	mock *MockJobRunner
+}
+
+// NewMockJobRunner creates a new mock instance.
+func NewMockJobRunner(ctrl *gomock.Controller) *MockJobRunner {
+	mock := &MockJobRunner{ctrl: ctrl}
+	mock.recorder = &MockJobRunnerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobRunner) EXPECT() *MockJobRunnerMockRecorder {
+	return m.recorder
+}
+
+// RunJob mocks base method.
+func (m *MockJobRunner) RunJob(ctx context.Context, job domain.Job, input io.Reader, output io.Writer, progress func(int)) (domain.JobOutcome, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunJob", ctx, job, input, output, progress)
+	ret0, _ := ret[0].(domain.JobOutcome)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunJob indicates an expected call of RunJob.
+func (mr *MockJobRunnerMockRecorder) RunJob(ctx, job, input, output, progress any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunJob", reflect.TypeOf((*MockJobRunner)(nil).RunJob), ctx, job, input, output, progress)
+}
+
+// MockJobUseCase is a mock of JobUseCase interface.
+type MockJobUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockJobUseCaseMockRecorder
+	isgomock struct{}
+}
+
+// MockJobUseCaseMockRecorder is the mock recorder for MockJobUseCase.
+type MockJobUseCaseMockRecorder struct {
+	mock *MockJobUseCase
+}
+
+// NewMockJobUseCase creates a new mock instance.
+func NewMockJobUseCase(ctrl *gomock.Controller) *MockJobUseCase {
+	mock := &MockJobUseCase{ctrl: ctrl}
+	mock.recorder = &MockJobUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockJobUseCase) EXPECT() *MockJobUseCaseMockRecorder {
+	return m.recorder
+}
+
+// GetJob mocks base method.
+func (m *MockJobUseCase) GetJob(ctx context.Context, id int) (domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJob", ctx, id)
+	ret0, _ := ret[0].(domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJob indicates an expected call of GetJob.
+func (mr *MockJobUseCaseMockRecorder) GetJob(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockJobUseCase)(nil).GetJob), ctx, id)
+}
+
+// OpenJobArtifact mocks base method.
+func (m *MockJobUseCase) OpenJobArtifact(ctx context.Context, id int) (domain.Job, io.Reader, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenJobArtifact", ctx, id)
+	ret0, _ := ret[0].(domain.Job)
+	ret1, _ := ret[1].(io.Reader)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OpenJobArtifact indicates an expected call of OpenJobArtifact.
+func (mr *MockJobUseCaseMockRecorder) OpenJobArtifact(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenJobArtifact", reflect.TypeOf((*MockJobUseCase)(nil).OpenJobArtifact), ctx, id)
+}
+
+// RunNextJob mocks base method.
+func (m *MockJobUseCase) RunNextJob(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunNextJob", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunNextJob indicates an expected call of RunNextJob.
+func (mr *MockJobUseCaseMockRecorder) RunNextJob(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunNextJob", reflect.TypeOf((*MockJobUseCase)(nil).RunNextJob), ctx)
+}
+
+// SubmitJob mocks base method.
+func (m *MockJobUseCase) SubmitJob(ctx context.Context, kind domain.JobKind, params json.RawMessage, input io.Reader) (domain.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitJob", ctx, kind, params, input)
+	ret0, _ := ret[0].(domain.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitJob indicates an expected call of SubmitJob.
+func (mr *MockJobUseCaseMockRecorder) SubmitJob(ctx, kind, params, input any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitJob", reflect.TypeOf((*MockJobUseCase)(nil).SubmitJob), ctx, kind, params, input)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/out/messagepublisher.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/out/messagepublisher.go -destination=mocks/mock_messagepublisher.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "go-api-boilerplate/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMessagePublisher is a mock of MessagePublisher interface.
+type MockMessagePublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessagePublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockMessagePublisherMockRecorder is the mock recorder for MockMessagePublisher.
+type MockMessagePublisherMockRecorder struct {
+	mock *MockMessagePublisher
+}
+
+// NewMockMessagePublisher creates a new mock instance.
+func NewMockMessagePublisher(ctrl *gomock.Controller) *MockMessagePublisher {
+	mock := &MockMessagePublisher{ctrl: ctrl}
+	mock.recorder = &MockMessagePublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessagePublisher) EXPECT() *MockMessagePublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockMessagePublisher) Publish(ctx context.Context, msg domain.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockMessagePublisherMockRecorder) Publish(ctx, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockMessagePublisher)(nil).Publish), ctx, msg)
+}
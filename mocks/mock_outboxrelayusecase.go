@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/in/outboxrelayusecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/in/outboxrelayusecase.go -destination=mocks/mock_outboxrelayusecase.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOutboxRelayUseCase is a mock of OutboxRelayUseCase interface.
+type MockOutboxRelayUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRelayUseCaseMockRecorder
+	isgomock struct{}
+}
+
+// MockOutboxRelayUseCaseMockRecorder is the mock recorder for MockOutboxRelayUseCase.
+type MockOutboxRelayUseCaseMockRecorder struct {
+	mock *MockOutboxRelayUseCase
+}
+
+// NewMockOutboxRelayUseCase creates a new mock instance.
+func NewMockOutboxRelayUseCase(ctrl *gomock.Controller) *MockOutboxRelayUseCase {
+	mock := &MockOutboxRelayUseCase{ctrl: ctrl}
+	mock.recorder = &MockOutboxRelayUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRelayUseCase) EXPECT() *MockOutboxRelayUseCaseMockRecorder {
+	return m.recorder
+}
+
+// RelayMessages mocks base method.
+func (m *MockOutboxRelayUseCase) RelayMessages(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelayMessages", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RelayMessages indicates an expected call of RelayMessages.
+func (mr *MockOutboxRelayUseCaseMockRecorder) RelayMessages(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelayMessages", reflect.TypeOf((*MockOutboxRelayUseCase)(nil).RelayMessages), ctx)
+}
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/out/outboxrepository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/out/outboxrepository.go -destination=mocks/mock_outboxrepository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "go-api-boilerplate/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddOutboxMessages mocks base method.
+func (m *MockOutboxRepository) AddOutboxMessages(ctx context.Context, messages []domain.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOutboxMessages", ctx, messages)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddOutboxMessages indicates an expected call of AddOutboxMessages.
+func (mr *MockOutboxRepositoryMockRecorder) AddOutboxMessages(ctx, messages any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOutboxMessages", reflect.TypeOf((*MockOutboxRepository)(nil).AddOutboxMessages), ctx, messages)
+}
+
+// ClaimOutboxMessages mocks base method.
+func (m *MockOutboxRepository) ClaimOutboxMessages(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOutboxMessages", ctx, limit)
+	ret0, _ := ret[0].([]domain.OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOutboxMessages indicates an expected call of ClaimOutboxMessages.
+func (mr *MockOutboxRepositoryMockRecorder) ClaimOutboxMessages(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOutboxMessages", reflect.TypeOf((*MockOutboxRepository)(nil).ClaimOutboxMessages), ctx, limit)
+}
+
+// DeleteOutboxMessage mocks base method.
+func (m *MockOutboxRepository) DeleteOutboxMessage(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOutboxMessage", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOutboxMessage indicates an expected call of DeleteOutboxMessage.
+func (mr *MockOutboxRepositoryMockRecorder) DeleteOutboxMessage(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOutboxMessage", reflect.TypeOf((*MockOutboxRepository)(nil).DeleteOutboxMessage), ctx, id)
+}
+
+// FailOutboxMessage mocks base method.
+func (m *MockOutboxRepository) FailOutboxMessage(ctx context.Context, msg domain.OutboxMessage, retryAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailOutboxMessage", ctx, msg, retryAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailOutboxMessage indicates an expected call of FailOutboxMessage.
+func (mr *MockOutboxRepositoryMockRecorder) FailOutboxMessage(ctx, msg, retryAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailOutboxMessage", reflect.TypeOf((*MockOutboxRepository)(nil).FailOutboxMessage), ctx, msg, retryAt)
+}
@@ -0,0 +1,220 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/out/webhookrepository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/out/webhookrepository.go -destination=mocks/mock_webhookrepository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "go-api-boilerplate/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWebhookRepository is a mock of WebhookRepository interface.
+type MockWebhookRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookRepositoryMockRecorder is the mock recorder for MockWebhookRepository.
+type MockWebhookRepositoryMockRecorder struct {
+	mock *MockWebhookRepository
+}
+
+// NewMockWebhookRepository creates a new mock instance.
+func NewMockWebhookRepository(ctrl *gomock.Controller) *MockWebhookRepository {
+	mock := &MockWebhookRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookRepository) EXPECT() *MockWebhookRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddWebhookDeliveries mocks base method.
+func (m *MockWebhookRepository) AddWebhookDeliveries(ctx context.Context, delivery domain.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWebhookDeliveries", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWebhookDeliveries indicates an expected call of AddWebhookDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) AddWebhookDeliveries(ctx, delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWebhookDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).AddWebhookDeliveries), ctx, delivery)
+}
+
+// ClaimWebhookDelivery mocks base method.
+func (m *MockWebhookRepository) ClaimWebhookDelivery(ctx context.Context, lease time.Duration) (domain.WebhookDelivery, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDelivery", ctx, lease)
+	ret0, _ := ret[0].(domain.WebhookDelivery)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ClaimWebhookDelivery indicates an expected call of ClaimWebhookDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) ClaimWebhookDelivery(ctx, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).ClaimWebhookDelivery), ctx, lease)
+}
+
+// CompleteWebhookDelivery mocks base method.
+func (m *MockWebhookRepository) CompleteWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteWebhookDelivery", ctx, delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteWebhookDelivery indicates an expected call of CompleteWebhookDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) CompleteWebhookDelivery(ctx, delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteWebhookDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).CompleteWebhookDelivery), ctx, delivery)
+}
+
+// CountWebhookDeliveries mocks base method.
+func (m *MockWebhookRepository) CountWebhookDeliveries(ctx context.Context, webhookID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWebhookDeliveries", ctx, webhookID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWebhookDeliveries indicates an expected call of CountWebhookDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) CountWebhookDeliveries(ctx, webhookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWebhookDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).CountWebhookDeliveries), ctx, webhookID)
+}
+
+// CountWebhooks mocks base method.
+func (m *MockWebhookRepository) CountWebhooks(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWebhooks", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWebhooks indicates an expected call of CountWebhooks.
+func (mr *MockWebhookRepositoryMockRecorder) CountWebhooks(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWebhooks", reflect.TypeOf((*MockWebhookRepository)(nil).CountWebhooks), ctx)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhookRepository) CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(domain.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) CreateWebhook(ctx, webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).CreateWebhook), ctx, webhook)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookRepository) DeleteWebhook(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) DeleteWebhook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).DeleteWebhook), ctx, id)
+}
+
+// FailWebhookDelivery mocks base method.
+func (m *MockWebhookRepository) FailWebhookDelivery(ctx context.Context, delivery domain.WebhookDelivery, retryAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailWebhookDelivery", ctx, delivery, retryAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailWebhookDelivery indicates an expected call of FailWebhookDelivery.
+func (mr *MockWebhookRepositoryMockRecorder) FailWebhookDelivery(ctx, delivery, retryAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailWebhookDelivery", reflect.TypeOf((*MockWebhookRepository)(nil).FailWebhookDelivery), ctx, delivery, retryAt)
+}
+
+// GetWebhook mocks base method.
+func (m *MockWebhookRepository) GetWebhook(ctx context.Context, id int) (domain.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(domain.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) GetWebhook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).GetWebhook), ctx, id)
+}
+
+// GetWebhookDeliveries mocks base method.
+func (m *MockWebhookRepository) GetWebhookDeliveries(ctx context.Context, webhookID, offset, limit int) ([]domain.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", ctx, webhookID, offset, limit)
+	ret0, _ := ret[0].([]domain.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockWebhookRepositoryMockRecorder) GetWebhookDeliveries(ctx, webhookID, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockWebhookRepository)(nil).GetWebhookDeliveries), ctx, webhookID, offset, limit)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockWebhookRepository) GetWebhooks(ctx context.Context, offset, limit int) ([]domain.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockWebhookRepositoryMockRecorder) GetWebhooks(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockWebhookRepository)(nil).GetWebhooks), ctx, offset, limit)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockWebhookRepository) UpdateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(domain.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockWebhookRepositoryMockRecorder) UpdateWebhook(ctx, webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockWebhookRepository)(nil).UpdateWebhook), ctx, webhook)
+}
@@ -0,0 +1,162 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/application/port/in/webhookusecase.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/application/port/in/webhookusecase.go -destination=mocks/mock_webhookusecase.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	domain "go-api-boilerplate/internal/domain"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWebhookUseCase is a mock of WebhookUseCase interface.
+type MockWebhookUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookUseCaseMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhookUseCaseMockRecorder is the mock recorder for MockWebhookUseCase.
+type MockWebhookUseCaseMockRecorder struct {
+	mock *MockWebhookUseCase
+}
+
+// NewMockWebhookUseCase creates a new mock instance.
+func NewMockWebhookUseCase(ctrl *gomock.Controller) *MockWebhookUseCase {
+	mock := &MockWebhookUseCase{ctrl: ctrl}
+	mock.recorder = &MockWebhookUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookUseCase) EXPECT() *MockWebhookUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhookUseCase) CreateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(domain.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhookUseCaseMockRecorder) CreateWebhook(ctx, webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookUseCase)(nil).CreateWebhook), ctx, webhook)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhookUseCase) DeleteWebhook(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhookUseCaseMockRecorder) DeleteWebhook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookUseCase)(nil).DeleteWebhook), ctx, id)
+}
+
+// DeliverNextWebhook mocks base method.
+func (m *MockWebhookUseCase) DeliverNextWebhook(ctx context.Context) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeliverNextWebhook", ctx)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeliverNextWebhook indicates an expected call of DeliverNextWebhook.
+func (mr *MockWebhookUseCaseMockRecorder) DeliverNextWebhook(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeliverNextWebhook", reflect.TypeOf((*MockWebhookUseCase)(nil).DeliverNextWebhook), ctx)
+}
+
+// EnqueueWebhookDeliveries mocks base method.
+func (m *MockWebhookUseCase) EnqueueWebhookDeliveries(ctx context.Context, msg domain.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueWebhookDeliveries", ctx, msg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnqueueWebhookDeliveries indicates an expected call of EnqueueWebhookDeliveries.
+func (mr *MockWebhookUseCaseMockRecorder) EnqueueWebhookDeliveries(ctx, msg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueWebhookDeliveries", reflect.TypeOf((*MockWebhookUseCase)(nil).EnqueueWebhookDeliveries), ctx, msg)
+}
+
+// GetWebhook mocks base method.
+func (m *MockWebhookUseCase) GetWebhook(ctx context.Context, id int) (domain.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", ctx, id)
+	ret0, _ := ret[0].(domain.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockWebhookUseCaseMockRecorder) GetWebhook(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockWebhookUseCase)(nil).GetWebhook), ctx, id)
+}
+
+// GetWebhookDeliveries mocks base method.
+func (m *MockWebhookUseCase) GetWebhookDeliveries(ctx context.Context, id, page, perPage int) ([]domain.WebhookDelivery, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", ctx, id, page, perPage)
+	ret0, _ := ret[0].([]domain.WebhookDelivery)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockWebhookUseCaseMockRecorder) GetWebhookDeliveries(ctx, id, page, perPage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockWebhookUseCase)(nil).GetWebhookDeliveries), ctx, id, page, perPage)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockWebhookUseCase) GetWebhooks(ctx context.Context, page, perPage int) ([]domain.Webhook, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx, page, perPage)
+	ret0, _ := ret[0].([]domain.Webhook)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockWebhookUseCaseMockRecorder) GetWebhooks(ctx, page, perPage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockWebhookUseCase)(nil).GetWebhooks), ctx, page, perPage)
+}
+
+// UpdateWebhook mocks base method.
+func (m *MockWebhookUseCase) UpdateWebhook(ctx context.Context, webhook domain.Webhook) (domain.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebhook", ctx, webhook)
+	ret0, _ := ret[0].(domain.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateWebhook indicates an expected call of UpdateWebhook.
+func (mr *MockWebhookUseCaseMockRecorder) UpdateWebhook(ctx, webhook any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebhook", reflect.TypeOf((*MockWebhookUseCase)(nil).UpdateWebhook), ctx, webhook)
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-api-boilerplate/test/helpers"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestBookAPI_AsOf(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		app.Router.ServeHTTP(w, req)
+		return w
+	}
+	// mark returns the current time as an as_of parameter, between the
+	// writes before and after it.
+	mark := func() string {
+		time.Sleep(20 * time.Millisecond)
+		at := time.Now().UTC().Format(time.RFC3339Nano)
+		time.Sleep(20 * time.Millisecond)
+		return url.QueryEscape(at)
+	}
+
+	beforeAll := mark()
+	createBook(t, "1984", "George Orwell")
+	createBook(t, "Animal Farm", "George Orwell")
+	created := mark()
+	if w := do("PUT", "/books/1", map[string]string{"title": "Nineteen Eighty-Four", "author": "George Orwell"}); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("DELETE", "/books/2", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	t.Run("book", func(t *testing.T) {
+		w := do("GET", "/books/1?as_of="+created, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var book map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &book)
+		if book["title"] != "1984" || book["version"] != float64(1) {
+			t.Errorf("expected the book as created, got %v", book)
+		}
+		if w.Header().Get("ETag") != "" {
+			t.Error("expected no ETag for a past version")
+		}
+	})
+
+	t.Run("deleted_since", func(t *testing.T) {
+		if w := do("GET", "/books/2", nil); w.Code != http.StatusNotFound {
+			t.Fatalf("expected 404 now, got %d", w.Code)
+		}
+		if w := do("GET", "/books/2?as_of="+created, nil); w.Code != http.StatusOK {
+			t.Errorf("expected 200 then, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("not_created_yet", func(t *testing.T) {
+		if w := do("GET", "/books/1?as_of="+beforeAll, nil); w.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		w := do("GET", "/books?sort=title&as_of="+created, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var books []map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &books)
+		if len(books) != 2 || books[0]["title"] != "1984" || books[1]["title"] != "Animal Farm" {
+			t.Errorf("expected both books as created, got %v", books)
+		}
+		if got := w.Header().Get("X-Total-Count"); got != "2" {
+			t.Errorf("expected X-Total-Count 2, got %q", got)
+		}
+
+		w = do("GET", "/books?title_contains=nineteen&as_of="+mark(), nil)
+		json.Unmarshal(w.Body.Bytes(), &books)
+		if len(books) != 1 || books[0]["id"] != float64(1) {
+			t.Errorf("expected the renamed book, got %v", books)
+		}
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		if w := do("GET", "/books?limit=1&as_of="+created, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-api-boilerplate/test/helpers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthorAPI_BookLinks(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			jsonBody, _ := json.Marshal(body)
+			buf.Write(jsonBody)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+helpers.AdminToken)
+		app.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, name := range []string{"Neil Gaiman", "Terry Pratchett"} {
+		if w := do("POST", "/authors", map[string]string{"name": name}); w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	t.Run("create_book_with_author_ids", func(t *testing.T) {
+		w := do("POST", "/books", map[string]interface{}{"title": "Good Omens", "author_ids": []int{2, 1}})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var created struct {
+			Author  string `json:"author"`
+			Authors []struct {
+				ID   int    `json:"id"`
+				Name string `json:"name"`
+			} `json:"authors"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if created.Author != "Terry Pratchett, Neil Gaiman" {
+			t.Errorf("expected byline in link order, got %q", created.Author)
+		}
+		if len(created.Authors) != 2 || created.Authors[0].ID != 2 || created.Authors[1].ID != 1 {
+			t.Errorf("expected authors [2 1], got %+v", created.Authors)
+		}
+	})
+
+	t.Run("unknown_author_id", func(t *testing.T) {
+		w := do("POST", "/books", map[string]interface{}{"title": "Nobody", "author_ids": []int{99}})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("author_books", func(t *testing.T) {
+		w := do("GET", "/authors/1/books", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var books []map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &books); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(books) != 1 || books[0]["title"] != "Good Omens" {
+			t.Errorf("expected [Good Omens], got %v", books)
+		}
+	})
+
+	t.Run("rename_updates_byline", func(t *testing.T) {
+		if w := do("PUT", "/authors/1", map[string]string{"name": "N. Gaiman"}); w.Code != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var author string
+		err := helpers.DB().QueryRow(
+			context.Background(),
+			"SELECT author FROM books WHERE id = 1",
+		).Scan(&author)
+		if err != nil {
+			t.Fatalf("failed to query database: %v", err)
+		}
+		if author != "Terry Pratchett, N. Gaiman" {
+			t.Errorf("expected refreshed byline, got %q", author)
+		}
+
+		// The rename is recorded as an update of the book.
+		w := do("GET", "/books/1/history/2", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var change struct {
+			Action string `json:"action"`
+			Before struct {
+				Author string `json:"author"`
+			} `json:"before"`
+			After struct {
+				Author string `json:"author"`
+			} `json:"after"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &change); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if change.Action != "update" || change.Before.Author != "Terry Pratchett, Neil Gaiman" || change.After.Author != "Terry Pratchett, N. Gaiman" {
+			t.Errorf("expected the rename in the history, got %+v", change)
+		}
+	})
+
+	t.Run("delete_linked_author", func(t *testing.T) {
+		if w := do("DELETE", "/authors/1", nil); w.Code != http.StatusConflict {
+			t.Errorf("expected 409, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("delete_unlinked_author", func(t *testing.T) {
+		if w := do("DELETE", "/books/1", nil); w.Code != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+		}
+		// A trashed book still holds its author links until it is purged.
+		if w := do("DELETE", "/authors/1", nil); w.Code != http.StatusConflict {
+			t.Errorf("expected 409, got %d: %s", w.Code, w.Body.String())
+		}
+		if w := do("DELETE", "/admin/books/1", nil); w.Code != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+		}
+		if w := do("DELETE", "/authors/1", nil); w.Code != http.StatusNoContent {
+			t.Errorf("expected 204, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestAuthorAPI_CreateBookByName(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	for _, title := range []string{"1984", "Animal Farm"} {
+		jsonBody, _ := json.Marshal(map[string]string{"title": title, "author": "George Orwell"})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/books", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		app.Router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	var count int
+	err := helpers.DB().QueryRow(
+		context.Background(),
+		"SELECT COUNT(*) FROM authors WHERE name = 'George Orwell'",
+	).Scan(&count)
+	if err != nil {
+		t.Fatalf("failed to query database: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected the author name to be reused, got %d authors", count)
+	}
+}
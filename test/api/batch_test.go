@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"go-api-boilerplate/test/helpers"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBookAPI_CreateBooks(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	type result struct {
+		Index  int    `json:"index"`
+		Status string `json:"status"`
+		ID     int    `json:"id"`
+		Error  *struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	batch := func(t *testing.T, mode, body string) (int, []result) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/books:batch?mode="+mode, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		app.Router.ServeHTTP(w, req)
+		var res struct {
+			Results []result `json:"results"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return w.Code, res.Results
+	}
+	statuses := func(results []result) []string {
+		out := []string{}
+		for _, r := range results {
+			out = append(out, r.Status)
+		}
+		return out
+	}
+	count := func(t *testing.T) string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books", nil)
+		app.Router.ServeHTTP(w, req)
+		return w.Header().Get("X-Total-Count")
+	}
+
+	t.Run("all_or_nothing_created", func(t *testing.T) {
+		code, results := batch(t, "all_or_nothing", `[
+			{"title": "Animal Farm", "author": "George Orwell", "isbn": "978-0-7432-7356-5"},
+			{"title": "1984", "author": "George Orwell"}
+		]`)
+		if code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d", code)
+		}
+		if got := statuses(results); !reflect.DeepEqual(got, []string{"created", "created"}) {
+			t.Fatalf("unexpected statuses %v", got)
+		}
+		if results[0].ID == 0 || results[1].ID == 0 {
+			t.Errorf("expected ids, got %+v", results)
+		}
+		if got := count(t); got != "2" {
+			t.Errorf("expected 2 books, got %s", got)
+		}
+	})
+
+	t.Run("all_or_nothing_rolled_back", func(t *testing.T) {
+		code, results := batch(t, "all_or_nothing", `[
+			{"title": "Brave New World", "author": "Aldous Huxley"},
+			{"title": "Copy", "author": "Someone", "isbn": "9780743273565"}
+		]`)
+		if code != http.StatusUnprocessableEntity {
+			t.Fatalf("expected 422, got %d", code)
+		}
+		if got := statuses(results); !reflect.DeepEqual(got, []string{"rolled_back", "failed"}) {
+			t.Fatalf("unexpected statuses %v", got)
+		}
+		if results[1].Error == nil || results[1].Error.Code != "CONFLICT" {
+			t.Errorf("expected a conflict, got %+v", results[1].Error)
+		}
+		if got := count(t); got != "2" {
+			t.Errorf("expected the batch to be rolled back, got %s books", got)
+		}
+	})
+
+	t.Run("best_effort", func(t *testing.T) {
+		code, results := batch(t, "best_effort", `[
+			{"title": "Brave New World", "author": "Aldous Huxley"},
+			{"author": "No Title"},
+			{"title": "Dup", "author": "Someone", "isbn": "9780743273565"},
+			{"title": "Island", "author": "Aldous Huxley", "isbn": "bad"}
+		]`)
+		if code != http.StatusMultiStatus {
+			t.Fatalf("expected 207, got %d", code)
+		}
+		if got := statuses(results); !reflect.DeepEqual(got, []string{"created", "failed", "failed", "failed"}) {
+			t.Fatalf("unexpected statuses %v", got)
+		}
+		if got := count(t); got != "3" {
+			t.Errorf("expected 3 books, got %s", got)
+		}
+	})
+}
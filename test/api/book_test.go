@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestMain(m *testing.M) {
@@ -38,7 +39,7 @@ func TestBookAPI_CreateBook(t *testing.T) {
 		{
 			name:       "success",
 			body:       map[string]string{"title": "1984", "author": "George"},
-			wantStatus: http.StatusNoContent,
+			wantStatus: http.StatusCreated,
 			checkDB:    true,
 		},
 		{
@@ -61,6 +62,11 @@ func TestBookAPI_CreateBook(t *testing.T) {
 			body:       map[string]string{"title": "Title", "author": ""},
 			wantStatus: http.StatusBadRequest,
 		},
+		{
+			name:       "invalid_isbn",
+			body:       map[string]string{"title": "Title", "author": "Author", "isbn": "978-0-7432-7356-6"},
+			wantStatus: http.StatusBadRequest,
+		},
 	}
 
 	for _, tt := range tests {
@@ -87,6 +93,18 @@ func TestBookAPI_CreateBook(t *testing.T) {
 			}
 
 			if tt.checkDB {
+				if got := w.Header().Get("Location"); got != "/books/1" {
+					t.Errorf("expected Location /books/1, got %q", got)
+				}
+
+				var created map[string]interface{}
+				if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+					t.Fatalf("failed to unmarshal response: %v", err)
+				}
+				if created["id"] != float64(1) {
+					t.Errorf("expected id 1 in response, got %v", created["id"])
+				}
+
 				var title, author string
 				err := helpers.DB().QueryRow(
 					context.Background(),
@@ -113,6 +131,92 @@ func TestBookAPI_CreateBook(t *testing.T) {
 	}
 }
 
+func TestBookAPI_CreateBook_ISBN(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	post := func(body map[string]string) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/books", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		app.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("normalized_to_isbn13", func(t *testing.T) {
+		w := post(map[string]string{"title": "The Great Gatsby", "author": "F. Scott Fitzgerald", "isbn": "0-7432-7356-7"})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var isbn string
+		err := helpers.DB().QueryRow(
+			context.Background(),
+			"SELECT isbn FROM books WHERE id = 1",
+		).Scan(&isbn)
+
+		if err != nil {
+			t.Fatalf("book not found in database: %v", err)
+		}
+		if isbn != "9780743273565" {
+			t.Errorf("expected isbn 9780743273565, got %s", isbn)
+		}
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		w := post(map[string]string{"title": "The Great Gatsby", "author": "F. Scott Fitzgerald", "isbn": "978-0-7432-7356-5"})
+		if w.Code != http.StatusConflict {
+			t.Errorf("expected 409, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("books_without_isbn", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			w := post(map[string]string{"title": "Untitled", "author": "Anonymous"})
+			if w.Code != http.StatusCreated {
+				t.Errorf("expected 201, got %d: %s", w.Code, w.Body.String())
+			}
+		}
+	})
+}
+
+func TestBookAPI_GetBookByISBN(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	_, err := helpers.DB().Exec(context.Background(),
+		"INSERT INTO books (title, author, isbn) VALUES ($1, $2, $3)",
+		"The Great Gatsby", "F. Scott Fitzgerald", "9780743273565",
+	)
+	if err != nil {
+		t.Fatalf("failed to create book: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		isbn       string
+		wantStatus int
+	}{
+		{"isbn13", "9780743273565", http.StatusOK},
+		{"isbn10_with_hyphens", "0-7432-7356-7", http.StatusOK},
+		{"invalid", "12345", http.StatusBadRequest},
+		{"not_found", "9780451524935", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/books/isbn/"+tt.isbn, nil)
+			app.Router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
 func TestBookAPI_GetBooks(t *testing.T) {
 	app := helpers.SetupTestApp(t)
 	defer helpers.CleanupDatabase(t)
@@ -219,6 +323,13 @@ func TestBookAPI_GetBook(t *testing.T) {
 		if book["title"] != "1984" {
 			t.Errorf("expected title '1984', got '%v'", book["title"])
 		}
+
+		for _, field := range []string{"created_at", "updated_at"} {
+			value, _ := book[field].(string)
+			if _, err := time.Parse(time.RFC3339, value); err != nil {
+				t.Errorf("expected %s in RFC 3339 format, got '%v'", field, book[field])
+			}
+		}
 	})
 
 	t.Run("not_found", func(t *testing.T) {
@@ -276,6 +387,20 @@ func TestBookAPI_UpdateBook(t *testing.T) {
 				author,
 			)
 		}
+
+		var bumped bool
+		err = helpers.DB().QueryRow(
+			context.Background(),
+			"SELECT updated_at > created_at FROM books WHERE id = 1",
+		).Scan(&bumped)
+
+		if err != nil {
+			t.Fatalf("failed to query database: %v", err)
+		}
+
+		if !bumped {
+			t.Errorf("expected updated_at to be bumped after update")
+		}
 	})
 
 	t.Run("not_found", func(t *testing.T) {
@@ -312,18 +437,36 @@ func TestBookAPI_DeleteBook(t *testing.T) {
 			t.Errorf("expected 204, got %d: %s", w.Code, w.Body.String())
 		}
 
-		var count int
+		var trashed bool
 		err := helpers.DB().QueryRow(
 			context.Background(),
-			"SELECT COUNT(*) FROM books WHERE id = 1",
-		).Scan(&count)
+			"SELECT deleted_at IS NOT NULL FROM books WHERE id = 1",
+		).Scan(&trashed)
 
 		if err != nil {
-			t.Fatalf("failed to query database: %v", err)
+			t.Fatalf("book should stay in the database until purged: %v", err)
+		}
+
+		if !trashed {
+			t.Errorf("book was not moved to the trash")
+		}
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("GET", "/books/1", nil)
+		app.Router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected trashed book to be hidden, got %d", w.Code)
 		}
+	})
 
-		if count != 0 {
-			t.Errorf("book still exists in database after deletion")
+	t.Run("already_deleted", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/books/1", nil)
+		app.Router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", w.Code)
 		}
 	})
 
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-api-boilerplate/internal/config"
+	"go-api-boilerplate/test/helpers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBookAPI_IfMatch(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	createBook(t, "Original", "Author")
+
+	put := func(ifMatch, title string) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(map[string]string{"title": title, "author": "Author"})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/books/1", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		app.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/books/1", nil)
+	app.Router.ServeHTTP(w, req)
+	etag := w.Header().Get("ETag")
+	if etag != `"1"` {
+		t.Fatalf(`expected ETag "1", got %q`, etag)
+	}
+
+	t.Run("matching_version", func(t *testing.T) {
+		w := put(etag, "First Editor")
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("ETag"); got != `"2"` {
+			t.Errorf(`expected ETag "2", got %q`, got)
+		}
+	})
+
+	t.Run("stale_version", func(t *testing.T) {
+		if w := put(etag, "Second Editor"); w.Code != http.StatusPreconditionFailed {
+			t.Errorf("expected 412, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("stale_delete", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("DELETE", "/books/1", nil)
+		req.Header.Set("If-Match", etag)
+		app.Router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusPreconditionFailed {
+			t.Errorf("expected 412, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("without_if_match", func(t *testing.T) {
+		if w := put("", "Last Write"); w.Code != http.StatusNoContent {
+			t.Errorf("expected 204, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestBookAPI_IfMatch_Strict(t *testing.T) {
+	app := helpers.SetupTestApp(t, func(cfg *config.Config) {
+		cfg.HTTP.RequireIfMatch = true
+	})
+	defer helpers.CleanupDatabase(t)
+
+	createBook(t, "Original", "Author")
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/books/1", nil)
+	app.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("expected 428, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/books/1", nil)
+	req.Header.Set("If-Match", `"1"`)
+	app.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+}
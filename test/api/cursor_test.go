@@ -0,0 +1,87 @@
+package api
+
+import (
+	"encoding/json"
+	"go-api-boilerplate/test/helpers"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestBookAPI_CursorPagination(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	for _, title := range []string{"Book 1", "Book 2", "Book 3", "Book 4", "Book 5"} {
+		createBook(t, title, "Author")
+	}
+
+	type page struct {
+		Data []struct {
+			ID int `json:"id"`
+		} `json:"data"`
+		NextCursor *string `json:"next_cursor"`
+		PrevCursor *string `json:"prev_cursor"`
+	}
+	get := func(query string) page {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books?"+query, nil)
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var p page
+		json.Unmarshal(w.Body.Bytes(), &p)
+		return p
+	}
+	ids := func(p page) []int {
+		out := []int{}
+		for _, b := range p.Data {
+			out = append(out, b.ID)
+		}
+		return out
+	}
+
+	first := get("limit=2")
+	if got := ids(first); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Fatalf("expected books [1 2], got %v", got)
+	}
+	if first.PrevCursor != nil || first.NextCursor == nil {
+		t.Fatalf("unexpected cursors on first page: %+v", first)
+	}
+
+	// A book deleted behind the cursor must not shift the next page.
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/books/1", nil)
+	app.Router.ServeHTTP(w, req)
+
+	second := get("limit=2&cursor=" + url.QueryEscape(*first.NextCursor))
+	if got := ids(second); !reflect.DeepEqual(got, []int{3, 4}) {
+		t.Fatalf("expected books [3 4], got %v", got)
+	}
+
+	last := get("limit=2&cursor=" + url.QueryEscape(*second.NextCursor))
+	if got := ids(last); !reflect.DeepEqual(got, []int{5}) {
+		t.Fatalf("expected books [5], got %v", got)
+	}
+	if last.NextCursor != nil {
+		t.Errorf("expected no next cursor on the last page, got %q", *last.NextCursor)
+	}
+
+	back := get("limit=2&cursor=" + url.QueryEscape(*last.PrevCursor))
+	if got := ids(back); !reflect.DeepEqual(got, []int{3, 4}) {
+		t.Errorf("expected books [3 4] going back, got %v", got)
+	}
+
+	t.Run("tampered_cursor", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books?cursor="+url.QueryEscape(*first.NextCursor+"x"), nil)
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"go-api-boilerplate/test/helpers"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestBookAPI_Export(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	for _, b := range []map[string]string{
+		{"title": "Animal Farm", "author": "George Orwell"},
+		{"title": "1984", "author": "George Orwell"},
+		{"title": "Brave New World", "author": "Aldous Huxley"},
+	} {
+		jsonBody, _ := json.Marshal(b)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/books", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	export := func(t *testing.T, query string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books/export?"+query, nil)
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		return w
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		w := export(t, "author="+url.QueryEscape("george orwell")+"&sort=title")
+		if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/csv") {
+			t.Errorf("expected text/csv, got %q", got)
+		}
+		if got := w.Header().Get("Content-Disposition"); !strings.HasPrefix(got, `attachment; filename="books-`) || !strings.HasSuffix(got, `.csv"`) {
+			t.Errorf("unexpected Content-Disposition %q", got)
+		}
+		records, err := csv.NewReader(w.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("failed to read csv: %v", err)
+		}
+		if len(records) != 3 || records[0][1] != "title" || records[1][1] != "1984" || records[2][1] != "Animal Farm" {
+			t.Errorf("unexpected records %v", records)
+		}
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		w := export(t, "format=ndjson&sort=-title")
+		var titles []string
+		scanner := bufio.NewScanner(w.Body)
+		for scanner.Scan() {
+			var book struct {
+				Title string `json:"title"`
+			}
+			if err := json.Unmarshal(scanner.Bytes(), &book); err != nil {
+				t.Fatalf("failed to unmarshal line %q: %v", scanner.Text(), err)
+			}
+			titles = append(titles, book.Title)
+		}
+		if want := []string{"Brave New World", "Animal Farm", "1984"}; !reflect.DeepEqual(titles, want) {
+			t.Errorf("expected %v, got %v", want, titles)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		w := export(t, "format=json&title_contains=brave")
+		var books []struct {
+			Title   string `json:"title"`
+			Authors []struct {
+				Name string `json:"name"`
+			} `json:"authors"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &books); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(books) != 1 || books[0].Title != "Brave New World" || len(books[0].Authors) != 1 || books[0].Authors[0].Name != "Aldous Huxley" {
+			t.Errorf("unexpected books %+v", books)
+		}
+	})
+
+	t.Run("invalid_format", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books/export?format=xml", nil)
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestBookAPI_ExportImportRoundTrip(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(body)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+helpers.AdminToken)
+		app.Router.ServeHTTP(w, req)
+		return w
+	}
+	for _, name := range []string{"Neil Gaiman", "Terry Pratchett"} {
+		if w := do("POST", "/authors", map[string]string{"name": name}); w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+	if w := do("POST", "/books", map[string]interface{}{"title": "Good Omens", "author_ids": []int{2, 1}}); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/books/export", nil)
+	app.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("file", "books.csv")
+	fw.Write(w.Body.Bytes())
+	mw.Close()
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/books/import", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	app.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var report struct {
+		Inserted int `json:"inserted"`
+		Rows     []struct {
+			ID int `json:"id"`
+		} `json:"rows"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if report.Inserted != 1 || len(report.Rows) != 1 {
+		t.Fatalf("unexpected report %s", w.Body.String())
+	}
+
+	w = do("GET", "/books/"+strconv.Itoa(report.Rows[0].ID), nil)
+	var imported struct {
+		Author  string `json:"author"`
+		Authors []struct {
+			ID int `json:"id"`
+		} `json:"authors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &imported); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if imported.Author != "Terry Pratchett, Neil Gaiman" || len(imported.Authors) != 2 || imported.Authors[0].ID != 2 || imported.Authors[1].ID != 1 {
+		t.Errorf("expected the imported book to keep authors [2 1], got %+v", imported)
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-api-boilerplate/test/helpers"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestBookAPI_FilterAndSort(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	for _, b := range []map[string]string{
+		{"title": "Animal Farm", "author": "George Orwell"},
+		{"title": "1984", "author": "George Orwell"},
+		{"title": "Brave New World", "author": "Aldous Huxley"},
+	} {
+		jsonBody, _ := json.Marshal(b)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/books", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books?"+query, nil)
+		app.Router.ServeHTTP(w, req)
+		return w
+	}
+	titles := func(t *testing.T, w *httptest.ResponseRecorder) []string {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var books []struct {
+			Title string `json:"title"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &books); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		out := []string{}
+		for _, b := range books {
+			out = append(out, b.Title)
+		}
+		return out
+	}
+
+	t.Run("by_author", func(t *testing.T) {
+		w := get("author=" + url.QueryEscape("george orwell"))
+		if got := titles(t, w); !reflect.DeepEqual(got, []string{"Animal Farm", "1984"}) {
+			t.Errorf("expected Orwell's books, got %v", got)
+		}
+		if got := w.Header().Get("X-Total-Count"); got != "2" {
+			t.Errorf("expected X-Total-Count 2, got %q", got)
+		}
+	})
+
+	t.Run("by_title", func(t *testing.T) {
+		if got := titles(t, get("title_contains=FARM")); !reflect.DeepEqual(got, []string{"Animal Farm"}) {
+			t.Errorf("expected Animal Farm, got %v", got)
+		}
+	})
+
+	t.Run("like_wildcards_are_literal", func(t *testing.T) {
+		if got := titles(t, get("title_contains=%25")); len(got) != 0 {
+			t.Errorf("expected no books, got %v", got)
+		}
+	})
+
+	t.Run("sorted", func(t *testing.T) {
+		want := []string{"Brave New World", "Animal Farm", "1984"}
+		if got := titles(t, get("sort=-title")); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("sorted_cursor", func(t *testing.T) {
+		type page struct {
+			Data []struct {
+				Title string `json:"title"`
+			} `json:"data"`
+			NextCursor *string `json:"next_cursor"`
+		}
+		var first, second page
+		json.Unmarshal(get("limit=2&sort=title").Body.Bytes(), &first)
+		if len(first.Data) != 2 || first.Data[0].Title != "1984" || first.NextCursor == nil {
+			t.Fatalf("unexpected first page: %+v", first)
+		}
+		json.Unmarshal(get("limit=2&cursor="+url.QueryEscape(*first.NextCursor)).Body.Bytes(), &second)
+		if len(second.Data) != 1 || second.Data[0].Title != "Brave New World" {
+			t.Errorf("expected the cursor to keep the title sort, got %+v", second)
+		}
+
+		if w := get("limit=2&sort=-title&cursor=" + url.QueryEscape(*first.NextCursor)); w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400 for a cursor from another sort, got %d", w.Code)
+		}
+	})
+
+	t.Run("unsortable_field", func(t *testing.T) {
+		if w := get("sort=isbn"); w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
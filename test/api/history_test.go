@@ -0,0 +1,132 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-api-boilerplate/test/helpers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type bookChangeRes struct {
+	Version   int                    `json:"version"`
+	Action    string                 `json:"action"`
+	Before    map[string]interface{} `json:"before"`
+	After     map[string]interface{} `json:"after"`
+	Actor     string                 `json:"actor"`
+	RequestID string                 `json:"request_id"`
+}
+
+func TestBookAPI_History(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	do := func(method, path string, body interface{}, header map[string]string) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		app.Router.ServeHTTP(w, req)
+		return w
+	}
+	alice := map[string]string{"X-Actor": "alice", "X-Request-ID": "req-1"}
+
+	if w := do("POST", "/books", map[string]string{"title": "1984", "author": "George Orwell"}, alice); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	w := do("PUT", "/books/1", map[string]string{"title": "Nineteen Eighty-Four", "author": "George Orwell"}, nil)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("X-Request-ID") == "" {
+		t.Error("expected a generated X-Request-ID")
+	}
+
+	t.Run("history", func(t *testing.T) {
+		w := do("GET", "/books/1/history", nil, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var changes []bookChangeRes
+		if err := json.Unmarshal(w.Body.Bytes(), &changes); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(changes) != 2 {
+			t.Fatalf("expected 2 changes, got %+v", changes)
+		}
+		update, create := changes[0], changes[1]
+		if update.Version != 2 || update.Action != "update" || update.Actor != "anonymous" ||
+			update.Before["title"] != "1984" || update.After["title"] != "Nineteen Eighty-Four" {
+			t.Errorf("unexpected update %+v", update)
+		}
+		if create.Version != 1 || create.Action != "create" || create.Actor != "alice" ||
+			create.RequestID != "req-1" || create.Before != nil {
+			t.Errorf("unexpected create %+v", create)
+		}
+	})
+
+	t.Run("version", func(t *testing.T) {
+		if w := do("GET", "/books/1/history/1", nil, nil); w.Code != http.StatusOK {
+			t.Errorf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if w := do("GET", "/books/1/history/9", nil, nil); w.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d: %s", w.Code, w.Body.String())
+		}
+		if w := do("GET", "/books/9/history", nil, nil); w.Code != http.StatusNotFound {
+			t.Errorf("expected 404 for an unknown book, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("revert", func(t *testing.T) {
+		if w := do("POST", "/books/1/revert/1", nil, map[string]string{"If-Match": `"1"`}); w.Code != http.StatusPreconditionFailed {
+			t.Errorf("expected 412 for a stale version, got %d: %s", w.Code, w.Body.String())
+		}
+
+		w := do("POST", "/books/1/revert/1", nil, map[string]string{"If-Match": `"2"`})
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("ETag"); got != `"3"` {
+			t.Errorf(`expected ETag "3", got %q`, got)
+		}
+		var book map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &book)
+		if book["title"] != "1984" {
+			t.Errorf("expected the reverted title, got %v", book)
+		}
+
+		var changes []bookChangeRes
+		json.Unmarshal(do("GET", "/books/1/history", nil, nil).Body.Bytes(), &changes)
+		if len(changes) != 3 || changes[0].Action != "revert" || changes[0].Version != 3 {
+			t.Errorf("expected the revert on top of the history, got %+v", changes)
+		}
+	})
+
+	t.Run("delete_and_restore", func(t *testing.T) {
+		if w := do("DELETE", "/books/1", nil, nil); w.Code != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+		}
+		if w := do("POST", "/books/1/revert/1", nil, nil); w.Code != http.StatusNotFound {
+			t.Errorf("expected 404 reverting a book in the trash, got %d: %s", w.Code, w.Body.String())
+		}
+		if w := do("POST", "/books/1/restore", nil, nil); w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if w := do("POST", "/books/1/revert/4", nil, nil); w.Code != http.StatusConflict {
+			t.Errorf("expected 409 reverting to a deleted version, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var changes []bookChangeRes
+		json.Unmarshal(do("GET", "/books/1/history?per_page=2", nil, nil).Body.Bytes(), &changes)
+		if len(changes) != 2 || changes[0].Action != "restore" || changes[1].Action != "delete" {
+			t.Errorf("expected restore and delete on top of the history, got %+v", changes)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-api-boilerplate/test/helpers"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestBookAPI_ImportCSV(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	const csv = "Name,Writer,ISBN\n" +
+		"Animal Farm,George Orwell,\n" +
+		"1984,George Orwell,978-0-451-52493-5\n" +
+		",No Title,\n" +
+		"Copy of 1984,George Orwell,9780451524935\n" +
+		"Bad ISBN,Someone,123\n"
+
+	type report struct {
+		DryRun   bool `json:"dry_run"`
+		Inserted int  `json:"inserted"`
+		Skipped  int  `json:"skipped"`
+		Failed   int  `json:"failed"`
+		Rows     []struct {
+			Line   int    `json:"line"`
+			Status string `json:"status"`
+		} `json:"rows"`
+	}
+	upload := func(t *testing.T, query string) report {
+		t.Helper()
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		fw, _ := mw.CreateFormFile("file", "books.csv")
+		fw.Write([]byte(csv))
+		mw.Close()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/books/import?title_column=name&author_column=writer"+query, &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var res report
+		if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return res
+	}
+	statuses := func(r report) map[int]string {
+		out := map[int]string{}
+		for _, row := range r.Rows {
+			out[row.Line] = row.Status
+		}
+		return out
+	}
+	count := func(t *testing.T) string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books", nil)
+		app.Router.ServeHTTP(w, req)
+		return w.Header().Get("X-Total-Count")
+	}
+	want := map[int]string{2: "inserted", 3: "inserted", 4: "failed", 5: "skipped", 6: "failed"}
+
+	t.Run("dry_run", func(t *testing.T) {
+		res := upload(t, "&dry_run=true")
+		if !res.DryRun || res.Inserted != 2 || res.Skipped != 1 || res.Failed != 2 {
+			t.Errorf("unexpected report %+v", res)
+		}
+		if got := statuses(res); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+		if got := count(t); got != "0" {
+			t.Errorf("expected nothing written, got %s books", got)
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		res := upload(t, "")
+		if res.DryRun || res.Inserted != 2 || res.Skipped != 1 || res.Failed != 2 {
+			t.Errorf("unexpected report %+v", res)
+		}
+		if got := statuses(res); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+		if got := count(t); got != "2" {
+			t.Errorf("expected 2 books, got %s", got)
+		}
+	})
+
+	t.Run("reimport_skips", func(t *testing.T) {
+		res := upload(t, "")
+		if res.Inserted != 1 || res.Skipped != 2 {
+			t.Errorf("expected only the book without an ISBN to be inserted again, got %+v", res)
+		}
+	})
+}
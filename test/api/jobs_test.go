@@ -0,0 +1,171 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"go-api-boilerplate/test/helpers"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type jobRes struct {
+	ID          int             `json:"id"`
+	Kind        string          `json:"kind"`
+	Status      string          `json:"status"`
+	Processed   int             `json:"processed"`
+	Attempts    int             `json:"attempts"`
+	Result      json.RawMessage `json:"result"`
+	Error       string          `json:"error"`
+	ArtifactURL string          `json:"artifact_url"`
+}
+
+func TestJobAPI(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	// accepted checks that a job was queued and returns its URL.
+	accepted := func(t *testing.T, w *httptest.ResponseRecorder, kind string) string {
+		t.Helper()
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("expected 202, got %d: %s", w.Code, w.Body.String())
+		}
+		var job jobRes
+		if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if job.Kind != kind || job.Status != "queued" {
+			t.Errorf("unexpected job %+v", job)
+		}
+		location := w.Header().Get("Location")
+		if location == "" {
+			t.Fatal("expected a Location header")
+		}
+		return location
+	}
+	// wait polls the job until it has finished.
+	wait := func(t *testing.T, location string) jobRes {
+		t.Helper()
+		deadline := time.Now().Add(30 * time.Second)
+		for {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", location, nil)
+			app.Router.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var job jobRes
+			if err := json.Unmarshal(w.Body.Bytes(), &job); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if job.Status == "succeeded" || job.Status == "failed" {
+				return job
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("job still %s after 30s", job.Status)
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	download := func(t *testing.T, job jobRes) *httptest.ResponseRecorder {
+		t.Helper()
+		if job.ArtifactURL == "" {
+			t.Fatalf("expected an artifact, got %+v", job)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", job.ArtifactURL, nil)
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		return w
+	}
+
+	t.Run("import", func(t *testing.T) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		fw, _ := mw.CreateFormFile("file", "books.csv")
+		fw.Write([]byte("title,author,isbn\n" +
+			"Animal Farm,George Orwell,\n" +
+			"1984,George Orwell,978-0-451-52493-5\n" +
+			",No Title,\n"))
+		mw.Close()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/books/import?async=true", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		app.Router.ServeHTTP(w, req)
+
+		job := wait(t, accepted(t, w, "book_import"))
+		if job.Status != "succeeded" || job.Processed != 3 {
+			t.Fatalf("unexpected job %+v", job)
+		}
+		var result struct {
+			Inserted int `json:"inserted"`
+			Failed   int `json:"failed"`
+		}
+		json.Unmarshal(job.Result, &result)
+		if result.Inserted != 2 || result.Failed != 1 {
+			t.Errorf("unexpected result %s", job.Result)
+		}
+
+		var report struct {
+			Rows []struct {
+				Line   int    `json:"line"`
+				Status string `json:"status"`
+			} `json:"rows"`
+		}
+		if err := json.Unmarshal(download(t, job).Body.Bytes(), &report); err != nil {
+			t.Fatalf("failed to unmarshal report: %v", err)
+		}
+		if len(report.Rows) != 3 {
+			t.Errorf("expected 3 report rows, got %+v", report.Rows)
+		}
+	})
+
+	t.Run("export", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/books/export?format=csv&sort=title", nil)
+		app.Router.ServeHTTP(w, req)
+
+		job := wait(t, accepted(t, w, "book_export"))
+		if job.Status != "succeeded" || job.Processed != 2 {
+			t.Fatalf("unexpected job %+v", job)
+		}
+
+		artifact := download(t, job)
+		if got := artifact.Header().Get("Content-Disposition"); got == "" {
+			t.Error("expected a Content-Disposition header")
+		}
+		records, err := csv.NewReader(artifact.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("failed to read csv: %v", err)
+		}
+		if len(records) != 3 || records[1][1] != "1984" || records[2][1] != "Animal Farm" {
+			t.Errorf("unexpected records %v", records)
+		}
+	})
+
+	t.Run("invalid export", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/books/export?format=xml", nil)
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		for _, path := range []string{"/jobs/999", "/jobs/999/artifact"} {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", path, nil)
+			app.Router.ServeHTTP(w, req)
+			if w.Code != http.StatusNotFound {
+				t.Errorf("%s: expected 404, got %d: %s", path, w.Code, w.Body.String())
+			}
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-api-boilerplate/internal/config"
+	"go-api-boilerplate/test/helpers"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type outboxMessageRes struct {
+	ID      int64  `json:"id"`
+	Type    string `json:"type"`
+	Key     string `json:"key"`
+	Payload struct {
+		Book    map[string]interface{} `json:"book"`
+		Changed []string               `json:"changed"`
+	} `json:"payload"`
+}
+
+func TestOutboxAPI_RelaysToNDJSONFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "outbox.ndjson")
+	app := helpers.SetupTestApp(t, func(cfg *config.Config) {
+		cfg.Outbox = config.Outbox{Publisher: "ndjson", File: file, MaxAttempts: 3}
+	})
+	defer helpers.CleanupDatabase(t)
+
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		app.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do("POST", "/books", map[string]string{"title": "1984", "author": "George Orwell"}); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("PATCH", "/books/1", map[string]string{"title": "Nineteen Eighty-Four"}); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := do("DELETE", "/books/1", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+	// A rejected write leaves nothing in the outbox.
+	if w := do("PATCH", "/books/1", map[string]string{"title": "Animal Farm"}); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// read returns the messages relayed so far.
+	read := func() []outboxMessageRes {
+		f, err := os.Open(file)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		var messages []outboxMessageRes
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var msg outboxMessageRes
+			if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+				t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+			}
+			messages = append(messages, msg)
+		}
+		return messages
+	}
+	deadline := time.Now().Add(10 * time.Second)
+	messages := read()
+	for len(messages) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 3 messages relayed after 10s, got %+v", messages)
+		}
+		time.Sleep(100 * time.Millisecond)
+		messages = read()
+	}
+
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages, got %+v", messages)
+	}
+	for i, want := range []string{"book.created", "book.updated", "book.deleted"} {
+		msg := messages[i]
+		if msg.Type != want || msg.Key != "book:1" || msg.Payload.Book["id"] != float64(1) {
+			t.Errorf("message %d: expected %s of book:1, got %+v", i, want, msg)
+		}
+		if i > 0 && msg.ID <= messages[i-1].ID {
+			t.Errorf("message %d: expected an ID above %d, got %d", i, messages[i-1].ID, msg.ID)
+		}
+	}
+	if changed := messages[1].Payload.Changed; len(changed) != 1 || changed[0] != "title" {
+		t.Errorf("expected the update to change the title, got %v", changed)
+	}
+
+	// Relayed messages leave the outbox once the relay commits.
+	for {
+		var pending int
+		if err := helpers.DB().QueryRow(context.Background(), "SELECT COUNT(*) FROM outbox").Scan(&pending); err != nil {
+			t.Fatal(err)
+		}
+		if pending == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected an empty outbox, got %d message(s)", pending)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"go-api-boilerplate/internal/config"
+	"go-api-boilerplate/test/helpers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBookAPI_PaginationHeaders(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	for _, title := range []string{"Book 1", "Book 2", "Book 3", "Book 4", "Book 5"} {
+		createBook(t, title, "Author")
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/books?page=2&per_page=2", nil)
+	app.Router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("X-Total-Count"); got != "5" {
+		t.Errorf("expected X-Total-Count 5, got %q", got)
+	}
+	wantLink := `</books?page=1&per_page=2>; rel="first", ` +
+		`</books?page=1&per_page=2>; rel="prev", ` +
+		`</books?page=3&per_page=2>; rel="next", ` +
+		`</books?page=3&per_page=2>; rel="last"`
+	if got := w.Header().Get("Link"); got != wantLink {
+		t.Errorf("expected Link %q, got %q", wantLink, got)
+	}
+	var books []map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &books); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(books) != 2 || books[0]["title"] != "Book 3" {
+		t.Errorf("expected books 3 and 4, got %v", books)
+	}
+}
+
+func TestBookAPI_PaginationEnvelope(t *testing.T) {
+	app := helpers.SetupTestApp(t, func(cfg *config.Config) {
+		cfg.HTTP.PaginationStyle = "envelope"
+	})
+	defer helpers.CleanupDatabase(t)
+
+	for _, title := range []string{"Book 1", "Book 2", "Book 3"} {
+		createBook(t, title, "Author")
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/books?page=2&per_page=2", nil)
+	app.Router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var res struct {
+		Data    []map[string]interface{} `json:"data"`
+		Page    int                      `json:"page"`
+		PerPage int                      `json:"per_page"`
+		Total   int                      `json:"total"`
+		HasNext bool                     `json:"has_next"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(res.Data) != 1 || res.Page != 2 || res.PerPage != 2 || res.Total != 3 || res.HasNext {
+		t.Errorf("expected the last page of 3 books, got %+v", res)
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"go-api-boilerplate/test/helpers"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBookAPI_PatchBook(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	_, err := helpers.DB().Exec(context.Background(),
+		"INSERT INTO books (title, author, isbn) VALUES ($1, $2, $3)",
+		"Original", "Author", "9780743273565",
+	)
+	if err != nil {
+		t.Fatalf("failed to create book: %v", err)
+	}
+
+	patch := func(body, ifMatch string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", "/books/1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		app.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("absent_fields_unchanged", func(t *testing.T) {
+		w := patch(`{"title":"Patched"}`, `"1"`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("ETag"); got != `"2"` {
+			t.Errorf(`expected ETag "2", got %q`, got)
+		}
+
+		var res map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &res)
+		if res["title"] != "Patched" || res["author"] != "Author" || res["isbn"] != "9780743273565" {
+			t.Errorf("unexpected book: %v", res)
+		}
+	})
+
+	t.Run("null_clears_isbn", func(t *testing.T) {
+		if w := patch(`{"isbn":null}`, ""); w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var isbn *string
+		err := helpers.DB().QueryRow(context.Background(), "SELECT isbn FROM books WHERE id = 1").Scan(&isbn)
+		if err != nil {
+			t.Fatalf("failed to query book: %v", err)
+		}
+		if isbn != nil {
+			t.Errorf("expected isbn to be cleared, got %q", *isbn)
+		}
+	})
+
+	t.Run("null_title_rejected", func(t *testing.T) {
+		if w := patch(`{"title":null}`, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("stale_version", func(t *testing.T) {
+		if w := patch(`{"title":"Stale"}`, `"1"`); w.Code != http.StatusPreconditionFailed {
+			t.Errorf("expected 412, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("wrong_content_type", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PATCH", "/books/1", strings.NewReader(`{"title":"Patched"}`))
+		req.Header.Set("Content-Type", "application/json")
+		app.Router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("expected 415, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-api-boilerplate/test/helpers"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestBookAPI_Search(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	for _, b := range []map[string]string{
+		{"title": "Animal Farm", "author": "George Orwell"},
+		{"title": "Farmer Giles of Ham", "author": "J. R. R. Tolkien"},
+		{"title": "The Farm Animals", "author": "Jane Doe"},
+	} {
+		jsonBody, _ := json.Marshal(b)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/books", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	type hit struct {
+		Title     string `json:"title"`
+		Highlight struct {
+			Title  string `json:"title"`
+			Author string `json:"author"`
+		} `json:"highlight"`
+	}
+	search := func(t *testing.T, q string) []hit {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books/search?q="+url.QueryEscape(q), nil)
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var hits []hit
+		if err := json.Unmarshal(w.Body.Bytes(), &hits); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return hits
+	}
+	titles := func(hits []hit) []string {
+		out := []string{}
+		for _, h := range hits {
+			out = append(out, h.Title)
+		}
+		return out
+	}
+
+	t.Run("words_match_title_and_author", func(t *testing.T) {
+		hits := search(t, "farm orwell")
+		if got := titles(hits); !reflect.DeepEqual(got, []string{"Animal Farm"}) {
+			t.Fatalf("expected Animal Farm, got %v", got)
+		}
+		if hits[0].Highlight.Title != "Animal <mark>Farm</mark>" || hits[0].Highlight.Author != "George <mark>Orwell</mark>" {
+			t.Errorf("unexpected highlight: %+v", hits[0].Highlight)
+		}
+	})
+
+	t.Run("phrase", func(t *testing.T) {
+		if got := titles(search(t, `"animal farm"`)); !reflect.DeepEqual(got, []string{"Animal Farm"}) {
+			t.Errorf("expected only the exact phrase, got %v", got)
+		}
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		if got := titles(search(t, "tolk*")); !reflect.DeepEqual(got, []string{"Farmer Giles of Ham"}) {
+			t.Errorf("expected Tolkien's book, got %v", got)
+		}
+	})
+
+	t.Run("stemmed", func(t *testing.T) {
+		want := []string{"Animal Farm", "The Farm Animals"}
+		if got := titles(search(t, "animals")); !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("no_words", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books/search?q=%26%26", nil)
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestBookAPI_FuzzySearchAndSuggest(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	for _, b := range []map[string]string{
+		{"title": "Animal Farm", "author": "George Orwell"},
+		{"title": "The Great Gatsby", "author": "F. Scott Fitzgerald"},
+		{"title": "Anna Karenina", "author": "Leo Tolstoy"},
+	} {
+		jsonBody, _ := json.Marshal(b)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/books", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	type hit struct {
+		Title string  `json:"title"`
+		Rank  float64 `json:"rank"`
+	}
+	fuzzy := func(t *testing.T, q string) []hit {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books/search?mode=fuzzy&q="+url.QueryEscape(q), nil)
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var hits []hit
+		if err := json.Unmarshal(w.Body.Bytes(), &hits); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return hits
+	}
+
+	for q, want := range map[string]string{"Orwel": "Animal Farm", "Gatsbi": "The Great Gatsby"} {
+		t.Run("typo_"+q, func(t *testing.T) {
+			hits := fuzzy(t, q)
+			if len(hits) != 1 || hits[0].Title != want {
+				t.Fatalf("expected %q, got %+v", want, hits)
+			}
+			if hits[0].Rank <= 0 || hits[0].Rank > 1 {
+				t.Errorf("expected a similarity in (0, 1], got %v", hits[0].Rank)
+			}
+		})
+	}
+
+	t.Run("unrelated", func(t *testing.T) {
+		if hits := fuzzy(t, "xylophone"); len(hits) != 0 {
+			t.Errorf("expected no hits, got %+v", hits)
+		}
+	})
+
+	t.Run("suggest", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/books/suggest?prefix=an", nil)
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var suggestions []struct {
+			Text  string `json:"text"`
+			Field string `json:"field"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &suggestions); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		got := []string{}
+		for _, s := range suggestions {
+			got = append(got, s.Field+":"+s.Text)
+		}
+		want := []string{"title:Animal Farm", "title:Anna Karenina"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+}
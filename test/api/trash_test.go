@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"go-api-boilerplate/test/helpers"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBookAPI_Trash(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	do := func(method, path, token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		app.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	createBook(t, "Kept", "Author")
+	createBook(t, "Trashed", "Author")
+
+	if w := do("DELETE", "/books/2", ""); w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+	}
+
+	t.Run("hidden_from_listing", func(t *testing.T) {
+		w := do("GET", "/books", "")
+		var books []map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &books); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(books) != 1 || books[0]["title"] != "Kept" {
+			t.Errorf("expected only the live book, got %v", books)
+		}
+	})
+
+	t.Run("listed_in_trash", func(t *testing.T) {
+		w := do("GET", "/books/trash", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var books []map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &books); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(books) != 1 || books[0]["title"] != "Trashed" || books[0]["deleted_at"] == nil {
+			t.Errorf("expected the trashed book with deleted_at, got %v", books)
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		if w := do("POST", "/books/2/restore", ""); w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if w := do("GET", "/books/2", ""); w.Code != http.StatusOK {
+			t.Errorf("expected restored book to be readable, got %d", w.Code)
+		}
+		if w := do("POST", "/books/2/restore", ""); w.Code != http.StatusNotFound {
+			t.Errorf("expected 404 when the book is not in the trash, got %d", w.Code)
+		}
+	})
+
+	t.Run("purge_requires_admin_token", func(t *testing.T) {
+		do("DELETE", "/books/2", "")
+
+		if w := do("DELETE", "/admin/books/2", ""); w.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", w.Code)
+		}
+		if w := do("DELETE", "/admin/books/2", "wrong"); w.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", w.Code)
+		}
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		if w := do("DELETE", "/admin/books/1", helpers.AdminToken); w.Code != http.StatusNotFound {
+			t.Errorf("expected 404 for a live book, got %d", w.Code)
+		}
+		if w := do("DELETE", "/admin/books/2", helpers.AdminToken); w.Code != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var count int
+		err := helpers.DB().QueryRow(
+			context.Background(),
+			"SELECT COUNT(*) FROM books WHERE id = 2",
+		).Scan(&count)
+		if err != nil {
+			t.Fatalf("failed to query database: %v", err)
+		}
+		if count != 0 {
+			t.Errorf("book still exists in database after purge")
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-api-boilerplate/internal/http/util"
+	"go-api-boilerplate/test/helpers"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestBookAPI_ValidationErrors(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	tests := []struct {
+		name       string
+		body       map[string]interface{}
+		wantMsg    string
+		wantFields []util.FieldError
+	}{
+		{
+			name:    "binding",
+			body:    map[string]interface{}{"author_ids": []int{0}},
+			wantMsg: "request validation failed",
+			wantFields: []util.FieldError{
+				{Field: "title", Rule: "required", Message: "title is required"},
+				{Field: "author_ids[0]", Rule: "min", Message: "author_ids[0] must be at least 1"},
+			},
+		},
+		{
+			name:    "domain",
+			body:    map[string]interface{}{"title": "1984", "author": "George Orwell", "isbn": "123"},
+			wantMsg: "isbn is invalid",
+			wantFields: []util.FieldError{
+				{Field: "isbn", Rule: "isbn", Message: "isbn is invalid"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonBody, _ := json.Marshal(tt.body)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/books", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			app.Router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+			}
+			var res util.HTTPError
+			json.Unmarshal(w.Body.Bytes(), &res)
+			if res.Code != "VALIDATION_ERROR" {
+				t.Errorf("expected VALIDATION_ERROR, got %s", res.Code)
+			}
+			if res.Message != tt.wantMsg {
+				t.Errorf("expected message %q, got %q", tt.wantMsg, res.Message)
+			}
+			if !reflect.DeepEqual(res.Errors, tt.wantFields) {
+				t.Errorf("expected errors %v, got %v", tt.wantFields, res.Errors)
+			}
+		})
+	}
+}
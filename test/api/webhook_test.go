@@ -0,0 +1,206 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-api-boilerplate/internal/adapter/webhooks"
+	"go-api-boilerplate/test/helpers"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type webhookDeliveryRes struct {
+	ID             int64  `json:"id"`
+	MessageID      int64  `json:"message_id"`
+	Event          string `json:"event"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	ResponseStatus int    `json:"response_status"`
+	Error          string `json:"error"`
+	NextAttemptAt  string `json:"next_attempt_at"`
+	DeliveredAt    string `json:"delivered_at"`
+}
+
+// webhookReceiver records the signed requests posted to it and answers
+// with status.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	rcv.requests = append(rcv.requests, r)
+	rcv.bodies = append(rcv.bodies, body)
+	w.WriteHeader(rcv.status)
+}
+
+func (rcv *webhookReceiver) received() int {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	return len(rcv.requests)
+}
+
+func TestWebhookAPI_DeliversSignedEvents(t *testing.T) {
+	app := helpers.SetupTestApp(t)
+	defer helpers.CleanupDatabase(t)
+
+	do := func(method, path string, body interface{}) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+helpers.AdminToken)
+		app.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	// deliveries waits until the webhook's log has a delivery matching done.
+	deliveries := func(id int, done func(webhookDeliveryRes) bool) []webhookDeliveryRes {
+		deadline := time.Now().Add(10 * time.Second)
+		for {
+			w := do("GET", fmt.Sprintf("/webhooks/%d/deliveries", id), nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var res []webhookDeliveryRes
+			if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+			if len(res) > 0 && done(res[0]) {
+				return res
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("webhook %d: no finished delivery after 10s, got %+v", id, res)
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	t.Run("requires the admin token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/webhooks", nil)
+		app.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", w.Code)
+		}
+	})
+
+	t.Run("rejects invalid webhooks", func(t *testing.T) {
+		w := do("POST", "/webhooks", map[string]interface{}{"url": "ftp://example.com", "events": []string{"author.created"}})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	ok := &webhookReceiver{status: http.StatusNoContent}
+	okServer := httptest.NewServer(ok)
+	defer okServer.Close()
+	failing := &webhookReceiver{status: http.StatusInternalServerError}
+	failingServer := httptest.NewServer(failing)
+	defer failingServer.Close()
+
+	w := do("POST", "/webhooks", map[string]interface{}{"url": okServer.URL, "events": []string{"book.created"}, "secret": "s3cret"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	w = do("POST", "/webhooks", map[string]interface{}{"url": failingServer.URL})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created struct {
+		Secret string `json:"secret"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if created.Secret == "" {
+		t.Error("expected a generated secret")
+	}
+
+	// The secret is not returned after creation.
+	w = do("GET", "/webhooks/1", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if bytes.Contains(w.Body.Bytes(), []byte("s3cret")) {
+		t.Errorf("expected no secret, got %s", w.Body.String())
+	}
+
+	if w := do("POST", "/books", map[string]string{"title": "1984", "author": "George Orwell"}); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	t.Run("delivers signed events", func(t *testing.T) {
+		res := deliveries(1, func(d webhookDeliveryRes) bool { return d.Status == "succeeded" })
+		if len(res) != 1 || res[0].Event != "book.created" || res[0].Attempts != 1 || res[0].ResponseStatus != http.StatusNoContent || res[0].DeliveredAt == "" {
+			t.Fatalf("expected one succeeded book.created delivery, got %+v", res)
+		}
+
+		if ok.received() != 1 {
+			t.Fatalf("expected 1 request, got %d", ok.received())
+		}
+		req, body := ok.requests[0], ok.bodies[0]
+		if got := req.Header.Get(webhooks.EventHeader); got != "book.created" {
+			t.Errorf("expected event book.created, got %q", got)
+		}
+		if got := req.Header.Get(webhooks.DeliveryHeader); got != fmt.Sprint(res[0].ID) {
+			t.Errorf("expected delivery %d, got %q", res[0].ID, got)
+		}
+		err := webhooks.Verify("s3cret", req.Header.Get(webhooks.SignatureHeader), req.Header.Get(webhooks.TimestampHeader), body, time.Now(), 5*time.Minute)
+		if err != nil {
+			t.Errorf("expected a valid signature, got %v", err)
+		}
+		var msg outboxMessageRes
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatalf("invalid body %s: %v", body, err)
+		}
+		if msg.ID != res[0].MessageID || msg.Type != "book.created" || msg.Payload.Book["title"] != "1984" {
+			t.Errorf("expected the book.created message, got %s", body)
+		}
+	})
+
+	t.Run("schedules a retry after a failed delivery", func(t *testing.T) {
+		res := deliveries(2, func(d webhookDeliveryRes) bool { return d.Attempts > 0 })
+		if len(res) != 1 {
+			t.Fatalf("expected 1 delivery, got %+v", res)
+		}
+		d := res[0]
+		if d.Status != "pending" || d.Attempts != 1 || d.ResponseStatus != http.StatusInternalServerError || d.Error == "" || d.NextAttemptAt == "" {
+			t.Errorf("expected a pending delivery to retry after a 500, got %+v", d)
+		}
+	})
+
+	t.Run("filters events", func(t *testing.T) {
+		if w := do("PATCH", "/books/1", map[string]string{"title": "Nineteen Eighty-Four"}); w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		res := deliveries(2, func(d webhookDeliveryRes) bool { return d.Event == "book.updated" })
+		if len(res) != 2 {
+			t.Errorf("expected the unfiltered webhook to get 2 deliveries, got %+v", res)
+		}
+		w := do("GET", "/webhooks/1/deliveries", nil)
+		if got := w.Header().Get("X-Total-Count"); got != "1" {
+			t.Errorf("expected the book.created webhook to keep 1 delivery, got %s", got)
+		}
+	})
+
+	t.Run("deletes webhooks", func(t *testing.T) {
+		if w := do("DELETE", "/webhooks/2", nil); w.Code != http.StatusNoContent {
+			t.Fatalf("expected 204, got %d: %s", w.Code, w.Body.String())
+		}
+		if w := do("GET", "/webhooks/2/deliveries", nil); w.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
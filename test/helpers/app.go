@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"go-api-boilerplate/internal/bootstrap"
 	"go-api-boilerplate/internal/config"
-	"path/filepath"
-	"runtime"
+	"go-api-boilerplate/internal/infra"
+	"go-api-boilerplate/migrations"
 	"sync"
 	"testing"
 	"time"
@@ -18,6 +18,9 @@ import (
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
+// AdminToken is the token the test app accepts on /admin routes.
+const AdminToken = "test-admin-token"
+
 var (
 	pgContainer *postgres.PostgresContainer
 	dbPool      *pgxpool.Pool
@@ -37,7 +40,6 @@ func InitTestContainer() error {
 			postgres.WithDatabase("testdb"),
 			postgres.WithUsername("testuser"),
 			postgres.WithPassword("testpass"),
-			postgres.WithInitScripts(getInitSQLPath()),
 			testcontainers.WithWaitStrategy(
 				wait.ForLog("database system is ready to accept connections").
 					WithOccurrence(2).
@@ -74,6 +76,25 @@ func InitTestContainer() error {
 					Schema:   "public",
 				},
 			},
+			Admin: config.Admin{
+				Token: AdminToken,
+			},
+			Search: config.Search{
+				FuzzyThreshold: 0.5,
+			},
+			Jobs: config.Jobs{
+				Workers:     2,
+				MaxAttempts: 3,
+			},
+			Outbox: config.Outbox{
+				MaxAttempts: 3,
+			},
+			Webhooks: config.Webhooks{
+				Workers:     1,
+				MaxAttempts: 3,
+				// The test receivers listen on 127.0.0.1.
+				AllowPrivate: true,
+			},
 		}
 
 		// Create a dedicated DB pool for cleanup operations
@@ -91,6 +112,17 @@ func InitTestContainer() error {
 			initErr = fmt.Errorf("failed to create db pool: %w", initErr)
 			return
 		}
+
+		// Apply the same schema migrations as production
+		migrator, err := infra.NewMigrator(dbPool, migrations.FS)
+		if err != nil {
+			initErr = fmt.Errorf("failed to load migrations: %w", err)
+			return
+		}
+		if err := migrator.Up(ctx); err != nil {
+			initErr = fmt.Errorf("failed to run migrations: %w", err)
+			return
+		}
 	})
 
 	return initErr
@@ -108,15 +140,21 @@ func TeardownTestContainer() error {
 	return nil
 }
 
-// SetupTestApp creates a new app instance using the shared container
-func SetupTestApp(t *testing.T) *bootstrap.App {
+// SetupTestApp creates a new app instance using the shared container.
+// Options adjust a copy of the shared config for this app only.
+func SetupTestApp(t *testing.T, opts ...func(*config.Config)) *bootstrap.App {
 	if err := InitTestContainer(); err != nil {
 		t.Fatalf("failed to initialize test container: %v", err)
 	}
 
 	gin.SetMode(gin.TestMode)
 
-	app, err := bootstrap.NewApp(context.Background(), cfg)
+	appCfg := *cfg
+	for _, opt := range opts {
+		opt(&appCfg)
+	}
+
+	app, err := bootstrap.NewApp(context.Background(), &appCfg)
 	if err != nil {
 		t.Fatalf("failed to setup app: %v", err)
 	}
@@ -136,7 +174,7 @@ func CleanupDatabase(t *testing.T) {
 
 	_, err := dbPool.Exec(
 		context.Background(),
-		"TRUNCATE books RESTART IDENTITY CASCADE",
+		"TRUNCATE books, authors, jobs, book_history, outbox, webhooks, webhook_deliveries RESTART IDENTITY CASCADE",
 	)
 	if err != nil {
 		t.Logf("warning: failed to truncate: %v", err)
@@ -147,8 +185,3 @@ func CleanupDatabase(t *testing.T) {
 func DB() *pgxpool.Pool {
 	return dbPool
 }
-
-func getInitSQLPath() string {
-	_, filename, _, _ := runtime.Caller(0)
-	return filepath.Join(filepath.Dir(filename), "..", "..", "init.sql")
-}